@@ -0,0 +1,76 @@
+// Package chiadapter serves components on chi routes: the url parameters of the route are added to the data of the
+// component, and htmx requests get the fragment while full page loads get the component in its layouts.
+//
+//	r := chi.NewRouter()
+//	chiadapter.Handle(r, "/users/{id}", func() htmx.RenderableComponent {
+//		return htmx.NewComponent("templates/user.html").Wrap(layout(), "content")
+//	})
+//
+// The template reads the parameters from .Data.Params, e.g. {{ .Data.Params.id }}.
+package chiadapter
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jkc-2/go-htmx"
+)
+
+// Renderer renders components for chi routes with its htmx instance
+type Renderer struct {
+	htmx *htmx.HTMX
+}
+
+// defaultRenderer is the renderer of Handle
+var defaultRenderer = NewRenderer(htmx.New())
+
+// NewRenderer returns a renderer for the htmx instance
+func NewRenderer(h *htmx.HTMX) *Renderer {
+	return &Renderer{htmx: h}
+}
+
+// Handle serves a new component of the factory on GET requests of the pattern, see Renderer.Handle
+func Handle(r chi.Router, pattern string, factory htmx.ComponentFactory) {
+	defaultRenderer.Handle(r, pattern, factory)
+}
+
+// Handle serves a new component of the factory on GET requests of the pattern. Components aren't safe for concurrent
+// use, so every request renders a component of its own.
+func (rd *Renderer) Handle(r chi.Router, pattern string, factory htmx.ComponentFactory) {
+	r.Get(pattern, rd.Handler(factory))
+}
+
+// Handler returns a handler that renders a new component of the factory with the url parameters of the route
+func (rd *Renderer) Handler(factory htmx.ComponentFactory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			r = htmx.WithRoute(r, rctx.RoutePattern())
+		}
+
+		c := factory()
+		c.AddData(htmx.ParamsKey, Params(r))
+
+		if _, err := rd.htmx.NewHandler(w, r).Render(r.Context(), c); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+}
+
+// Params returns the url parameters of the chi route of the request
+func Params(r *http.Request) map[string]string {
+	params := make(map[string]string)
+
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return params
+	}
+
+	// the wildcard of a pattern ending in /* has the key *
+	for i, key := range rctx.URLParams.Keys {
+		if i < len(rctx.URLParams.Values) {
+			params[key] = rctx.URLParams.Values[i]
+		}
+	}
+
+	return params
+}
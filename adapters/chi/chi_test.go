@@ -0,0 +1,55 @@
+package chiadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestHandle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<main>{{ .Partials.content }}</main>`)},
+		"user.html":   {Data: []byte(`<p>{{ .Data.Params.id }} {{ .Data.Params.tab }}</p>`)},
+	}
+
+	r := chi.NewRouter()
+	Handle(r, "/users/{id}/{tab}", func() htmx.RenderableComponent {
+		return htmx.NewComponent("user.html").FS(fsys).Wrap(htmx.NewComponent("layout.html").FS(fsys), "content")
+	})
+
+	tests := []struct {
+		name     string
+		htmx     bool
+		expected string
+	}{
+		{"full page load", false, `<main><p>7 orders</p></main>`},
+		{"htmx request", true, `<p>7 orders</p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/7/orders", nil)
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			equal(t, http.StatusOK, w.Code)
+			equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
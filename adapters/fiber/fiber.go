@@ -0,0 +1,195 @@
+// Package fiberadapter integrates components with fiber, which doesn't use the net/http types: a fiber.Views engine
+// that renders templates as components, helpers that read and write the htmx headers of a fiber.Ctx and Render, which
+// renders a component with the htmx handler.
+//
+//	app := fiber.New(fiber.Config{Views: fiberadapter.NewEngine(templates, ".html")})
+//
+//	app.Get("/users", func(c *fiber.Ctx) error {
+//		return c.Render("users", fiber.Map{"Users": users}, fiberadapter.Layout(c, "layout")...)
+//	})
+package fiberadapter
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/jkc-2/go-htmx"
+)
+
+// Engine is a fiber.Views engine that renders the templates as components, with the template functions and the
+// template cache of the htmx package
+type Engine struct {
+	fsys      fs.FS
+	extension string
+	target    string
+}
+
+var _ fiber.Views = (*Engine)(nil)
+
+// NewEngine returns an engine for the templates of the filesystem with the extension, e.g. ".html". A nil
+// filesystem loads the templates from the active template set.
+func NewEngine(fsys fs.FS, extension string) *Engine {
+	return &Engine{
+		fsys:      fsys,
+		extension: extension,
+		target:    "content",
+	}
+}
+
+// Target sets the partial of the layouts the template is rendered into, the default is content
+//
+//	<main>{{ .Partials.content }}</main>
+func (e *Engine) Target(target string) *Engine {
+	e.target = target
+	return e
+}
+
+// Load does nothing, templates are parsed on their first render and kept in the template cache
+func (e *Engine) Load() error {
+	return nil
+}
+
+// Render renders the template with the binding as its data, wrapped in the layouts from the innermost to the
+// outermost, which get the binding as well. A fiber.Map or other map is the data of the templates, other bindings
+// are their Binding data.
+func (e *Engine) Render(w io.Writer, name string, binding any, layouts ...string) error {
+	var c htmx.RenderableComponent = e.component(name, binding)
+	for _, layout := range layouts {
+		c = e.component(layout, binding).With(c, e.target)
+	}
+
+	output, err := c.Render(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, string(output))
+	return err
+}
+
+// component returns the component of the template name with the binding
+func (e *Engine) component(name string, binding any) *htmx.Component {
+	c := htmx.NewComponent(name + e.extension)
+	if e.fsys != nil {
+		c.FS(e.fsys)
+	}
+
+	switch data := binding.(type) {
+	case fiber.Map:
+		c.SetData(data)
+	case map[string]any:
+		c.SetData(data)
+	case nil:
+	default:
+		c.AddData("Binding", binding)
+	}
+
+	return c
+}
+
+// Layout returns the layouts for c.Render, or none when the request wants a fragment
+//
+//	c.Render("users", data, fiberadapter.Layout(c, "layout")...)
+func Layout(c *fiber.Ctx, layouts ...string) []string {
+	if WantsFragment(c) {
+		return nil
+	}
+
+	return layouts
+}
+
+// WantsFragment returns true if the request was made by htmx to swap a fragment, see htmx.WantsFragment
+func WantsFragment(c *fiber.Ctx) bool {
+	req := Request(c)
+	return req.IsHTMX && !req.Boosted && !req.HistoryRestoreRequest
+}
+
+// Request returns the htmx request headers of the request
+func Request(c *fiber.Ctx) htmx.Request {
+	return htmx.ParseRequest(&http.Request{Header: c.GetReqHeaders()})
+}
+
+// Response sets the htmx response headers that are set by the function
+//
+//	err := fiberadapter.Response(c, func(res *htmx.HxResponse) error {
+//		res.PushURL("/users")
+//		return res.Location(htmx.NewLocation("/users"))
+//	})
+func Response(c *fiber.Ctx, set func(res *htmx.HxResponse) error) error {
+	w := &responseWriter{c: c, header: make(http.Header)}
+	if err := set(htmx.NewHxResponse(w)); err != nil {
+		return err
+	}
+
+	w.copyHeader()
+	return nil
+}
+
+// Render renders the component with the htmx handler of the instance: htmx requests get the fragment, full page
+// loads get the component in its layouts. The route of the fiber context labels the statistics of the response, see
+// htmx.WithRoute.
+func Render(c *fiber.Ctx, h *htmx.HTMX, component htmx.RenderableComponent) error {
+	r, err := adaptor.ConvertRequest(c, false)
+	if err != nil {
+		return err
+	}
+
+	w := &responseWriter{c: c, header: make(http.Header)}
+	if _, err := h.NewHandler(w, htmx.WithRoute(r, c.Route().Path)).Render(c.UserContext(), component); err != nil {
+		return err
+	}
+
+	w.copyHeader()
+	return nil
+}
+
+// responseWriter is a http.ResponseWriter that writes to a fiber.Ctx, the header is copied on the first write
+type responseWriter struct {
+	c           *fiber.Ctx
+	header      http.Header
+	wroteHeader bool
+}
+
+// Header returns the header of the response
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader copies the header and sets the status code
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.copyHeader()
+	w.c.Status(status)
+	w.wroteHeader = true
+}
+
+// Write writes the body of the response
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.c.Write(b)
+}
+
+// copyHeader copies the header to the response of the fiber.Ctx
+func (w *responseWriter) copyHeader() {
+	for key, values := range w.header {
+		w.c.Response().Header.Del(key)
+		for _, value := range values {
+			w.c.Response().Header.Add(key, value)
+		}
+	}
+
+	if w.header.Get("Content-Type") == "" && strings.HasPrefix(string(w.c.Response().Header.ContentType()), "text/plain") {
+		w.c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	}
+}
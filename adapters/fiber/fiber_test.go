@@ -0,0 +1,86 @@
+package fiberadapter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestFiber(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<main>{{ len .Data.Users }}{{ .Partials.content }}</main>`)},
+		"users.html":  {Data: []byte(`<ul>{{ range .Data.Users }}<li>{{ . }}</li>{{ end }}</ul>`)},
+	}
+
+	app := fiber.New(fiber.Config{Views: NewEngine(fsys, ".html")})
+	app.Get("/users", func(c *fiber.Ctx) error {
+		return c.Render("users", fiber.Map{"Users": []string{"Ada"}}, Layout(c, "layout")...)
+	})
+	app.Get("/component", func(c *fiber.Ctx) error {
+		err := Response(c, func(res *htmx.HxResponse) error {
+			res.PushURL("/users")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		page := htmx.NewComponent("users.html").FS(fsys).AddData("Users", []string{Request(c).Target}).
+			Wrap(htmx.NewComponent("layout.html").FS(fsys), "content")
+		return Render(c, htmx.New(), page)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		expected string
+	}{
+		{"engine full page", "/users", nil, `<main>1<ul><li>Ada</li></ul></main>`},
+		{"engine fragment", "/users", map[string]string{"HX-Request": "true"}, `<ul><li>Ada</li></ul>`},
+		{"component full page", "/component", nil, `<main>1<ul><li></li></ul></main>`},
+		{"component fragment", "/component", map[string]string{"HX-Request": "true", "HX-Target": "list"}, `<ul><li>list</li></ul>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, http.StatusOK, res.StatusCode)
+			equal(t, tt.expected, string(body))
+			equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+		})
+	}
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/component", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "/users", res.Header.Get("HX-Push-Url"))
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
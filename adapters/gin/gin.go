@@ -0,0 +1,159 @@
+// Package ginadapter integrates components with gin: a render.HTMLRender that renders templates as components for
+// c.HTML, a middleware that parses the htmx request headers once and Render, which renders a component with the htmx
+// handler.
+//
+//	router := gin.New()
+//	router.HTMLRender = ginadapter.NewHTMLRender(templates, ".html")
+//	router.Use(ginadapter.Middleware())
+//
+//	router.GET("/users", func(c *gin.Context) {
+//		ginadapter.Render(c, h, htmx.NewComponent("users.html").Wrap(layout(), "content"))
+//	})
+package ginadapter
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/jkc-2/go-htmx"
+)
+
+// ContextKey is the key of the htmx request headers in the gin context
+const ContextKey = "htmx"
+
+type (
+	// HTMLRender is a render.HTMLRender that renders the templates as components, with the template functions and
+	// the template cache of the htmx package
+	HTMLRender struct {
+		fsys      fs.FS
+		extension string
+		layouts   []string
+		target    string
+	}
+
+	// instance is the render.Render of a template and its data
+	instance struct {
+		render *HTMLRender
+		name   string
+		data   any
+	}
+)
+
+var (
+	_ render.HTMLRender = (*HTMLRender)(nil)
+	_ render.Render     = instance{}
+)
+
+// NewHTMLRender returns a renderer for the templates of the filesystem with the extension, e.g. ".html". A nil
+// filesystem loads the templates from the active template set.
+func NewHTMLRender(fsys fs.FS, extension string) *HTMLRender {
+	return &HTMLRender{
+		fsys:      fsys,
+		extension: extension,
+		target:    "content",
+	}
+}
+
+// Layout wraps every template in the layouts, from the innermost to the outermost, the template is rendered into the
+// content partial of the layout. The layouts are rendered for htmx requests as well, use Render for components that
+// render the fragment only for htmx requests.
+func (h *HTMLRender) Layout(layouts ...string) *HTMLRender {
+	h.layouts = layouts
+	return h
+}
+
+// Target sets the partial of the layouts the template is rendered into, the default is content
+func (h *HTMLRender) Target(target string) *HTMLRender {
+	h.target = target
+	return h
+}
+
+// Instance returns the render.Render of the template and its data, a gin.H or other map is the data of the
+// templates, other data is their Binding data
+func (h *HTMLRender) Instance(name string, data any) render.Render {
+	return instance{render: h, name: name, data: data}
+}
+
+// Render renders the template in its layouts
+func (i instance) Render(w http.ResponseWriter) error {
+	i.WriteContentType(w)
+
+	var c htmx.RenderableComponent = i.render.component(i.name, i.data)
+	for _, layout := range i.render.layouts {
+		c = i.render.component(layout, i.data).With(c, i.render.target)
+	}
+
+	output, err := c.Render(context.Background())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte(output))
+	return err
+}
+
+// WriteContentType writes the html content type
+func (i instance) WriteContentType(w http.ResponseWriter) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+}
+
+// component returns the component of the template name with the data
+func (h *HTMLRender) component(name string, data any) *htmx.Component {
+	c := htmx.NewComponent(name + h.extension)
+	if h.fsys != nil {
+		c.FS(h.fsys)
+	}
+
+	switch data := data.(type) {
+	case gin.H:
+		c.SetData(data)
+	case map[string]any:
+		c.SetData(data)
+	case nil:
+	default:
+		c.AddData("Binding", data)
+	}
+
+	return c
+}
+
+// Middleware parses the htmx request headers once and stores them in the gin context and the request context, where
+// htmx.FromContext and the hxRequest template function find them
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		htmx.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			if req, ok := htmx.FromContext(r.Context()); ok {
+				c.Set(ContextKey, req)
+			}
+
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// Request returns the htmx request headers stored by the middleware, or parses them from the request
+func Request(c *gin.Context) htmx.Request {
+	if req, ok := c.Get(ContextKey); ok {
+		if req, ok := req.(htmx.Request); ok {
+			return req
+		}
+	}
+
+	return htmx.ParseRequest(c.Request)
+}
+
+// Render renders the component with the htmx handler of the instance: htmx requests get the fragment, full page
+// loads get the component in its layouts. A failed render is added to the errors of the gin context and answered with
+// 500 Internal Server Error. The route of the gin context labels the statistics of the response, see htmx.WithRoute.
+func Render(c *gin.Context, h *htmx.HTMX, component htmx.RenderableComponent) {
+	r := htmx.WithRoute(c.Request, c.FullPath())
+	if _, err := h.NewHandler(c.Writer, r).Render(r.Context(), component); err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+	}
+}
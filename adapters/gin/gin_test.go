@@ -0,0 +1,72 @@
+package ginadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestGin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<main>{{ len .Data.Users }}{{ .Partials.content }}</main>`)},
+		"users.html":  {Data: []byte(`<ul>{{ range .Data.Users }}<li>{{ . }}</li>{{ end }}</ul>`)},
+		"target.html": {Data: []byte(`<p>{{ (hxRequest).Target }}</p>`)},
+	}
+
+	router := gin.New()
+	router.HTMLRender = NewHTMLRender(fsys, ".html").Layout("layout")
+	router.Use(Middleware())
+
+	router.GET("/users", func(c *gin.Context) {
+		c.HTML(http.StatusOK, "users", gin.H{"Users": []string{"Ada"}})
+	})
+	router.GET("/component", func(c *gin.Context) {
+		page := htmx.NewComponent("users.html").FS(fsys).AddData("Users", []string{Request(c).Target}).
+			Wrap(htmx.NewComponent("layout.html").FS(fsys), "content")
+		Render(c, htmx.New(), page)
+	})
+	router.GET("/target", func(c *gin.Context) {
+		Render(c, htmx.New(), htmx.NewComponent("target.html").FS(fsys))
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		expected string
+	}{
+		{"html render", "/users", nil, `<main>1<ul><li>Ada</li></ul></main>`},
+		{"component full page", "/component", nil, `<main>1<ul><li></li></ul></main>`},
+		{"component fragment", "/component", map[string]string{"HX-Request": "true", "HX-Target": "list"}, `<ul><li>list</li></ul>`},
+		{"request context", "/target", map[string]string{"HX-Request": "true", "HX-Target": "list"}, `<p>list</p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			equal(t, http.StatusOK, w.Code)
+			equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,42 @@
+// Package gomponentsadapter composes gomponents nodes into template based pages: a node is rendered as a partial,
+// page or layout of components, or from a template with the gomponent template function.
+//
+//	page := htmx.NewComponent("templates/page.html").With(gomponentsadapter.Component(ui.Badge("new")), "badge")
+//
+//	htmx.DefaultTemplateFuncs["gomponent"] = gomponentsadapter.HTML
+//	page.AddData("Avatar", ui.Avatar(user)) // {{ gomponent .Data.Avatar }}
+package gomponentsadapter
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/jkc-2/go-htmx"
+	"maragu.dev/gomponents"
+)
+
+// Component returns a component that renders the node. It can be used as a partial, wrapped in layouts and rendered
+// with the htmx handler like any component, the data of the component isn't available to the node.
+func Component(n gomponents.Node) *htmx.Component {
+	return htmx.NewWriterComponent(func(_ context.Context, w io.Writer) error {
+		return n.Render(w)
+	})
+}
+
+// HTML renders the node for a template, register it as a template function to embed nodes in templates. The output
+// of the node isn't escaped, gomponents escapes text and attributes itself. A nil node renders nothing.
+func HTML(n gomponents.Node) (template.HTML, error) {
+	if n == nil {
+		return "", nil
+	}
+
+	var b strings.Builder
+	if err := n.Render(&b); err != nil {
+		return "", err
+	}
+
+	//nolint:gosec // gomponents escapes the text and attributes of the node
+	return template.HTML(b.String()), nil
+}
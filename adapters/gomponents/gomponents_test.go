@@ -0,0 +1,69 @@
+package gomponentsadapter
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+	"maragu.dev/gomponents"
+	"maragu.dev/gomponents/html"
+)
+
+func TestComponent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`<main>{{ .Partials.badge }}</main>`)},
+	}
+
+	badge := html.Span(html.Class("badge"), gomponents.Text("<new>"))
+
+	out, err := htmx.NewComponent("page.html").FS(fsys).With(Component(badge), "badge").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<main><span class="badge">&lt;new&gt;</span></main>`, string(out))
+}
+
+func TestComponentDevMode(t *testing.T) {
+	htmx.SetMode(htmx.Dev)
+	defer htmx.SetMode(htmx.Prod)
+
+	out, err := Component(html.Span(gomponents.Text("new"))).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<!-- htmx:begin component="(writer)" templates="" --><span>new</span><!-- htmx:end component="(writer)" -->`, string(out))
+}
+
+func TestComponentMultiFragment(t *testing.T) {
+	out, err := htmx.NewMultiFragment(Component(html.Li(gomponents.Text("Ada")))).
+		OOB("count", Component(html.Span(gomponents.Text("4"))), nil).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<li>Ada</li><span id="count" hx-swap-oob="outerHTML">4</span>`, string(out))
+}
+
+func TestHTML(t *testing.T) {
+	fsys := fstest.MapFS{
+		"card.html": {Data: []byte(`<div>{{ gomponent .Data.Avatar }}</div>`)},
+	}
+
+	out, err := htmx.NewComponent("card.html").FS(fsys).
+		AddTemplateFunction("gomponent", HTML).
+		AddData("Avatar", html.Img(html.Src("/ada.png"), html.Alt("Ada"))).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<div><img src="/ada.png" alt="Ada"></div>`, string(out))
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
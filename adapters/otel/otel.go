@@ -0,0 +1,67 @@
+// Package oteladapter traces the renders of go-htmx components with OpenTelemetry, every component is a span with its
+// partials as child spans.
+//
+//	htmx.RenderTracer = oteladapter.New(nil)
+package oteladapter
+
+import (
+	"context"
+
+	"github.com/jkc-2/go-htmx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans
+const ScopeName = "github.com/jkc-2/go-htmx"
+
+type (
+	// Tracer starts OpenTelemetry spans for component renders
+	Tracer struct {
+		tracer trace.Tracer
+	}
+
+	// span is the OpenTelemetry span of a render
+	span struct {
+		span trace.Span
+	}
+)
+
+// New returns a tracer that starts its spans with the tracer provider, the global provider is used when it is nil
+func New(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &Tracer{tracer: provider.Tracer(ScopeName)}
+}
+
+// StartRender starts the span of the render, named after the component
+func (t *Tracer) StartRender(ctx context.Context, info htmx.RenderInfo) (context.Context, htmx.RenderSpan) {
+	ctx, s := t.tracer.Start(ctx, "htmx.render "+info.Component,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("htmx.component", info.Component),
+			attribute.StringSlice("htmx.templates", info.Templates),
+			attribute.Int("htmx.partials", info.Partials),
+		))
+
+	return ctx, &span{span: s}
+}
+
+// End records the cache status and the error of the render and ends the span
+func (s *span) End(cacheStatus string, err error) {
+	s.span.SetAttributes(
+		attribute.String("htmx.cache", cacheStatus),
+		attribute.Bool("htmx.cache.hit", cacheStatus == "hit"),
+	)
+
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
@@ -0,0 +1,54 @@
+package oteladapter
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	htmx.RenderTracer = New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer func() { htmx.RenderTracer = nil }()
+
+	fsys := fstest.MapFS{
+		"otel-page.html": {Data: []byte(`<main>{{ .Partials.Nav }}</main>`)},
+		"otel-nav.html":  {Data: []byte(`<nav></nav>`)},
+	}
+
+	page := htmx.NewComponent("otel-page.html").FS(fsys).With(htmx.NewComponent("otel-nav.html").FS(fsys), "Nav")
+	if _, err := page.Render(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	nav, main := spans[0], spans[1]
+	equal(t, "htmx.render otel-nav.html", nav.Name())
+	equal(t, "htmx.render otel-page.html", main.Name())
+	equal(t, main.SpanContext().SpanID(), nav.Parent().SpanID())
+
+	attrs := map[string]string{}
+	for _, kv := range main.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+
+	equal(t, "otel-page.html", attrs["htmx.component"])
+	equal(t, "1", attrs["htmx.partials"])
+	equal(t, "miss", attrs["htmx.cache"])
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
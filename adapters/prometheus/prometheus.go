@@ -0,0 +1,108 @@
+// Package promadapter exposes the render and template cache metrics of go-htmx to Prometheus. The Collector observes
+// the renders of all components once it is set as htmx.RenderMetrics, register it with the registry of the app:
+//
+//	collector := promadapter.New()
+//	prometheus.MustRegister(collector)
+//	htmx.RenderMetrics = collector
+package promadapter
+
+import (
+	"github.com/jkc-2/go-htmx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the namespace of the metrics
+const Namespace = "htmx"
+
+// Collector collects the render durations, partial counts and errors per component and the template cache hits,
+// misses and evictions
+type Collector struct {
+	duration  *prometheus.HistogramVec
+	partials  *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	cache     *prometheus.CounterVec
+	evictions prometheus.Counter
+}
+
+// New returns a collector with the default histogram buckets for the render durations
+func New() *Collector {
+	return NewWithBuckets(prometheus.DefBuckets)
+}
+
+// NewWithBuckets returns a collector with the histogram buckets for the render durations, in seconds
+func NewWithBuckets(buckets []float64) *Collector {
+	labels := []string{"component", "template"}
+
+	return &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "render_duration_seconds",
+			Help:      "Duration of component renders, their partials included.",
+			Buckets:   buckets,
+		}, labels),
+		partials: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "render_partials",
+			Help:      "Number of partials rendered into a component.",
+			Buckets:   []float64{0, 1, 2, 4, 8, 16, 32},
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "render_errors_total",
+			Help:      "Number of failed component renders.",
+		}, labels),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "template_cache_lookups_total",
+			Help:      "Number of template cache lookups by result: hit, miss or off when the cache is disabled.",
+		}, []string{"result"}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "template_cache_evictions_total",
+			Help:      "Number of parsed templates removed from the template cache.",
+		}),
+	}
+}
+
+// ObserveRender observes the render of a component
+func (c *Collector) ObserveRender(info htmx.RenderInfo, stats htmx.RenderStats) {
+	template := ""
+	if len(info.Templates) > 0 {
+		template = info.Templates[0]
+	}
+
+	c.duration.WithLabelValues(info.Component, template).Observe(stats.Duration.Seconds())
+	c.partials.WithLabelValues(info.Component, template).Observe(float64(info.Partials))
+
+	if stats.Err != nil {
+		c.errors.WithLabelValues(info.Component, template).Inc()
+	}
+
+	// renders that fail before their templates are parsed have no cache status
+	if stats.CacheStatus != "" {
+		c.cache.WithLabelValues(stats.CacheStatus).Inc()
+	}
+}
+
+// ObserveCacheEvictions observes the removal of templates from the template cache
+func (c *Collector) ObserveCacheEvictions(n int) {
+	c.evictions.Add(float64(n))
+}
+
+// Describe sends the descriptions of the metrics
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.partials.Describe(ch)
+	c.errors.Describe(ch)
+	c.cache.Describe(ch)
+	c.evictions.Describe(ch)
+}
+
+// Collect sends the metrics
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.partials.Collect(ch)
+	c.errors.Collect(ch)
+	c.cache.Collect(ch)
+	c.evictions.Collect(ch)
+}
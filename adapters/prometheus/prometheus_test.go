@@ -0,0 +1,69 @@
+package promadapter
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	collector := New()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	htmx.RenderMetrics = collector
+	defer func() { htmx.RenderMetrics = nil }()
+
+	fsys := fstest.MapFS{
+		"prom-page.html":  {Data: []byte(`<main>{{ .Partials.Nav }}</main>`)},
+		"prom-nav.html":   {Data: []byte(`<nav></nav>`)},
+		"prom-error.html": {Data: []byte(`{{ .Data.Missing.Name }}`)},
+	}
+
+	htmx.ClearTemplateCache()
+	for range 2 {
+		page := htmx.NewComponent("prom-page.html").FS(fsys).With(htmx.NewComponent("prom-nav.html").FS(fsys), "Nav")
+		if _, err := page.Render(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := htmx.NewComponent("prom-error.html").FS(fsys).AddData("Missing", 1).Render(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	htmx.ClearTemplateCache()
+
+	expected := `
+# HELP htmx_render_errors_total Number of failed component renders.
+# TYPE htmx_render_errors_total counter
+htmx_render_errors_total{component="prom-error.html",template="prom-error.html"} 1
+# HELP htmx_template_cache_evictions_total Number of parsed templates removed from the template cache.
+# TYPE htmx_template_cache_evictions_total counter
+htmx_template_cache_evictions_total 3
+# HELP htmx_template_cache_lookups_total Number of template cache lookups by result: hit, miss or off when the cache is disabled.
+# TYPE htmx_template_cache_lookups_total counter
+htmx_template_cache_lookups_total{result="hit"} 2
+htmx_template_cache_lookups_total{result="miss"} 3
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"htmx_render_errors_total", "htmx_template_cache_evictions_total", "htmx_template_cache_lookups_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	equal(t, 3, testutil.CollectAndCount(collector, "htmx_render_duration_seconds"))
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/jkc-2/go-htmx/adapters/templ
+
+go 1.23.0
+
+require (
+	github.com/a-h/templ v0.3.960
+	github.com/jkc-2/go-htmx v0.0.0
+)
+
+require (
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+)
+
+replace github.com/jkc-2/go-htmx => ../..
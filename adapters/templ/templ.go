@@ -0,0 +1,43 @@
+// Package templadapter bridges templ components and the components of this package, so codebases that migrate
+// between the two can mix them: templ components are rendered as partials, pages and layouts of components, and
+// components are rendered from templ templates.
+//
+//	page := htmx.NewComponent("templates/page.html").With(templadapter.Component(views.Sidebar(user)), "sidebar")
+//
+//	templ Page(card htmx.RenderableComponent) {
+//		<main>@templadapter.Templ(card)</main>
+//	}
+package templadapter
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+	"github.com/jkc-2/go-htmx"
+)
+
+// Component returns a component that renders the templ component. It can be used as a partial, wrapped in layouts
+// and rendered with the htmx handler like any component, the data of the component isn't available to templ.
+func Component(t templ.Component) *htmx.Component {
+	return htmx.NewWriterComponent(t.Render)
+}
+
+// Templ returns a templ component that renders the component with the context of the templ render
+func Templ(c htmx.RenderableComponent) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if to, ok := c.(interface {
+			RenderTo(ctx context.Context, w io.Writer) error
+		}); ok {
+			return to.RenderTo(ctx, w)
+		}
+
+		output, err := c.Render(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, string(output))
+		return err
+	})
+}
@@ -0,0 +1,74 @@
+package templadapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/a-h/templ"
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestComponent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`<main>{{ .Partials.sidebar }}</main>`)},
+	}
+
+	sidebar := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<nav>templ</nav>")
+		return err
+	})
+
+	out, err := htmx.NewComponent("page.html").FS(fsys).With(Component(sidebar), "sidebar").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<main><nav>templ</nav></main>", string(out))
+
+	failing := templ.ComponentFunc(func(context.Context, io.Writer) error {
+		return errors.New("templ failed")
+	})
+	_, err = Component(failing).Render(context.Background())
+	equal(t, "templ failed", err.Error())
+}
+
+func TestComponentDevMode(t *testing.T) {
+	htmx.SetMode(htmx.Dev)
+	defer htmx.SetMode(htmx.Prod)
+
+	sidebar := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<nav>templ</nav>")
+		return err
+	})
+
+	out, err := Component(sidebar).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<!-- htmx:begin component="(writer)" templates="" --><nav>templ</nav><!-- htmx:end component="(writer)" -->`, string(out))
+}
+
+func TestTempl(t *testing.T) {
+	fsys := fstest.MapFS{
+		"card.html": {Data: []byte(`<div>{{ .Data.Title }}</div>`)},
+	}
+
+	card := htmx.NewComponent("card.html").FS(fsys).AddData("Title", "Ada")
+
+	var b strings.Builder
+	if err := Templ(card).Render(context.Background(), &b); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<div>Ada</div>", b.String())
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
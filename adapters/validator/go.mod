@@ -0,0 +1,22 @@
+module github.com/jkc-2/go-htmx/adapters/validator
+
+go 1.23.0
+
+require (
+	github.com/go-playground/validator/v10 v10.27.0
+	github.com/jkc-2/go-htmx v0.0.0
+)
+
+require (
+	github.com/gabriel-vasile/mimetype v1.4.8 // indirect
+	github.com/go-playground/locales v0.14.1 // indirect
+	github.com/go-playground/universal-translator v0.18.1 // indirect
+	github.com/leodido/go-urn v1.4.0 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+replace github.com/jkc-2/go-htmx => ../..
@@ -0,0 +1,131 @@
+// Package validatoradapter validates bound structs with go-playground/validator and returns the failed fields as
+// htmx.FieldErrors, named like the form fields htmx.Bind reads them from.
+//
+//	htmx.DefaultValidator = validatoradapter.New(validator.New(validator.WithRequiredStructEnabled()))
+//
+//	type Signup struct {
+//		Email string `form:"email" validate:"required,email"`
+//	}
+package validatoradapter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jkc-2/go-htmx"
+)
+
+// Validator validates structs with a go-playground validator
+type Validator struct {
+	validate *validator.Validate
+	message  func(fe validator.FieldError) string
+}
+
+// New returns a validator for the go-playground validator
+func New(v *validator.Validate) *Validator {
+	return &Validator{validate: v, message: Message}
+}
+
+// Message sets the function that returns the message of a failed field, it defaults to Message
+func (v *Validator) Message(fn func(fe validator.FieldError) string) *Validator {
+	v.message = fn
+	return v
+}
+
+// Validate validates the struct, the failed fields are returned as htmx.FieldErrors
+func (v *Validator) Validate(s any) error {
+	err := v.validate.Struct(s)
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	t := reflect.TypeOf(s)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	errs := make(htmx.FieldErrors, len(verrs))
+	for i, fe := range verrs {
+		errs[i] = htmx.FieldError{Field: fieldName(t, fe.StructNamespace()), Message: v.message(fe)}
+	}
+
+	return errs
+}
+
+// Message returns an english message for the common validation tags
+func Message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "min", "gte":
+		if isLength(fe.Kind()) {
+			return "must be at least " + fe.Param() + " characters"
+		}
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		if isLength(fe.Kind()) {
+			return "must be at most " + fe.Param() + " characters"
+		}
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "len":
+		return "must be " + fe.Param() + " characters long"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "eqfield":
+		return "must match " + fe.Param()
+	default:
+		return "is invalid"
+	}
+}
+
+// isLength returns true for kinds whose min and max apply to their length
+func isLength(k reflect.Kind) bool {
+	return k == reflect.String || k == reflect.Slice || k == reflect.Map || k == reflect.Array
+}
+
+// fieldName returns the name of the form field for the namespace of the struct fields, e.g. Signup.Address.City
+// becomes address.city. Embedded structs add no name and slices no index, like htmx.Bind binds them.
+func fieldName(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+
+	var names []string
+	for _, part := range parts {
+		// the values of a slice are bound from fields of the same name
+		goName, _, _ := strings.Cut(part, "[")
+
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+
+		sf, ok := t.FieldByName(goName)
+		if !ok {
+			names = append(names, goName)
+			continue
+		}
+		t = sf.Type
+
+		tag := sf.Tag.Get("form")
+		switch {
+		case sf.Anonymous && tag == "":
+			continue
+		case tag != "" && tag != "-":
+			names = append(names, tag)
+		default:
+			names = append(names, goName)
+		}
+	}
+
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,76 @@
+package validatoradapter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jkc-2/go-htmx"
+)
+
+type (
+	address struct {
+		City string `form:"city" validate:"required"`
+	}
+
+	audit struct {
+		Note string `form:"note" validate:"max=3"`
+	}
+
+	signup struct {
+		audit
+		Email   string   `form:"email" validate:"required,email"`
+		Age     int      `form:"age" validate:"gte=18"`
+		Tags    []string `form:"tag" validate:"dive,oneof=a b"`
+		Address address  `form:"address"`
+	}
+)
+
+func TestValidate(t *testing.T) {
+	v := New(validator.New(validator.WithRequiredStructEnabled()))
+
+	err := v.Validate(&signup{audit: audit{Note: "long"}, Email: "ann", Age: 12, Tags: []string{"c"}})
+
+	var errs htmx.FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected field errors, got %v", err)
+	}
+
+	m := errs.Map()
+	equal(t, "must be at most 3 characters", m["note"])
+	equal(t, "must be a valid email address", m["email"])
+	equal(t, "must be at least 18", m["age"])
+	equal(t, "must be one of a, b", m["tag"])
+	equal(t, "is required", m["address.city"])
+
+	equal(t, nil, v.Validate(&signup{Email: "ann@example.com", Age: 18, Address: address{City: "Berlin"}}))
+}
+
+func TestBindAndValidate(t *testing.T) {
+	htmx.DefaultValidator = New(validator.New()).Message(func(fe validator.FieldError) string { return fe.Tag() })
+	defer func() { htmx.DefaultValidator = nil }()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("email=&age=many&address.city=Berlin"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var s signup
+	err := htmx.BindAndValidate(r, &s)
+
+	c := htmx.NewComponent("signup.html")
+	equal(t, true, htmx.ValidationErrors(c, r, err))
+
+	var errs htmx.FieldErrors
+	errors.As(err, &errs)
+	equal(t, "age: must be a whole number, email: required, age: gte", errs.Error())
+	equal(t, false, htmx.ValidationErrors(c, r, errors.New("malformed")))
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,136 @@
+package htmx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"regexp"
+	"sync"
+)
+
+var (
+	// events are the event names declared with DefineEvent
+	events   = make(map[string]bool)
+	eventsMu sync.RWMutex
+
+	// alpineAttrName matches the attribute names that xBind binds
+	alpineAttrName = regexp.MustCompile(`^[a-zA-Z_][-a-zA-Z0-9_.:]*$`)
+)
+
+// DefineEvent declares the name of an event that handlers trigger with HX-Trigger and templates dispatch with Alpine,
+// so both use the same name. Once events are declared, the dispatch template function fails for the names that were
+// not, which catches typos in templates.
+//
+//	var CartUpdated = htmx.DefineEvent("cart-updated")
+//
+//	h.Trigger(CartUpdated) // HX-Trigger: cart-updated
+//	<button @click="{{ dispatch "cart-updated" .Data.ID }}">Add</button>
+func DefineEvent(name string) string {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	events[name] = true
+	return name
+}
+
+// ResetEvents removes the declared events
+func ResetEvents() {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	events = make(map[string]bool)
+}
+
+// eventDefined returns true when the event was declared or no events were declared
+func eventDefined(name string) bool {
+	eventsMu.RLock()
+	defer eventsMu.RUnlock()
+
+	return len(events) == 0 || events[name]
+}
+
+// xData returns the x-data attribute of Alpine with the map or struct, or the names and values, as a JSON object. The
+// JSON is escaped for the attribute.
+//
+//	<div {{ xData .Data.Filters }}>
+//	<div {{ xData "open" false "count" .Data.Count }}>
+func xData(args ...any) (template.HTMLAttr, error) {
+	args, prev := splitPrevAttr(args)
+
+	var v any
+	switch {
+	case len(args) == 1:
+		v = args[0]
+	case len(args)%2 == 0:
+		m, err := pairs("xData", args)
+		if err != nil {
+			return "", err
+		}
+		v = m
+	default:
+		return "", fmt.Errorf("xData: value %v has no name", args[len(args)-1])
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("xData: %w", err)
+	}
+
+	if !bytes.HasPrefix(value, []byte("{")) {
+		return "", fmt.Errorf("xData: %T is not a JSON object", v)
+	}
+
+	return prev + NewAttributes().Set("x-data", string(value)).HTMLAttr(), nil
+}
+
+// xBind returns the x-bind attribute of Alpine that binds the attribute to the value, encoded as a JSON expression
+//
+//	<select {{ xBind "data-options" .Data.Options }}>
+func xBind(name string, args ...any) (template.HTMLAttr, error) {
+	args, prev := splitPrevAttr(args)
+	if len(args) != 1 {
+		return "", fmt.Errorf("xBind: %s requires a value", name)
+	}
+
+	if !alpineAttrName.MatchString(name) {
+		return "", fmt.Errorf("xBind: invalid attribute name %q", name)
+	}
+
+	value, err := json.Marshal(args[0])
+	if err != nil {
+		return "", fmt.Errorf("xBind: %w", err)
+	}
+
+	return prev + NewAttributes().Set("x-bind:"+name, string(value)).HTMLAttr(), nil
+}
+
+// dispatch returns the Alpine expression that dispatches the event with the detail, for event handler attributes.
+// The event name and the detail are encoded as JSON.
+//
+//	<button @click="{{ dispatch "cart-updated" .Data.ID }}">Add</button>
+func dispatch(event string, detail ...any) (string, error) {
+	if !eventDefined(event) {
+		return "", fmt.Errorf("dispatch: event %q is not defined", event)
+	}
+
+	if len(detail) > 1 {
+		return "", fmt.Errorf("dispatch: event %q has more than one detail", event)
+	}
+
+	name, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	if len(detail) == 0 {
+		return "$dispatch(" + string(name) + ")", nil
+	}
+
+	value, err := json.Marshal(detail[0])
+	if err != nil {
+		return "", fmt.Errorf("dispatch: %w", err)
+	}
+
+	return "$dispatch(" + string(name) + ", " + string(value) + ")", nil
+}
@@ -0,0 +1,44 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAlpine(t *testing.T) {
+	ResetEvents()
+	defer ResetEvents()
+
+	fsys := fstest.MapFS{
+		"alpine.html": {Data: []byte(`<div {{ xData "open" false "name" .Data.Name }}>` +
+			`<select {{ xBind "data-options" .Data.Options }}></select>` +
+			`<button @click="{{ dispatch "cart-updated" .Data.ID }}"></button></div>`)},
+	}
+
+	cartUpdated := DefineEvent("cart-updated")
+	equal(t, "cart-updated", cartUpdated)
+
+	output, err := NewComponent("alpine.html").FS(fsys).
+		AddData("Name", `"Ada"</div>`).
+		AddData("Options", []string{"a", "b"}).
+		AddData("ID", 7).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div x-data="{&#34;name&#34;:&#34;\&#34;Ada\&#34;\u003c/div\u003e&#34;,&#34;open&#34;:false}">`+
+		`<select x-bind:data-options="[&#34;a&#34;,&#34;b&#34;]"></select>`+
+		`<button @click="$dispatch(&#34;cart-updated&#34;, 7)"></button></div>`, string(output))
+
+	if _, err := dispatch("cart-update"); err == nil {
+		t.Error("expected an error for an event that wasn't defined")
+	}
+	if _, err := xBind(`x" onclick="alert(1)`, 1); err == nil {
+		t.Error("expected an error for an invalid attribute name")
+	}
+	if _, err := xData("open"); err == nil {
+		t.Error("expected an error for x-data that isn't an object")
+	}
+}
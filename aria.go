@@ -0,0 +1,133 @@
+package htmx
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"strconv"
+	"sync"
+)
+
+//go:embed templates/aria.html
+var ariaTemplates embed.FS
+
+var (
+	shared = &sharedTemplates{}
+
+	// SharedTemplates is a TemplateEngine whose templates are parsed into every component,
+	// the templates of a component take precedence over shared templates with the same name.
+	SharedTemplates TemplateEngine = shared
+)
+
+type (
+	// TemplateEngine is a set of templates that additional templates can be parsed into, *template.Template implements it
+	TemplateEngine interface {
+		ParseFS(fsys fs.FS, patterns ...string) (*template.Template, error)
+	}
+
+	// AriaMenuItem is an item of the aria.menu template
+	AriaMenuItem struct {
+		Label  string
+		URL    string
+		Target string // optional hx-target, the item is loaded with hx-get when set
+	}
+
+	// AriaTab is a tab of the aria.tabs template
+	AriaTab struct {
+		ID    string
+		Label string
+		URL   string // url of the tab panel content
+	}
+
+	// AriaOption is an option of the aria.options template, the response to the request of an aria.combobox
+	AriaOption struct {
+		Label string
+		Value string
+	}
+
+	sharedTemplates struct {
+		mu      sync.RWMutex
+		entries []sharedTemplate
+		version int
+	}
+
+	sharedTemplate struct {
+		fsys     fs.FS
+		patterns []string
+	}
+)
+
+// RegisterAriaTemplates parses the accessible widget templates into the engine. The templates render the markup with the
+// correct roles and aria attributes, and are wired with htmx to load their content:
+//
+//	aria.disclosure   props: id, label, url or content
+//	aria.menu         props: id, label, items ([]AriaMenuItem)
+//	aria.dialog       props: id, title, content, close
+//	aria.dialogButton props: id (of the dialog), label, url
+//	aria.tabs         props: id, label, tabs ([]AriaTab), selected (index), content
+//	aria.combobox     props: id, name, label, url
+//	aria.options      props: id (of the combobox), options ([]AriaOption)
+//
+// Register them with SharedTemplates to use them in every component.
+//
+//	htmx.RegisterAriaTemplates(htmx.SharedTemplates)
+//
+//	{{ template "aria.disclosure" (props "id" "faq-1" "label" "How does it work?" "url" "/faq/1") }}
+func RegisterAriaTemplates(engine TemplateEngine) error {
+	_, err := engine.ParseFS(ariaTemplates, "templates/aria.html")
+	return err
+}
+
+// ParseFS adds the templates to every component, the templates are parsed once to validate them
+func (s *sharedTemplates) ParseFS(fsys fs.FS, patterns ...string) (*template.Template, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("htmx: no shared template patterns")
+	}
+
+	functions, contextFuncs := (&Component{}).templateFunctions(nil)
+	for key, value := range contextFuncPlaceholders(contextFuncs) {
+		functions[key] = value
+	}
+
+	tmpl, err := template.New("shared").Funcs(functions).ParseFS(fsys, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, sharedTemplate{fsys: fsys, patterns: patterns})
+	s.version++
+
+	return tmpl, nil
+}
+
+// parseInto parses the shared templates into the template
+func (s *sharedTemplates) parseInto(tmpl *template.Template) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.entries {
+		if _, err := tmpl.ParseFS(e.fsys, e.patterns...); err != nil {
+			return fmt.Errorf("htmx: shared templates: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// cacheKey returns the part of the template cache key for the shared templates
+func (s *sharedTemplates) cacheKey() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return strconv.Itoa(s.version)
+}
+
+// props returns a map of the key value pairs, it is used to pass arguments to the aria templates
+func props(pairs ...any) (map[string]any, error) {
+	return dict(pairs...)
+}
@@ -0,0 +1,53 @@
+package htmx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAriaTemplates(t *testing.T) {
+	defer func(s *sharedTemplates) { shared = s }(shared)
+	shared = &sharedTemplates{}
+
+	if err := RegisterAriaTemplates(shared); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ template "aria.disclosure" (props "id" "faq" "label" "Question" "url" "/faq/1") }}` +
+			`{{ template "aria.tabs" (props "id" "settings" "tabs" .Data.Tabs "selected" 1) }}` +
+			`{{ template "aria.menu" (props "id" "user" "label" "Account" "items" .Data.Items) }}` +
+			`{{ template "aria.dialog" (props "id" "confirm" "title" "Are you sure?") }}` +
+			`{{ template "aria.dialogButton" (props "id" "confirm" "label" "Delete" "url" "/confirm") }}` +
+			`{{ template "aria.combobox" (props "id" "city" "label" "City" "url" "/cities") }}` +
+			`{{ template "aria.options" (props "id" "city" "options" .Data.Options) }}`)},
+	}
+
+	out, err := NewComponent("page.html").FS(fsys).
+		AddData("Tabs", []AriaTab{{ID: "a", Label: "A", URL: "/a"}, {ID: "b", Label: "B", URL: "/b"}}).
+		AddData("Items", []AriaMenuItem{{Label: "Profile", URL: "/profile", Target: "#main"}}).
+		AddData("Options", []AriaOption{{Label: "Ghent", Value: "gent"}}).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`<button type="button" id="faq-button" aria-expanded="false" aria-controls="faq-panel"`,
+		`hx-get="/faq/1" hx-trigger="click once" hx-target="next">Question</button>`,
+		`<div id="faq-panel" role="region" aria-labelledby="faq-button" hidden>`,
+		`id="settings-tab-a" aria-controls="settings-panel" aria-selected="false" tabindex="-1"`,
+		`id="settings-tab-b" aria-controls="settings-panel" aria-selected="true" tabindex="0"`,
+		`<div id="settings-panel" role="tabpanel" tabindex="0" aria-labelledby="settings-tab-b">`,
+		`<a role="menuitem" tabindex="-1" href="/profile" hx-get="/profile" hx-target="#main">Profile</a>`,
+		`<dialog id="confirm" class="aria-dialog" aria-labelledby="confirm-title">`,
+		`role="combobox" aria-autocomplete="list" aria-expanded="false" aria-controls="city-listbox"`,
+		`<li role="option" id="city-option-0" data-value="gent" aria-selected="false">Ghent</li>`,
+	} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("expected %q in %s", expected, out)
+		}
+	}
+}
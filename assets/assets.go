@@ -0,0 +1,265 @@
+// Package assets resolves fingerprinted asset urls from a Vite or esbuild manifest, or by hashing the files of a filesystem.
+//
+//	manifest, err := assets.LoadVite(os.DirFS("dist"), ".vite/manifest.json", "/static/")
+//	maps.Copy(htmx.DefaultTemplateFuncs, manifest.Funcs())
+//
+//	<script type="module" src="{{ asset "src/main.js" }}"></script>
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+type (
+	// Entry is an asset of the manifest
+	Entry struct {
+		File    string   `json:"file"`    // path of the fingerprinted file, relative to the prefix
+		CSS     []string `json:"css"`     // stylesheets imported by the asset
+		Imports []string `json:"imports"` // manifest keys of the chunks imported by the asset
+	}
+
+	// Manifest maps asset names to fingerprinted urls
+	Manifest struct {
+		prefix  string
+		entries map[string]Entry
+		fsys    fs.FS             // the hashed filesystem, only set by Hash
+		files   map[string]string // fingerprinted file to original file, only set by Hash
+	}
+
+	esbuildMetafile struct {
+		Outputs map[string]struct {
+			EntryPoint string `json:"entryPoint"`
+			CSSBundle  string `json:"cssBundle"`
+		} `json:"outputs"`
+	}
+)
+
+// LoadVite reads a Vite manifest.json, prefix is the url path the build directory is served on
+func LoadVite(fsys fs.FS, name, prefix string) (*Manifest, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]Entry)
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("assets: invalid vite manifest: %w", err)
+	}
+
+	return &Manifest{prefix: prefix, entries: entries}, nil
+}
+
+// LoadEsbuild reads an esbuild metafile, outputs are keyed by their entry point. dir is the output directory of the build,
+// it is stripped from the output paths, and prefix is the url path the output directory is served on.
+func LoadEsbuild(fsys fs.FS, name, dir, prefix string) (*Manifest, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta esbuildMetafile
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return nil, fmt.Errorf("assets: invalid esbuild metafile: %w", err)
+	}
+
+	dir = strings.TrimSuffix(dir, "/") + "/"
+	entries := make(map[string]Entry)
+	for output, o := range meta.Outputs {
+		if o.EntryPoint == "" {
+			continue
+		}
+
+		e := Entry{File: strings.TrimPrefix(output, dir)}
+		if o.CSSBundle != "" {
+			e.CSS = []string{strings.TrimPrefix(o.CSSBundle, dir)}
+		}
+		entries[o.EntryPoint] = e
+	}
+
+	return &Manifest{prefix: prefix, entries: entries}, nil
+}
+
+// Hash fingerprints every file of the filesystem with a hash of its content, app.js becomes app.1a2b3c4d.js.
+// Serve the files with the Handler of the manifest.
+func Hash(fsys fs.FS, prefix string) (*Manifest, error) {
+	m := &Manifest{
+		prefix:  prefix,
+		entries: make(map[string]Entry),
+		fsys:    fsys,
+		files:   make(map[string]string),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(b)
+		ext := path.Ext(name)
+		file := strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+
+		m.entries[name] = Entry{File: file}
+		m.files[file] = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// URL returns the fingerprinted url of the asset
+func (m *Manifest) URL(name string) (string, error) {
+	e, ok := m.entry(name)
+	if !ok {
+		return "", fmt.Errorf("assets: %s not found in manifest", name)
+	}
+
+	return m.url(e.File), nil
+}
+
+// Preload returns the link tags preloading the assets, their imported chunks and stylesheets
+func (m *Manifest) Preload(names ...string) (template.HTML, error) {
+	var sb strings.Builder
+	seen := make(map[string]bool)
+
+	var preload func(key string, e Entry)
+	preload = func(key string, e Entry) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		link(&sb, m.url(e.File))
+		for _, css := range e.CSS {
+			if !seen[css] {
+				seen[css] = true
+				link(&sb, m.url(css))
+			}
+		}
+
+		for _, imp := range e.Imports {
+			if ie, ok := m.entries[imp]; ok {
+				preload(imp, ie)
+			}
+		}
+	}
+
+	for _, name := range names {
+		key, ok := m.key(name)
+		if !ok {
+			return "", fmt.Errorf("assets: %s not found in manifest", name)
+		}
+		preload(key, m.entries[key])
+	}
+
+	//nolint:gosec // the urls are escaped by link
+	return template.HTML(sb.String()), nil
+}
+
+// Funcs returns the asset and assetPreload template functions
+//
+//	{{ assetPreload "src/main.js" }}
+//	<script type="module" src="{{ asset "src/main.js" }}"></script>
+func (m *Manifest) Funcs() template.FuncMap {
+	return template.FuncMap{
+		"asset":        m.URL,
+		"assetPreload": m.Preload,
+	}
+}
+
+// Handler serves the files of a manifest created with Hash by their fingerprinted name, with a long-lived cache header.
+// Mount it on the prefix of the manifest.
+//
+//	mux.Handle("/static/", http.StripPrefix("/static/", manifest.Handler()))
+func (m *Manifest) Handler() http.Handler {
+	files := http.FileServerFS(m.fsys)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := m.files[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/" + name
+		files.ServeHTTP(w, r2)
+	})
+}
+
+// Names returns the sorted names of the assets in the manifest
+func (m *Manifest) Names() []string {
+	names := make([]string, 0, len(m.entries))
+	for name := range m.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// entry returns the entry of the asset
+func (m *Manifest) entry(name string) (Entry, bool) {
+	key, ok := m.key(name)
+	if !ok {
+		return Entry{}, false
+	}
+
+	return m.entries[key], true
+}
+
+// key returns the manifest key of the asset, an asset can be referenced by its key or by its base name when that is unique
+func (m *Manifest) key(name string) (string, bool) {
+	if _, ok := m.entries[name]; ok {
+		return name, true
+	}
+
+	var found string
+	for key := range m.entries {
+		if path.Base(key) == name {
+			if found != "" {
+				return "", false
+			}
+			found = key
+		}
+	}
+
+	return found, found != ""
+}
+
+// url returns the url of a file
+func (m *Manifest) url(file string) string {
+	return strings.TrimSuffix(m.prefix, "/") + "/" + strings.TrimPrefix(file, "/")
+}
+
+// link writes the preload link tag of the url, files that can't be preloaded are skipped
+func link(sb *strings.Builder, url string) {
+	escaped := template.HTMLEscapeString(url)
+
+	switch path.Ext(url) {
+	case ".js", ".mjs":
+		sb.WriteString(`<link rel="modulepreload" href="` + escaped + `">`)
+	case ".css":
+		sb.WriteString(`<link rel="preload" as="style" href="` + escaped + `">`)
+	case ".woff", ".woff2":
+		sb.WriteString(`<link rel="preload" as="font" href="` + escaped + `" crossorigin>`)
+	}
+}
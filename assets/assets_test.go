@@ -0,0 +1,90 @@
+package assets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVite(t *testing.T) {
+	fsys := fstest.MapFS{
+		".vite/manifest.json": {Data: []byte(`{
+			"src/main.js": {"file": "assets/main-4f2a.js", "css": ["assets/main-9c1e.css"], "imports": ["_shared.js"]},
+			"_shared.js": {"file": "assets/shared-77ab.js"}
+		}`)},
+	}
+
+	m, err := LoadVite(fsys, ".vite/manifest.json", "/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := m.URL("main.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "/static/assets/main-4f2a.js", url)
+
+	links, err := m.Preload("src/main.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<link rel="modulepreload" href="/static/assets/main-4f2a.js">`+
+		`<link rel="preload" as="style" href="/static/assets/main-9c1e.css">`+
+		`<link rel="modulepreload" href="/static/assets/shared-77ab.js">`, string(links))
+
+	if _, err := m.URL("missing.js"); err == nil {
+		t.Error("expected an error for a missing asset")
+	}
+}
+
+func TestEsbuild(t *testing.T) {
+	fsys := fstest.MapFS{
+		"meta.json": {Data: []byte(`{"outputs": {
+			"dist/app-QX3L.js": {"entryPoint": "src/app.ts", "cssBundle": "dist/app-5TXR.css"},
+			"dist/chunk-A1.js": {}
+		}}`)},
+	}
+
+	m, err := LoadEsbuild(fsys, "meta.json", "dist", "/static")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := m.URL("src/app.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "/static/app-QX3L.js", url)
+}
+
+func TestHash(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/app.css": {Data: []byte(`body{}`)},
+	}
+
+	m, err := Hash(fsys, "/static/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := m.URL("css/app.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "/static/css/app.7c98040a.css", url)
+
+	w := httptest.NewRecorder()
+	http.StripPrefix("/static/", m.Handler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	equal(t, "body{}", w.Body.String())
+	equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+}
+
+func equal(t *testing.T, expected, actual string) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
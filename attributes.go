@@ -0,0 +1,132 @@
+package htmx
+
+import (
+	"html"
+	"html/template"
+	"strings"
+)
+
+const (
+	// AttrDisinherit disables attribute inheritance for the listed attributes on the element and its children
+	// https://htmx.org/attributes/hx-disinherit/
+	AttrDisinherit = "hx-disinherit"
+
+	// AttrInherit enables attribute inheritance for the listed attributes when htmx.config.disableInheritance is set
+	// https://htmx.org/attributes/hx-inherit/
+	AttrInherit = "hx-inherit"
+
+	// InheritAll can be used with Disinherit and Inherit to target all attributes
+	InheritAll = "*"
+)
+
+// InheritableAttributes are the htmx attributes that are inherited by child elements.
+// https://htmx.org/docs/#inheritance
+var InheritableAttributes = []string{
+	"hx-boost",
+	"hx-confirm",
+	"hx-disabled-elt",
+	"hx-encoding",
+	"hx-ext",
+	"hx-headers",
+	"hx-include",
+	"hx-indicator",
+	"hx-params",
+	"hx-push-url",
+	"hx-replace-url",
+	"hx-select",
+	"hx-select-oob",
+	"hx-swap",
+	"hx-sync",
+	"hx-target",
+	"hx-vals",
+}
+
+// Attributes is an ordered set of html attributes, it is used to build hx-* attributes in a type safe manner.
+type Attributes struct {
+	keys   []string
+	values map[string]string
+}
+
+// NewAttributes returns a new, empty, attribute set
+func NewAttributes() *Attributes {
+	return &Attributes{
+		keys:   make([]string, 0),
+		values: make(map[string]string),
+	}
+}
+
+// Set sets the value of an attribute, an existing attribute keeps its position
+func (a *Attributes) Set(name, value string) *Attributes {
+	if _, ok := a.values[name]; !ok {
+		a.keys = append(a.keys, name)
+	}
+
+	a.values[name] = value
+	return a
+}
+
+// Get returns the value of an attribute
+func (a *Attributes) Get(name string) (string, bool) {
+	v, ok := a.values[name]
+	return v, ok
+}
+
+// Del removes an attribute from the set
+func (a *Attributes) Del(name string) *Attributes {
+	if _, ok := a.values[name]; !ok {
+		return a
+	}
+
+	delete(a.values, name)
+	for i, k := range a.keys {
+		if k == name {
+			a.keys = append(a.keys[:i], a.keys[i+1:]...)
+			break
+		}
+	}
+
+	return a
+}
+
+// Disinherit sets hx-disinherit for the given attributes, all attributes are disinherited when none are given
+func (a *Attributes) Disinherit(attrs ...string) *Attributes {
+	return a.Set(AttrDisinherit, inheritValue(attrs))
+}
+
+// Inherit sets hx-inherit for the given attributes, all attributes are inherited when none are given
+func (a *Attributes) Inherit(attrs ...string) *Attributes {
+	return a.Set(AttrInherit, inheritValue(attrs))
+}
+
+// String returns the escaped attributes as they would appear in an html tag
+func (a *Attributes) String() string {
+	var sb strings.Builder
+
+	for i, k := range a.keys {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(k)
+		sb.WriteString(`="`)
+		sb.WriteString(html.EscapeString(a.values[k]))
+		sb.WriteByte('"')
+	}
+
+	return sb.String()
+}
+
+// HTMLAttr returns the attributes as a template.HTMLAttr so they can be used inside an html tag in templates
+func (a *Attributes) HTMLAttr() template.HTMLAttr {
+	//nolint:gosec // the attribute values are escaped by String
+	return template.HTMLAttr(a.String())
+}
+
+// inheritValue returns the value for hx-inherit and hx-disinherit
+func inheritValue(attrs []string) string {
+	if len(attrs) == 0 {
+		return InheritAll
+	}
+
+	return strings.Join(attrs, " ")
+}
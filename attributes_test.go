@@ -0,0 +1,57 @@
+package htmx
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestAttributes(t *testing.T) {
+	attrs := NewAttributes().
+		Set("hx-get", "/users?a=1&b=2").
+		Set("hx-target", "#list").
+		Disinherit("hx-target", "hx-swap")
+
+	equal(t, `hx-get="/users?a=1&amp;b=2" hx-target="#list" hx-disinherit="hx-target hx-swap"`, attrs.String())
+
+	attrs.Del("hx-target").Inherit()
+	equal(t, `hx-get="/users?a=1&amp;b=2" hx-disinherit="hx-target hx-swap" hx-inherit="*"`, attrs.String())
+}
+
+func TestCheckInheritance(t *testing.T) {
+	layout := template.HTML(`<body hx-boost="true"><main id="main" hx-target="#main" hx-swap="outerHTML"><!--slot--></main></body>`)
+	fragment := template.HTML(`<div><button hx-get="/a">a</button><button hx-post="/b" hx-target="this">b</button><p>no request</p></div>`)
+
+	issues, err := CheckInheritance(layout, "<!--slot-->", fragment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, len(issues))
+	for i, issue := range issues {
+		got[i] = issue.String()
+	}
+
+	expected := []string{
+		`button[hx-get="/a"] inherits hx-boost="true" from body`,
+		`button[hx-get="/a"] inherits hx-swap="outerHTML" from main#main`,
+		`button[hx-get="/a"] inherits hx-target="#main" from main#main`,
+		`button[hx-post="/b"] inherits hx-boost="true" from body`,
+		`button[hx-post="/b"] inherits hx-swap="outerHTML" from main#main`,
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d issues, got %d: %v", len(expected), len(got), got)
+	}
+
+	for i := range expected {
+		equal(t, expected[i], got[i])
+	}
+
+	// wrapping the fragment with disinherit stops the inheritance from the layout
+	issues, err = CheckInheritance(layout, "<!--slot-->", disinherit(`<button hx-get="/a">a</button>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalInt(t, 0, len(issues))
+}
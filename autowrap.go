@@ -0,0 +1,51 @@
+package htmx
+
+import (
+	"net/http"
+)
+
+// autoWrapper is implemented by the components that choose between the fragment and the full page per request
+type autoWrapper interface {
+	autoWrapped() bool
+}
+
+// AutoWrap wraps the component in the layout, with the component rendered into the target of the layout. The Handler
+// renders the fragment only for htmx requests, full page loads, boosted navigation and history restores after a
+// cache miss render the layout, as they swap the whole page.
+//
+//	h.Render(ctx, htmx.NewComponent("templates/users.html").AutoWrap(layout, "content"))
+//
+// Wrap renders the fragment for boosted requests as well, it expects the boosted elements to select their target.
+func (c *Component) AutoWrap(layout RenderableComponent, target string) *Component {
+	c.Wrap(layout, target)
+	c.autoWrap = true
+
+	return c
+}
+
+// autoWrapped returns true if the component was wrapped by AutoWrap
+func (c *Component) autoWrapped() bool {
+	return c.autoWrap
+}
+
+// autoWrapped returns true if the primary fragment was wrapped by AutoWrap
+func (m *MultiFragment) autoWrapped() bool {
+	a, ok := m.RenderableComponent.(autoWrapper)
+	return ok && a.autoWrapped()
+}
+
+// WantsFragment returns true if the request was made by htmx to swap a fragment, false for full page loads, boosted
+// navigation and history restores
+func WantsFragment(r *http.Request) bool {
+	req := ParseRequest(r)
+	return req.IsHTMX && !req.Boosted && !req.HistoryRestoreRequest
+}
+
+// renderFragment returns true if the component is rendered without its wrappers
+func (h *Handler) renderFragment(r RenderableComponent) bool {
+	if a, ok := r.(autoWrapper); ok && a.autoWrapped() {
+		return WantsFragment(h.r)
+	}
+
+	return h.renderPartial()
+}
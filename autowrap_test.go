@@ -0,0 +1,45 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAutoWrap(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<body>{{ .Partials.content }}</body>`)},
+		"users.html":  {Data: []byte(`<ul id="users"></ul>`)},
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"full page load", nil, `<body><ul id="users"></ul></body>`},
+		{"htmx request", map[string]string{"HX-Request": "true"}, `<ul id="users"></ul>`},
+		{"boosted", map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, `<body><ul id="users"></ul></body>`},
+		{"history restore", map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, `<body><ul id="users"></ul></body>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			page := NewComponent("users.html").FS(fsys).AutoWrap(NewComponent("layout.html").FS(fsys), "content")
+
+			if _, err := New().NewHandler(w, r).Render(context.Background(), page); err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
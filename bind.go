@@ -0,0 +1,304 @@
+package htmx
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BindMaxMemory is the memory Bind uses for the parts of a multipart form, the rest is stored in temporary files
+var BindMaxMemory int64 = 32 << 20
+
+// BindTimeLayouts are the layouts of time.Time fields without a layout tag, the formats of the date and time inputs
+var BindTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02", "15:04:05", "15:04"}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType     = reflect.TypeOf([]*multipart.FileHeader(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+type (
+	// FieldError is the error of a form field, the message can be shown next to the field
+	FieldError struct {
+		Field   string
+		Message string
+	}
+
+	// FieldErrors are the errors of the form fields
+	FieldErrors []FieldError
+)
+
+// Error returns the errors of the fields
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Field + ": " + fe.Message
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// Map returns the message per field, the first message of a field wins
+func (e FieldErrors) Map() map[string]string {
+	m := make(map[string]string, len(e))
+	for _, fe := range e {
+		if _, ok := m[fe.Field]; !ok {
+			m[fe.Field] = fe.Message
+		}
+	}
+
+	return m
+}
+
+// Bind decodes the form, query and multipart values of the request into the struct v points to. Fields are bound to
+// the value of their form tag, or their name, and fields tagged with form:"-" are skipped. Values are converted to
+// the type of the field, which can be a string, bool, number, time.Time, time.Duration, encoding.TextUnmarshaler, a
+// pointer or slice of these, or a *multipart.FileHeader or []*multipart.FileHeader for uploads. Embedded structs are
+// bound as if their fields were fields of the struct, other struct fields with the prefix of their name and a dot.
+//
+//	type Signup struct {
+//		Email    string    `form:"email"`
+//		Tags     []string  `form:"tag"`
+//		Birthday time.Time `form:"birthday" layout:"02.01.2006"`
+//		Address  Address   `form:"address"` // address.city, address.zip
+//	}
+//
+// Fields without a submitted value keep their value. Values that can't be converted are returned as FieldErrors,
+// after all other fields were bound.
+func Bind(r *http.Request, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("htmx: Bind requires a pointer to a struct, got %T", v)
+	}
+
+	if err := r.ParseMultipartForm(BindMaxMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if r.MultipartForm != nil {
+		files = r.MultipartForm.File
+	}
+
+	var errs FieldErrors
+	bindStruct(rv.Elem(), "", r.Form, files, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// bindStruct binds the values to the fields of the struct, the names of the fields are prefixed with the prefix
+func bindStruct(v reflect.Value, prefix string, values map[string][]string, files map[string][]*multipart.FileHeader, errs *FieldErrors) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("form")
+		if tag == "-" || !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		if sf.Anonymous && tag == "" {
+			if embedded, ok := embeddedStruct(fv); ok {
+				bindStruct(embedded, prefix, values, files, errs)
+			}
+			continue
+		}
+
+		name := prefix + sf.Name
+		if tag != "" {
+			name = prefix + tag
+		}
+
+		switch {
+		case sf.Type == fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs[0]))
+			}
+		case sf.Type == fileHeadersType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fv.Set(reflect.ValueOf(fhs))
+			}
+		case isNestedStruct(sf.Type):
+			bindStruct(fv, name+".", values, files, errs)
+		default:
+			submitted, ok := values[name]
+			if !ok {
+				continue
+			}
+
+			if err := bindField(fv, submitted, sf.Tag.Get("layout")); err != nil {
+				*errs = append(*errs, FieldError{Field: name, Message: err.Error()})
+			}
+		}
+	}
+}
+
+// embeddedStruct returns the struct of an embedded field, a nil pointer to an exported struct is allocated
+func embeddedStruct(fv reflect.Value) (reflect.Value, bool) {
+	if fv.Kind() == reflect.Pointer {
+		if fv.Type().Elem().Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		if fv.IsNil() {
+			if !fv.CanSet() {
+				return reflect.Value{}, false
+			}
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+
+	return fv, fv.Kind() == reflect.Struct
+}
+
+// isNestedStruct returns true for struct fields whose fields are bound, rather than the struct itself
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// bindField sets the field to the submitted values, a slice gets all values and other fields the first
+func bindField(fv reflect.Value, submitted []string, layout string) error {
+	if fv.Kind() == reflect.Slice && !fv.Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(fv.Type(), len(submitted), len(submitted))
+		for i, s := range submitted {
+			if err := bindValue(slice.Index(i), s, layout); err != nil {
+				return err
+			}
+		}
+
+		fv.Set(slice)
+		return nil
+	}
+
+	return bindValue(fv, submitted[0], layout)
+}
+
+// bindValue converts the value to the type of the field and sets it
+func bindValue(fv reflect.Value, s, layout string) error {
+	if fv.Kind() == reflect.Pointer {
+		if s == "" {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+
+		ptr := reflect.New(fv.Type().Elem())
+		if err := bindValue(ptr.Elem(), s, layout); err != nil {
+			return err
+		}
+
+		fv.Set(ptr)
+		return nil
+	}
+
+	switch {
+	case fv.Type() == timeType:
+		return bindTime(fv, s, layout)
+	case fv.Type() == durationType:
+		if s == "" {
+			fv.SetInt(0)
+			return nil
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("must be a duration")
+		}
+
+		fv.SetInt(int64(d))
+		return nil
+	case fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType):
+		//nolint:forcetypeassert // checked by Implements
+		if err := fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return errors.New("is invalid")
+		}
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		switch strings.ToLower(s) {
+		case "", "0", "false", "off", "no":
+			fv.SetBool(false)
+		case "1", "true", "on", "yes":
+			fv.SetBool(true)
+		default:
+			return errors.New("must be true or false")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s == "" {
+			fv.SetInt(0)
+			return nil
+		}
+
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s == "" {
+			fv.SetUint(0)
+			return nil
+		}
+
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("must be a positive whole number")
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if s == "" {
+			fv.SetFloat(0)
+			return nil
+		}
+
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("can't be bound to %s", fv.Type())
+	}
+
+	return nil
+}
+
+// bindTime parses the time with the layout, or the first of BindTimeLayouts that matches
+func bindTime(fv reflect.Value, s, layout string) error {
+	if s == "" {
+		fv.Set(reflect.Zero(timeType))
+		return nil
+	}
+
+	layouts := BindTimeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+
+	for _, l := range layouts {
+		if t, err := time.Parse(l, s); err == nil {
+			fv.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+
+	return errors.New("must be a date")
+}
@@ -0,0 +1,122 @@
+package htmx
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBind(t *testing.T) {
+	type Address struct {
+		City string `form:"city"`
+		Zip  int    `form:"zip"`
+	}
+
+	type Audit struct {
+		Note string `form:"note"`
+	}
+
+	type signup struct {
+		Audit
+		Name     string
+		Email    string        `form:"email"`
+		Age      int           `form:"age"`
+		Score    *float64      `form:"score"`
+		Terms    bool          `form:"terms"`
+		Tags     []string      `form:"tag"`
+		IDs      []uint        `form:"id"`
+		Birthday time.Time     `form:"birthday" layout:"02.01.2006"`
+		Meeting  time.Time     `form:"meeting"`
+		Timeout  time.Duration `form:"timeout"`
+		Address  Address       `form:"address"`
+		Ignored  string        `form:"-"`
+		Missing  string        `form:"missing"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?tag=query", strings.NewReader(
+		"Name=Ann&email=ann@example.com&age=42&score=1.5&terms=on&tag=a&tag=b&id=1&id=2&birthday=24.12.1990"+
+			"&meeting=2024-05-01T14:30&timeout=1m30s&address.city=Berlin&address.zip=10115&note=hi&Ignored=x"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s := signup{Missing: "default"}
+	if err := Bind(r, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "Ann", s.Name)
+	equal(t, "ann@example.com", s.Email)
+	equalInt(t, 42, s.Age)
+	equalBool(t, true, s.Score != nil && *s.Score == 1.5)
+	equalBool(t, true, s.Terms)
+	equal(t, "a,b,query", strings.Join(s.Tags, ","))
+	equalInt(t, 2, len(s.IDs))
+	equal(t, "1990-12-24", s.Birthday.Format(time.DateOnly))
+	equal(t, "2024-05-01 14:30", s.Meeting.Format("2006-01-02 15:04"))
+	equal(t, "1m30s", s.Timeout.String())
+	equal(t, "Berlin", s.Address.City)
+	equalInt(t, 10115, s.Address.Zip)
+	equal(t, "hi", s.Note)
+	equal(t, "", s.Ignored)
+	equal(t, "default", s.Missing)
+}
+
+func TestBindFieldErrors(t *testing.T) {
+	var form struct {
+		Name string    `form:"name"`
+		Age  int       `form:"age"`
+		Date time.Time `form:"date"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?name=Ann&age=old&date=tomorrow", nil)
+
+	err := Bind(r, &form)
+
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected field errors, got %v", err)
+	}
+
+	equal(t, "Ann", form.Name)
+	equal(t, "age: must be a whole number, date: must be a date", err.Error())
+	equal(t, "must be a date", errs.Map()["date"])
+}
+
+func TestBindMultipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("title", "Report")
+	fw, err := mw.CreateFormFile("file", "report.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write([]byte("content"))
+	_ = mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var upload struct {
+		Title string                `form:"title"`
+		File  *multipart.FileHeader `form:"file"`
+	}
+	if err := Bind(r, &upload); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "Report", upload.Title)
+	equal(t, "report.txt", upload.File.Filename)
+}
+
+func TestBindRequiresStructPointer(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var s struct{}
+	if err := Bind(r, s); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,76 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/boosted.html
+var boostedTemplates embed.FS
+
+// boostedWrapper is implemented by the components with a layout for boosted requests
+type boostedWrapper interface {
+	boostedLayout() (RenderableComponent, string)
+}
+
+// BoostedLayout sets the layout of boosted requests, with the component rendered into the target of the layout.
+// Boosted navigation swaps the body, so it doesn't need the whole layout, but it does need the title and the parts of
+// the head that change per page. The boosted layout is used instead of the layouts of Wrap and AutoWrap when
+// HX-Boosted is set, other requests are not affected.
+func (c *Component) BoostedLayout(layout RenderableComponent, target string) *Component {
+	c.boostedRenderer = layout
+	c.boostedTarget = target
+
+	return c
+}
+
+// boostedLayout returns the layout of boosted requests and its target
+func (c *Component) boostedLayout() (RenderableComponent, string) {
+	return c.boostedRenderer, c.boostedTarget
+}
+
+// boostedLayout returns the layout of boosted requests of the primary fragment and its target
+func (m *MultiFragment) boostedLayout() (RenderableComponent, string) {
+	if b, ok := m.RenderableComponent.(boostedWrapper); ok {
+		return b.boostedLayout()
+	}
+
+	return nil, ""
+}
+
+// NewBoostedLayout returns a lightweight layout for boosted requests that renders the title, the component in the
+// "content" target and the head elements. The Title and Head data of the component are used, Head holds elements
+// like meta tags with an id and hx-swap-oob, which replace the elements with the same id in the head.
+//
+//	page.BoostedLayout(htmx.NewBoostedLayout(), "content").
+//		AddData("Title", "Users").
+//		AddData("Head", template.HTML(`<meta id="description" name="description" content="All users" hx-swap-oob="true">`))
+func NewBoostedLayout() *Component {
+	return NewComponent("templates/boosted.html").FS(boostedTemplates)
+}
+
+// boostedLayout returns the boosted layout of the component when the request is boosted
+func (h *Handler) boostedLayout(r RenderableComponent) (RenderableComponent, string, bool) {
+	if !h.request.HxBoosted || h.request.HxHistoryRestoreRequest {
+		return nil, "", false
+	}
+
+	b, ok := r.(boostedWrapper)
+	if !ok {
+		return nil, "", false
+	}
+
+	layout, target := b.boostedLayout()
+	return layout, target, layout != nil
+}
+
+// wrapBoosted renders the output in the boosted layout
+func (h *Handler) wrapBoosted(ctx context.Context, r, layout RenderableComponent, target string, output template.HTML) (template.HTML, error) {
+	layout.SetRequest(h.r)
+	layout.injectData(r.data())
+	layout.injectGlobalData(h.globalData())
+	layout.addPartial(target, output)
+
+	return layout.Render(ctx)
+}
@@ -0,0 +1,50 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBoostedLayout(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<html><head><title>{{ .Data.Title }}</title></head><body>{{ .Partials.content }}</body></html>`)},
+		"users.html":  {Data: []byte(`<ul id="users"></ul>`)},
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"full page load", nil, `<html><head><title>Users</title></head><body><ul id="users"></ul></body></html>`},
+		{"htmx request", map[string]string{"HX-Request": "true"}, `<ul id="users"></ul>`},
+		{"boosted", map[string]string{"HX-Request": "true", "HX-Boosted": "true"},
+			`<title>Users</title><ul id="users"></ul><meta id="description" content="All users" hx-swap-oob="true">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			page := NewComponent("users.html").FS(fsys).
+				AutoWrap(NewComponent("layout.html").FS(fsys), "content").
+				BoostedLayout(NewBoostedLayout(), "content")
+			page.AddData("Title", "Users")
+			page.AddData("Head", template.HTML(`<meta id="description" content="All users" hx-swap-oob="true">`))
+
+			w := httptest.NewRecorder()
+			if _, err := New().NewHandler(w, r).Render(context.Background(), page); err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+)
+
+var (
+	// DefaultErrorFallback renders the fragment that replaces a failed partial of an error boundary without its own fallback
+	DefaultErrorFallback ErrorFallback = func(_ context.Context, target string, _ error) template.HTML {
+		return template.HTML(`<div class="htmx-error" role="alert" data-partial="` + template.HTMLEscapeString(target) + `"></div>`)
+	}
+
+	// OnPartialError is called with the error of every partial that was replaced by an error boundary
+	OnPartialError func(ctx context.Context, target string, err error)
+)
+
+// ErrorFallback returns the fragment that replaces the partial with the given target after it failed to render
+type ErrorFallback func(ctx context.Context, target string, err error) template.HTML
+
+// ErrorBoundary makes the component an error boundary for its partials: a partial that fails to render is replaced by the
+// fragment of the fallback and the error is reported to OnPartialError, while the other partials still render.
+// DefaultErrorFallback is used when the fallback is nil. Renders of aborted requests still fail.
+func (c *Component) ErrorBoundary(fallback ErrorFallback) *Component {
+	if fallback == nil {
+		fallback = func(ctx context.Context, target string, err error) template.HTML {
+			return DefaultErrorFallback(ctx, target, err)
+		}
+	}
+
+	c.errorFallback = fallback
+	return c
+}
+
+// recoverPartial returns the fallback of the error boundary for the failed partial, or the error when the component
+// isn't an error boundary or the render was canceled
+func (c *Component) recoverPartial(ctx context.Context, target string, err error) (template.HTML, error) {
+	if c.errorFallback == nil || renderCanceled(ctx) != nil {
+		return "", err
+	}
+
+	if OnPartialError != nil {
+		OnPartialError(ctx, target, err)
+	}
+
+	return c.errorFallback(ctx, target, err), nil
+}
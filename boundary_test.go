@@ -0,0 +1,54 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+)
+
+func TestErrorBoundary(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html":  {Data: []byte(`{{ .Partials.stats }}|{{ .Partials.feed }}`)},
+		"stats.html": {Data: []byte(`{{ fail }}`)},
+		"feed.html":  {Data: []byte(`feed`)},
+	}
+
+	newPage := func() *Component {
+		stats := NewComponent("stats.html").FS(fsys).AddTemplateFunction("fail", func() (string, error) {
+			return "", context.DeadlineExceeded
+		})
+
+		page := NewComponent("page.html").FS(fsys)
+		page.With(stats, "stats")
+		page.With(NewComponent("feed.html").FS(fsys), "feed")
+
+		return page
+	}
+
+	// without a boundary the whole render fails
+	if _, err := newPage().Render(context.Background()); err == nil {
+		t.Fatal("expected the render to fail")
+	}
+
+	var reported string
+	OnPartialError = func(_ context.Context, target string, _ error) {
+		reported = target
+	}
+	defer func() { OnPartialError = nil }()
+
+	out, err := newPage().ErrorBoundary(nil).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<div class="htmx-error" role="alert" data-partial="stats"></div>|feed`, string(out))
+	equal(t, "stats", reported)
+
+	out, err = newPage().ErrorBoundary(func(_ context.Context, target string, _ error) template.HTML {
+		return template.HTML("<p>" + target + " unavailable</p>")
+	}).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<p>stats unavailable</p>|feed`, string(out))
+}
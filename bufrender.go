@@ -0,0 +1,101 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"io"
+	"unsafe"
+)
+
+type (
+	// borrowedPartials are the partials of a render whose output points into pooled buffers. The output is only valid
+	// until the buffers are released, so it is removed from the partials of the component when they are.
+	borrowedPartials []borrowedPartial
+
+	// borrowedPartial is the output of a partial in a pooled buffer
+	borrowedPartial struct {
+		key string
+		buf *bytes.Buffer
+	}
+)
+
+// RenderTo renders the component and writes the output to w straight from a pooled buffer, without the copy into a
+// string that Render makes. The partials of the component are rendered the same way and are not copied either.
+func (c *Component) RenderTo(ctx context.Context, w io.Writer) error {
+	buf, err := c.renderBuffer(ctx)
+	if err != nil {
+		return err
+	}
+	defer putBuffer(buf)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// renderBuffer renders the component into a pooled buffer, the caller owns the buffer and returns it with putBuffer.
+// Journaled and shared renders go through Render and are copied into the buffer.
+func (c *Component) renderBuffer(ctx context.Context) (*bytes.Buffer, error) {
+	if journal.Load() != nil || c.flightKey != "" {
+		output, err := c.Render(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		buf := getBuffer()
+		buf.WriteString(string(output))
+		return buf, nil
+	}
+
+	if RenderTracer != nil || RenderMetrics != nil {
+		return observeRender(ctx, c, c.renderComponentBuffer)
+	}
+
+	return c.renderComponentBuffer(ctx)
+}
+
+// renderPartial renders the partial, components are rendered into a pooled buffer that is returned along with the
+// output pointing into it. The output is only valid until the buffer is returned with putBuffer.
+func renderPartial(ctx context.Context, partial RenderableComponent) (template.HTML, *bytes.Buffer, error) {
+	c, ok := partial.(*Component)
+	if !ok {
+		output, err := partial.Render(ctx)
+		return output, nil, err
+	}
+
+	buf, err := c.renderBuffer(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return bufferHTML(buf), buf, nil
+}
+
+// add borrows the buffer of the output of the partial
+func (b *borrowedPartials) add(key string, buf *bytes.Buffer) {
+	*b = append(*b, borrowedPartial{key: key, buf: buf})
+}
+
+// release removes the borrowed output from the partials of the component and returns the buffers to the pool
+func (b *borrowedPartials) release(c *Component) {
+	for _, p := range *b {
+		delete(c.partial, p.key)
+		putBuffer(p.buf)
+	}
+}
+
+// bufferHTML returns the contents of the buffer as html without copying them, the html must not be used after the
+// buffer was written to or returned to the pool
+func bufferHTML(buf *bytes.Buffer) template.HTML {
+	b := buf.Bytes()
+	if len(b) == 0 {
+		return ""
+	}
+
+	return template.HTML(unsafe.String(&b[0], len(b))) //nolint:gosec // the buffer outlives the html, see borrowedPartials
+}
+
+// htmlBytes returns the bytes of the html without copying them, they must not be modified
+func htmlBytes(output template.HTML) []byte {
+	return unsafe.Slice(unsafe.StringData(string(output)), len(output))
+}
@@ -0,0 +1,122 @@
+package htmx
+
+import (
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+)
+
+// cachedTemplate is an entry of the template cache
+type cachedTemplate struct {
+	tmpl         *template.Template // the parsed templates, never executed so they can be cloned
+	files        []string           // the template files that were parsed
+	contextFuncs []string           // the names of the context functions
+	clones       sync.Pool          // executable clones of tmpl
+}
+
+// newCachedTemplate returns a cache entry for the parsed templates
+func newCachedTemplate(tmpl *template.Template, fsys fs.FS, templates []string, contextFuncs map[string]ContextFunc) *cachedTemplate {
+	var files []string
+	for _, pattern := range templates {
+		files = append(files, expandPattern(fsys, pattern)...)
+	}
+
+	names := make([]string, 0, len(contextFuncs))
+	for name := range contextFuncs {
+		names = append(names, name)
+	}
+
+	return &cachedTemplate{
+		tmpl:         tmpl,
+		files:        files,
+		contextFuncs: names,
+	}
+}
+
+// InvalidateTemplate removes every cached template that was parsed from the given template file
+func InvalidateTemplate(file string) {
+	file = path.Clean(file)
+
+	n := invalidate(func(ct *cachedTemplate) bool {
+		for _, f := range ct.files {
+			if path.Clean(f) == file {
+				return true
+			}
+		}
+		return false
+	})
+
+	logReload("template invalidated", slog.String("file", file), slog.Int("dropped", n))
+}
+
+// InvalidatePrefix removes every cached template that was parsed from a template file within the given directory
+func InvalidatePrefix(dir string) {
+	dir = strings.TrimSuffix(path.Clean(dir), "/") + "/"
+
+	n := invalidate(func(ct *cachedTemplate) bool {
+		for _, f := range ct.files {
+			if dir == "./" || strings.HasPrefix(path.Clean(f), dir) {
+				return true
+			}
+		}
+		return false
+	})
+
+	logReload("templates invalidated", slog.String("dir", dir), slog.Int("dropped", n))
+}
+
+// ClearTemplateCache removes all cached templates
+func ClearTemplateCache() {
+	n := 0
+	templateCache.Range(func(any, any) bool {
+		n++
+		return true
+	})
+
+	templateCache.Clear()
+	observeEvictions(n)
+	logReload("template cache cleared", slog.Int("dropped", n))
+}
+
+// invalidate removes the cached templates that match and returns their number
+func invalidate(match func(ct *cachedTemplate) bool) int {
+	n := 0
+	templateCache.Range(func(key, value any) bool {
+		if ct, ok := value.(*cachedTemplate); ok && match(ct) {
+			templateCache.Delete(key)
+			n++
+		}
+		return true
+	})
+
+	observeEvictions(n)
+	return n
+}
+
+// dropCacheNamespace removes all cached templates of the namespace
+func dropCacheNamespace(namespace string) {
+	n := 0
+	templateCache.Range(func(key, value any) bool {
+		if k, ok := key.(string); ok && inCacheNamespace(cacheNamespace(k), namespace) {
+			templateCache.Delete(key)
+			n++
+		}
+		return true
+	})
+
+	observeEvictions(n)
+}
+
+// inCacheNamespace returns true if the namespace is the given namespace or the namespace of one of its tenants
+func inCacheNamespace(ns, namespace string) bool {
+	return ns == namespace || strings.HasPrefix(ns, namespace+"#")
+}
+
+// cacheNamespace returns the namespace of a template cache key
+func cacheNamespace(key string) string {
+	namespace, _, _ := strings.Cut(key, "|")
+	return namespace
+}
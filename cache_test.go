@@ -0,0 +1,56 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInvalidateTemplate(t *testing.T) {
+	ClearTemplateCache()
+	defer ClearTemplateCache()
+
+	fsys := fstest.MapFS{
+		"pages/home.html":   {Data: []byte(`home {{ template "nav" }}`)},
+		"pages/about.html":  {Data: []byte(`about {{ template "nav" }}`)},
+		"partials/nav.html": {Data: []byte(`{{ define "nav" }}nav{{ end }}`)},
+	}
+
+	warm := func() {
+		for _, page := range []string{"pages/home.html", "pages/about.html"} {
+			if _, err := NewComponent(page, "partials/nav.html").FS(fsys).Render(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	warm()
+	equalInt(t, 2, cachedTemplates())
+
+	InvalidateTemplate("pages/home.html")
+	equalInt(t, 1, cachedTemplates())
+
+	warm()
+	InvalidateTemplate("partials/nav.html")
+	equalInt(t, 0, cachedTemplates())
+
+	warm()
+	InvalidatePrefix("pages/")
+	equalInt(t, 0, cachedTemplates())
+
+	warm()
+	InvalidatePrefix("partials/other")
+	equalInt(t, 2, cachedTemplates())
+
+	ClearTemplateCache()
+	equalInt(t, 0, cachedTemplates())
+}
+
+func cachedTemplates() int {
+	n := 0
+	templateCache.Range(func(any, any) bool {
+		n++
+		return true
+	})
+	return n
+}
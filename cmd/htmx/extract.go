@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jkc-2/go-htmx/refactor"
+)
+
+// runExtract extracts a block or line range of a template into a new partial file,
+// and attaches the partial to the components that use the template.
+//
+//	htmx extract -block sidebar -out templates/sidebar.html templates/page.html
+//	htmx extract -lines 12:30 -name user-row -out templates/user-row.html -go ./handlers templates/users.html
+func runExtract(args []string) error {
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	block := flags.String("block", "", "name of the {{define}} or {{block}} to extract")
+	lines := flags.String("lines", "", "line range to extract, e.g. 12:30")
+	name := flags.String("name", "", "name of the template defined in the partial, required with -lines")
+	out := flags.String("out", "", "path of the partial file to create")
+	goDir := flags.String("go", "", "directory with Go sources whose NewComponent calls are updated with the partial")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *out == "" || (*block == "") == (*lines == "") {
+		flags.Usage()
+		return errors.New("expected a template, -out and one of -block or -lines")
+	}
+
+	source := flags.Arg(0)
+	sel := refactor.Selection{Block: *block}
+
+	if *lines != "" {
+		start, end, ok := strings.Cut(*lines, ":")
+		var err1, err2 error
+		sel.Start, err1 = strconv.Atoi(start)
+		sel.End, err2 = strconv.Atoi(end)
+		if !ok || err1 != nil || err2 != nil {
+			return fmt.Errorf("invalid line range %q", *lines)
+		}
+	}
+
+	src, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	result, err := refactor.Extract(src, sel, *name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(*out); err == nil {
+		return fmt.Errorf("%s already exists", *out)
+	}
+
+	if err := os.WriteFile(*out, result.Partial, 0o644); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(source, result.Source, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("extracted %q from %s into %s\n", result.Name, source, *out)
+
+	if *goDir == "" {
+		fmt.Printf("attach %s to the components that render %s\n", *out, source)
+		return nil
+	}
+
+	return filepath.WalkDir(*goDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		updated, n, err := refactor.AttachTemplate(src, filepath.ToSlash(source), filepath.ToSlash(*out))
+		if err != nil || n == 0 {
+			return err
+		}
+
+		fmt.Printf("attached %s to %d component(s) in %s\n", *out, n, path)
+		return os.WriteFile(path, updated, 0o644)
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jkc-2/go-htmx/refactor"
+)
+
+// runList lists the components registered with RegisterComponent in the Go sources of the directory and its
+// subdirectories, vendor and testdata directories excluded.
+//
+//	htmx list ./...
+func runList(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("expected at most one directory")
+	}
+
+	dir := strings.TrimSuffix(flags.Arg(0), "/...")
+	if dir == "" {
+		dir = "."
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	defer tw.Flush()
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		registrations, err := refactor.RegisteredComponents(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, r := range registrations {
+			fmt.Fprintf(tw, "%s\t%s:%d\n", r.Name, path, r.Line)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+// Command htmx contains tooling for applications built with go-htmx.
+//
+// Usage:
+//
+//	htmx <command> [arguments]
+//
+// The commands are:
+//
+//	extract    extract a block or line range of a template into a new partial
+//	list       list the components registered with RegisterComponent
+//	new        generate a new component with its template and snapshot test
+//	scaffold   generate list, detail and form components from an OpenAPI document
+//	vet        check the .Data references of templates against the data of their components
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "extract", usage: "extract a block or line range of a template into a new partial", run: runExtract},
+	{name: "list", usage: "list the components registered with RegisterComponent", run: runList},
+	{name: "new", usage: "generate a new component with its template and snapshot test", run: runNew},
+	{name: "scaffold", usage: "generate list, detail and form components from an OpenAPI document", run: runScaffold},
+	{name: "vet", usage: "check the .Data references of templates against the data of their components", run: runVet},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "htmx %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: htmx <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.usage)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/jkc-2/go-htmx/scaffold"
+)
+
+// runNew generates a new component: its constructor, template and, with -with-test, a snapshot test.
+// Existing files are never overwritten.
+//
+//	htmx new component UserCard -with-test -out ./views
+func runNew(args []string) error {
+	if len(args) == 0 || args[0] != "component" {
+		return errors.New("usage: htmx new component <Name> [-with-test] [-out dir] [-package name]")
+	}
+
+	flags := flag.NewFlagSet("new component", flag.ContinueOnError)
+	withTest := flags.Bool("with-test", false, "generate a snapshot test of the component")
+	out := flags.String("out", ".", "directory of the generated files")
+	pkg := flags.String("package", "", "package of the generated Go files, defaults to the name of the directory")
+	templates := flags.String("templates", "templates", "directory of the template, relative to -out")
+
+	names, err := parseInterspersed(flags, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if len(names) != 1 {
+		flags.Usage()
+		return errors.New("expected the name of the component")
+	}
+
+	if *pkg == "" {
+		*pkg = "components"
+		if abs, err := filepath.Abs(*out); err == nil && token.IsIdentifier(filepath.Base(abs)) {
+			*pkg = filepath.Base(abs)
+		}
+	}
+
+	files, err := scaffold.Component(scaffold.ComponentOptions{
+		Name:        names[0],
+		Package:     *pkg,
+		TemplateDir: filepath.ToSlash(*templates),
+		WithTest:    *withTest,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(*out, filepath.FromSlash(f.Path))
+
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("skipped %s, it already exists\n", path)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return err
+		}
+
+		fmt.Printf("created %s\n", path)
+	}
+
+	if *withTest {
+		fmt.Println("run the test with HTMXTEST_UPDATE=1 to write its first snapshot")
+	}
+
+	return nil
+}
+
+// parseInterspersed parses the flags that come before, between and after the positional arguments, which it returns
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jkc-2/go-htmx/scaffold"
+)
+
+// runScaffold generates list, detail and form components for the collections of a JSON OpenAPI document.
+// Existing files are never overwritten.
+//
+//	htmx scaffold -out ./admin -package admin openapi.json
+func runScaffold(args []string) error {
+	flags := flag.NewFlagSet("scaffold", flag.ContinueOnError)
+	out := flags.String("out", ".", "directory of the generated Go files")
+	pkg := flags.String("package", "admin", "package of the generated Go files")
+	templates := flags.String("templates", "templates", "directory of the generated templates, as loaded by the components")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected an OpenAPI document")
+	}
+
+	source := flags.Arg(0)
+	if ext := strings.ToLower(filepath.Ext(source)); ext == ".yaml" || ext == ".yml" {
+		return errors.New("only JSON OpenAPI documents are supported, convert the YAML document first")
+	}
+
+	document, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	files, err := scaffold.Generate(document, scaffold.Options{
+		Package:     *pkg,
+		TemplateDir: filepath.ToSlash(*templates),
+		Source:      filepath.Base(source),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.FromSlash(f.Path)
+		if strings.HasSuffix(f.Path, ".go") {
+			path = filepath.Join(*out, path)
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("skipped %s, it already exists\n", path)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return err
+		}
+
+		fmt.Printf("created %s\n", path)
+	}
+
+	return nil
+}
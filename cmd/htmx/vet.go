@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jkc-2/go-htmx/vet"
+)
+
+// runVet checks the .Data references of the templates of the components constructed in the Go sources of the
+// directory and its subdirectories against the keys the components are given, vendor and testdata directories
+// excluded. Templates are looked up relative to the Go file and to the -templates directories. It fails when it
+// reports a problem, so it can run in CI.
+//
+//	htmx vet -templates ./web ./...
+func runVet(args []string) error {
+	flags := flag.NewFlagSet("vet", flag.ContinueOnError)
+	templates := flags.String("templates", "", "comma separated directories the template paths are relative to")
+
+	dirs, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(dirs) > 1 {
+		flags.Usage()
+		return errors.New("expected at most one directory")
+	}
+
+	dir := "."
+	if len(dirs) == 1 {
+		dir = strings.TrimSuffix(dirs[0], "/...")
+	}
+
+	var roots []string
+	if *templates != "" {
+		roots = strings.Split(*templates, ",")
+	}
+
+	problems := 0
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		components, err := vet.Components(path, src)
+		if err != nil {
+			return err
+		}
+
+		lookup := append([]string{filepath.Dir(path)}, roots...)
+		diagnostics, err := vet.Check(components, func(name string) ([]byte, bool) {
+			for _, root := range lookup {
+				if b, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name))); err == nil {
+					return b, true
+				}
+			}
+			return nil, false
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, d := range diagnostics {
+			fmt.Println(d)
+		}
+		problems += len(diagnostics)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case problems == 1:
+		return errors.New("found 1 problem")
+	case problems > 1:
+		return fmt.Errorf("found %d problems", problems)
+	}
+
+	return nil
+}
@@ -6,20 +6,45 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"html/template"
 	"io/fs"
+	"net/http"
 	"net/url"
-	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
 	DefaultTemplateFuncs = template.FuncMap{}
 	UseTemplateCache     = true
 	templateCache        = sync.Map{} // Cache for parsed templates
+
+	// ReloadTemplates makes the template cache key include the modification time and size of the template files, so
+	// edited templates are parsed again on their next render. It stats every template on every render, Dev mode
+	// implies it. Without it, edited templates are picked up with InvalidateTemplate and the other invalidation calls.
+	ReloadTemplates = false
+
+	// fsIDs are the ids of the filesystems of the template cache keys
+	fsIDs   = map[any]int{}
+	fsIDsMu sync.Mutex
+
+	// MaxPooledBufferSize is the maximum capacity of a render buffer that is returned to the pool,
+	// larger buffers are left to the garbage collector so a single large page doesn't pin its memory.
+	MaxPooledBufferSize = 64 << 10
+
+	// ErrMissingData is returned when rendering a component without the data it Requires
+	ErrMissingData = errors.New("htmx: missing required data")
+
+	bufferPool = sync.Pool{
+		New: func() any {
+			return new(bytes.Buffer)
+		},
+	}
 )
 
 type (
@@ -34,7 +59,9 @@ type (
 		AddGlobalData(key string, value any) RenderableComponent
 		AddTemplateFunction(name string, function interface{}) RenderableComponent
 		AddTemplateFunctions(funcs template.FuncMap) RenderableComponent
+		AddContextFunction(name string, function ContextFunc) RenderableComponent
 		SetURL(url *url.URL)
+		SetRequest(r *http.Request)
 		Reset() *Component
 
 		data() map[string]any
@@ -45,19 +72,42 @@ type (
 		isWrapped() bool
 		wrapper() RenderableComponent
 		target() string
+		templateFiles() []string
+		parseTemplates() error
+		warmTemplates() (bool, error)
 	}
 
 	Component struct {
-		templateData    map[string]any
-		with            map[string]RenderableComponent
-		partial         map[string]any
-		globalData      map[string]any
-		wrappedRenderer RenderableComponent
-		wrappedTarget   string
-		templates       []string
-		url             *url.URL
-		functions       template.FuncMap
-		fs              fs.FS
+		templateData     map[string]any
+		injectedData     map[string]any // the data of the parent, layered below templateData when rendering
+		with             map[string]RenderableComponent
+		partial          map[string]any
+		globalData       map[string]any
+		injectedGlobal   map[string]any // the global data of the parent, layered below globalData when rendering
+		wrappedRenderer  RenderableComponent
+		wrappedTarget    string
+		autoWrap         bool
+		boostedRenderer  RenderableComponent
+		boostedTarget    string
+		preload          time.Duration
+		templates        []string
+		url              *url.URL
+		request          *http.Request
+		form             *Form
+		functions        template.FuncMap
+		contextFunctions map[string]ContextFunc
+		fs               fs.FS
+		sanitizer        SanitizeFunc
+		sanitizeOutput   bool
+		postProcessor    *PostProcessor
+		errorFallback    ErrorFallback
+		cacheStatus      string // the template cache status of the last parse, shown by the debug overlay
+		required         []string
+		flightKey        string
+		writer           WriterFunc // renders the component instead of its templates, see NewWriterComponent
+		hydration        []hydration
+		customElement    *customElement
+		contentType      string // the content type of components that don't render html, see NewFeedComponent
 	}
 )
 
@@ -68,11 +118,11 @@ func NewComponent(templates ...string) *Component {
 		partial:      make(map[string]any),
 		with:         make(map[string]RenderableComponent),
 		templates:    templates,
-		fs:           os.DirFS("./"),
 	}
 }
 
 // FS sets the filesystem to load templates from, this allows for embedding templates into the go binary.
+// Components without a filesystem load their templates from the active template set.
 func (c *Component) FS(fsys fs.FS) *Component {
 	c.fs = fsys
 	return c
@@ -82,63 +132,175 @@ func (c *Component) FS(fsys fs.FS) *Component {
 // it has all the default template functions and the additional template functions
 // that are added with AddTemplateFunction
 func (c *Component) Render(ctx context.Context) (template.HTML, error) {
+	if j := journal.Load(); j != nil {
+		return j.render(ctx, c)
+	}
+
+	return c.render(ctx)
+}
+
+// render renders the component and its partials, shared with the concurrent renders of the same Singleflight key
+func (c *Component) render(ctx context.Context) (template.HTML, error) {
+	if c.flightKey != "" {
+		return c.renderShared(ctx)
+	}
+
+	return c.renderUnshared(ctx)
+}
+
+// renderUnshared renders the component and its partials, observed by the RenderTracer and RenderMetrics when they are
+// set
+func (c *Component) renderUnshared(ctx context.Context) (template.HTML, error) {
+	if RenderTracer != nil || RenderMetrics != nil {
+		return observeRender(ctx, c, c.renderComponent)
+	}
+
+	return c.renderComponent(ctx)
+}
+
+// renderComponent renders the component and its partials
+func (c *Component) renderComponent(ctx context.Context) (template.HTML, error) {
+	buf, err := c.renderComponentBuffer(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer putBuffer(buf)
+
+	return template.HTML(buf.String()), nil
+}
+
+// renderComponentBuffer renders the component and its partials into a pooled buffer, the caller owns the buffer and
+// returns it with putBuffer
+func (c *Component) renderComponentBuffer(ctx context.Context) (*bytes.Buffer, error) {
+	start := time.Now()
+
+	// Stop rendering when the request was aborted
+	if err := renderCanceled(ctx); err != nil {
+		return nil, err
+	}
+
 	// Check for circular references
 	if ctx.Value(c) != nil {
-		return "", errors.New("circular reference detected in partials")
+		return nil, errors.New("circular reference detected in partials")
 	}
 
-	// Add current component to context
+	// Add current component to context, its partials are post-processed with its output
+	outermost := !withinRender(ctx)
 	ctx = context.WithValue(ctx, c, true)
+	ctx = withOuterRender(ctx)
+	ctx = c.requestContext(ctx)
+
+	ctx, err := tenantContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, global := c.data(), c.global()
 
+	for _, key := range c.required {
+		if _, ok := data[key]; !ok {
+			return nil, fmt.Errorf("%w: %s requires %q", ErrMissingData, c.renderInfo().Component, key)
+		}
+	}
+
+	// the partials rendered into pooled buffers are borrowed until the component was executed
+	borrowed := make(borrowedPartials, 0, len(c.with))
+	defer borrowed.release(c)
+
+	endPartials := c.startPhase(PhasePartials)
 	for key, value := range c.partials() {
-		value.injectData(c.templateData)
-		value.injectGlobalData(c.globalData)
+		value.injectData(data)
+		value.injectGlobalData(global)
 
-		ch, err := value.Render(ctx)
+		ch, buf, err := renderPartial(ctx, value)
 		if err != nil {
-			return "", err
+			if ch, err = c.recoverPartial(ctx, key, err); err != nil {
+				endPartials()
+				return nil, err
+			}
+		}
+		if buf != nil {
+			borrowed.add(key, buf)
 		}
 		c.addPartial(key, ch)
 	}
+	endPartials()
+
+	var buf *bytes.Buffer
+	if c.writer != nil {
+		buf, err = c.renderWriter(ctx)
+	} else {
+		//get the name of the first template file
+		if len(c.templates) == 0 {
+			return nil, errors.New("no templates provided for rendering")
+		}
 
-	//get the name of the first template file
-	if len(c.templates) == 0 {
-		return "", errors.New("no templates provided for rendering")
+		buf, err = c.renderNamed(ctx, filepath.Base(c.templates[0]), c.templates, data)
+	}
+	if err != nil {
+		c.logRenderError(ctx, err)
+		return nil, err
+	}
+
+	if buf, err = c.wrapCustomElement(buf, data); err != nil {
+		return nil, err
+	}
+
+	if err := c.writeHydration(buf, data); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+
+	if p := c.outputPostProcessor(outermost); c.contentType == "" && (c.sanitizeOutput || p != nil) {
+		output, err := p.Process(c.sanitizeRendered(template.HTML(buf.String())))
+		if err != nil {
+			putBuffer(buf)
+			return nil, err
+		}
+		buf.Reset()
+		buf.WriteString(string(output))
 	}
 
-	return c.renderNamed(ctx, filepath.Base(c.templates[0]), c.templates, c.templateData)
+	duration := time.Since(start)
+	c.logSlowRender(ctx, duration)
+
+	if isDev() && c.contentType == "" {
+		output := template.HTML(buf.String())
+		if debugRequested(ctx) {
+			output = c.debugAttributes(output, duration)
+		}
+		buf.Reset()
+		buf.WriteString(string(c.debugComments(output)))
+	}
+
+	return buf, nil
 }
 
-// renderNamed renders the given templates with the given data
-// it has all the default template functions and the additional template functions
+// renderNamed renders the given templates with the given data into a pooled buffer, the caller owns the buffer and
+// returns it with putBuffer. It has all the default template functions and the additional template functions
 // that are added with AddTemplateFunction
-func (c *Component) renderNamed(ctx context.Context, name string, templates []string, input map[string]any) (template.HTML, error) {
+func (c *Component) renderNamed(ctx context.Context, name string, templates []string, input map[string]any) (*bytes.Buffer, error) {
 	if len(templates) == 0 {
-		return "", nil
+		return getBuffer(), nil
 	}
 
-	var err error
-	functions := make(template.FuncMap)
-	for key, value := range DefaultTemplateFuncs {
-		functions[key] = value
-	}
+	tenant := renderTenant(ctx)
 
-	if c.functions != nil {
-		for key, value := range c.functions {
-			functions[key] = value
-		}
+	endParse := c.startPhase(PhaseParse)
+	ct, contextFuncs, err := c.parse(tenant, name, templates)
+	endParse()
+	if err != nil {
+		return nil, err
 	}
 
-	cacheKey := generateCacheKey(templates, functions)
-	tmpl, cached := templateCache.Load(cacheKey)
-	if !cached || !UseTemplateCache {
-		// Parse and cache template as before
-		tmpl, err = template.New(name).Funcs(functions).ParseFS(c.fs, templates...)
-		if err != nil {
-			return "", err
-		}
-		templateCache.Store(cacheKey, tmpl)
+	clone, err := ct.acquire()
+	if err != nil {
+		return nil, err
 	}
+	defer ct.release(clone)
+
+	clone.state.ctx = ctx
+	clone.state.funcs = contextFuncs
 
 	data := struct {
 		Ctx      context.Context
@@ -149,22 +311,91 @@ func (c *Component) renderNamed(ctx context.Context, name string, templates []st
 	}{
 		Ctx:      ctx,
 		Data:     input,
-		Global:   c.globalData,
+		Global:   tenant.global(c.global()),
 		Partials: c.partial,
 		URL:      c.url,
 	}
 
-	if t, ok := tmpl.(*template.Template); ok {
-		var buf bytes.Buffer
-		err = t.Execute(&buf, data)
-		if err != nil {
-			return "", err
+	buf := getBuffer()
+
+	endExecute := c.startPhase(PhaseExecute)
+	err = clone.tmpl.Execute(buf, data)
+	endExecute()
+	if err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// parse parses the given templates for the tenant, or returns them from the template cache
+func (c *Component) parse(tenant *Tenant, name string, templates []string) (*cachedTemplate, map[string]ContextFunc, error) {
+	functions, contextFuncs := c.templateFunctions(tenant)
+	for key, value := range contextFuncPlaceholders(contextFuncs) {
+		functions[key] = value
+	}
+
+	fsys, namespace := c.templateFS(tenant)
+
+	cacheKey := namespace + "|" + generateCacheKey(fsys, templates, functions) + "|" + contextFuncsKey(contextFuncs) + "|" + shared.cacheKey()
+	useCache := UseTemplateCache && !isDev()
+	if cached, ok := templateCache.Load(cacheKey); ok && useCache {
+		if ct, ok := cached.(*cachedTemplate); ok {
+			c.cacheStatus = "hit"
+			return ct, contextFuncs, nil
 		}
+	}
+
+	c.cacheStatus = "miss"
+	if !useCache {
+		c.cacheStatus = "off"
+	} else {
+		c.logCacheMiss(namespace)
+	}
+
+	tmpl := template.New(name).Funcs(functions)
+	if err := shared.parseInto(tmpl); err != nil {
+		return nil, nil, err
+	}
+
+	tmpl, err := tmpl.ParseFS(fsys, templates...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ct := newCachedTemplate(tmpl, fsys, templates, contextFuncs)
+	if useCache {
+		templateCache.Store(cacheKey, ct)
+	}
 
-		return template.HTML(buf.String()), nil // Return rendered content
+	return ct, contextFuncs, nil
+}
+
+// parseTemplates parses the templates of the component and its partials without rendering them
+func (c *Component) parseTemplates() error {
+	var errs []error
+
+	if _, err := c.warmTemplates(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, partial := range c.with {
+		errs = append(errs, partial.parseTemplates())
 	}
 
-	return "", errors.New("template is not a *template.Template")
+	return errors.Join(errs...)
+}
+
+// warmTemplates parses the templates of the component, not those of its partials, and returns true if they were
+// already cached
+func (c *Component) warmTemplates() (bool, error) {
+	if len(c.templates) == 0 {
+		return false, nil
+	}
+
+	_, _, err := c.parse(nil, filepath.Base(c.templates[0]), c.templates)
+	return err == nil && c.cacheStatus == "hit", err
 }
 
 // Wrap wraps the component with the given renderer
@@ -181,7 +412,9 @@ func (c *Component) With(r RenderableComponent, target string) RenderableCompone
 		c.with = make(map[string]RenderableComponent)
 	}
 
-	if c.url != nil {
+	if c.request != nil {
+		r.SetRequest(c.request)
+	} else if c.url != nil {
 		r.SetURL(c.url)
 	}
 
@@ -200,6 +433,14 @@ func (c *Component) Attach(target string) RenderableComponent {
 	return c
 }
 
+// Requires declares the data keys the templates of the component need, rendering without one of them fails with
+// ErrMissingData. The keys can be provided by the component or by the components it is a partial of. htmx vet checks
+// the .Data references of the templates against them.
+func (c *Component) Requires(keys ...string) *Component {
+	c.required = append(c.required, keys...)
+	return c
+}
+
 func (c *Component) AddTemplateFunction(name string, function interface{}) RenderableComponent {
 	if c.functions == nil {
 		c.functions = make(template.FuncMap)
@@ -272,6 +513,18 @@ func (c *Component) SetURL(url *url.URL) {
 	}
 }
 
+// SetRequest sets the request the component is rendered for, this sets the url of the component and makes the
+// request available to the query, path and host template functions.
+func (c *Component) SetRequest(r *http.Request) {
+	c.request = r
+	c.url = r.URL
+
+	// Recursively set the request for all partials
+	for _, partial := range c.with {
+		partial.SetRequest(r)
+	}
+}
+
 // isWrapped returns true if the component is wrapped
 func (c *Component) isWrapped() bool {
 	return c.wrappedRenderer != nil
@@ -287,30 +540,25 @@ func (c *Component) target() string {
 	return c.wrappedTarget
 }
 
+// templateFiles returns the template files of the component
+func (c *Component) templateFiles() []string {
+	return c.templates
+}
+
 // partials returns the partials
 func (c *Component) partials() map[string]RenderableComponent {
 	return c.with
 }
 
-// injectData injects the input data into the template data
+// injectData layers the template data over the input data, the keys of the component take precedence. Neither map is
+// modified, the input replaces the data injected before.
 func (c *Component) injectData(input map[string]any) {
-	for key, value := range input {
-		if _, ok := c.templateData[key]; !ok {
-			c.templateData[key] = value
-		}
-	}
+	c.injectedData = input
 }
 
+// injectGlobalData layers the global data over the input data, like injectData
 func (c *Component) injectGlobalData(input map[string]any) {
-	if c.globalData == nil {
-		c.globalData = make(map[string]any)
-	}
-
-	for key, value := range input {
-		if _, ok := c.globalData[key]; !ok {
-			c.globalData[key] = value
-		}
-	}
+	c.injectedGlobal = input
 }
 
 // addPartial adds a partial to the component
@@ -318,29 +566,161 @@ func (c *Component) addPartial(key string, value any) {
 	c.partial[key] = value
 }
 
-// data returns the template data
+// data returns the template data layered over the injected data
 func (c *Component) data() map[string]any {
-	return c.templateData
+	return layerData(c.templateData, c.injectedData)
+}
+
+// global returns the global data layered over the injected global data
+func (c *Component) global() map[string]any {
+	if global := layerData(c.globalData, c.injectedGlobal); global != nil {
+		return global
+	}
+
+	return make(map[string]any)
+}
+
+// layerData returns the keys of own and the keys of injected that own doesn't have. Either map is returned as is when
+// the other adds no keys to it, so the data of partials that don't shadow it is shared with their parent instead of
+// copied. The maps must not be modified by the caller.
+func layerData(own, injected map[string]any) map[string]any {
+	if len(injected) == 0 {
+		return own
+	}
+	if len(own) == 0 {
+		return injected
+	}
+
+	shadowed := 0
+	for key := range injected {
+		if _, ok := own[key]; ok {
+			shadowed++
+		}
+	}
+	if shadowed == len(injected) {
+		return own
+	}
+
+	merged := make(map[string]any, len(own)+len(injected)-shadowed)
+	for key, value := range injected {
+		merged[key] = value
+	}
+	for key, value := range own {
+		merged[key] = value
+	}
+
+	return merged
 }
 
 func (c *Component) Reset() *Component {
 	c.templateData = make(map[string]any)
+	c.injectedData = nil
 	c.globalData = make(map[string]any)
+	c.injectedGlobal = nil
 	c.partial = make(map[string]any)
 	c.with = make(map[string]RenderableComponent)
 	c.url = nil
+	c.request = nil
+	c.form = nil
 
 	return c
 }
 
-// Generate a hash of the function names to include in the cache key
-func generateCacheKey(templates []string, funcs template.FuncMap) string {
+// generateCacheKey derives the cache key from the filesystem, all template paths and the names of the template
+// functions. Components with different template lists or filesystems never share a cache entry. With ReloadTemplates
+// or in Dev mode the modification time and size of the template files are part of the key, so edited template files
+// invalidate their entries.
+func generateCacheKey(fsys fs.FS, templates []string, funcs template.FuncMap) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "fs:%d;", fsID(fsys))
+
+	reload := ReloadTemplates || isDev()
+	for _, pattern := range templates {
+		hash.Write([]byte(pattern))
+		hash.Write([]byte{0})
+
+		if !reload {
+			continue
+		}
+
+		for _, name := range expandPattern(fsys, pattern) {
+			if info, err := fs.Stat(fsys, name); err == nil {
+				fmt.Fprintf(hash, "%s:%d:%d;", name, info.ModTime().UnixNano(), info.Size())
+			}
+		}
+	}
+
 	var funcNames []string
 	for name := range funcs {
 		funcNames = append(funcNames, name)
 	}
 	// Sort function names to ensure consistent ordering
 	sort.Strings(funcNames)
-	hash := sha256.Sum256([]byte(strings.Join(funcNames, ",")))
-	return templates[0] + ":" + hex.EncodeToString(hash[:])
+	hash.Write([]byte(strings.Join(funcNames, ",")))
+
+	return templates[0] + ":" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// fsID returns the id of the filesystem: comparable filesystems like os.DirFS and embed.FS are identified by their
+// value, maps like fstest.MapFS by their address, tenant overlays by their layers and other filesystems by their type
+func fsID(fsys fs.FS) int {
+	if fsys == nil {
+		return 0
+	}
+
+	var key any = fmt.Sprintf("%T", fsys)
+	v := reflect.ValueOf(fsys)
+	switch overlay, ok := fsys.(overlayFS); {
+	case ok:
+		key = [2]int{fsID(overlay.top), fsID(overlay.base)}
+	case v.Comparable():
+		key = fsys
+	case v.Kind() == reflect.Map || v.Kind() == reflect.Slice || v.Kind() == reflect.Func:
+		key = [2]any{v.Type(), v.Pointer()}
+	}
+
+	fsIDsMu.Lock()
+	defer fsIDsMu.Unlock()
+
+	id, ok := fsIDs[key]
+	if !ok {
+		id = len(fsIDs) + 1
+		fsIDs[key] = id
+	}
+
+	return id
+}
+
+// expandPattern returns the files matching the template pattern, like template.ParseFS does
+func expandPattern(fsys fs.FS, pattern string) []string {
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		return []string{pattern}
+	}
+
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil
+	}
+
+	return matches
+}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf, ok := bufferPool.Get().(*bytes.Buffer)
+	if !ok {
+		return new(bytes.Buffer)
+	}
+
+	return buf
+}
+
+// putBuffer resets the buffer and returns it to the pool
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > MaxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
 }
@@ -0,0 +1,202 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var benchFS = fstest.MapFS{
+	"page.html": {Data: []byte(`<main>{{ .Partials.list }}</main>`)},
+	"list.html": {Data: []byte(`<ul>{{ range .Data.Items }}<li hx-get="/items/{{ . }}">{{ . }}</li>{{ end }}</ul>`)},
+}
+
+func TestComponentRender(t *testing.T) {
+	out, err := benchComponent().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(out), `<main><ul><li hx-get="/items/item-0">item-0</li>`) {
+		t.Errorf("unexpected output %s", out)
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchComponent().Render(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderTo(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := benchComponent().(*Component).RenderTo(ctx, io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderParallel(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := benchComponent().Render(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchComponent() RenderableComponent {
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = "item-" + strconv.Itoa(i)
+	}
+
+	list := NewComponent("list.html").FS(benchFS).AddData("Items", items)
+
+	return NewComponent("page.html").FS(benchFS).With(list, "list")
+}
+
+func TestRenderTo(t *testing.T) {
+	expected, err := benchComponent().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := benchComponent().(*Component)
+
+	var buf bytes.Buffer
+	if err := c.RenderTo(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, string(expected), buf.String())
+
+	// the output of the partials was borrowed from pooled buffers, it is not kept after the render
+	equalInt(t, 0, len(c.partial))
+
+	// rendering again reuses the pooled buffers without changing the output of the first render
+	again, err := benchComponent().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, string(expected), string(again))
+}
+
+func TestCacheKeyTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html":  {Data: []byte(`<main>{{ template "nav" . }}</main>`)},
+		"nav-a.html": {Data: []byte(`{{ define "nav" }}a{{ end }}`)},
+		"nav-b.html": {Data: []byte(`{{ define "nav" }}b{{ end }}`)},
+	}
+
+	render := func(templates ...string) string {
+		out, err := NewComponent(templates...).FS(fsys).Render(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	equal(t, "<main>a</main>", render("page.html", "nav-a.html"))
+	equal(t, "<main>b</main>", render("page.html", "nav-b.html"))
+
+	// edited template files are served from the cache until they are invalidated or reloading is on
+	fsys["nav-a.html"] = &fstest.MapFile{Data: []byte(`{{ define "nav" }}edited{{ end }}`)}
+	equal(t, "<main>a</main>", render("page.html", "nav-a.html"))
+
+	ReloadTemplates = true
+	defer func() { ReloadTemplates = false }()
+	equal(t, "<main>edited</main>", render("page.html", "nav-a.html"))
+}
+
+func TestCacheKeyFS(t *testing.T) {
+	render := func(fsys fstest.MapFS) string {
+		out, err := NewComponent("fs-page.html").FS(fsys).Render(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	// same template names and sizes in different filesystems
+	equal(t, "<p>a</p>", render(fstest.MapFS{"fs-page.html": {Data: []byte(`<p>a</p>`)}}))
+	equal(t, "<p>b</p>", render(fstest.MapFS{"fs-page.html": {Data: []byte(`<p>b</p>`)}}))
+}
+
+func TestRequires(t *testing.T) {
+	fsys := fstest.MapFS{
+		"requires-page.html": {Data: []byte(`<main>{{ .Partials.card }}</main>`)},
+		"requires-card.html": {Data: []byte(`<h2>{{ .Data.Name }}</h2>`)},
+	}
+
+	card := NewComponent("requires-card.html").FS(fsys).Requires("Name")
+	_, err := card.Render(context.Background())
+	equalBool(t, true, errors.Is(err, ErrMissingData))
+
+	// the data of the parent is injected into its partials
+	out, err := NewComponent("requires-page.html").FS(fsys).AddData("Name", "Ada").With(card, "card").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<main><h2>Ada</h2></main>", string(out))
+}
+
+func TestInjectedData(t *testing.T) {
+	fsys := fstest.MapFS{
+		"inject-page.html": {Data: []byte(`<main>{{ .Partials.card }}</main>`)},
+		"inject-card.html": {Data: []byte(`<h2>{{ .Data.Name }} {{ .Data.Role }} {{ .Global.Site }}</h2>`)},
+	}
+
+	own := map[string]any{"Role": "admin"}
+	card := NewComponent("inject-card.html").FS(fsys).SetData(own)
+	parent := map[string]any{"Name": "Ada", "Role": "guest"}
+	page := NewComponent("inject-page.html").FS(fsys).SetData(parent).With(card, "card")
+	page.AddGlobalData("Site", "docs")
+
+	out, err := page.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<main><h2>Ada admin docs</h2></main>", string(out))
+
+	// the data is layered, neither the data of the partial nor that of the parent is modified
+	equalInt(t, 1, len(own))
+	equalInt(t, 2, len(parent))
+
+	// the data of the next parent replaces the injected data
+	out, err = NewComponent("inject-page.html").FS(fsys).AddData("Name", "Grace").With(card, "card").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<main><h2>Grace admin </h2></main>", string(out))
+}
+
+func TestLayerData(t *testing.T) {
+	own := map[string]any{"A": 1}
+	injected := map[string]any{"A": 2, "B": 3}
+
+	equalInt(t, 2, len(layerData(nil, injected)))
+	equalInt(t, 1, len(layerData(own, nil)))
+	equalInt(t, 1, len(layerData(own, map[string]any{"A": 2})))
+
+	merged := layerData(own, injected)
+	equalInt(t, 2, len(merged))
+	equalInt(t, 1, merged["A"].(int))
+	equalInt(t, 3, merged["B"].(int))
+}
@@ -0,0 +1,177 @@
+package htmx
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// UseCompression enables the negotiation of a content encoding when rendering through the Handler.
+	UseCompression = false
+
+	// DefaultCompressionLevel is the compression level used by the built-in gzip and deflate encoders.
+	DefaultCompressionLevel = gzip.DefaultCompression
+
+	// CompressionMinSize is the size in bytes below which responses are written uncompressed, as the encoding
+	// overhead outweighs the savings for small fragments.
+	CompressionMinSize = 1024
+
+	encoders          = map[string]*encoderPool{}
+	encoderPreference = make([]string, 0)
+	encodersMu        sync.RWMutex
+)
+
+type (
+	// CompressWriter is implemented by compressing writers that can be reset and reused,
+	// like gzip.Writer, flate.Writer and the brotli.Writer from github.com/andybalholm/brotli.
+	CompressWriter interface {
+		io.WriteCloser
+		Reset(w io.Writer)
+	}
+
+	encoderPool struct {
+		pool sync.Pool
+	}
+)
+
+func init() {
+	RegisterEncoder("deflate", func(w io.Writer) CompressWriter {
+		fl, err := flate.NewWriter(w, DefaultCompressionLevel)
+		if err != nil {
+			fl, _ = flate.NewWriter(w, flate.DefaultCompression)
+		}
+		return fl
+	})
+
+	RegisterEncoder("gzip", func(w io.Writer) CompressWriter {
+		gz, err := gzip.NewWriterLevel(w, DefaultCompressionLevel)
+		if err != nil {
+			return gzip.NewWriter(w)
+		}
+		return gz
+	})
+}
+
+// RegisterEncoder registers a content encoding that can be negotiated through the Accept-Encoding header.
+// Encoders registered later take precedence over earlier ones, this allows registering "br" on top of the
+// built-in gzip and deflate encoders without adding a dependency to this package.
+func RegisterEncoder(name string, fn func(w io.Writer) CompressWriter) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	name = strings.ToLower(name)
+	if _, ok := encoders[name]; !ok {
+		encoderPreference = append([]string{name}, encoderPreference...)
+	}
+
+	encoders[name] = &encoderPool{
+		pool: sync.Pool{
+			New: func() any {
+				return fn(io.Discard)
+			},
+		},
+	}
+}
+
+// NegotiateEncoding returns the preferred registered encoding that is accepted by the request,
+// an empty string is returned when no encoding is acceptable.
+func NegotiateEncoding(r *http.Request) string {
+	accepted := parseAcceptEncoding(r.Header.Get("Accept-Encoding"))
+	if len(accepted) == 0 {
+		return ""
+	}
+
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	best, bestQ := "", 0.0
+	for _, name := range encoderPreference {
+		q, ok := accepted[name]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+
+		if ok && q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+
+	return best
+}
+
+// parseAcceptEncoding parses the Accept-Encoding header into a map of encodings and their quality values.
+func parseAcceptEncoding(header string) map[string]float64 {
+	accepted := make(map[string]float64)
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, params, _ := strings.Cut(part, ";")
+		q := 1.0
+
+		params = strings.TrimSpace(params)
+		if v, ok := strings.CutPrefix(params, "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	return accepted
+}
+
+// writeCompressed writes the data to the response writer using the negotiated content encoding.
+// the returned number of bytes is the number of uncompressed bytes that were consumed.
+func (h *Handler) writeCompressed(data []byte) (int, error) {
+	// the Content-Encoding header can't be set anymore once the status was written, e.g. by StopPolling
+	if !UseCompression || len(data) < CompressionMinSize || h.wroteHeader || h.w.Header().Get("Content-Encoding") != "" {
+		return h.Write(data)
+	}
+
+	encoding := NegotiateEncoding(h.r)
+	if encoding == "" {
+		return h.Write(data)
+	}
+
+	encodersMu.RLock()
+	ep := encoders[encoding]
+	encodersMu.RUnlock()
+
+	header := h.w.Header()
+	header.Set("Content-Encoding", encoding)
+	addVary(header, "Accept-Encoding")
+	header.Del("Content-Length")
+
+	// net/http would sniff the compressed bytes, detect the type from the uncompressed data like it does without encoding
+	if _, ok := header["Content-Type"]; !ok {
+		header.Set("Content-Type", http.DetectContentType(data))
+	}
+
+	cw, ok := ep.pool.Get().(CompressWriter)
+	if !ok {
+		return h.Write(data)
+	}
+	defer ep.pool.Put(cw)
+
+	h.wroteHeader = true
+	cw.Reset(h.w)
+
+	n, err := cw.Write(data)
+	if err != nil {
+		return n, err
+	}
+
+	return n, cw.Close()
+}
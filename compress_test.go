@@ -0,0 +1,122 @@
+package htmx
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	r.Header.Set("Accept-Encoding", "gzip, deflate")
+	equal(t, "gzip", NegotiateEncoding(r))
+
+	r.Header.Set("Accept-Encoding", "gzip;q=0.5, deflate")
+	equal(t, "deflate", NegotiateEncoding(r))
+
+	r.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+	equal(t, "", NegotiateEncoding(r))
+
+	r.Header.Set("Accept-Encoding", "")
+	equal(t, "", NegotiateEncoding(r))
+}
+
+func TestRenderCompressed(t *testing.T) {
+	UseCompression, CompressionMinSize = true, 0
+	defer func() { UseCompression, CompressionMinSize = false, 1024 }()
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>{{ .Data.Text }}</p>`)},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	c := NewComponent("index.html").FS(fsys)
+	c.AddData("Text", "compressed")
+
+	_, err := New().NewHandler(w, r).Render(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "<p>compressed</p>", string(body))
+}
+
+func TestRenderCompressedMinSize(t *testing.T) {
+	UseCompression = true
+	defer func() { UseCompression = false }()
+
+	fsys := fstest.MapFS{
+		"small.html": {Data: []byte(`<p>{{ .Data.Text }}</p>`)},
+	}
+
+	render := func(text string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Encoding", "gzip")
+		r.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+
+		if _, err := New().NewHandler(w, r).Render(context.Background(), NewComponent("small.html").FS(fsys).AddData("Text", text)); err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+
+	// below the minimum size the body is written as is
+	w := render("small")
+	equal(t, "", w.Header().Get("Content-Encoding"))
+	equal(t, "<p>small</p>", w.Body.String())
+
+	w = render(strings.Repeat("large ", CompressionMinSize/6))
+	equal(t, "gzip", w.Header().Get("Content-Encoding"))
+}
+
+func TestRenderCompressedContentType(t *testing.T) {
+	UseCompression, CompressionMinSize = true, 0
+	defer func() { UseCompression, CompressionMinSize = false, 1024 }()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	// the Content-Type set by the handler is kept
+	h := New().NewHandler(w, r)
+	h.Header().Set("Content-Type", "image/svg+xml")
+	if _, err := h.writeCompressed([]byte(`<svg></svg>`)); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+
+	// otherwise it is detected from the uncompressed body
+	w = httptest.NewRecorder()
+	if _, err := New().NewHandler(w, r).writeCompressed([]byte(`{"ok":true}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+}
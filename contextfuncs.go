@@ -0,0 +1,142 @@
+package htmx
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// DefaultContextFuncs are the context functions that are available in every component,
+// they can be overridden by the context functions of the component.
+var DefaultContextFuncs = map[string]ContextFunc{}
+
+type (
+	// ContextFunc is a template function that receives the context of the render, e.g. to read the locale of the request.
+	// The arguments of the template call are passed as args.
+	//
+	//	{{ t "greeting" .Data.Name }}
+	ContextFunc func(ctx context.Context, args ...any) (any, error)
+
+	// renderState is the state of a single render, the context functions of a template clone read it
+	renderState struct {
+		ctx   context.Context
+		funcs map[string]ContextFunc
+	}
+
+	// templateClone is a clone of a cached template with its context functions bound to the render state
+	templateClone struct {
+		tmpl  *template.Template
+		state *renderState
+	}
+)
+
+// AddContextFunction adds a context function to the component
+func (c *Component) AddContextFunction(name string, function ContextFunc) RenderableComponent {
+	if c.contextFunctions == nil {
+		c.contextFunctions = make(map[string]ContextFunc)
+	}
+
+	c.contextFunctions[name] = function
+
+	return c
+}
+
+// templateFunctions returns the template and context functions of the component, in order of precedence:
+// the built-in functions, the default functions, the functions of the tenant and the functions of the component.
+func (c *Component) templateFunctions(tenant *Tenant) (template.FuncMap, map[string]ContextFunc) {
+	functions := make(template.FuncMap)
+	contextFuncs := make(map[string]ContextFunc)
+
+	layers := []struct {
+		functions    template.FuncMap
+		contextFuncs map[string]ContextFunc
+	}{
+		{builtinTemplateFuncs, builtinContextFuncs},
+		{DefaultTemplateFuncs, DefaultContextFuncs},
+		{nil, nil},
+		{c.functions, c.contextFunctions},
+	}
+
+	if tenant != nil {
+		layers[2].functions, layers[2].contextFuncs = tenant.Funcs, tenant.ContextFuncs
+	}
+
+	for _, layer := range layers {
+		for name, fn := range layer.functions {
+			functions[name] = fn
+			delete(contextFuncs, name)
+		}
+
+		for name, fn := range layer.contextFuncs {
+			contextFuncs[name] = fn
+			delete(functions, name)
+		}
+	}
+
+	return functions, contextFuncs
+}
+
+// contextFuncPlaceholders returns the functions the templates are parsed with, the parsed template is never executed.
+// The placeholders are replaced by functions bound to the render state in every clone.
+func contextFuncPlaceholders(contextFuncs map[string]ContextFunc) template.FuncMap {
+	placeholders := make(template.FuncMap, len(contextFuncs))
+	for name := range contextFuncs {
+		placeholders[name] = func(...any) (any, error) {
+			return nil, fmt.Errorf("context function %s called outside of a render", name)
+		}
+	}
+
+	return placeholders
+}
+
+// contextFuncsKey returns the part of the cache key for the context functions
+func contextFuncsKey(contextFuncs map[string]ContextFunc) string {
+	names := make([]string, 0, len(contextFuncs))
+	for name := range contextFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ",")
+}
+
+// acquire returns a clone of the cached template that can be executed, clones are reused through a pool
+func (ct *cachedTemplate) acquire() (*templateClone, error) {
+	if clone, ok := ct.clones.Get().(*templateClone); ok {
+		return clone, nil
+	}
+
+	tmpl, err := ct.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &renderState{}
+	funcs := make(template.FuncMap, len(ct.contextFuncs))
+	for _, name := range ct.contextFuncs {
+		funcs[name] = state.dispatch(name)
+	}
+
+	return &templateClone{tmpl: tmpl.Funcs(funcs), state: state}, nil
+}
+
+// release clears the render state of the clone and returns it to the pool
+func (ct *cachedTemplate) release(clone *templateClone) {
+	clone.state.ctx = nil
+	clone.state.funcs = nil
+	ct.clones.Put(clone)
+}
+
+// dispatch returns the template function calling the context function of the current render
+func (s *renderState) dispatch(name string) func(args ...any) (any, error) {
+	return func(args ...any) (any, error) {
+		fn, ok := s.funcs[name]
+		if !ok || s.ctx == nil {
+			return nil, fmt.Errorf("context function %s called outside of a render", name)
+		}
+
+		return fn(s.ctx, args...)
+	}
+}
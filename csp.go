@@ -0,0 +1,47 @@
+package htmx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// DefaultCSP is the Content-Security-Policy used by the CSP middleware when no policy is given,
+// {nonce} is replaced by the nonce of the request.
+var DefaultCSP = "default-src 'self'; script-src 'self' 'nonce-{nonce}'; style-src 'self' 'nonce-{nonce}'; object-src 'none'; base-uri 'self'"
+
+type nonceKey struct{}
+
+// NewNonce returns a new random nonce for a Content-Security-Policy
+func NewNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// WithNonce returns a context with the CSP nonce of the request
+func WithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, nonceKey{}, nonce)
+}
+
+// Nonce returns the CSP nonce of the context, or an empty string when it has none
+func Nonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(nonceKey{}).(string)
+	return nonce
+}
+
+// CSPHeader returns the policy with the {nonce} placeholders replaced by the nonce
+func CSPHeader(policy, nonce string) string {
+	return strings.ReplaceAll(policy, "{nonce}", nonce)
+}
+
+// nonceFunc is the nonce template function
+//
+//	<script nonce="{{ nonce }}">...</script>
+func nonceFunc(ctx context.Context, _ ...any) (any, error) {
+	return Nonce(ctx), nil
+}
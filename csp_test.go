@@ -0,0 +1,28 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNonce(t *testing.T) {
+	fsys := fstest.MapFS{
+		"script.html": {Data: []byte(`<script nonce="{{ nonce }}">init()</script>`)},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(WithNonce(r.Context(), "abc+/="))
+	w := httptest.NewRecorder()
+
+	// the nonce of the request is used when rendering with another context
+	_, err := New().NewHandler(w, r).Render(context.Background(), NewComponent("script.html").FS(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<script nonce="abc&#43;/=">init()</script>`, w.Body.String())
+	equal(t, "script-src 'nonce-abc'", CSPHeader("script-src 'nonce-{nonce}'", "abc"))
+}
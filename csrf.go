@@ -0,0 +1,139 @@
+package htmx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+)
+
+var (
+	// CSRFCookieName is the name of the cookie that holds the CSRF token
+	CSRFCookieName = "_csrf"
+
+	// CSRFFieldName is the name of the form field with the CSRF token
+	CSRFFieldName = "csrf_token"
+
+	// CSRFHeaderName is the name of the request header with the CSRF token, htmx requests send it through hx-headers
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+const csrfTokenLength = 32
+
+type csrfKey struct{}
+
+// NewCSRFToken returns a new random CSRF token
+func NewCSRFToken() (string, error) {
+	b := make([]byte, csrfTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// WithCSRFToken returns a context with the CSRF token of the request
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfKey{}, token)
+}
+
+// CSRFToken returns the CSRF token of the context, or an empty string when it has none
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfKey{}).(string)
+	return token
+}
+
+// MaskCSRFToken returns the token xor'ed with a random pad, prefixed by the pad. The masked token is different
+// for every response, so a compressed response doesn't leak the token (BREACH).
+func MaskCSRFToken(token string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+
+	masked := make([]byte, 2*len(raw))
+	if _, err := rand.Read(masked[:len(raw)]); err != nil {
+		return "", err
+	}
+
+	for i, b := range raw {
+		masked[len(raw)+i] = b ^ masked[i]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(masked), nil
+}
+
+// VerifyCSRFToken returns true if the submitted token, masked or not, matches the token
+func VerifyCSRFToken(token, submitted string) bool {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) == 0 {
+		return false
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(submitted)
+	if err != nil {
+		return false
+	}
+
+	if len(got) == 2*len(raw) {
+		pad, masked := got[:len(raw)], got[len(raw):]
+		for i := range masked {
+			masked[i] ^= pad[i]
+		}
+		got = masked
+	}
+
+	return subtle.ConstantTimeCompare(raw, got) == 1
+}
+
+// maskedCSRFToken returns the masked CSRF token of the render context
+func maskedCSRFToken(ctx context.Context) (string, error) {
+	token := CSRFToken(ctx)
+	if token == "" {
+		return "", errors.New("no CSRF token in the context, is the CSRF middleware installed?")
+	}
+
+	return MaskCSRFToken(token)
+}
+
+// csrfTokenFunc is the csrfToken template function
+//
+//	<meta name="csrf-token" content="{{ csrfToken }}">
+func csrfTokenFunc(ctx context.Context, _ ...any) (any, error) {
+	return maskedCSRFToken(ctx)
+}
+
+// csrfFieldFunc is the csrfField template function, it returns the hidden input with the CSRF token
+//
+//	<form method="post">{{ csrfField }}</form>
+func csrfFieldFunc(ctx context.Context, _ ...any) (any, error) {
+	token, err := maskedCSRFToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		template.HTMLEscapeString(CSRFFieldName), token)), nil
+}
+
+// csrfHeadersFunc is the csrfHeaders template function, it returns the hx-headers value that makes every htmx
+// request below the element carry the CSRF token
+//
+//	<body hx-headers='{{ csrfHeaders }}'>
+func csrfHeadersFunc(ctx context.Context, _ ...any) (any, error) {
+	token, err := maskedCSRFToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := json.Marshal(map[string]string{CSRFHeaderName: token})
+	if err != nil {
+		return nil, err
+	}
+
+	return string(headers), nil
+}
@@ -0,0 +1,71 @@
+package htmx
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCSRFToken(t *testing.T) {
+	token, err := NewCSRFToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := MaskCSRFToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := MaskCSRFToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == second {
+		t.Error("expected a different masked token for every call")
+	}
+
+	for _, submitted := range []string{token, first, second} {
+		if !VerifyCSRFToken(token, submitted) {
+			t.Errorf("expected %q to verify", submitted)
+		}
+	}
+
+	other, _ := NewCSRFToken()
+	for _, submitted := range []string{other, "", "not base64!", token[:10]} {
+		if VerifyCSRFToken(token, submitted) {
+			t.Errorf("expected %q to fail", submitted)
+		}
+	}
+}
+
+func TestCSRFTemplateFuncs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"form.html": {Data: []byte(`<body hx-headers='{{ csrfHeaders }}'><form>{{ csrfField }}</form></body>`)},
+	}
+
+	token, _ := NewCSRFToken()
+	out, err := NewComponent("form.html").FS(fsys).Render(WithCSRFToken(context.Background(), token))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match := regexp.MustCompile(`hx-headers='\{&#34;X-CSRF-Token&#34;:&#34;([^&]+)&#34;\}'><form><input type="hidden" name="csrf_token" value="([^"]+)">`).
+		FindStringSubmatch(string(out))
+	if match == nil {
+		t.Fatalf("unexpected output %s", out)
+	}
+
+	for _, submitted := range match[1:] {
+		if !VerifyCSRFToken(token, html.UnescapeString(submitted)) {
+			t.Errorf("expected %q to verify", submitted)
+		}
+	}
+
+	if _, err := NewComponent("form.html").FS(fsys).Render(context.Background()); err == nil {
+		t.Error("expected an error without a CSRF token")
+	}
+}
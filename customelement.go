@@ -0,0 +1,133 @@
+package htmx
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ShadowRootMode is the mode of the declarative shadow root of a custom element, see CustomElement
+type ShadowRootMode string
+
+const (
+	ShadowRootNone   ShadowRootMode = ""       // the output is the light DOM of the element
+	ShadowRootOpen   ShadowRootMode = "open"   // the output is rendered into an open shadow root
+	ShadowRootClosed ShadowRootMode = "closed" // the output is rendered into a closed shadow root
+)
+
+var (
+	// customElementName matches valid custom element names: lowercase, starting with a letter and with a hyphen
+	customElementName = regexp.MustCompile(`^[a-z][-._0-9a-z]*-[-._0-9a-z]*$`)
+
+	// reservedElementNames are the names with a hyphen that belong to SVG and MathML
+	reservedElementNames = []string{
+		"annotation-xml", "color-profile", "font-face", "font-face-src", "font-face-uri", "font-face-format",
+		"font-face-name", "missing-glyph",
+	}
+)
+
+// customElement is the custom element shell a component is rendered in, see CustomElement
+type customElement struct {
+	tag    string
+	shadow ShadowRootMode
+	keys   []string
+}
+
+// CustomElement renders the component wrapped in a custom element with the tag, so the fragment can be consumed by
+// pages without htmx, e.g. as a web component. The data of the keys is written as data attributes of the element,
+// UserID as data-user-id, strings as they are and other values as JSON. Keys without data are left out. With a shadow
+// root mode the output is rendered into a declarative shadow root, which isolates its styles from the page.
+//
+//	c.CustomElement("user-card", htmx.ShadowRootOpen, "ID", "Name").AddData("ID", 7).AddData("Name", "Ada")
+//
+//	<user-card data-id="7" data-name="Ada"><template shadowrootmode="open">...</template></user-card>
+//
+// Browsers only attach declarative shadow roots while parsing a document, fragments that are inserted with script
+// need setHTMLUnsafe instead of innerHTML.
+func (c *Component) CustomElement(tag string, shadow ShadowRootMode, keys ...string) *Component {
+	c.customElement = &customElement{tag: tag, shadow: shadow, keys: keys}
+	return c
+}
+
+// wrapCustomElement returns the output in the buffer wrapped in the custom element of the component, the buffer is
+// returned to the pool unless the component has no custom element
+func (c *Component) wrapCustomElement(buf *bytes.Buffer, data map[string]any) (*bytes.Buffer, error) {
+	if c.customElement == nil {
+		return buf, nil
+	}
+
+	open, err := c.customElement.open(data)
+	if err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+
+	wrapped := getBuffer()
+	wrapped.WriteString(open)
+	wrapped.Write(buf.Bytes())
+	if c.customElement.shadow != ShadowRootNone {
+		wrapped.WriteString("</template>")
+	}
+	wrapped.WriteString("</" + c.customElement.tag + ">")
+	putBuffer(buf)
+
+	return wrapped, nil
+}
+
+// open returns the start tag of the element with its data attributes, and the start of its shadow root
+func (e *customElement) open(data map[string]any) (string, error) {
+	if !customElementName.MatchString(e.tag) || slices.Contains(reservedElementNames, e.tag) {
+		return "", fmt.Errorf("invalid custom element name %q", e.tag)
+	}
+
+	switch e.shadow {
+	case ShadowRootNone, ShadowRootOpen, ShadowRootClosed:
+	default:
+		return "", fmt.Errorf("invalid shadow root mode %q", e.shadow)
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + e.tag)
+	for _, key := range e.keys {
+		value, ok := data[key]
+		if !ok || value == nil {
+			continue
+		}
+
+		s, err := dataAttributeValue(value)
+		if err != nil {
+			return "", fmt.Errorf("custom element %s: %s: %w", e.tag, key, err)
+		}
+		name := strings.ToLower(strings.Join(splitWords(key), "-"))
+		b.WriteString(` data-` + name + `="` + template.HTMLEscapeString(s) + `"`)
+	}
+	b.WriteString(">")
+
+	if e.shadow != ShadowRootNone {
+		b.WriteString(`<template shadowrootmode="` + string(e.shadow) + `">`)
+	}
+
+	return b.String(), nil
+}
+
+// dataAttributeValue returns strings and scalars as they are and other values as JSON
+func dataAttributeValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	}
+
+	payload, err := marshalJSONKeys(value, JSONKeyCase)
+	if err != nil {
+		return "", err
+	}
+
+	return string(payload), nil
+}
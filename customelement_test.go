@@ -0,0 +1,63 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCustomElement(t *testing.T) {
+	fsys := fstest.MapFS{
+		"custom-element.html": {Data: []byte(`<p>{{ .Data.Name }}</p>`)},
+	}
+
+	tests := []struct {
+		name   string
+		shadow ShadowRootMode
+		keys   []string
+		want   string
+	}{
+		{
+			name: "light DOM",
+			keys: []string{"UserID", "Name", "Missing"},
+			want: `<user-card data-user-id="7" data-name="Ada &amp; &#34;Grace&#34;"><p>Ada &amp; &#34;Grace&#34;</p></user-card>`,
+		},
+		{
+			name:   "shadow root",
+			shadow: ShadowRootOpen,
+			keys:   []string{"Tags"},
+			want:   `<user-card data-tags="[&#34;admin&#34;]"><template shadowrootmode="open"><p>Ada &amp; &#34;Grace&#34;</p></template></user-card>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComponent("custom-element.html").FS(fsys).CustomElement("user-card", tt.shadow, tt.keys...)
+			out, err := c.AddData("UserID", 7).AddData("Name", `Ada & "Grace"`).AddData("Tags", []string{"admin"}).
+				Render(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.want, string(out))
+		})
+	}
+}
+
+func TestCustomElementInvalid(t *testing.T) {
+	fsys := fstest.MapFS{
+		"custom-element.html": {Data: []byte(`<p></p>`)},
+	}
+
+	for _, tag := range []string{"card", "User-card", "font-face", "user-card><script"} {
+		_, err := NewComponent("custom-element.html").FS(fsys).CustomElement(tag, ShadowRootNone).Render(context.Background())
+		if err == nil {
+			t.Errorf("%s: expected an error", tag)
+		}
+	}
+
+	_, err := NewComponent("custom-element.html").FS(fsys).CustomElement("user-card", "none").Render(context.Background())
+	if err == nil {
+		t.Error("expected an error for the shadow root mode")
+	}
+}
@@ -0,0 +1,191 @@
+package htmx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jkc-2/go-htmx/sse"
+)
+
+// DefaultDeferredTimeout is the time a deferred loader may take, and the time a rendered fragment waits for its client
+var DefaultDeferredTimeout = 30 * time.Second
+
+type (
+	// DeferredLoader loads the data of a deferred partial, it runs after the page was rendered
+	DeferredLoader func(ctx context.Context) (map[string]any, error)
+
+	// DeferredStream renders deferred partials in the background and pushes them over sse, mount it on the path given
+	// to NewDeferredStream.
+	//
+	//	stream := htmx.NewDeferredStream("/deferred")
+	//	mux.Handle("GET /deferred", stream)
+	//
+	//	page.With(stream.Defer(htmx.NewComponent("stats.html"), loadStats, "<p>Loading…</p>"), "Stats")
+	//
+	// The page needs the htmx sse extension.
+	DeferredStream struct {
+		path    string
+		timeout time.Duration
+
+		mu      sync.Mutex
+		pending map[string]*deferredFragment
+	}
+
+	// deferredFragment is a deferred partial that is loaded and rendered in the background
+	deferredFragment struct {
+		done   chan struct{}
+		output template.HTML
+	}
+
+	// deferred is a partial that renders a placeholder, the wrapped component is rendered by the stream
+	deferred struct {
+		RenderableComponent
+
+		stream      *DeferredStream
+		loader      DeferredLoader
+		placeholder template.HTML
+	}
+)
+
+// NewDeferredStream returns a stream for deferred partials that is served on path
+func NewDeferredStream(path string) *DeferredStream {
+	return &DeferredStream{
+		path:    path,
+		timeout: DefaultDeferredTimeout,
+		pending: make(map[string]*deferredFragment),
+	}
+}
+
+// Timeout sets the time a deferred loader may take, and the time a rendered fragment waits for its client
+func (s *DeferredStream) Timeout(timeout time.Duration) *DeferredStream {
+	s.timeout = timeout
+	return s
+}
+
+// Defer marks the component as deferred: the parent renders the placeholder, while the loader runs in the background.
+// Once the loader resolves, the component is rendered with the loaded data and pushed to the placeholder as an
+// out-of-band swap. A failing loader or render is replaced by DefaultErrorFallback and reported to OnPartialError.
+func (s *DeferredStream) Defer(c RenderableComponent, loader DeferredLoader, placeholder template.HTML) RenderableComponent {
+	return &deferred{
+		RenderableComponent: c,
+		stream:              s,
+		loader:              loader,
+		placeholder:         placeholder,
+	}
+}
+
+// Render starts loading the partial and returns the placeholder
+func (d *deferred) Render(ctx context.Context) (template.HTML, error) {
+	if err := renderCanceled(ctx); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	id := "htmx-deferred-" + token
+
+	fragment := &deferredFragment{done: make(chan struct{})}
+	d.stream.mu.Lock()
+	d.stream.pending[token] = fragment
+	d.stream.mu.Unlock()
+
+	go d.stream.load(detachRender(context.WithoutCancel(ctx)), token, id, fragment, d.RenderableComponent, d.loader)
+
+	return template.HTML(fmt.Sprintf(`<div id="%s" hx-ext="sse" sse-connect="%s?id=%s" sse-swap="deferred" hx-swap="none">%s</div>`,
+		id, template.HTMLEscapeString(d.stream.path), token, d.placeholder)), nil
+}
+
+// load runs the loader, renders the component and keeps the fragment until its client received it or the timeout passed
+func (s *DeferredStream) load(ctx context.Context, token, id string, fragment *deferredFragment, c RenderableComponent, loader DeferredLoader) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	output, err := func() (template.HTML, error) {
+		if loader != nil {
+			data, err := loader(ctx)
+			if err != nil {
+				return "", err
+			}
+
+			for key, value := range data {
+				c.AddData(key, value)
+			}
+		}
+
+		return c.Render(ctx)
+	}()
+
+	if err != nil {
+		if OnPartialError != nil {
+			OnPartialError(ctx, id, err)
+		}
+		output = DefaultErrorFallback(ctx, id, err)
+	}
+
+	fragment.output = template.HTML(`<div id="` + id + `" hx-swap-oob="outerHTML">` + string(output) + `</div>`)
+	close(fragment.done)
+
+	time.AfterFunc(s.timeout, func() { s.claim(token) })
+}
+
+// claim removes and returns the pending fragment of the token
+func (s *DeferredStream) claim(token string) *deferredFragment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fragment, ok := s.pending[token]
+	if !ok {
+		return nil
+	}
+
+	delete(s.pending, token)
+	return fragment
+}
+
+// ServeHTTP pushes the deferred fragment of the id query parameter once it was rendered. Unknown or already delivered
+// fragments are answered with 204 No Content, which stops the client from reconnecting.
+func (s *DeferredStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fragment := s.claim(r.URL.Query().Get("id"))
+	if fragment == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	select {
+	case <-fragment.done:
+	case <-r.Context().Done():
+		// keep the fragment for the reconnecting client
+		s.mu.Lock()
+		s.pending[r.URL.Query().Get("id")] = fragment
+		s.mu.Unlock()
+		return
+	}
+
+	msg := sse.NewMessage(string(fragment.output))
+	msg.Event = "deferred"
+	if _, err := io.WriteString(w, msg.String()); err != nil {
+		return
+	}
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,75 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDeferred(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html":  {Data: []byte(`<main>{{ .Partials.stats }}</main>`)},
+		"stats.html": {Data: []byte("<p>{{ .Data.Visits }}</p>\n<p>visits</p>")},
+	}
+
+	stream := NewDeferredStream("/deferred")
+	release := make(chan struct{})
+
+	page := NewComponent("page.html").FS(fsys)
+	page.With(stream.Defer(NewComponent("stats.html").FS(fsys), func(ctx context.Context) (map[string]any, error) {
+		<-release
+		return map[string]any{"Visits": 42}, nil
+	}, "<p>Loading…</p>"), "stats")
+
+	output, err := page.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match := regexp.MustCompile(`<div id="(htmx-deferred-[0-9a-f]+)" hx-ext="sse" sse-connect="/deferred\?id=([0-9a-f]+)" sse-swap="deferred" hx-swap="none"><p>Loading…</p></div>`).
+		FindStringSubmatch(string(output))
+	if match == nil {
+		t.Fatalf("expected a placeholder, got %s", output)
+	}
+
+	close(release)
+
+	w := httptest.NewRecorder()
+	stream.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deferred?id="+match[2], nil))
+
+	equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	equal(t, "event: deferred\ndata: <div id=\""+match[1]+"\" hx-swap-oob=\"outerHTML\"><p>42</p>\ndata: <p>visits</p></div>\n\n", w.Body.String())
+
+	// delivered fragments stop the client from reconnecting
+	w = httptest.NewRecorder()
+	stream.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deferred?id="+match[2], nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+}
+
+func TestDeferredError(t *testing.T) {
+	fsys := fstest.MapFS{"stats.html": {Data: []byte(`stats`)}}
+
+	stream := NewDeferredStream("/deferred")
+	output, err := stream.Defer(NewComponent("stats.html").FS(fsys), func(ctx context.Context) (map[string]any, error) {
+		return nil, errors.New("database down")
+	}, "").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := regexp.MustCompile(`id=([0-9a-f]+)`).FindStringSubmatch(string(output))[1]
+
+	w := httptest.NewRecorder()
+	stream.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/deferred?id="+token, nil))
+
+	if !strings.Contains(w.Body.String(), `class="htmx-error"`) {
+		t.Errorf("expected the error fallback, got %s", w.Body.String())
+	}
+}
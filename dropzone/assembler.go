@@ -0,0 +1,104 @@
+package dropzone
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrOffsetMismatch is returned when a chunk doesn't start where the previous chunk ended
+var ErrOffsetMismatch = errors.New("dropzone: chunk offset mismatch")
+
+type (
+	// Assembler stores the chunks of an upload and assembles them into the final file
+	Assembler interface {
+		// Create prepares the storage of a new upload
+		Create(ctx context.Context, upload Upload) error
+		// Offset returns the number of bytes that have been stored for the upload
+		Offset(ctx context.Context, upload Upload) (int64, error)
+		// WriteChunk stores the chunk at the offset, ErrOffsetMismatch is returned when the offset is not the current offset
+		WriteChunk(ctx context.Context, upload Upload, offset int64, chunk io.Reader) (int64, error)
+		// Complete is called when all chunks of the upload have been stored
+		Complete(ctx context.Context, upload Upload) error
+	}
+
+	// DiskAssembler assembles uploads in a directory, incomplete uploads are stored with a .part extension
+	DiskAssembler struct {
+		dir string
+	}
+)
+
+// NewDiskAssembler returns an assembler storing uploads in the directory
+func NewDiskAssembler(dir string) *DiskAssembler {
+	return &DiskAssembler{dir: dir}
+}
+
+// Path returns the path of a completed upload
+func (a *DiskAssembler) Path(upload Upload) string {
+	return filepath.Join(a.dir, upload.ID)
+}
+
+// Create creates the partial file of the upload
+func (a *DiskAssembler) Create(_ context.Context, upload Upload) error {
+	f, err := os.OpenFile(a.partPath(upload), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// Offset returns the size of the partial file
+func (a *DiskAssembler) Offset(_ context.Context, upload Upload) (int64, error) {
+	info, err := os.Stat(a.partPath(upload))
+	if errors.Is(err, os.ErrNotExist) {
+		// the upload has been completed
+		if info, err := os.Stat(a.Path(upload)); err == nil {
+			return info.Size(), nil
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// WriteChunk appends the chunk to the partial file
+func (a *DiskAssembler) WriteChunk(_ context.Context, upload Upload, offset int64, chunk io.Reader) (int64, error) {
+	f, err := os.OpenFile(a.partPath(upload), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if info.Size() != offset {
+		return 0, ErrOffsetMismatch
+	}
+
+	n, err := io.Copy(f, chunk)
+	if err != nil {
+		// drop the partially written chunk so the client can retry it
+		_ = f.Truncate(offset)
+		return 0, err
+	}
+
+	return n, f.Close()
+}
+
+// Complete moves the partial file to its final path
+func (a *DiskAssembler) Complete(_ context.Context, upload Upload) error {
+	return os.Rename(a.partPath(upload), a.Path(upload))
+}
+
+// partPath returns the path of the partial file of the upload
+func (a *DiskAssembler) partPath(upload Upload) string {
+	return a.Path(upload) + ".part"
+}
@@ -0,0 +1,354 @@
+// Package dropzone provides a drag-and-drop file upload component with chunked, resumable uploads.
+//
+// The browser splits files into chunks and uploads them one by one with a signed upload token, an interrupted upload
+// continues where it stopped, even after a reload of the page. The progress of every file is swapped out-of-band into
+// the file list of the dropzone.
+package dropzone
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const (
+	// HeaderToken is the request header carrying the signed upload token
+	HeaderToken = "X-Upload-Token"
+
+	// HeaderOffset is the request header carrying the offset of a chunk
+	HeaderOffset = "X-Upload-Offset"
+)
+
+type (
+	// Options configures a Dropzone
+	Options struct {
+		Endpoint   string        // path the dropzone handler is mounted on, e.g. /uploads
+		ChunkSize  int64         // size of the uploaded chunks, defaults to 1MiB
+		Accept     []string      // accepted content types and extensions, validated and used for the file input
+		Validators []Validator   // additional validation of the uploaded files
+		TokenTTL   time.Duration // time an upload can be resumed, defaults to 24 hours
+
+		// OnComplete is called after the assembler completed an upload, e.g. to store a reference to the file
+		OnComplete func(ctx context.Context, upload Upload) error
+	}
+
+	// Upload is the state of an upload, it is signed and handed to the browser as upload token
+	Upload struct {
+		ID          string `json:"id"`
+		Target      string `json:"target"` // id of the dropzone element
+		Name        string `json:"name"`
+		Size        int64  `json:"size"`
+		ContentType string `json:"type"`
+		Expires     int64  `json:"exp"`
+	}
+
+	// Dropzone is the http handler and component of a dropzone
+	Dropzone struct {
+		opts      Options
+		signer    *htmx.Signer
+		assembler Assembler
+		mux       *http.ServeMux
+	}
+
+	// status is the response to every request of the upload script
+	status struct {
+		Token     string `json:"token,omitempty"`
+		Offset    int64  `json:"offset"`
+		ChunkSize int64  `json:"chunkSize"`
+		Complete  bool   `json:"complete,omitempty"`
+		Error     string `json:"error,omitempty"`
+		HTML      string `json:"html,omitempty"`
+	}
+
+	// startRequest is the body of the request starting an upload
+	startRequest struct {
+		Target string `json:"target"`
+		Name   string `json:"name"`
+		Size   int64  `json:"size"`
+		Type   string `json:"type"`
+	}
+)
+
+// New returns a dropzone that signs its upload tokens with a signer derived for the endpoint and stores the uploads with the assembler
+func New(signer *htmx.Signer, assembler Assembler, opts Options) *Dropzone {
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 1 << 20
+	}
+
+	if opts.TokenTTL <= 0 {
+		opts.TokenTTL = 24 * time.Hour
+	}
+
+	if len(opts.Accept) > 0 {
+		opts.Validators = append([]Validator{Accept(opts.Accept...)}, opts.Validators...)
+	}
+
+	d := &Dropzone{
+		opts:      opts,
+		signer:    signer.Derive("dropzone/upload" + opts.Endpoint),
+		assembler: assembler,
+		mux:       http.NewServeMux(),
+	}
+
+	d.mux.HandleFunc("POST "+opts.Endpoint+"/start", d.start)
+	d.mux.HandleFunc("POST "+opts.Endpoint+"/status", d.status)
+	d.mux.HandleFunc("PUT "+opts.Endpoint+"/chunk", d.chunk)
+
+	return d
+}
+
+// ServeHTTP handles the requests of the upload script, mount it on the endpoint and all paths below it
+//
+//	mux.Handle("/uploads/", dz)
+func (d *Dropzone) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+// Component returns the dropzone component, id is the id of the dropzone element
+func (d *Dropzone) Component(id string) htmx.RenderableComponent {
+	return htmx.NewComponent("templates/dropzone.html").
+		FS(templates).
+		AddData("ID", id).
+		AddData("Endpoint", d.opts.Endpoint).
+		AddData("Accept", strings.Join(d.opts.Accept, ","))
+}
+
+// start validates a new upload and prepares its storage
+func (d *Dropzone) start(w http.ResponseWriter, r *http.Request) {
+	var req startRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&req); err != nil || req.Target == "" {
+		d.respond(w, r, http.StatusBadRequest, status{Error: "invalid upload request"}, nil, false)
+		return
+	}
+
+	upload := Upload{
+		ID:          newID(),
+		Target:      req.Target,
+		Name:        req.Name,
+		Size:        req.Size,
+		ContentType: req.Type,
+		Expires:     time.Now().Add(d.opts.TokenTTL).Unix(),
+	}
+
+	if err := d.validate(upload, upload.ContentType); err != nil {
+		d.respond(w, r, http.StatusUnprocessableEntity, status{Error: err.Error()}, &upload, true)
+		return
+	}
+
+	if err := d.assembler.Create(r.Context(), upload); err != nil {
+		d.respond(w, r, http.StatusInternalServerError, status{Error: "upload could not be started"}, &upload, true)
+		return
+	}
+
+	d.progress(w, r, upload, 0, true)
+}
+
+// status returns the offset of an upload, so the browser can resume it
+func (d *Dropzone) status(w http.ResponseWriter, r *http.Request) {
+	upload, err := d.upload(r)
+	if err != nil {
+		// the browser starts a new upload, so no error is shown
+		writeJSON(w, http.StatusForbidden, status{Error: err.Error()})
+		return
+	}
+
+	offset, err := d.assembler.Offset(r.Context(), upload)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, status{Error: "upload not found"})
+		return
+	}
+
+	d.progress(w, r, upload, offset, true)
+}
+
+// chunk stores a chunk of an upload
+func (d *Dropzone) chunk(w http.ResponseWriter, r *http.Request) {
+	upload, err := d.upload(r)
+	if err != nil {
+		d.respond(w, r, http.StatusForbidden, status{Error: err.Error()}, nil, false)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get(HeaderOffset), 10, 64)
+	if err != nil || offset < 0 || offset >= upload.Size {
+		d.respond(w, r, http.StatusBadRequest, status{Error: "invalid chunk offset"}, &upload, false)
+		return
+	}
+
+	var body io.Reader = io.LimitReader(r.Body, min(d.opts.ChunkSize, upload.Size-offset))
+
+	if offset == 0 {
+		// the content type reported by the browser is based on the extension, validate the sniffed type as well
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(body, head)
+		head = head[:n]
+
+		if err := d.validate(upload, sniff(head, upload.ContentType)); err != nil {
+			d.respond(w, r, http.StatusUnprocessableEntity, status{Error: err.Error()}, &upload, false)
+			return
+		}
+
+		body = io.MultiReader(bytes.NewReader(head), body)
+	}
+
+	n, err := d.assembler.WriteChunk(r.Context(), upload, offset, body)
+	if errors.Is(err, ErrOffsetMismatch) {
+		// let the browser continue from the stored offset
+		if offset, err = d.assembler.Offset(r.Context(), upload); err == nil {
+			d.progress(w, r, upload, offset, false)
+			return
+		}
+	}
+	if err != nil {
+		d.respond(w, r, http.StatusInternalServerError, status{Error: "chunk could not be stored"}, &upload, false)
+		return
+	}
+
+	if offset+n >= upload.Size {
+		if err := d.complete(r.Context(), upload); err != nil {
+			d.respond(w, r, http.StatusInternalServerError, status{Error: err.Error()}, &upload, false)
+			return
+		}
+	}
+
+	d.progress(w, r, upload, offset+n, false)
+}
+
+// progress responds with the progress of the upload, a new token is handed out until the upload is complete
+func (d *Dropzone) progress(w http.ResponseWriter, r *http.Request, upload Upload, offset int64, insert bool) {
+	st := status{Offset: offset, ChunkSize: d.opts.ChunkSize, Complete: offset >= upload.Size}
+
+	if !st.Complete {
+		token, err := d.signer.SignJSON(upload)
+		if err != nil {
+			d.respond(w, r, http.StatusInternalServerError, status{Error: "upload token could not be signed"}, &upload, insert)
+			return
+		}
+		st.Token = token
+	}
+
+	d.respond(w, r, http.StatusOK, st, &upload, insert)
+}
+
+// complete assembles the upload after its last chunk has been stored
+func (d *Dropzone) complete(ctx context.Context, upload Upload) error {
+	if err := d.assembler.Complete(ctx, upload); err != nil {
+		return errors.New("upload could not be completed")
+	}
+
+	if d.opts.OnComplete != nil {
+		return d.opts.OnComplete(ctx, upload)
+	}
+
+	return nil
+}
+
+// respond writes the status with the progress fragment of the upload
+func (d *Dropzone) respond(w http.ResponseWriter, r *http.Request, code int, st status, upload *Upload, insert bool) {
+	if upload != nil {
+		fragment, err := d.fragment(r.Context(), *upload, st, insert)
+		if err != nil {
+			code, st.Error = http.StatusInternalServerError, err.Error()
+		}
+		st.HTML = string(fragment)
+	}
+
+	writeJSON(w, code, st)
+}
+
+// fragment renders the progress of the upload as an out-of-band swap, the fragment of a new upload is appended to the file list
+func (d *Dropzone) fragment(ctx context.Context, upload Upload, st status, insert bool) (template.HTML, error) {
+	percent := int64(100)
+	if upload.Size > 0 {
+		percent = st.Offset * 100 / upload.Size
+	}
+
+	return htmx.NewComponent("templates/file.html").
+		FS(templates).
+		SetData(map[string]any{
+			"Insert":    insert,
+			"List":      upload.Target + "-files",
+			"ElementID": upload.Target + "-" + upload.ID,
+			"Name":      upload.Name,
+			"Size":      upload.Size,
+			"Offset":    st.Offset,
+			"Percent":   percent,
+			"Complete":  st.Complete,
+			"Error":     st.Error,
+		}).
+		Render(ctx)
+}
+
+// upload returns the verified upload of the request token
+func (d *Dropzone) upload(r *http.Request) (Upload, error) {
+	var upload Upload
+	if err := d.signer.VerifyJSON(r.Header.Get(HeaderToken), &upload); err != nil {
+		return upload, errors.New("invalid upload token")
+	}
+
+	if time.Now().Unix() > upload.Expires {
+		return upload, errors.New("upload token expired")
+	}
+
+	return upload, nil
+}
+
+// validate runs the validators for the upload with the given content type
+func (d *Dropzone) validate(upload Upload, contentType string) error {
+	f := File{Name: upload.Name, Size: upload.Size, ContentType: contentType}
+
+	if f.Size <= 0 {
+		return errors.New("file is empty")
+	}
+
+	for _, v := range d.opts.Validators {
+		if err := v(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sniff returns the content type detected from the head of the file,
+// the reported content type is kept when the detected type is generic
+func sniff(head []byte, reported string) string {
+	detected := http.DetectContentType(head)
+	if detected == "application/octet-stream" || strings.HasPrefix(detected, "text/plain") {
+		return reported
+	}
+
+	return detected
+}
+
+// writeJSON writes the status as json
+func writeJSON(w http.ResponseWriter, code int, st status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(st)
+}
+
+// newID returns a random upload id
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,111 @@
+package dropzone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestDropzone(t *testing.T) {
+	assembler := NewDiskAssembler(t.TempDir())
+
+	var completed Upload
+	dz := New(htmx.NewSigner([]byte("secret")), assembler, Options{
+		Endpoint:   "/uploads",
+		ChunkSize:  4,
+		Accept:     []string{"text/*"},
+		Validators: []Validator{MaxSize(10)},
+		OnComplete: func(_ context.Context, upload Upload) error {
+			completed = upload
+			return nil
+		},
+	})
+
+	do := func(method, path, token string, offset int64, body string) (int, status) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			r.Header.Set(HeaderToken, token)
+			r.Header.Set(HeaderOffset, strconv.FormatInt(offset, 10))
+		}
+
+		w := httptest.NewRecorder()
+		dz.ServeHTTP(w, r)
+
+		var st status
+		if err := json.NewDecoder(w.Body).Decode(&st); err != nil {
+			t.Fatal(err)
+		}
+
+		return w.Code, st
+	}
+
+	code, st := do(http.MethodPost, "/uploads/start", "", 0, `{"target":"dz","name":"big.txt","size":11,"type":"text/plain"}`)
+	if code != http.StatusUnprocessableEntity || st.Error == "" {
+		t.Fatalf("expected the file to be rejected, got %d %+v", code, st)
+	}
+
+	code, st = do(http.MethodPost, "/uploads/start", "", 0, `{"target":"dz","name":"a.txt","size":10,"type":"text/plain"}`)
+	if code != http.StatusOK || st.Token == "" || !strings.Contains(st.HTML, `hx-swap-oob="beforeend:#dz-files"`) {
+		t.Fatalf("unexpected start response %d %+v", code, st)
+	}
+
+	_, st = do(http.MethodPut, "/uploads/chunk", st.Token, 0, "0123")
+	if st.Offset != 4 || !strings.Contains(st.HTML, `<progress max="10" value="4">40%</progress>`) {
+		t.Fatalf("unexpected chunk response %+v", st)
+	}
+
+	// resuming returns the stored offset
+	_, st = do(http.MethodPost, "/uploads/status", st.Token, 0, "")
+	if st.Offset != 4 {
+		t.Fatalf("expected offset 4, got %+v", st)
+	}
+
+	// a chunk at the wrong offset continues from the stored offset
+	_, st = do(http.MethodPut, "/uploads/chunk", st.Token, 0, "0123")
+	if st.Offset != 4 {
+		t.Fatalf("expected offset 4, got %+v", st)
+	}
+
+	_, st = do(http.MethodPut, "/uploads/chunk", st.Token, 4, "4567")
+	_, st = do(http.MethodPut, "/uploads/chunk", st.Token, 8, "89")
+	if !st.Complete || st.Token != "" {
+		t.Fatalf("expected the upload to be complete, got %+v", st)
+	}
+
+	b, err := os.ReadFile(assembler.Path(completed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(b, []byte("0123456789")) {
+		t.Errorf("unexpected file content %q", b)
+	}
+
+	code, _ = do(http.MethodPut, "/uploads/chunk", "invalid", 0, "0123")
+	if code != http.StatusForbidden {
+		t.Errorf("expected invalid tokens to be rejected, got %d", code)
+	}
+}
+
+func TestDropzoneComponent(t *testing.T) {
+	dz := New(htmx.NewSigner([]byte("secret")), NewDiskAssembler(t.TempDir()), Options{Endpoint: "/uploads/", Accept: []string{"image/*", ".pdf"}})
+
+	out, err := dz.Component("dz").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`<div id="dz" class="dropzone" data-endpoint="/uploads">`, `accept="image/*,.pdf"`, `<div id="dz-files"`} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("expected %q in %s", expected, out)
+		}
+	}
+}
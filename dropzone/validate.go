@@ -0,0 +1,63 @@
+package dropzone
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+)
+
+type (
+	// File describes an uploaded file for validation
+	File struct {
+		Name        string
+		Size        int64
+		ContentType string // the content type reported by the browser, or sniffed from the first chunk
+	}
+
+	// Validator validates a file before and during the upload, the error message is shown to the user
+	Validator func(f File) error
+)
+
+// MaxSize rejects files larger than n bytes
+func MaxSize(n int64) Validator {
+	return func(f File) error {
+		if f.Size > n {
+			return fmt.Errorf("file is larger than %d bytes", n)
+		}
+		return nil
+	}
+}
+
+// Accept only allows files matching one of the patterns, a pattern is a content type like image/png,
+// a wildcard content type like image/* or a file extension like .pdf
+func Accept(patterns ...string) Validator {
+	return func(f File) error {
+		if accepted(f, patterns) {
+			return nil
+		}
+		return fmt.Errorf("file type %s is not allowed", f.ContentType)
+	}
+}
+
+// accepted returns true when the file matches one of the patterns
+func accepted(f File, patterns []string) bool {
+	contentType, _, _ := mime.ParseMediaType(f.ContentType)
+
+	for _, p := range patterns {
+		switch {
+		case strings.HasPrefix(p, "."):
+			if strings.EqualFold(path.Ext(f.Name), p) {
+				return true
+			}
+		case strings.HasSuffix(p, "/*"):
+			if strings.HasPrefix(contentType, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		case strings.EqualFold(contentType, p):
+			return true
+		}
+	}
+
+	return false
+}
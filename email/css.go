@@ -0,0 +1,203 @@
+package email
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jkc-2/go-htmx/internal/selector"
+)
+
+type (
+	// rule is a style rule of a stylesheet with a single selector, rules with a selector list are split
+	rule struct {
+		selector     *selector.Selector
+		specificity  [3]int // the ids, the classes and attributes, and the tags of the selector
+		declarations []declaration
+	}
+
+	// declaration is a property and its value
+	declaration struct {
+		property  string
+		value     string
+		important bool
+	}
+)
+
+// parseStylesheet parses the rules of the stylesheet that can be inlined and returns the rest, at-rules like @media
+// and rules with selectors that can't be matched against a single element like :hover, as CSS
+func parseStylesheet(css string) ([]rule, string, error) {
+	css = stripComments(css)
+
+	var (
+		rules []rule
+		rest  strings.Builder
+	)
+
+	for i := 0; i < len(css); {
+		open := strings.IndexByte(css[i:], '{')
+		if open < 0 {
+			if s := strings.TrimSpace(css[i:]); s != "" {
+				if !strings.HasPrefix(s, "@") {
+					return nil, "", fmt.Errorf("unexpected %q", s)
+				}
+				// statements like @import and @charset
+				rest.WriteString(s + "\n")
+			}
+			break
+		}
+
+		prelude := strings.TrimSpace(css[i : i+open])
+
+		// statements before the block, like @import "a.css"; h1 {}
+		for strings.HasPrefix(prelude, "@") {
+			end := strings.IndexByte(prelude, ';')
+			if end < 0 {
+				break
+			}
+			rest.WriteString(prelude[:end+1] + "\n")
+			prelude = strings.TrimSpace(prelude[end+1:])
+		}
+
+		end, err := blockEnd(css, i+open)
+		if err != nil {
+			return nil, "", err
+		}
+		block := css[i+open+1 : end]
+		i = end + 1
+
+		if strings.HasPrefix(prelude, "@") {
+			rest.WriteString(prelude + " {" + block + "}\n")
+			continue
+		}
+
+		declarations := parseDeclarations(block)
+		var kept []string
+		for _, sel := range strings.Split(prelude, ",") {
+			sel = strings.TrimSpace(sel)
+			if sel == "" {
+				continue
+			}
+
+			s, err := selector.Parse(sel)
+			if err != nil {
+				kept = append(kept, sel)
+				continue
+			}
+
+			rules = append(rules, rule{selector: s, specificity: s.Specificity(), declarations: declarations})
+		}
+
+		if len(kept) > 0 {
+			rest.WriteString(strings.Join(kept, ", ") + " {" + block + "}\n")
+		}
+	}
+
+	return rules, rest.String(), nil
+}
+
+// stripComments removes the comments of the stylesheet
+func stripComments(css string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			b.WriteString(css)
+			return b.String()
+		}
+
+		b.WriteString(css[:start])
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			return b.String()
+		}
+		css = css[start+2+end+2:]
+	}
+}
+
+// blockEnd returns the index of the brace that closes the block opened at the index
+func blockEnd(css string, open int) (int, error) {
+	depth := 0
+	quote := byte(0)
+
+	for i := open; i < len(css); i++ {
+		c := css[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unclosed block at %d", open)
+}
+
+// parseDeclarations parses the declarations of a block or a style attribute, semicolons within quotes and
+// parentheses like url(data:...) don't end a declaration
+func parseDeclarations(block string) []declaration {
+	var declarations []declaration
+
+	add := func(s string) {
+		property, value, ok := strings.Cut(s, ":")
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+		if !ok || property == "" || value == "" {
+			return
+		}
+
+		important := false
+		if i := strings.LastIndexByte(value, '!'); i >= 0 && strings.EqualFold(strings.TrimSpace(value[i+1:]), "important") {
+			important = true
+			value = strings.TrimSpace(value[:i])
+		}
+
+		declarations = append(declarations, declaration{property: property, value: value, important: important})
+	}
+
+	depth := 0
+	quote := byte(0)
+	start := 0
+	for i := 0; i < len(block); i++ {
+		c := block[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ';' && depth == 0:
+			add(block[start:i])
+			start = i + 1
+		}
+	}
+	add(block[start:])
+
+	return declarations
+}
+
+// formatDeclarations returns the declarations as the value of a style attribute
+func formatDeclarations(declarations []declaration) string {
+	parts := make([]string, len(declarations))
+	for i, d := range declarations {
+		parts[i] = d.property + ": " + d.value
+		if d.important {
+			parts[i] += " !important"
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
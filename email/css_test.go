@@ -0,0 +1,27 @@
+package email
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStylesheet(t *testing.T) {
+	rules, rest, err := parseStylesheet(`@import url("fonts.css");
+h1, .title:first-child { color: red; background: url("data:image/png;base64,AA==") }
+#footer p.note[lang="en"] > a { font-size: 12px !important }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, 2, len(rules))
+	equal(t, [3]int{0, 0, 1}, rules[0].specificity)
+	equal(t, `color: red; background: url("data:image/png;base64,AA==")`, formatDeclarations(rules[0].declarations))
+	equal(t, [3]int{1, 2, 2}, rules[1].specificity)
+	equal(t, true, rules[1].declarations[0].important)
+
+	equal(t, true, strings.HasPrefix(rest, `@import url("fonts.css");`))
+	equal(t, true, strings.Contains(rest, `.title:first-child {`))
+
+	_, _, err = parseStylesheet(`p { color: red`)
+	equal(t, true, err != nil)
+}
@@ -0,0 +1,243 @@
+// Package email renders components as emails. The templates of emails are components like the templates of pages:
+// the rendered html gets the rules of a stylesheet inlined into style attributes, as many email clients ignore
+// stylesheets, loses the elements email clients don't support, and is converted to a plain text version.
+//
+//	mailer, err := email.NewRenderer(css)
+//	...
+//	msg, err := mailer.Render(ctx, htmx.NewComponent("emails/welcome.html").AddData("User", user))
+//	...
+//	send(user.Email, msg.Subject, msg.HTML, msg.Text)
+package email
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jkc-2/go-htmx"
+	"github.com/jkc-2/go-htmx/internal/selector"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// UnsupportedTags are the elements that are removed from emails with their content, as email clients don't support
+// them or strip them
+var UnsupportedTags = []string{
+	"script", "noscript", "iframe", "object", "embed", "form", "input", "button", "select", "textarea", "video",
+	"audio", "canvas", "link", "base",
+}
+
+type (
+	// Renderer renders components as emails with the rules of its stylesheet inlined
+	Renderer struct {
+		rules []rule
+		rest  string
+	}
+
+	// Message is a rendered email
+	Message struct {
+		Subject string        // the text of the title element, if any
+		HTML    template.HTML // the html with the styles inlined
+		Text    string        // the plain text version of the html
+	}
+)
+
+// NewRenderer returns a renderer that inlines the rules of the stylesheet. Rules that can't be inlined, like @media
+// queries and :hover, are kept in a style element in the head of the email.
+func NewRenderer(stylesheet string) (*Renderer, error) {
+	rules, rest, err := parseStylesheet(stylesheet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{rules: rules, rest: rest}, nil
+}
+
+// Render renders the component as an email
+func (r *Renderer) Render(ctx context.Context, c htmx.RenderableComponent) (*Message, error) {
+	output, err := c.Render(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Convert(output)
+}
+
+// Convert converts rendered html to an email. The style elements of the html are inlined like the stylesheet of the
+// renderer, after it, and the attributes of htmx and event handlers are removed along with the UnsupportedTags.
+func (r *Renderer) Convert(output template.HTML) (*Message, error) {
+	doc, err := html.Parse(strings.NewReader(string(output)))
+	if err != nil {
+		return nil, err
+	}
+
+	rules, rest := r.rules, r.rest
+	var styles []*html.Node
+	selector.Walk(doc, func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Style {
+			styles = append(styles, n)
+		}
+	})
+
+	for _, style := range styles {
+		own, ownRest, err := parseStylesheet(selector.Text(style))
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(slices.Clip(rules), own...)
+		rest += ownRest
+		style.Parent.RemoveChild(style)
+	}
+
+	strip(doc)
+	inline(doc, rules)
+
+	if strings.TrimSpace(rest) != "" {
+		if head := find(doc, atom.Head); head != nil {
+			style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+			style.AppendChild(&html.Node{Type: html.TextNode, Data: rest})
+			head.AppendChild(style)
+		}
+	}
+
+	msg := &Message{Text: text(doc)}
+	if title := find(doc, atom.Title); title != nil {
+		msg.Subject = strings.Join(strings.Fields(selector.Text(title)), " ")
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // the html was rendered by html/template and re-serialized
+	msg.HTML = template.HTML(buf.String())
+
+	return msg, nil
+}
+
+// strip removes the unsupported elements, the htmx attributes and the event handler attributes
+func strip(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		if child.Type == html.ElementNode && slices.Contains(UnsupportedTags, child.Data) {
+			n.RemoveChild(child)
+		} else {
+			strip(child)
+		}
+
+		child = next
+	}
+
+	if n.Type == html.ElementNode {
+		n.Attr = slices.DeleteFunc(n.Attr, func(a html.Attribute) bool {
+			return strings.HasPrefix(a.Key, "hx-") || strings.HasPrefix(a.Key, "data-hx-") || strings.HasPrefix(a.Key, "on")
+		})
+	}
+}
+
+// inline sets the declarations of the matching rules as the style attribute of the elements. Declarations are applied
+// by importance, specificity and order, the style attribute of an element takes precedence over rules that aren't
+// important.
+func inline(doc *html.Node, rules []rule) {
+	type match struct {
+		declaration declaration
+		specificity [3]int
+		order       int
+		inline      bool
+	}
+
+	selector.Walk(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+
+		var matches []match
+		for i, r := range rules {
+			if !r.selector.Match(n) {
+				continue
+			}
+			for _, d := range r.declarations {
+				matches = append(matches, match{declaration: d, specificity: r.specificity, order: i})
+			}
+		}
+		if len(matches) == 0 {
+			return
+		}
+
+		style, _ := selector.Attribute(n, "style")
+		for _, d := range parseDeclarations(style) {
+			matches = append(matches, match{declaration: d, inline: true})
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			a, b := matches[i], matches[j]
+			if a.declaration.important != b.declaration.important {
+				return !a.declaration.important
+			}
+			if a.inline != b.inline {
+				return !a.inline
+			}
+			if a.specificity != b.specificity {
+				return less(a.specificity, b.specificity)
+			}
+			return a.order < b.order
+		})
+
+		var declarations []declaration
+		index := make(map[string]int)
+		for _, m := range matches {
+			if i, ok := index[m.declaration.property]; ok {
+				declarations[i] = m.declaration
+				continue
+			}
+			index[m.declaration.property] = len(declarations)
+			declarations = append(declarations, m.declaration)
+		}
+
+		setAttr(n, "style", formatDeclarations(declarations))
+	})
+}
+
+// less compares specificities
+func less(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return false
+}
+
+// find returns the first element with the tag
+func find(n *html.Node, tag atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == tag {
+		return n
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := find(child, tag); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// setAttr sets the attribute of the element
+func setAttr(n *html.Node, key, value string) {
+	for i, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
@@ -0,0 +1,76 @@
+package email
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+const stylesheet = `
+/* the base styles */
+p { color: #333; margin: 0 0 16px; }
+.button { background: #06c; color: #fff !important; }
+td.total, th { font-weight: bold; }
+a:hover { text-decoration: underline; }
+@media (max-width: 600px) { .wrapper { width: 100% !important; } }
+`
+
+func TestRender(t *testing.T) {
+	fsys := fstest.MapFS{
+		"welcome.html": {Data: []byte(`<!DOCTYPE html>
+<html><head><title>Welcome, {{ .Data.Name }}</title><style>h1 { font-size: 24px; }</style></head>
+<body><div class="wrapper">
+<h1>Hello {{ .Data.Name }}</h1>
+<p>Thanks for signing up.</p>
+<p style="color: red">Confirm your address:</p>
+<p><a class="button" href="https://example.com/confirm" style="color: black" hx-boost="true">Confirm</a></p>
+<script>alert(1)</script>
+<form><input name="q"></form>
+</div></body></html>`)},
+	}
+
+	r, err := NewRenderer(stylesheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := r.Render(context.Background(), htmx.NewComponent("welcome.html").FS(fsys).AddData("Name", "Ada"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(msg.HTML)
+	equal(t, "Welcome, Ada", msg.Subject)
+	equal(t, true, strings.Contains(out, `<h1 style="font-size: 24px">Hello Ada</h1>`))
+	equal(t, true, strings.Contains(out, `<p style="color: #333; margin: 0 0 16px">Thanks for signing up.</p>`))
+	equal(t, true, strings.Contains(out, `<p style="color: red; margin: 0 0 16px">Confirm your address:</p>`))
+	equal(t, true, strings.Contains(out, `<a class="button" href="https://example.com/confirm" style="background: #06c; color: #fff !important">`))
+	equal(t, true, strings.Contains(out, `<style>a:hover {`))
+	equal(t, true, strings.Contains(out, `@media (max-width: 600px) {`))
+	equal(t, false, strings.Contains(out, "<script"))
+	equal(t, false, strings.Contains(out, "<form"))
+	equal(t, false, strings.Contains(out, "hx-boost"))
+
+	equal(t, "Hello Ada\n\nThanks for signing up.\n\nConfirm your address:\n\nConfirm (https://example.com/confirm)", msg.Text)
+}
+
+func TestText(t *testing.T) {
+	text, err := Text(`<h2>Your   order</h2><ol><li>Tea</li><li>Cake <img alt="(photo)"></li></ol>` +
+		`<table><tr><th>Total</th><td>12 EUR</td></tr></table><p>Questions? <a href="mailto:help@example.com">Write us</a><br>Thanks</p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "Your order\n\n1. Tea\n2. Cake (photo)\n\nTotal 12 EUR\n\nQuestions? Write us (help@example.com)\nThanks", text)
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
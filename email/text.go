@@ -0,0 +1,177 @@
+package email
+
+import (
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jkc-2/go-htmx/internal/selector"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// blankLines matches runs of blank lines
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// Text returns the plain text version of the html: paragraphs and headings are separated by blank lines, list items
+// are prefixed with a dash or their number, links are followed by their url and images are replaced by their alt text
+func Text(output template.HTML) (string, error) {
+	doc, err := html.Parse(strings.NewReader(string(output)))
+	if err != nil {
+		return "", err
+	}
+
+	return text(doc), nil
+}
+
+// text returns the plain text version of the document
+func text(doc *html.Node) string {
+	w := &textWriter{}
+	w.node(doc)
+
+	lines := strings.Split(w.b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// textWriter writes the text of the nodes, collapsing whitespace like a browser
+type textWriter struct {
+	b     strings.Builder
+	space bool // whitespace is pending, it is written before the next text on the line
+	pre   int  // the depth of pre elements
+}
+
+// node writes the text of the node and its descendants
+func (w *textWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		w.children(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Head, atom.Style, atom.Script, atom.Title, atom.Template:
+	case atom.Br:
+		w.newline(1)
+	case atom.Hr:
+		w.newline(2)
+		w.b.WriteString("---")
+		w.newline(2)
+	case atom.Img:
+		if alt, _ := selector.Attribute(n, "alt"); alt != "" {
+			w.text(alt)
+		}
+	case atom.A:
+		w.children(n)
+		href, _ := selector.Attribute(n, "href")
+		if href != "" && !strings.HasPrefix(href, "#") && strings.TrimSpace(selector.Text(n)) != href {
+			w.text(" (" + strings.TrimPrefix(href, "mailto:") + ")")
+		}
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Blockquote, atom.Ul, atom.Ol, atom.Table:
+		w.newline(2)
+		w.children(n)
+		w.newline(2)
+	case atom.Li:
+		w.newline(1)
+		prefix := "- "
+		if n.Parent != nil && n.Parent.DataAtom == atom.Ol {
+			prefix = strconv.Itoa(position(n)) + ". "
+		}
+		w.b.WriteString(prefix)
+		w.children(n)
+		w.newline(1)
+	case atom.Pre:
+		w.newline(2)
+		w.pre++
+		w.children(n)
+		w.pre--
+		w.newline(2)
+	case atom.Td, atom.Th:
+		w.space = true
+		w.children(n)
+		w.space = true
+	case atom.Div, atom.Tr, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Main, atom.Nav, atom.Center:
+		w.newline(1)
+		w.children(n)
+		w.newline(1)
+	default:
+		w.children(n)
+	}
+}
+
+// children writes the text of the children of the node
+func (w *textWriter) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		w.node(child)
+	}
+}
+
+// text writes the text with its whitespace collapsed, outside of pre elements
+func (w *textWriter) text(s string) {
+	if w.pre > 0 {
+		w.b.WriteString(s)
+		return
+	}
+
+	if s != "" && isSpace(s[0]) {
+		w.space = true
+	}
+
+	fields := strings.Fields(s)
+	for i, field := range fields {
+		if (i > 0 || w.space) && !w.lineStart() {
+			w.b.WriteByte(' ')
+		}
+		w.b.WriteString(field)
+		w.space = false
+	}
+
+	if s != "" && isSpace(s[len(s)-1]) {
+		w.space = true
+	}
+}
+
+// newline ends the line, n is 2 for a blank line
+func (w *textWriter) newline(n int) {
+	w.space = false
+	if w.b.Len() == 0 {
+		return
+	}
+
+	s := w.b.String()
+	trailing := len(s) - len(strings.TrimRight(s, "\n"))
+	for i := trailing; i < n; i++ {
+		w.b.WriteByte('\n')
+	}
+}
+
+// lineStart returns true if nothing was written on the current line
+func (w *textWriter) lineStart() bool {
+	s := w.b.String()
+	return len(s) == 0 || s[len(s)-1] == '\n'
+}
+
+// position returns the position of the list item in its list, starting at 1
+func position(li *html.Node) int {
+	p := 1
+	for sibling := li.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
+		if sibling.Type == html.ElementNode && sibling.DataAtom == atom.Li {
+			p++
+		}
+	}
+
+	return p
+}
+
+// isSpace returns true for html whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
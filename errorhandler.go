@@ -0,0 +1,197 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"net/http"
+)
+
+//go:embed templates/error_fragment.html templates/error_page.html
+var errorTemplates embed.FS
+
+// DefaultErrorTarget is the CSS selector of the element the errors of htmx requests are swapped into
+var DefaultErrorTarget = "#errors"
+
+type (
+	// HTTPError is an error with the status of its response and the message that is shown to the user
+	HTTPError struct {
+		Status  int
+		Message string
+		Err     error
+	}
+
+	// ErrorComponentFunc returns the component that renders the error with its status and message
+	ErrorComponentFunc func(r *http.Request, status int, message string) RenderableComponent
+
+	// ErrorHandler writes errors as responses. htmx requests for a fragment get an error fragment that is retargeted
+	// into the error container of the page, other requests get a full error page.
+	//
+	//	errs := h.NewErrorHandler()
+	//	mux.Handle("GET /users/{id}", errs.Wrap(func(w http.ResponseWriter, r *http.Request) error {
+	//		user, err := users.Get(r.PathValue("id"))
+	//		if err != nil {
+	//			return htmx.NewHTTPError(http.StatusNotFound, "The user doesn't exist", err)
+	//		}
+	//		...
+	//	}))
+	//
+	// htmx doesn't swap responses with an error status by default, allow it with htmx.config.responseHandling in
+	// htmx 2 or the response-targets extension.
+	ErrorHandler struct {
+		htmx     *HTMX
+		target   string
+		swap     *Swap
+		fragment ErrorComponentFunc
+		page     ErrorComponentFunc
+	}
+
+	// statusWriter writes the status right before the first write of the body
+	statusWriter struct {
+		http.ResponseWriter
+		status int
+		wrote  bool
+	}
+)
+
+// NewHTTPError returns an error with the status and the message that is shown to the user
+func NewHTTPError(status int, message string, err error) *HTTPError {
+	return &HTTPError{Status: status, Message: message, Err: err}
+}
+
+// Error returns the message of the error and its cause
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorStatus returns the status of the response to the error: the status of an HTTPError, 413 for uploads that are
+// too large, 422 for field errors, 503 for canceled requests, 504 for timeouts and 500 otherwise
+func ErrorStatus(err error) int {
+	var httpErr *HTTPError
+	var fieldErrs FieldErrors
+
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr.Status
+	case errors.Is(err, ErrUploadTooLarge), errors.Is(err, ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &fieldErrs):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrRequestAborted):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// NewErrorHandler returns an error handler that swaps the errors of htmx requests into the DefaultErrorTarget
+func (h *HTMX) NewErrorHandler() *ErrorHandler {
+	return &ErrorHandler{
+		htmx:     h,
+		target:   DefaultErrorTarget,
+		swap:     NewSwap(SwapInnerHTML),
+		fragment: defaultErrorComponent("templates/error_fragment.html"),
+		page:     defaultErrorComponent("templates/error_page.html"),
+	}
+}
+
+// Target sets the CSS selector of the error container and how the error fragment is swapped into it
+func (e *ErrorHandler) Target(selector string, swap *Swap) *ErrorHandler {
+	e.target = selector
+	e.swap = swap
+	return e
+}
+
+// Fragment sets the component of the errors of htmx requests
+func (e *ErrorHandler) Fragment(fn ErrorComponentFunc) *ErrorHandler {
+	e.fragment = fn
+	return e
+}
+
+// Page sets the component of the errors of full page loads, e.g. the error page in the layout of the site
+func (e *ErrorHandler) Page(fn ErrorComponentFunc) *ErrorHandler {
+	e.page = fn
+	return e
+}
+
+// Handle writes the response to the error. The message of an HTTPError is shown to the user, other errors show the
+// text of their status and are logged when they are server errors.
+func (e *ErrorHandler) Handle(w http.ResponseWriter, r *http.Request, err error) {
+	status := ErrorStatus(err)
+
+	message := http.StatusText(status)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.Message != "" {
+		message = httpErr.Message
+	}
+
+	if status >= http.StatusInternalServerError {
+		e.htmx.log.Warn("request failed", "path", r.URL.Path, "request_id", RequestID(r.Context()), "status", status,
+			"error", err)
+	}
+
+	// the status is written with the body, after the handler set the headers of the render
+	handler := e.htmx.NewHandler(&statusWriter{ResponseWriter: w, status: status}, r)
+	handler.Vary(fragmentVary...)
+
+	component := e.page(r, status, message)
+	if WantsFragment(r) {
+		handler.ReTarget(e.target)
+		handler.ReSwapWithObject(e.swap)
+		component = e.fragment(r, status, message)
+	}
+
+	if _, err := handler.Render(r.Context(), component); err != nil {
+		e.htmx.log.Warn("unable to render the error", "path", r.URL.Path, "error", err)
+	}
+}
+
+// Wrap returns a handler that calls fn and handles the error it returns
+func (e *ErrorHandler) Wrap(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			e.Handle(w, r, err)
+		}
+	})
+}
+
+// defaultErrorComponent returns the error component of the embedded template
+func defaultErrorComponent(name string) ErrorComponentFunc {
+	return func(_ *http.Request, status int, message string) RenderableComponent {
+		return NewComponent(name).FS(errorTemplates).
+			AddData("Status", status).
+			AddData("StatusText", http.StatusText(status)).
+			AddData("Message", message)
+	}
+}
+
+// WriteHeader replaces the status that is written
+func (s *statusWriter) WriteHeader(status int) {
+	s.status = status
+}
+
+// Write writes the status before the first write
+func (s *statusWriter) Write(b []byte) (int, error) {
+	if !s.wrote {
+		s.wrote = true
+		s.ResponseWriter.WriteHeader(s.status)
+	}
+
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the response writer for http.ResponseController
+func (s *statusWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
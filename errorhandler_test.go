@@ -0,0 +1,44 @@
+package htmx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorStatus(t *testing.T) {
+	equalInt(t, http.StatusNotFound, ErrorStatus(NewHTTPError(http.StatusNotFound, "missing", nil)))
+	equalInt(t, http.StatusUnprocessableEntity, ErrorStatus(FieldErrors{{Field: "email", Message: "is required"}}))
+	equalInt(t, http.StatusRequestEntityTooLarge, ErrorStatus(ErrFileTooLarge))
+	equalInt(t, http.StatusInternalServerError, ErrorStatus(errors.New("boom")))
+}
+
+func TestErrorHandler(t *testing.T) {
+	errs := New().NewErrorHandler()
+	handler := errs.Wrap(func(http.ResponseWriter, *http.Request) error {
+		return NewHTTPError(http.StatusNotFound, "The user doesn't exist", errors.New("no rows"))
+	})
+
+	// htmx requests get the fragment in the error container
+	r := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	equalInt(t, http.StatusNotFound, w.Code)
+	equal(t, "#errors", w.Header().Get("HX-Retarget"))
+	equal(t, "innerHTML", w.Header().Get("HX-Reswap"))
+	equal(t, `<div class="error" role="alert" data-status="404">The user doesn&#39;t exist</div>`, w.Body.String())
+
+	// full page loads get the error page, server errors don't show their cause
+	w = httptest.NewRecorder()
+	errs.Handle(w, httptest.NewRequest(http.MethodGet, "/users/7", nil), errors.New("connection refused"))
+
+	equalInt(t, http.StatusInternalServerError, w.Code)
+	equal(t, "", w.Header().Get("HX-Retarget"))
+	if body := w.Body.String(); !strings.Contains(body, "<h1>Internal Server Error</h1>") || strings.Contains(body, "refused") {
+		t.Errorf("expected the error page without the cause, got %s", body)
+	}
+}
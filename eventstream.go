@@ -0,0 +1,158 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jkc-2/go-htmx/sse"
+)
+
+// ErrStreamClosed is returned when sending to an event stream that was closed or whose context is done
+var ErrStreamClosed = errors.New("htmx: event stream closed")
+
+// EventStream writes rendered components as server-sent events to a single client, for the htmx sse extension.
+//
+//	stream := htmx.NewEventStream(w).WithContext(r.Context()).KeepAlive(15 * time.Second)
+//	defer stream.Close()
+//
+//	for {
+//		select {
+//		case <-stream.Done():
+//			return
+//		case count := <-updates:
+//			if err := stream.Send("count", htmx.NewComponent("count.html").AddData("Count", count)); err != nil {
+//				return
+//			}
+//		}
+//	}
+type EventStream struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	ctx context.Context
+
+	mu     sync.Mutex
+	closed chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// NewEventStream starts an event stream on the response writer, the headers are sent right away
+func NewEventStream(w http.ResponseWriter) *EventStream {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	s := &EventStream{
+		w:      w,
+		rc:     http.NewResponseController(w),
+		ctx:    context.Background(),
+		closed: make(chan struct{}),
+	}
+
+	s.err = s.flush()
+	return s
+}
+
+// WithContext sets the context the components are rendered with, the stream is closed when the context is done.
+// Use the context of the request, so the stream stops when the client disconnects.
+func (s *EventStream) WithContext(ctx context.Context) *EventStream {
+	s.ctx = ctx
+
+	context.AfterFunc(ctx, s.Close)
+	return s
+}
+
+// KeepAlive writes a comment every interval while the stream is open, so proxies don't close an idle connection
+func (s *EventStream) KeepAlive(interval time.Duration) *EventStream {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.write(": ping\n\n"); err != nil {
+					return
+				}
+			case <-s.closed:
+				return
+			}
+		}
+	}()
+
+	return s
+}
+
+// Send renders the component and sends it as the event, the htmx sse extension swaps it into the elements with a
+// matching sse-swap attribute
+func (s *EventStream) Send(event string, c RenderableComponent) error {
+	output, err := c.Render(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.SendHTML(event, output)
+}
+
+// SendHTML sends the html as the event
+func (s *EventStream) SendHTML(event string, html template.HTML) error {
+	msg := sse.NewMessage(string(html))
+	msg.Event = event
+
+	return s.write(msg.String())
+}
+
+// Done returns a channel that is closed when the stream was closed
+func (s *EventStream) Done() <-chan struct{} {
+	return s.closed
+}
+
+// Close closes the stream, it stops the keep alive and makes the next sends fail with ErrStreamClosed
+func (s *EventStream) Close() {
+	s.once.Do(func() { close(s.closed) })
+}
+
+// write writes and flushes the frame, a failed write closes the stream
+func (s *EventStream) write(frame string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.closed:
+		return ErrStreamClosed
+	default:
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, err := io.WriteString(s.w, frame); err != nil {
+		s.err = err
+		s.Close()
+		return err
+	}
+
+	if err := s.flush(); err != nil {
+		s.err = err
+		s.Close()
+		return err
+	}
+
+	return nil
+}
+
+// flush flushes the response, writers that can't flush are written without
+func (s *EventStream) flush() error {
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
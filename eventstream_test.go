@@ -0,0 +1,52 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestEventStream(t *testing.T) {
+	fsys := fstest.MapFS{"count.html": {Data: []byte("<span>{{ .Data.Count }}</span>\n<span>items</span>")}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	stream := NewEventStream(w).WithContext(ctx)
+
+	equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	if err := stream.Send("count", NewComponent("count.html").FS(fsys).AddData("Count", 3)); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "event: count\ndata: <span>3</span>\ndata: <span>items</span>\n\n", w.Body.String())
+
+	cancel()
+	select {
+	case <-stream.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the stream to close with its context")
+	}
+
+	if err := stream.SendHTML("count", "<span>4</span>"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func TestEventStreamKeepAlive(t *testing.T) {
+	w := httptest.NewRecorder()
+	stream := NewEventStream(w).KeepAlive(time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	stream.Close()
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if !strings.HasPrefix(w.Body.String(), ": ping\n\n") {
+		t.Errorf("expected keep alive comments, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,262 @@
+package htmx
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// FeedFormat is the format of a feed component, see NewFeedComponent
+type FeedFormat string
+
+const (
+	FeedRSS  FeedFormat = "rss"  // RSS 2.0, served as application/rss+xml
+	FeedAtom FeedFormat = "atom" // Atom 1.0, served as application/atom+xml
+)
+
+type (
+	// Feed is the channel of a feed and its items, links are absolute urls
+	Feed struct {
+		Title       string
+		Link        string    // the url of the site
+		Self        string    // the url of the feed
+		Description string    // the description of an RSS channel and the subtitle of an Atom feed
+		Author      string    // the name of the author, required by Atom
+		Updated     time.Time // the time the feed was updated, the newest date of the items if zero
+		Items       []FeedItem
+	}
+
+	// FeedItem is an item of a feed, an entry of Atom feeds
+	FeedItem struct {
+		ID      string // the unique id of the item, the link if empty
+		Title   string
+		Link    string
+		Date    time.Time
+		Summary string
+		Body    RenderableComponent // the content of the item, rendered as a partial of the feed component
+	}
+
+	rssFeed struct {
+		XMLName   xml.Name   `xml:"rss"`
+		Version   string     `xml:"version,attr"`
+		AtomNS    string     `xml:"xmlns:atom,attr"`
+		ContentNS string     `xml:"xmlns:content,attr"`
+		Channel   rssChannel `xml:"channel"`
+	}
+
+	rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+		Self          *atomLink `xml:"atom:link,omitempty"`
+		Items         []rssItem `xml:"item"`
+	}
+
+	rssItem struct {
+		Title       string   `xml:"title"`
+		Link        string   `xml:"link,omitempty"`
+		GUID        *rssGUID `xml:"guid,omitempty"`
+		PubDate     string   `xml:"pubDate,omitempty"`
+		Description string   `xml:"description,omitempty"`
+		Content     string   `xml:"content:encoded,omitempty"`
+	}
+
+	rssGUID struct {
+		Value       string `xml:",chardata"`
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+	}
+
+	atomFeed struct {
+		XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		Title    string      `xml:"title"`
+		Subtitle string      `xml:"subtitle,omitempty"`
+		ID       string      `xml:"id"`
+		Updated  string      `xml:"updated"`
+		Links    []atomLink  `xml:"link"`
+		Author   *atomAuthor `xml:"author,omitempty"`
+		Entries  []atomEntry `xml:"entry"`
+	}
+
+	atomEntry struct {
+		Title     string       `xml:"title"`
+		ID        string       `xml:"id"`
+		Links     []atomLink   `xml:"link,omitempty"`
+		Updated   string       `xml:"updated"`
+		Published string       `xml:"published,omitempty"`
+		Summary   string       `xml:"summary,omitempty"`
+		Content   *atomContent `xml:"content,omitempty"`
+	}
+
+	atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+		Type string `xml:"type,attr,omitempty"`
+	}
+
+	atomAuthor struct {
+		Name string `xml:"name"`
+	}
+
+	atomContent struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// NewFeedComponent returns a component that renders the feed as RSS 2.0 or Atom XML, so blogs render their feeds with
+// the components of their pages. The bodies of the items are partials of the feed component, they receive its data
+// like any other partial and their html is escaped into the content of the items. The handler serves the feed with
+// the content type of the format, and Generate writes it to a route like /feed.xml as it is.
+//
+//	h.Mount(mux, htmx.Route("/feed.xml", func() htmx.RenderableComponent {
+//		feed := htmx.Feed{Title: "Blog", Link: "https://example.com/", Self: "https://example.com/feed.xml"}
+//		for _, post := range posts {
+//			feed.Items = append(feed.Items, htmx.FeedItem{Title: post.Title, Link: post.URL, Date: post.Date,
+//				Body: htmx.NewComponent("posts/body.html").AddData("Post", post)})
+//		}
+//		return htmx.NewFeedComponent(htmx.FeedRSS, feed)
+//	}))
+//
+// The feed isn't post-processed, sanitized or annotated in development, as it isn't html.
+func NewFeedComponent(format FeedFormat, feed Feed) *Component {
+	c := NewComponent()
+	for i, item := range feed.Items {
+		if item.Body != nil {
+			c.With(item.Body, feedItemKey(i))
+		}
+	}
+
+	c.contentType = "application/" + string(format) + "+xml; charset=utf-8"
+	c.writer = func(_ context.Context, w io.Writer) error {
+		bodies := make([]string, len(feed.Items))
+		for i := range feed.Items {
+			body, _ := c.partial[feedItemKey(i)].(template.HTML)
+			bodies[i] = string(body)
+		}
+
+		return writeFeed(w, format, feed, bodies)
+	}
+
+	return c
+}
+
+// feedItemKey returns the partial key of the body of the item
+func feedItemKey(i int) string {
+	return "feed-item-" + strconv.Itoa(i)
+}
+
+// writeFeed writes the feed in the format with the rendered bodies of its items
+func writeFeed(w io.Writer, format FeedFormat, feed Feed, bodies []string) error {
+	updated := feed.Updated
+	if updated.IsZero() {
+		for _, item := range feed.Items {
+			if item.Date.After(updated) {
+				updated = item.Date
+			}
+		}
+	}
+
+	var v any
+	switch format {
+	case FeedRSS:
+		v = rssFromFeed(feed, bodies, updated)
+	case FeedAtom:
+		v = atomFromFeed(feed, bodies, updated)
+	default:
+		return fmt.Errorf("htmx: unknown feed format %q", format)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// rssFromFeed returns the RSS 2.0 document of the feed
+func rssFromFeed(feed Feed, bodies []string, updated time.Time) rssFeed {
+	doc := rssFeed{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: feed.Description,
+		},
+	}
+
+	if !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+	if feed.Self != "" {
+		doc.Channel.Self = &atomLink{Href: feed.Self, Rel: "self", Type: "application/rss+xml"}
+	}
+
+	for i, item := range feed.Items {
+		ri := rssItem{Title: item.Title, Link: item.Link, Description: item.Summary, Content: bodies[i]}
+		if item.ID != "" || item.Link != "" {
+			ri.GUID = &rssGUID{Value: item.Link, IsPermaLink: item.ID == ""}
+			if item.ID != "" {
+				ri.GUID.Value = item.ID
+			}
+		}
+		if !item.Date.IsZero() {
+			ri.PubDate = item.Date.Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, ri)
+	}
+
+	return doc
+}
+
+// atomFromFeed returns the Atom document of the feed, the link of the feed is its id
+func atomFromFeed(feed Feed, bodies []string, updated time.Time) atomFeed {
+	doc := atomFeed{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.Link,
+		Updated:  updated.Format(time.RFC3339),
+	}
+
+	if feed.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: feed.Link})
+	}
+	if feed.Self != "" {
+		doc.Links = append(doc.Links, atomLink{Href: feed.Self, Rel: "self", Type: "application/atom+xml"})
+	}
+	if feed.Author != "" {
+		doc.Author = &atomAuthor{Name: feed.Author}
+	}
+
+	for i, item := range feed.Items {
+		entry := atomEntry{Title: item.Title, ID: item.ID, Summary: item.Summary}
+		if entry.ID == "" {
+			entry.ID = item.Link
+		}
+		if item.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.Link})
+		}
+
+		date := item.Date
+		if date.IsZero() {
+			date = updated
+		}
+		entry.Updated = date.Format(time.RFC3339)
+		if !item.Date.IsZero() {
+			entry.Published = entry.Updated
+		}
+
+		if bodies[i] != "" {
+			entry.Content = &atomContent{Type: "html", Value: bodies[i]}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return doc
+}
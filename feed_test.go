@@ -0,0 +1,100 @@
+package htmx
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestNewFeedComponent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"feed-body.html": {Data: []byte(`<p>{{ .Data.Post }} by {{ .Data.Site }}</p>`)},
+	}
+
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	feed := Feed{
+		Title:  "Blog & News",
+		Link:   "https://example.com/",
+		Self:   "https://example.com/feed.xml",
+		Author: "Ada",
+		Items: []FeedItem{
+			{Title: "First", Link: "https://example.com/first", Date: date, Body: NewComponent("feed-body.html").FS(fsys).AddData("Post", "first")},
+			{Title: "Second", ID: "urn:second", Date: date.Add(time.Hour), Summary: "no body"},
+		},
+	}
+
+	t.Run("rss", func(t *testing.T) {
+		out, err := NewFeedComponent(FeedRSS, feed).AddData("Site", "Example").Render(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var doc struct {
+			Channel struct {
+				Title         string `xml:"title"`
+				LastBuildDate string `xml:"lastBuildDate"`
+				Items         []struct {
+					GUID    string `xml:"guid"`
+					PubDate string `xml:"pubDate"`
+					Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				} `xml:"item"`
+			} `xml:"channel"`
+		}
+		if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatal(err, out)
+		}
+
+		equal(t, "Blog & News", doc.Channel.Title)
+		equal(t, "Wed, 01 May 2024 13:00:00 +0000", doc.Channel.LastBuildDate)
+		equalInt(t, 2, len(doc.Channel.Items))
+		equal(t, "https://example.com/first", doc.Channel.Items[0].GUID)
+		equal(t, "<p>first by Example</p>", doc.Channel.Items[0].Content)
+		equal(t, "urn:second", doc.Channel.Items[1].GUID)
+		equalBool(t, true, strings.Contains(string(out), `<guid isPermaLink="false">urn:second</guid>`))
+	})
+
+	t.Run("atom", func(t *testing.T) {
+		out, err := NewFeedComponent(FeedAtom, feed).AddData("Site", "Example").Render(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var doc struct {
+			XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+			Updated string   `xml:"updated"`
+			Author  string   `xml:"author>name"`
+			Entries []struct {
+				ID      string `xml:"id"`
+				Content struct {
+					Type  string `xml:"type,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"content"`
+			} `xml:"entry"`
+		}
+		if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatal(err, out)
+		}
+
+		equal(t, "2024-05-01T13:00:00Z", doc.Updated)
+		equal(t, "Ada", doc.Author)
+		equal(t, "https://example.com/first", doc.Entries[0].ID)
+		equal(t, "html", doc.Entries[0].Content.Type)
+		equal(t, "<p>first by Example</p>", doc.Entries[0].Content.Value)
+		equal(t, "urn:second", doc.Entries[1].ID)
+	})
+}
+
+func TestNewFeedComponentHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	feed := NewFeedComponent(FeedAtom, Feed{Title: "Blog", Link: "https://example.com/"})
+	if _, err := New().NewHandler(w, httptest.NewRequest("GET", "/feed.xml", nil)).Render(context.Background(), feed); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	equalBool(t, true, strings.HasPrefix(w.Body.String(), xml.Header+`<feed xmlns="http://www.w3.org/2005/Atom">`))
+}
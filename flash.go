@@ -0,0 +1,182 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+//go:embed templates/flash.html
+var flashTemplates embed.FS
+
+const (
+	FlashInfo    FlashLevel = "info"
+	FlashSuccess FlashLevel = "success"
+	FlashWarning FlashLevel = "warning"
+	FlashError   FlashLevel = "error"
+)
+
+var (
+	// DefaultFlashTarget is the id of the element the flash messages are rendered into
+	DefaultFlashTarget = "flashes"
+
+	// DefaultFlashCookie is the name of the cookie of the CookieFlashStore
+	DefaultFlashCookie = "_flash"
+)
+
+type (
+	// FlashLevel is the level of a flash message, it is added to the css class of the message
+	FlashLevel string
+
+	// Flash is a message that is shown to the user once, e.g. after a form post
+	Flash struct {
+		Level   FlashLevel `json:"l"`
+		Message string     `json:"m"`
+	}
+
+	// FlashStore keeps the flash messages until they are shown. Session backed stores implement it on top of their session.
+	FlashStore interface {
+		// Load returns the flash messages of the request
+		Load(r *http.Request) ([]Flash, error)
+
+		// Save stores the flash messages for the next request, no messages clear the store
+		Save(w http.ResponseWriter, r *http.Request, flashes []Flash) error
+	}
+
+	// CookieFlashStore keeps the flash messages in a signed cookie
+	CookieFlashStore struct {
+		signer *Signer
+		Name   string
+		Path   string
+		Secure bool
+	}
+)
+
+// NewCookieFlashStore returns a flash store that keeps the messages in a cookie signed by a signer derived for flashes
+func NewCookieFlashStore(signer *Signer) *CookieFlashStore {
+	return &CookieFlashStore{
+		signer: signer.Derive("flash"),
+		Name:   DefaultFlashCookie,
+		Path:   "/",
+	}
+}
+
+// Load returns the flash messages of the cookie, a cookie with an invalid signature is ignored
+func (s *CookieFlashStore) Load(r *http.Request) ([]Flash, error) {
+	cookie, err := r.Cookie(s.Name)
+	if err != nil {
+		return nil, nil
+	}
+
+	var flashes []Flash
+	if err := s.signer.VerifyJSON(cookie.Value, &flashes); err != nil {
+		if errors.Is(err, ErrInvalidSignature) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return flashes, nil
+}
+
+// Save sets the cookie with the flash messages, or expires it when there are no messages
+func (s *CookieFlashStore) Save(w http.ResponseWriter, _ *http.Request, flashes []Flash) error {
+	cookie := &http.Cookie{
+		Name:     s.Name,
+		Path:     s.Path,
+		Secure:   s.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if len(flashes) == 0 {
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+		return nil
+	}
+
+	value, err := s.signer.SignJSON(flashes)
+	if err != nil {
+		return err
+	}
+
+	cookie.Value = value
+	http.SetCookie(w, cookie)
+
+	return nil
+}
+
+// SetFlashStore sets the store of the flash messages of the handlers
+func (h *HTMX) SetFlashStore(store FlashStore) {
+	h.flash = store
+}
+
+// Flash queues a flash message. Htmx requests that are rendered by the handler receive the messages as an out-of-band
+// swap into the DefaultFlashTarget element, other requests keep them in the store until they are read with Flashes.
+func (h *Handler) Flash(level FlashLevel, message string) error {
+	if h.flash == nil {
+		return errors.New("htmx: no flash store, use SetFlashStore")
+	}
+
+	flashes, err := h.loadFlashes()
+	if err != nil {
+		return err
+	}
+
+	h.flashes = append(flashes, Flash{Level: level, Message: message})
+
+	return h.flash.Save(h.w, h.r, h.flashes)
+}
+
+// Flashes returns the queued flash messages and clears the store
+func (h *Handler) Flashes() ([]Flash, error) {
+	if h.flash == nil {
+		return nil, nil
+	}
+
+	flashes, err := h.loadFlashes()
+	if err != nil || len(flashes) == 0 {
+		return nil, err
+	}
+
+	h.flashes = nil
+	return flashes, h.flash.Save(h.w, h.r, nil)
+}
+
+// loadFlashes loads the flash messages of the request once
+func (h *Handler) loadFlashes() ([]Flash, error) {
+	if h.flashesLoaded {
+		return h.flashes, nil
+	}
+
+	flashes, err := h.flash.Load(h.r)
+	if err != nil {
+		return nil, err
+	}
+
+	h.flashes, h.flashesLoaded = flashes, true
+	return flashes, nil
+}
+
+// renderFlashes renders the queued flash messages as an out-of-band swap
+func (h *Handler) renderFlashes(ctx context.Context) (template.HTML, error) {
+	flashes, err := h.Flashes()
+	if err != nil || len(flashes) == 0 {
+		return "", err
+	}
+
+	return NewFlashComponent(flashes, true).Render(ctx)
+}
+
+// NewFlashComponent returns a component rendering the flash messages into the DefaultFlashTarget element. Layouts render
+// it in place to show the messages of full page loads, oob renders it as an out-of-band swap appending the messages.
+func NewFlashComponent(flashes []Flash, oob bool) *Component {
+	c := NewComponent("templates/flash.html").FS(flashTemplates)
+	c.AddData("Target", DefaultFlashTarget)
+	c.AddData("Flashes", flashes)
+	c.AddData("OOB", oob)
+
+	return c
+}
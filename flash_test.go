@@ -0,0 +1,67 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFlash(t *testing.T) {
+	fsys := fstest.MapFS{"row.html": {Data: []byte(`<tr><td>saved</td></tr>`)}}
+
+	h := New()
+	h.SetFlashStore(NewCookieFlashStore(NewSigner([]byte("0123456789abcdef0123456789abcdef"))))
+
+	// a plain form post keeps the message in the cookie for the redirect
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/items", nil)
+	if err := h.NewHandler(w, r).Flash(FlashSuccess, "Saved <b>item</b>"); err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultFlashCookie {
+		t.Fatalf("expected the flash cookie, got %v", cookies)
+	}
+
+	// the next htmx request receives the message as an out-of-band swap and clears the cookie
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/items", nil)
+	r.Header.Set("HX-Request", "true")
+	r.AddCookie(cookies[0])
+
+	handler := h.NewHandler(w, r)
+	if _, err := handler.Render(context.Background(), NewComponent("row.html").FS(fsys)); err != nil {
+		t.Fatal(err)
+	}
+
+	body := w.Body.String()
+	if !strings.HasPrefix(body, `<tr><td>saved</td></tr><div id="flashes" class="flashes" role="status" aria-live="polite" hx-swap-oob="beforeend">`) {
+		t.Errorf("unexpected body %s", body)
+	}
+
+	if !strings.Contains(body, `<div class="flash flash-success">Saved &lt;b&gt;item&lt;/b&gt;</div>`) {
+		t.Errorf("expected the escaped message, got %s", body)
+	}
+
+	cookies = w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge != -1 {
+		t.Errorf("expected the flash cookie to be cleared, got %v", cookies)
+	}
+
+	flashes, err := handler.Flashes()
+	if err != nil || len(flashes) != 0 {
+		t.Errorf("expected no flashes after the render, got %v %v", flashes, err)
+	}
+
+	// a tampered cookie is ignored
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: DefaultFlashCookie, Value: "W10.invalid"})
+	flashes, err = h.NewHandler(httptest.NewRecorder(), r).Flashes()
+	if err != nil || len(flashes) != 0 {
+		t.Errorf("expected no flashes for a tampered cookie, got %v %v", flashes, err)
+	}
+}
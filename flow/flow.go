@@ -0,0 +1,357 @@
+// Package flow coordinates multi-step interactions, like a checkout or an onboarding, as a server-side state machine.
+//
+// Every step renders a component and handles its submitted form. A successful submit advances the flow to the next step,
+// the state is kept in a pluggable store and the url of every step is pushed to the browser history, so the back button
+// and reloads keep working.
+package flow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+type (
+	// Step is a step of a flow
+	Step struct {
+		Name string // name of the step, used in its url, back and cancel are reserved
+
+		// Render returns the component of the step, the component receives the View of the flow as Flow data
+		Render func(ctx context.Context, state *State) (htmx.RenderableComponent, error)
+
+		// Submit validates the submitted form, stores its values in the state and returns the name of the next step.
+		// An empty name completes the flow, a ValidationError renders the step again with the errors.
+		Submit func(ctx context.Context, r *http.Request, state *State) (next string, err error)
+	}
+
+	// Options configures a Flow
+	Options struct {
+		Path  string // path the flow is mounted on, e.g. /checkout
+		Store Store  // store of the flow states, defaults to a memory store with a 24 hour ttl
+
+		// OnComplete is called when the last step is submitted, it writes the response, e.g. a redirect to a confirmation page
+		OnComplete func(ctx context.Context, h *htmx.Handler, state *State) error
+
+		// CancelURL is the url the browser is redirected to when the flow is cancelled, defaults to /
+		CancelURL string
+	}
+
+	// Flow is the http handler of a multi-step flow
+	Flow struct {
+		name  string
+		htmx  *htmx.HTMX
+		opts  Options
+		steps []Step
+		mux   *http.ServeMux
+	}
+
+	// View is the flow data available to the component of a step as .Data.Flow
+	View struct {
+		ID        string
+		Step      string
+		Data      map[string]any
+		Errors    ValidationError
+		Action    string // url the form of the step is posted to
+		BackURL   string // url to go back to the previous step, empty for the first step
+		CancelURL string // url to cancel the flow
+	}
+
+	// ValidationError holds the validation errors of a submitted step by field name
+	ValidationError map[string]string
+)
+
+// Error returns the validation errors as a single message
+func (v ValidationError) Error() string {
+	fields := make([]string, 0, len(v))
+	for field, msg := range v {
+		fields = append(fields, field+": "+msg)
+	}
+	slices.Sort(fields)
+
+	return "flow: " + strings.Join(fields, ", ")
+}
+
+// New returns a flow with the steps, the first step is the start of the flow
+func New(h *htmx.HTMX, name string, opts Options, steps ...Step) *Flow {
+	opts.Path = strings.TrimSuffix(opts.Path, "/")
+
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore(24 * time.Hour)
+	}
+
+	if opts.CancelURL == "" {
+		opts.CancelURL = "/"
+	}
+
+	f := &Flow{
+		name:  name,
+		htmx:  h,
+		opts:  opts,
+		steps: steps,
+		mux:   http.NewServeMux(),
+	}
+
+	f.mux.HandleFunc("GET "+opts.Path+"/{$}", f.start)
+	f.mux.HandleFunc("GET "+opts.Path+"/{id}/{step}", f.show)
+	f.mux.HandleFunc("POST "+opts.Path+"/{id}/{step}", f.submit)
+	f.mux.HandleFunc("POST "+opts.Path+"/{id}/back", f.back)
+	f.mux.HandleFunc("POST "+opts.Path+"/{id}/cancel", f.cancel)
+
+	return f
+}
+
+// ServeHTTP handles the requests of the flow, mount it on the path and all paths below it
+//
+//	mux.Handle("/checkout/", checkout)
+func (f *Flow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mux.ServeHTTP(w, r)
+}
+
+// Start creates a new state at the first step of the flow
+func (f *Flow) Start(ctx context.Context) (*State, error) {
+	if len(f.steps) == 0 {
+		return nil, errors.New("flow: no steps")
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		ID:      hex.EncodeToString(b),
+		Flow:    f.name,
+		Step:    f.steps[0].Name,
+		Data:    make(map[string]any),
+		Updated: time.Now(),
+	}
+
+	return state, f.opts.Store.Save(ctx, state)
+}
+
+// URL returns the url of a step
+func (f *Flow) URL(state *State, step string) string {
+	return f.opts.Path + "/" + state.ID + "/" + step
+}
+
+// start starts a new flow and redirects to its first step
+func (f *Flow) start(w http.ResponseWriter, r *http.Request) {
+	state, err := f.Start(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if f.htmx.NewHandler(w, r).IsHxRequest() {
+		f.render(w, r, state, nil)
+		return
+	}
+
+	http.Redirect(w, r, f.URL(state, state.Step), http.StatusSeeOther)
+}
+
+// show renders the current step, requesting a completed step goes back to it, e.g. when using the browser's back button
+func (f *Flow) show(w http.ResponseWriter, r *http.Request) {
+	state, ok := f.load(w, r)
+	if !ok {
+		return
+	}
+
+	if step := r.PathValue("step"); step != state.Step {
+		if i := slices.Index(state.History, step); i >= 0 {
+			state.Step, state.History = step, state.History[:i]
+			if !f.save(w, r, state) {
+				return
+			}
+		}
+	}
+
+	f.render(w, r, state, nil)
+}
+
+// submit handles the form of the current step and advances the flow
+func (f *Flow) submit(w http.ResponseWriter, r *http.Request) {
+	state, ok := f.load(w, r)
+	if !ok {
+		return
+	}
+
+	// a stale form, e.g. submitted twice, renders the current step again
+	if r.PathValue("step") != state.Step {
+		f.render(w, r, state, nil)
+		return
+	}
+
+	step, _ := f.step(state.Step)
+	next := ""
+
+	if step.Submit != nil {
+		var err error
+		next, err = step.Submit(r.Context(), r, state)
+
+		var verr ValidationError
+		if errors.As(err, &verr) {
+			f.render(w, r, state, verr)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if next == "" {
+		f.complete(w, r, state)
+		return
+	}
+
+	if _, ok := f.step(next); !ok {
+		http.Error(w, fmt.Sprintf("flow: unknown step %s", next), http.StatusInternalServerError)
+		return
+	}
+
+	state.History = append(state.History, state.Step)
+	state.Step = next
+	if !f.save(w, r, state) {
+		return
+	}
+
+	f.render(w, r, state, nil)
+}
+
+// back renders the previous step
+func (f *Flow) back(w http.ResponseWriter, r *http.Request) {
+	state, ok := f.load(w, r)
+	if !ok {
+		return
+	}
+
+	if n := len(state.History); n > 0 {
+		state.Step, state.History = state.History[n-1], state.History[:n-1]
+		if !f.save(w, r, state) {
+			return
+		}
+	}
+
+	f.render(w, r, state, nil)
+}
+
+// cancel removes the state and redirects to the cancel url
+func (f *Flow) cancel(w http.ResponseWriter, r *http.Request) {
+	if err := f.opts.Store.Delete(r.Context(), r.PathValue("id")); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	f.redirect(w, r, f.opts.CancelURL)
+}
+
+// complete finishes the flow
+func (f *Flow) complete(w http.ResponseWriter, r *http.Request, state *State) {
+	if err := f.opts.Store.Delete(r.Context(), state.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if f.opts.OnComplete == nil {
+		f.redirect(w, r, f.opts.CancelURL)
+		return
+	}
+
+	if err := f.opts.OnComplete(r.Context(), f.htmx.NewHandler(w, r), state); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// render renders the current step and pushes its url
+func (f *Flow) render(w http.ResponseWriter, r *http.Request, state *State, errs ValidationError) {
+	step, ok := f.step(state.Step)
+	if !ok || step.Render == nil {
+		http.Error(w, fmt.Sprintf("flow: step %s can't be rendered", state.Step), http.StatusInternalServerError)
+		return
+	}
+
+	component, err := step.Render(r.Context(), state)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	view := View{
+		ID:        state.ID,
+		Step:      state.Step,
+		Data:      state.Data,
+		Errors:    errs,
+		Action:    f.URL(state, state.Step),
+		CancelURL: f.URL(state, "cancel"),
+	}
+	if len(state.History) > 0 {
+		view.BackURL = f.URL(state, "back")
+	}
+
+	h := f.htmx.NewHandler(w, r)
+	if h.IsHxRequest() {
+		h.PushURL(f.URL(state, state.Step))
+	}
+
+	if _, err := h.Render(r.Context(), component.AddData("Flow", view)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// redirect redirects the browser, htmx requests are redirected with the HX-Redirect header
+func (f *Flow) redirect(w http.ResponseWriter, r *http.Request, url string) {
+	h := f.htmx.NewHandler(w, r)
+	if h.IsHxRequest() {
+		h.Redirect(url)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusSeeOther)
+}
+
+// load loads the state of the request
+func (f *Flow) load(w http.ResponseWriter, r *http.Request) (*State, bool) {
+	state, err := f.opts.Store.Load(r.Context(), r.PathValue("id"))
+	if errors.Is(err, ErrNotFound) || err == nil && state.Flow != f.name {
+		// the flow expired or doesn't exist, start over
+		f.redirect(w, r, f.opts.Path+"/")
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return state, true
+}
+
+// save saves the state
+func (f *Flow) save(w http.ResponseWriter, r *http.Request, state *State) bool {
+	state.Updated = time.Now()
+	if err := f.opts.Store.Save(r.Context(), state); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+// step returns the step with the name
+func (f *Flow) step(name string) (Step, bool) {
+	for _, s := range f.steps {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return Step{}, false
+}
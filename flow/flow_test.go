@@ -0,0 +1,94 @@
+package flow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestFlow(t *testing.T) {
+	fsys := fstest.MapFS{
+		"step.html": {Data: []byte(`{{ .Data.Flow.Step }}|{{ .Data.Flow.Action }}|{{ .Data.Flow.BackURL }}|{{ range $k, $v := .Data.Flow.Errors }}{{ $k }}={{ $v }}{{ end }}`)},
+	}
+
+	render := func(ctx context.Context, state *State) (htmx.RenderableComponent, error) {
+		return htmx.NewComponent("step.html").FS(fsys), nil
+	}
+
+	var completed *State
+	f := New(htmx.New(), "checkout", Options{
+		Path: "/checkout",
+		OnComplete: func(ctx context.Context, h *htmx.Handler, state *State) error {
+			completed = state
+			h.Redirect("/thanks")
+			return nil
+		},
+	},
+		Step{Name: "address", Render: render, Submit: func(ctx context.Context, r *http.Request, state *State) (string, error) {
+			if r.FormValue("street") == "" {
+				return "", ValidationError{"street": "required"}
+			}
+			state.Data["street"] = r.FormValue("street")
+			return "payment", nil
+		}},
+		Step{Name: "payment", Render: render, Submit: func(ctx context.Context, r *http.Request, state *State) (string, error) {
+			return "", nil
+		}},
+	)
+
+	do := func(method, path string, form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+		f.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodGet, "/checkout/", nil)
+	step := w.Header().Get("HX-Push-Url")
+	if !strings.HasSuffix(step, "/address") {
+		t.Fatalf("expected the url of the first step to be pushed, got %q", step)
+	}
+	id := strings.Split(step, "/")[2]
+
+	w = do(http.MethodPost, step, url.Values{})
+	equal(t, "address|/checkout/"+id+"/address||street=required", w.Body.String())
+
+	w = do(http.MethodPost, step, url.Values{"street": {"Main street"}})
+	equal(t, "payment|/checkout/"+id+"/payment|/checkout/"+id+"/back|", w.Body.String())
+	equal(t, "/checkout/"+id+"/payment", w.Header().Get("HX-Push-Url"))
+
+	w = do(http.MethodPost, "/checkout/"+id+"/back", nil)
+	equal(t, "address|/checkout/"+id+"/address||", w.Body.String())
+
+	// submitting a stale form renders the current step
+	w = do(http.MethodPost, "/checkout/"+id+"/payment", nil)
+	equal(t, "address|/checkout/"+id+"/address||", w.Body.String())
+
+	do(http.MethodPost, step, url.Values{"street": {"Main street"}})
+	w = do(http.MethodPost, "/checkout/"+id+"/payment", nil)
+	equal(t, "/thanks", w.Header().Get("HX-Redirect"))
+
+	if completed == nil || completed.Data["street"] != "Main street" {
+		t.Fatalf("unexpected completed state %+v", completed)
+	}
+
+	// a completed flow starts over
+	w = do(http.MethodGet, "/checkout/"+id+"/payment", nil)
+	equal(t, "/checkout/", w.Header().Get("HX-Redirect"))
+}
+
+func equal(t *testing.T, expected, actual string) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
@@ -0,0 +1,88 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by a store when the flow state doesn't exist
+var ErrNotFound = errors.New("flow: state not found")
+
+type (
+	// State is the server-side state of a running flow
+	State struct {
+		ID      string         `json:"id"`
+		Flow    string         `json:"flow"`
+		Step    string         `json:"step"`    // the current step
+		History []string       `json:"history"` // the completed steps, used to go back
+		Data    map[string]any `json:"data"`    // values collected by the steps
+		Updated time.Time      `json:"updated"`
+	}
+
+	// Store persists flow states
+	Store interface {
+		Load(ctx context.Context, id string) (*State, error)
+		Save(ctx context.Context, state *State) error
+		Delete(ctx context.Context, id string) error
+	}
+
+	// MemoryStore keeps flow states in memory, states are lost on restart
+	MemoryStore struct {
+		mu     sync.Mutex
+		states map[string]*State
+		ttl    time.Duration
+	}
+)
+
+// NewMemoryStore returns an in-memory store, states that are not updated within the ttl are removed
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		states: make(map[string]*State),
+		ttl:    ttl,
+	}
+}
+
+// Load returns a copy of the state
+func (s *MemoryStore) Load(_ context.Context, id string) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[id]
+	if !ok || s.ttl > 0 && time.Since(state.Updated) > s.ttl {
+		delete(s.states, id)
+		return nil, ErrNotFound
+	}
+
+	return state.clone(), nil
+}
+
+// Save stores a copy of the state
+func (s *MemoryStore) Save(_ context.Context, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[state.ID] = state.clone()
+	return nil
+}
+
+// Delete removes the state
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, id)
+	return nil
+}
+
+// clone returns a copy of the state, the values of Data are copied shallowly
+func (s *State) clone() *State {
+	c := *s
+	c.History = slices.Clone(s.History)
+	c.Data = maps.Clone(s.Data)
+
+	return &c
+}
@@ -0,0 +1,133 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// Form holds the submitted values of a form and the validation errors per field,
+	// it is used to render the form again with the previous input after a failed validation.
+	//
+	//	<input name="email" value="{{ old "email" }}" {{ if hasError "email" }}aria-invalid="true"{{ end }}>
+	//	<span class="error">{{ fieldError "email" }}</span>
+	Form struct {
+		Values url.Values
+		Errors map[string]string
+	}
+
+	formKey struct{}
+)
+
+// NewForm returns a form with the submitted values of the request
+func NewForm(r *http.Request) (*Form, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return &Form{
+		Values: r.Form,
+		Errors: make(map[string]string),
+	}, nil
+}
+
+// Get returns the submitted value of the field
+func (f *Form) Get(field string) string {
+	return f.Values.Get(field)
+}
+
+// AddError sets the validation error of the field
+func (f *Form) AddError(field, message string) *Form {
+	if f.Errors == nil {
+		f.Errors = make(map[string]string)
+	}
+
+	f.Errors[field] = message
+	return f
+}
+
+// Error returns the validation error of the field
+func (f *Form) Error(field string) string {
+	return f.Errors[field]
+}
+
+// Valid returns true when the form has no validation errors
+func (f *Form) Valid() bool {
+	return len(f.Errors) == 0
+}
+
+// SetForm sets the form that is used by the old, fieldError and hasError template functions of the component and its partials
+func (c *Component) SetForm(f *Form) *Component {
+	c.form = f
+	return c
+}
+
+// WithForm returns a context with the form used by the old, fieldError and hasError template functions
+func WithForm(ctx context.Context, f *Form) context.Context {
+	return context.WithValue(ctx, formKey{}, f)
+}
+
+// FormFromContext returns the form of the context
+func FormFromContext(ctx context.Context) (*Form, bool) {
+	f, ok := ctx.Value(formKey{}).(*Form)
+	return f, ok && f != nil
+}
+
+// oldFunc is the old template function, it returns the submitted value of the field
+func oldFunc(ctx context.Context, args ...any) (any, error) {
+	field, err := fieldArg("old", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := FormFromContext(ctx); ok {
+		return f.Get(field), nil
+	}
+
+	return "", nil
+}
+
+// fieldErrorFunc is the fieldError template function, it returns the validation error of the field
+func fieldErrorFunc(ctx context.Context, args ...any) (any, error) {
+	field, err := fieldArg("fieldError", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := FormFromContext(ctx); ok {
+		return f.Error(field), nil
+	}
+
+	return "", nil
+}
+
+// hasErrorFunc is the hasError template function, it returns true when the field has a validation error
+func hasErrorFunc(ctx context.Context, args ...any) (any, error) {
+	field, err := fieldArg("hasError", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := FormFromContext(ctx); ok {
+		return f.Error(field) != "", nil
+	}
+
+	return false, nil
+}
+
+// fieldArg returns the field name argument of a form template function
+func fieldArg(fn string, args []any) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New(fn + " requires a field name")
+	}
+
+	field, ok := args[0].(string)
+	if !ok {
+		return "", fmt.Errorf("%s: field name %v is not a string", fn, args[0])
+	}
+
+	return field, nil
+}
@@ -0,0 +1,36 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestForm(t *testing.T) {
+	fsys := fstest.MapFS{
+		"form.html":  {Data: []byte(`<form>{{ .Partials.email }}<input name="name" value="{{ old "name" }}"></form>`)},
+		"email.html": {Data: []byte(`<input name="email" value="{{ old "email" }}"{{ if hasError "email" }} aria-invalid="true"{{ end }}><span>{{ fieldError "email" }}</span>`)},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`name=Ann&email=ann"example.com`))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	form, err := NewForm(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	form.AddError("email", "invalid email address")
+
+	c := NewComponent("form.html").FS(fsys).SetForm(form)
+	c.With(NewComponent("email.html").FS(fsys), "email")
+
+	out, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<form><input name="email" value="ann&#34;example.com" aria-invalid="true"><span>invalid email address</span><input name="name" value="Ann"></form>`, string(out))
+}
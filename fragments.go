@@ -0,0 +1,154 @@
+package htmx
+
+import (
+	"encoding/json"
+	"html/template"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// EmitFragmentManifest enables the X-Fragments response header on the responses rendered by the Handler,
+// it lists the fragments of the response for client-side debugging tools and end-to-end tests.
+var EmitFragmentManifest = false
+
+// HeaderFragments is the response header with the fragment manifest
+const HeaderFragments = "X-Fragments"
+
+// FragmentManifest describes the composition of a response
+type FragmentManifest struct {
+	Roots  []string `json:"roots"`  // the ids of the top level elements
+	OOB    []string `json:"oob"`    // the targets of the out-of-band swaps
+	Events []string `json:"events"` // the events triggered by the response headers
+}
+
+// NewFragmentManifest returns the manifest of the output and the response headers
+func NewFragmentManifest(output template.HTML, response *HxResponseHeader) FragmentManifest {
+	m := FragmentManifest{Roots: []string{}, OOB: []string{}, Events: []string{}}
+
+	depth := 0
+	z := html.NewTokenizer(strings.NewReader(string(output)))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			id, oob := tokenAttr(token, "id"), tokenAttr(token, "hx-swap-oob")
+
+			if depth == 0 && id != "" {
+				m.Roots = append(m.Roots, id)
+			}
+
+			if target := oobTarget(id, oob); target != "" {
+				m.OOB = append(m.OOB, target)
+			}
+
+			if tt == html.StartTagToken && !voidElements[token.Data] {
+				depth++
+			}
+		case html.EndTagToken:
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	if response != nil {
+		for _, key := range []HxResponseKey{HXTrigger, HXTriggerAfterSwap, HXTriggerAfterSettle} {
+			for _, event := range triggeredEvents(response.Get(key)) {
+				if !slices.Contains(m.Events, event) {
+					m.Events = append(m.Events, event)
+				}
+			}
+		}
+	}
+
+	return m
+}
+
+// setFragmentManifest sets the X-Fragments header for the output when the manifest is enabled
+func (h *Handler) setFragmentManifest(output template.HTML) {
+	if !EmitFragmentManifest {
+		return
+	}
+
+	manifest, err := json.Marshal(NewFragmentManifest(output, h.response))
+	if err != nil {
+		h.log.Warn("unable to encode the fragment manifest", "error", err)
+		return
+	}
+
+	h.Header().Set(HeaderFragments, string(manifest))
+}
+
+// voidElements are the elements without an end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// tokenAttr returns the value of the attribute of the token
+func tokenAttr(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// oobTarget returns the target of an out-of-band swap, "true" and a plain swap style target the element's id,
+// "style:selector" targets the selector.
+func oobTarget(id, oob string) string {
+	if oob == "" || oob == "false" {
+		return ""
+	}
+
+	if _, selector, ok := strings.Cut(oob, ":"); ok && selector != "" {
+		return selector
+	}
+
+	if id == "" {
+		return ""
+	}
+
+	return "#" + id
+}
+
+// triggeredEvents returns the event names of an HX-Trigger header, either a JSON object or a comma separated list
+func triggeredEvents(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, "{") {
+		var events map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &events); err != nil {
+			return nil
+		}
+
+		names := make([]string, 0, len(events))
+		for name := range events {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		return names
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
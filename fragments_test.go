@@ -0,0 +1,34 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFragmentManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"items.html": {Data: []byte(`<ul id="items"><li id="item-1">one<br></li></ul>` +
+			`<span id="count" hx-swap-oob="true">1</span>` +
+			`<li hx-swap-oob="beforeend:#log">added</li><p>no id</p>`)},
+	}
+
+	EmitFragmentManifest = true
+	defer func() { EmitFragmentManifest = false }()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/items", nil)
+	r.Header.Set("HX-Request", "true")
+
+	handler := New().NewHandler(w, r)
+	handler.Trigger(`{"itemAdded":{"id":1},"close":""}`)
+	handler.TriggerAfterSettle("focus, itemAdded")
+
+	if _, err := handler.Render(context.Background(), NewComponent("items.html").FS(fsys)); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `{"roots":["items","count"],"oob":["#count","#log"],"events":["close","itemAdded","focus"]}`, w.Header().Get(HeaderFragments))
+}
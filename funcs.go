@@ -0,0 +1,96 @@
+package htmx
+
+import (
+	"html/template"
+)
+
+// builtinTemplateFuncs are the template functions that are available in every component,
+// they can be overridden by DefaultTemplateFuncs and the functions of the component.
+var builtinTemplateFuncs = template.FuncMap{
+	"hxDisinherit": hxDisinherit,
+	"hxInherit":    hxInherit,
+	"disinherit":   disinherit,
+	"hxSync":       hxSync,
+	"swap":         newSwapFunc,
+	"trigger":      triggerSpecFunc,
+	"preload":      preloadAttr,
+	"keyed":        keyedList,
+	"sanitize":     sanitizeFunc,
+	"props":        props,
+	"pageRange":    pageRange,
+	"hydrate":      hydrate,
+
+	// the Alpine.js helpers encode their values as JSON
+	//
+	//	<div {{ xData "open" false }}><button @click="{{ dispatch "cart-updated" }}">Add</button></div>
+	"xData":    xData,
+	"xBind":    xBind,
+	"dispatch": dispatch,
+
+	// the hx-* attribute builders take the attributes of the previous function of a pipeline as their last argument
+	//
+	//	<button {{ hxGet "/users" | hxTarget "#list" | hxSwap "outerHTML" }}>Load</button>
+	"hx":           hxAttr,
+	"hxGet":        hxVerb("hx-get"),
+	"hxPost":       hxVerb("hx-post"),
+	"hxPut":        hxVerb("hx-put"),
+	"hxPatch":      hxVerb("hx-patch"),
+	"hxDelete":     hxVerb("hx-delete"),
+	"hxTarget":     hxString("hx-target"),
+	"hxSelect":     hxString("hx-select"),
+	"hxInclude":    hxString("hx-include"),
+	"hxIndicator":  hxString("hx-indicator"),
+	"hxConfirm":    hxString("hx-confirm"),
+	"hxPushURL":    hxString("hx-push-url"),
+	"hxSwap":       hxValue("hx-swap"),
+	"hxTrigger":    hxValue("hx-trigger"),
+	"hxHeaders":    hxHeaders,
+	"hxVals":       hxVals,
+	"hxValsUnsafe": hxValsUnsafe,
+}
+
+// builtinContextFuncs are the context functions that are available in every component
+var builtinContextFuncs = map[string]ContextFunc{
+	"t":       translateFunc,
+	"tn":      translatePluralFunc,
+	"nonce":   nonceFunc,
+	"query":   queryFunc,
+	"path":    pathFunc,
+	"host":    hostFunc,
+	"pageURL": pageURLFunc,
+	"toasts":  toastsFunc,
+
+	"hxRequest": hxRequestFunc,
+
+	"old":        oldFunc,
+	"fieldError": fieldErrorFunc,
+	"hasError":   hasErrorFunc,
+
+	"csrfToken":   csrfTokenFunc,
+	"csrfField":   csrfFieldFunc,
+	"csrfHeaders": csrfHeadersFunc,
+
+	"headTitle": headTitleFunc,
+	"headMeta":  headMetaFunc,
+	"headLink":  headLinkFunc,
+	"headTags":  headTagsFunc,
+}
+
+// hxDisinherit returns the hx-disinherit attribute for the given attributes, or all attributes when none are given
+func hxDisinherit(attrs ...string) template.HTMLAttr {
+	return NewAttributes().Disinherit(attrs...).HTMLAttr()
+}
+
+// hxInherit returns the hx-inherit attribute for the given attributes, or all attributes when none are given
+func hxInherit(attrs ...string) template.HTMLAttr {
+	return NewAttributes().Inherit(attrs...).HTMLAttr()
+}
+
+// disinherit wraps the content in a container that stops the inheritance of the given attributes (or all of them),
+// this is meant to be used in layouts around the partial that is injected into them.
+//
+//	<main hx-target="#main" hx-swap="outerHTML">{{ disinherit .Partials.content }}</main>
+func disinherit(content template.HTML, attrs ...string) template.HTML {
+	//nolint:gosec // content is already trusted html and the attributes are escaped
+	return template.HTML(`<div style="display:contents" ` + NewAttributes().Disinherit(attrs...).String() + `>` + string(content) + `</div>`)
+}
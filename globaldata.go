@@ -0,0 +1,72 @@
+package htmx
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	globalDataProviders   []GlobalDataProvider
+	globalDataProvidersMu sync.RWMutex
+)
+
+// GlobalDataProvider returns global data for the request, e.g. the current user or feature flags
+type GlobalDataProvider func(r *http.Request) map[string]any
+
+// RegisterGlobalDataProvider registers a provider whose data is added to the Global map of every component rendered
+// by a Handler. Later providers override the keys of earlier ones, the global data of the component takes precedence.
+//
+//	htmx.RegisterGlobalDataProvider(func(r *http.Request) map[string]any {
+//		return map[string]any{"User": auth.UserFromContext(r.Context())}
+//	})
+func RegisterGlobalDataProvider(provider GlobalDataProvider) {
+	globalDataProvidersMu.Lock()
+	defer globalDataProvidersMu.Unlock()
+
+	globalDataProviders = append(globalDataProviders, provider)
+}
+
+// ResetGlobalDataProviders removes all registered providers
+func ResetGlobalDataProviders() {
+	globalDataProvidersMu.Lock()
+	defer globalDataProvidersMu.Unlock()
+
+	globalDataProviders = nil
+}
+
+// providedGlobalData returns the merged data of all providers for the request
+func providedGlobalData(r *http.Request) map[string]any {
+	globalDataProvidersMu.RLock()
+	providers := globalDataProviders
+	globalDataProvidersMu.RUnlock()
+
+	if len(providers) == 0 {
+		return nil
+	}
+
+	data := make(map[string]any)
+	for _, provider := range providers {
+		for key, value := range provider(r) {
+			data[key] = value
+		}
+	}
+
+	return data
+}
+
+// globalData returns the provided global data of the request and its request id, the providers are called once per
+// handler
+func (h *Handler) globalData() map[string]any {
+	if !h.globalLoaded {
+		h.global, h.globalLoaded = providedGlobalData(h.r), true
+
+		if id := RequestID(h.r.Context()); id != "" {
+			if h.global == nil {
+				h.global = make(map[string]any)
+			}
+			h.global[RequestIDGlobalKey] = id
+		}
+	}
+
+	return h.global
+}
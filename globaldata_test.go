@@ -0,0 +1,42 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGlobalDataProviders(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{ .Global.User }}|{{ .Partials.content }}`)},
+		"page.html":   {Data: []byte(`{{ .Global.User }}:{{ .Global.Beta }}:{{ .Global.Theme }}`)},
+	}
+
+	calls := 0
+	RegisterGlobalDataProvider(func(r *http.Request) map[string]any {
+		calls++
+		return map[string]any{"User": r.Header.Get("X-User"), "Beta": false, "Theme": "light"}
+	})
+	RegisterGlobalDataProvider(func(r *http.Request) map[string]any {
+		return map[string]any{"Beta": true}
+	})
+	defer ResetGlobalDataProviders()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User", "ada")
+
+	layout := NewComponent("layout.html").FS(fsys)
+	page := NewComponent("page.html").FS(fsys).AddGlobalData("Theme", "dark").Wrap(layout, "content")
+
+	if _, err := New().NewHandler(w, r).Render(context.Background(), page); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "ada|ada:true:dark", w.Body.String())
+	if calls != 1 {
+		t.Errorf("expected the providers to be called once per handler, got %d calls", calls)
+	}
+}
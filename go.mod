@@ -1,3 +1,8 @@
 module github.com/jkc-2/go-htmx
 
-go 1.23
+go 1.23.0
+
+require (
+	golang.org/x/net v0.43.0
+	golang.org/x/sync v0.16.0
+)
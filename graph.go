@@ -0,0 +1,226 @@
+package htmx
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EdgePartial links a component to a partial added with With
+	EdgePartial EdgeKind = "partial"
+
+	// EdgeWrap links a layout to the component that it wraps with Wrap
+	EdgeWrap EdgeKind = "wrap"
+
+	// EdgeBoosted links the layout of boosted requests to the component, see BoostedLayout
+	EdgeBoosted EdgeKind = "boosted"
+)
+
+type (
+	// ComponentGraph is the composition of a component: its layout chain, its tree of partials and their template
+	// files. It is a snapshot, changes to the components after Graph returned are not reflected.
+	//
+	//	g := htmx.Graph(page)
+	//	fmt.Println(g.Mermaid())
+	//	if cycles := g.Cycles(); len(cycles) > 0 { ... }
+	ComponentGraph struct {
+		Nodes []GraphNode // the components, the graphed component first
+		Edges []GraphEdge // the edges from the containing component to the contained component
+	}
+
+	// GraphNode is a component of the graph
+	GraphNode struct {
+		ID        string   // the id of the node, n0, n1, ...
+		Name      string   // the name of the component, the base name of its first template
+		Templates []string // the template files of the component, attached templates included
+	}
+
+	// GraphEdge links a containing component to a contained component, a layout to the component it wraps or a
+	// component to its partial
+	GraphEdge struct {
+		From   string   // the id of the containing component
+		To     string   // the id of the contained component
+		Kind   EdgeKind // partial, wrap or boosted
+		Target string   // the target the contained component is rendered into
+	}
+
+	// EdgeKind is the kind of composition of an edge
+	EdgeKind string
+)
+
+// Graph walks the Wrap, BoostedLayout and With graph of the component and returns its composition. Components that
+// are used more than once are a single node, so a cycle in the graph is a circular reference that fails the render.
+func Graph(c RenderableComponent) *ComponentGraph {
+	g := &ComponentGraph{}
+	ids := make(map[RenderableComponent]string)
+
+	var walk func(c RenderableComponent) string
+	walk = func(c RenderableComponent) string {
+		if id, ok := ids[c]; ok {
+			return id
+		}
+
+		id := "n" + strconv.Itoa(len(ids))
+		ids[c] = id
+
+		templates := c.templateFiles()
+		node := GraphNode{ID: id, Templates: templates}
+		if len(templates) > 0 {
+			node.Name = filepath.Base(templates[0])
+		}
+		g.Nodes = append(g.Nodes, node)
+
+		if c.isWrapped() {
+			layout := walk(c.wrapper())
+			g.Edges = append(g.Edges, GraphEdge{From: layout, To: id, Kind: EdgeWrap, Target: c.target()})
+		}
+
+		if b, ok := c.(boostedWrapper); ok {
+			if layout, target := b.boostedLayout(); layout != nil {
+				g.Edges = append(g.Edges, GraphEdge{From: walk(layout), To: id, Kind: EdgeBoosted, Target: target})
+			}
+		}
+
+		partials := c.partials()
+		targets := make([]string, 0, len(partials))
+		for target := range partials {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		for _, target := range targets {
+			g.Edges = append(g.Edges, GraphEdge{From: id, To: walk(partials[target]), Kind: EdgePartial, Target: target})
+		}
+
+		return id
+	}
+
+	walk(c)
+
+	return g
+}
+
+// Cycles returns the cycles of the graph as the ids of their nodes in the order of their edges, the components of a
+// cycle contain each other and can't be rendered
+func (g *ComponentGraph) Cycles() [][]string {
+	edges := make(map[string][]string)
+	for _, e := range g.Edges {
+		edges[e.From] = append(edges[e.From], e.To)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var (
+		cycles [][]string
+		path   []string
+		state  = make(map[string]int)
+	)
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		path = append(path, id)
+
+		for _, to := range edges[id] {
+			switch state[to] {
+			case unvisited:
+				visit(to)
+			case visiting:
+				for i := range path {
+					if path[i] == to {
+						cycles = append(cycles, append([]string(nil), path[i:]...))
+						break
+					}
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+	}
+
+	for _, n := range g.Nodes {
+		if state[n.ID] == unvisited {
+			visit(n.ID)
+		}
+	}
+
+	return cycles
+}
+
+// DOT returns the graph in the DOT language of Graphviz, e.g. for dot -Tsvg. Layouts are linked with dashed edges,
+// boosted layouts with dotted edges.
+func (g *ComponentGraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph components {\n")
+	sb.WriteString("\tnode [shape=box];\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%s [label=%s];\n", n.ID, strconv.Quote(n.label("\n")))
+	}
+
+	for _, e := range g.Edges {
+		style := ""
+		switch e.Kind {
+		case EdgeWrap:
+			style = ", style=dashed"
+		case EdgeBoosted:
+			style = ", style=dotted"
+		}
+		fmt.Fprintf(&sb, "\t%s -> %s [label=%s%s];\n", e.From, e.To, strconv.Quote(e.label()), style)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, e.g. for markdown files on GitHub. Layouts are linked with dotted
+// edges.
+func (g *ComponentGraph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%s[\"%s\"]\n", n.ID, mermaidEscape(n.label("<br>")))
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Kind != EdgePartial {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "\t%s %s|\"%s\"| %s\n", e.From, arrow, mermaidEscape(e.label()), e.To)
+	}
+
+	return sb.String()
+}
+
+// label returns the template files of the node separated by the line break, or its id when it has none
+func (n GraphNode) label(br string) string {
+	if len(n.Templates) == 0 {
+		return n.ID
+	}
+
+	return strings.Join(n.Templates, br)
+}
+
+// label returns the kind of the edge and its target
+func (e GraphEdge) label() string {
+	if e.Kind == EdgePartial {
+		return e.Target
+	}
+
+	return string(e.Kind) + ": " + e.Target
+}
+
+// mermaidEscape escapes the quotes of a Mermaid label
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
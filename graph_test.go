@@ -0,0 +1,48 @@
+package htmx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	layout := NewComponent("graph-layout.html", "graph-nav.html")
+	list := NewComponent("graph-list.html")
+	page := NewComponent("graph-page.html").With(list, "list").With(NewComponent("graph-item.html"), "item")
+	page.Wrap(layout, "content")
+
+	g := Graph(page)
+
+	equalInt(t, 4, len(g.Nodes))
+	equal(t, "graph-page.html", g.Nodes[0].Name)
+	equal(t, "graph-layout.html", g.Nodes[1].Name)
+	equalInt(t, 2, len(g.Nodes[1].Templates))
+	equalInt(t, 0, len(g.Cycles()))
+
+	dot := g.DOT()
+	equalBool(t, true, strings.Contains(dot, `n1 -> n0 [label="wrap: content", style=dashed];`))
+	equalBool(t, true, strings.Contains(dot, `n0 -> n2 [label="item"];`))
+	equalBool(t, true, strings.Contains(dot, `n1 [label="graph-layout.html\ngraph-nav.html"];`))
+
+	mermaid := g.Mermaid()
+	equalBool(t, true, strings.HasPrefix(mermaid, "flowchart TD\n"))
+	equalBool(t, true, strings.Contains(mermaid, `n1 -.->|"wrap: content"| n0`))
+	equalBool(t, true, strings.Contains(mermaid, `n0 -->|"list"| n3`))
+	equalBool(t, true, strings.Contains(mermaid, `n1["graph-layout.html<br>graph-nav.html"]`))
+}
+
+func TestGraphCycles(t *testing.T) {
+	a := NewComponent("graph-a.html")
+	b := NewComponent("graph-b.html")
+	a.With(b, "b")
+	b.With(NewComponent("graph-c.html"), "c")
+	b.With(a, "a")
+
+	g := Graph(a)
+
+	equalInt(t, 3, len(g.Nodes))
+
+	cycles := g.Cycles()
+	equalInt(t, 1, len(cycles))
+	equal(t, "n0 n1", strings.Join(cycles[0], " "))
+}
@@ -0,0 +1,428 @@
+// Package grid provides a spreadsheet-style editable grid component.
+//
+// Cells are edited in place with hx-put requests, rows are rendered in windows that are fetched while scrolling,
+// and edits are tracked as dirty state in a signed token until they are saved in a single batch.
+package grid
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+//go:embed templates
+var templates embed.FS
+
+type (
+	// Column describes a column of the grid
+	Column struct {
+		Key      string                   // key of the column in the row values
+		Label    string                   // header of the column
+		Editable bool                     // whether the cells of the column can be edited
+		Validate func(value string) error // optional validation of edited values, the error is shown in the cell
+	}
+
+	// Row is a row of the grid, the id must be usable as part of an html id
+	Row struct {
+		ID     string
+		Values map[string]string
+	}
+
+	// Change is an edited cell
+	Change struct {
+		Row    string
+		Column string
+		Value  string
+	}
+
+	// Source provides and stores the rows of a grid
+	Source interface {
+		// Count returns the number of rows
+		Count(ctx context.Context) (int, error)
+		// Rows returns at most limit rows starting at offset
+		Rows(ctx context.Context, offset, limit int) ([]Row, error)
+		// Row returns a single row
+		Row(ctx context.Context, id string) (Row, error)
+		// Save stores the changes of a batch save
+		Save(ctx context.Context, changes []Change) error
+	}
+
+	// Options configures a Grid
+	Options struct {
+		ID         string   // id of the grid element
+		Endpoint   string   // path the grid handler is mounted on, e.g. /products/grid
+		Columns    []Column // columns of the grid
+		WindowSize int      // number of rows rendered per window, defaults to 50
+	}
+
+	// Grid is the http handler and component of an editable grid
+	Grid struct {
+		opts   Options
+		view   *gridView
+		signer *htmx.Signer
+		source Source
+		mux    *http.ServeMux
+	}
+
+	// state is the dirty state of the grid, it is signed and kept in a hidden input: row id > column key > value
+	state struct {
+		Changes map[string]map[string]string `json:"c,omitempty"`
+	}
+
+	gridView struct {
+		ID       string
+		Endpoint string
+		Columns  []Column
+	}
+
+	rowsView struct {
+		Grid    *gridView
+		Rows    []rowView
+		HasNext bool
+		Next    int
+	}
+
+	rowView struct {
+		DOMID string
+		Cells []cellView
+	}
+
+	cellView struct {
+		Grid     *gridView
+		DOMID    string
+		Row      string
+		Column   string
+		Label    string
+		Value    string
+		Editable bool
+		Editing  bool
+		Dirty    bool
+		Error    string
+		OOB      bool
+	}
+
+	stateView struct {
+		Grid  *gridView
+		Token string
+		Dirty int
+		OOB   bool
+	}
+)
+
+// New returns a grid that signs its dirty state with a signer derived for the grid id and reads and stores its rows with the source
+func New(signer *htmx.Signer, source Source, opts Options) *Grid {
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+
+	if opts.WindowSize <= 0 {
+		opts.WindowSize = 50
+	}
+
+	g := &Grid{
+		opts:   opts,
+		view:   &gridView{ID: opts.ID, Endpoint: opts.Endpoint, Columns: opts.Columns},
+		signer: signer.Derive("grid/state/" + opts.ID),
+		source: source,
+		mux:    http.NewServeMux(),
+	}
+
+	g.mux.HandleFunc("GET "+opts.Endpoint+"/rows", g.rows)
+	g.mux.HandleFunc("GET "+opts.Endpoint+"/cell", g.edit)
+	g.mux.HandleFunc("PUT "+opts.Endpoint+"/cell", g.update)
+	g.mux.HandleFunc("POST "+opts.Endpoint+"/save", g.save)
+
+	return g
+}
+
+// ServeHTTP handles the requests of the grid, mount it on the endpoint and all paths below it
+//
+//	mux.Handle("/products/grid/", g)
+func (g *Grid) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Component returns the grid component with the first window of rows
+func (g *Grid) Component(ctx context.Context) (htmx.RenderableComponent, error) {
+	rows, err := g.window(ctx, 0, state{})
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := g.stateView(state{}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.component("grid.html").
+		AddData("Grid", g.view).
+		AddData("Rows", rows).
+		AddData("State", st), nil
+}
+
+// rows renders the next window of rows
+func (g *Grid) rows(w http.ResponseWriter, r *http.Request) {
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	st, err := g.state(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := g.window(r.Context(), offset, st)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	g.render(w, r, g.component("rows.html").AddData("Rows", rows))
+}
+
+// edit renders the input of a cell
+func (g *Grid) edit(w http.ResponseWriter, r *http.Request) {
+	cell, _, ok := g.cell(w, r)
+	if !ok {
+		return
+	}
+
+	cell.Editing = true
+	g.render(w, r, g.component("cell.html").AddData("Cell", cell))
+}
+
+// update validates the value of a cell and marks it as dirty
+func (g *Grid) update(w http.ResponseWriter, r *http.Request) {
+	cell, st, ok := g.cell(w, r)
+	if !ok {
+		return
+	}
+
+	value := r.FormValue("value")
+	column := g.column(cell.Column)
+
+	if column.Validate != nil {
+		if err := column.Validate(value); err != nil {
+			cell.Value, cell.Editing, cell.Error = value, true, err.Error()
+			g.render(w, r, g.component("cell.html").AddData("Cell", cell))
+			return
+		}
+	}
+
+	row, err := g.source.Row(r.Context(), cell.Row)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// a value that is changed back to the stored value is no longer dirty
+	if value == row.Values[cell.Column] {
+		delete(st.Changes[cell.Row], cell.Column)
+		if len(st.Changes[cell.Row]) == 0 {
+			delete(st.Changes, cell.Row)
+		}
+	} else {
+		if st.Changes == nil {
+			st.Changes = make(map[string]map[string]string)
+		}
+		if st.Changes[cell.Row] == nil {
+			st.Changes[cell.Row] = make(map[string]string)
+		}
+		st.Changes[cell.Row][cell.Column] = value
+	}
+
+	cell.Value, cell.Dirty = value, value != row.Values[cell.Column]
+
+	sv, err := g.stateView(st, true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	g.render(w, r, g.component("cell.html").AddData("Cell", cell).AddData("State", sv))
+}
+
+// save stores the dirty cells and returns them as out-of-band swaps
+func (g *Grid) save(w http.ResponseWriter, r *http.Request) {
+	st, err := g.state(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changes := st.changes()
+	if err := g.source.Save(r.Context(), changes); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	cells := make([]cellView, len(changes))
+	for i, c := range changes {
+		cells[i] = g.cellView(c.Row, g.column(c.Column), c.Value, false)
+		cells[i].OOB = true
+	}
+
+	sv, err := g.stateView(state{}, true)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	g.render(w, r, g.component("save.html").AddData("Cells", cells).AddData("State", sv))
+}
+
+// cell returns the cell of the request with its current value
+func (g *Grid) cell(w http.ResponseWriter, r *http.Request) (cellView, state, bool) {
+	id, key := r.URL.Query().Get("row"), r.URL.Query().Get("col")
+
+	column := g.column(key)
+	if column.Key == "" || !column.Editable {
+		http.Error(w, "column is not editable", http.StatusBadRequest)
+		return cellView{}, state{}, false
+	}
+
+	st, err := g.state(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return cellView{}, state{}, false
+	}
+
+	row, err := g.source.Row(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return cellView{}, state{}, false
+	}
+
+	value, dirty := st.Changes[row.ID][column.Key]
+	if !dirty {
+		value = row.Values[column.Key]
+	}
+
+	return g.cellView(row.ID, column, value, dirty), st, true
+}
+
+// window returns the rows of the window starting at offset, with the dirty values of the state
+func (g *Grid) window(ctx context.Context, offset int, st state) (rowsView, error) {
+	count, err := g.source.Count(ctx)
+	if err != nil {
+		return rowsView{}, err
+	}
+
+	rows, err := g.source.Rows(ctx, offset, g.opts.WindowSize)
+	if err != nil {
+		return rowsView{}, err
+	}
+
+	view := rowsView{Grid: g.view}
+	for _, row := range rows {
+		rv := rowView{DOMID: g.opts.ID + "-" + row.ID}
+		for _, column := range g.opts.Columns {
+			value, dirty := st.Changes[row.ID][column.Key]
+			if !dirty {
+				value = row.Values[column.Key]
+			}
+			rv.Cells = append(rv.Cells, g.cellView(row.ID, column, value, dirty))
+		}
+		view.Rows = append(view.Rows, rv)
+	}
+
+	if next := offset + len(rows); len(rows) > 0 && next < count {
+		view.HasNext, view.Next = true, next
+	}
+
+	return view, nil
+}
+
+// cellView returns the view of a cell
+func (g *Grid) cellView(row string, column Column, value string, dirty bool) cellView {
+	return cellView{
+		Grid:     g.view,
+		DOMID:    g.opts.ID + "-" + row + "-" + column.Key,
+		Row:      row,
+		Column:   column.Key,
+		Label:    column.Label,
+		Value:    value,
+		Editable: column.Editable,
+		Dirty:    dirty,
+	}
+}
+
+// stateView signs the state
+func (g *Grid) stateView(st state, oob bool) (stateView, error) {
+	token, err := g.signer.SignJSON(st)
+	if err != nil {
+		return stateView{}, err
+	}
+
+	return stateView{Grid: g.view, Token: token, Dirty: len(st.changes()), OOB: oob}, nil
+}
+
+// state returns the verified dirty state of the request
+func (g *Grid) state(r *http.Request) (state, error) {
+	var st state
+
+	token := r.FormValue("state")
+	if token == "" {
+		return st, nil
+	}
+
+	if err := g.signer.VerifyJSON(token, &st); err != nil {
+		return st, errors.New("invalid grid state")
+	}
+
+	return st, nil
+}
+
+// column returns the column with the key
+func (g *Grid) column(key string) Column {
+	for _, c := range g.opts.Columns {
+		if c.Key == key {
+			return c
+		}
+	}
+
+	return Column{}
+}
+
+// component returns a grid component for the template
+func (g *Grid) component(name string) *htmx.Component {
+	return htmx.NewComponent("templates/"+name, "templates/parts.html").FS(templates)
+}
+
+// render renders the component as a response
+func (g *Grid) render(w http.ResponseWriter, r *http.Request, c htmx.RenderableComponent) {
+	out, err := c.Render(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(out))
+}
+
+// changes returns the changes of the state in a stable order
+func (s state) changes() []Change {
+	var changes []Change
+	for row, columns := range s.Changes {
+		for column, value := range columns {
+			changes = append(changes, Change{Row: row, Column: column, Value: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Row == changes[j].Row {
+			return changes[i].Column < changes[j].Column
+		}
+		return changes[i].Row < changes[j].Row
+	})
+
+	return changes
+}
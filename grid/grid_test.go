@@ -0,0 +1,116 @@
+package grid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+type memorySource struct {
+	rows  []Row
+	saved []Change
+}
+
+func (s *memorySource) Count(context.Context) (int, error) { return len(s.rows), nil }
+
+func (s *memorySource) Rows(_ context.Context, offset, limit int) ([]Row, error) {
+	return s.rows[min(offset, len(s.rows)):min(offset+limit, len(s.rows))], nil
+}
+
+func (s *memorySource) Row(_ context.Context, id string) (Row, error) {
+	for _, r := range s.rows {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return Row{}, errors.New("row not found")
+}
+
+func (s *memorySource) Save(_ context.Context, changes []Change) error {
+	s.saved = append(s.saved, changes...)
+	return nil
+}
+
+func TestGrid(t *testing.T) {
+	source := &memorySource{}
+	for i := 0; i < 5; i++ {
+		id := strconv.Itoa(i)
+		source.rows = append(source.rows, Row{ID: id, Values: map[string]string{"name": "item " + id, "price": id}})
+	}
+
+	g := New(htmx.NewSigner([]byte("secret")), source, Options{
+		ID:         "products",
+		Endpoint:   "/grid",
+		WindowSize: 2,
+		Columns: []Column{
+			{Key: "name", Label: "Name"},
+			{Key: "price", Label: "Price", Editable: true, Validate: func(v string) error {
+				if _, err := strconv.Atoi(v); err != nil {
+					return errors.New("not a number")
+				}
+				return nil
+			}},
+		},
+	})
+
+	c, err := g.Component(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contains(t, string(out), `<tr id="products-1">`, `hx-get="/grid/rows?offset=2"`, `<span id="products-dirty" class="grid-dirty-count">0</span>`)
+	if strings.Contains(string(out), `products-2`) {
+		t.Errorf("expected only the first window to be rendered")
+	}
+
+	do := func(method, path string, form url.Values) string {
+		r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s %s: unexpected status %d: %s", method, path, w.Code, w.Body)
+		}
+		return w.Body.String()
+	}
+
+	contains(t, do(http.MethodGet, "/grid/rows?offset=4", nil), `<tr id="products-4">`)
+
+	out2 := do(http.MethodPut, "/grid/cell?row=1&col=price", url.Values{"value": {"abc"}})
+	contains(t, out2, `grid-invalid`, `not a number`)
+
+	out2 = do(http.MethodPut, "/grid/cell?row=1&col=price", url.Values{"value": {"10"}})
+	contains(t, out2, `<td id="products-1-price" class="grid-cell grid-dirty"`, `>10</td>`, `hx-swap-oob="true">1</span>`)
+
+	token := regexp.MustCompile(`name="state" value="([^"]+)"`).FindStringSubmatch(out2)[1]
+	out2 = do(http.MethodPost, "/grid/save", url.Values{"state": {token}})
+	contains(t, out2, `<td id="products-1-price" class="grid-cell" hx-swap-oob="true"`, `hx-swap-oob="true">0</span>`)
+
+	if len(source.saved) != 1 || source.saved[0] != (Change{Row: "1", Column: "price", Value: "10"}) {
+		t.Errorf("unexpected saved changes %v", source.saved)
+	}
+}
+
+func contains(t *testing.T, s string, expected ...string) {
+	t.Helper()
+
+	for _, e := range expected {
+		if !strings.Contains(s, e) {
+			t.Errorf("expected %q in %s", e, s)
+		}
+	}
+}
@@ -5,15 +5,22 @@ import (
 	"encoding/json"
 	"html/template"
 	"net/http"
+	"strings"
 )
 
 type (
 	Handler struct {
-		log      Logger
-		w        http.ResponseWriter
-		r        *http.Request
-		request  HxRequestHeader
-		response *HxResponseHeader
+		log           Logger
+		w             http.ResponseWriter
+		r             *http.Request
+		request       HxRequestHeader
+		response      *HxResponseHeader
+		flash         FlashStore
+		flashes       []Flash
+		flashesLoaded bool
+		global        map[string]any
+		globalLoaded  bool
+		wroteHeader   bool
 	}
 )
 
@@ -22,29 +29,40 @@ const (
 	StatusStopPolling = 286
 )
 
-// IsHxRequest returns true if the request is a htmx request.
+// IsHxRequest returns true if the request is a htmx request, the response varies by HX-Request.
 func (h *Handler) IsHxRequest() bool {
+	h.Vary(HxRequestHeaderRequest.String())
 	return h.request.HxRequest
 }
 
-// IsHxBoosted returns true if the request is a htmx request and the request is boosted
+// IsHxBoosted returns true if the request is a htmx request and the request is boosted, the response varies by HX-Boosted
 func (h *Handler) IsHxBoosted() bool {
+	h.Vary(HxRequestHeaderBoosted.String())
 	return h.request.HxBoosted
 }
 
-// IsHxHistoryRestoreRequest returns true if the request is a htmx request and the request is a history restore request
+// IsHxHistoryRestoreRequest returns true if the request is a htmx request and the request is a history restore request,
+// the response varies by HX-History-Restore-Request
 func (h *Handler) IsHxHistoryRestoreRequest() bool {
+	h.Vary(HxRequestHeaderHistoryRestoreRequest.String())
 	return h.request.HxHistoryRestoreRequest
 }
 
 // RenderPartial returns true if the request is an HTMX request that is either boosted or a standard request,
-// provided it is not a history restore request.
+// provided it is not a history restore request. The response varies by the headers it depends on.
 func (h *Handler) RenderPartial() bool {
+	h.Vary(fragmentVary...)
+	return h.renderPartial()
+}
+
+// renderPartial returns true if the request renders a partial, without adding to the Vary header
+func (h *Handler) renderPartial() bool {
 	return (h.request.HxRequest || h.request.HxBoosted) && !h.request.HxHistoryRestoreRequest
 }
 
 // Write writes the data to the connection as part of an HTTP reply.
 func (h *Handler) Write(data []byte) (n int, err error) {
+	h.wroteHeader = true
 	return h.w.Write(data)
 }
 
@@ -132,12 +150,21 @@ func (h *Handler) MustWriteJSON(data any) {
 	}
 }
 
-// WriteHeader sets the HTTP response header with the provided status code.
+// WriteHeader sends an HTTP response header with the provided status code. The headers that are set afterwards,
+// e.g. by Render, are not sent anymore.
 func (h *Handler) WriteHeader(code int) {
+	h.wroteHeader = true
 	h.w.WriteHeader(code)
 }
 
-// StopPolling sets the response status to 286, which will stop htmx from polling
+// Flush flushes the buffered body to the client
+func (h *Handler) Flush() {
+	h.wroteHeader = true
+	_ = http.NewResponseController(h.w).Flush()
+}
+
+// StopPolling writes the status 286, which will stop htmx from polling. Like WriteHeader it sends the headers, so
+// response headers like triggers have to be set before it.
 func (h *Handler) StopPolling() {
 	h.WriteHeader(StatusStopPolling)
 }
@@ -158,7 +185,8 @@ type LocationInput struct {
 
 }
 
-// Location can be used to trigger a client side redirection without reloading the whole page
+// Location can be used to trigger a client side redirection without reloading the whole page, see LocationWithObject
+// for the location with a path and select
 // https://htmx.org/headers/hx-location/
 func (h *Handler) Location(li *LocationInput) error {
 	payload, err := json.Marshal(li)
@@ -262,26 +290,110 @@ func (h *Handler) ResponseHeader(header HxResponseKey) string {
 
 // Render renders the given renderer with the given context and writes the output to the response writer
 func (h *Handler) Render(ctx context.Context, r RenderableComponent) (int, error) {
-	r.SetURL(h.r.URL)
+	ctx, cancel := h.abortContext(h.renderContext(ctx))
+	defer cancel()
 
-	output, err := r.Render(ctx)
+	output, _, err := h.renderPage(ctx, r)
 	if err != nil {
 		return 0, err
 	}
 
-	// If it's a partial render, return the output directly
-	if h.RenderPartial() {
-		return h.WriteHTML(output)
+	h.recordStats(r, len(output))
+	h.setFragmentManifest(output)
+	h.setPreloadCache(r)
+
+	if c, ok := r.(*Component); ok && c.contentType != "" {
+		h.w.Header().Set("Content-Type", c.contentType)
+	}
+
+	// Write the final output
+	return h.writeCompressed(htmlBytes(output))
+}
+
+// renderPage renders the component for the request: wrapped in its layouts for full pages, as a fragment with the head
+// elements and flashes for htmx requests. fragment is true for the latter.
+func (h *Handler) renderPage(ctx context.Context, r RenderableComponent) (output template.HTML, fragment bool, err error) {
+	r.SetRequest(h.r)
+	r.injectGlobalData(h.globalData())
+
+	// The component and its layouts render within the page, DefaultPostProcessor processes the page once
+	ctx = withOuterRender(ctx)
+	output, err = r.Render(ctx)
+	if err != nil {
+		return "", false, h.renderError(err)
+	}
+
+	// Recursively wrap the output if the component is wrapped, partial renders return the output directly
+	// and boosted renders of components with a boosted layout are wrapped in that layout only
+	h.varyFragment(r)
+	layout, target, boosted := h.boostedLayout(r)
+	if boosted || h.renderFragment(r) {
+		if boosted {
+			output, err = h.wrapBoosted(ctx, r, layout, target, output)
+			if err != nil {
+				return "", false, h.renderError(err)
+			}
+		}
+
+		flashes, err := h.renderFlashes(ctx)
+		if err != nil {
+			return "", false, err
+		}
+
+		output, err = postProcessPage(r, HeadFromContext(ctx).resolve(output, true)+flashes)
+		return output, true, err
 	}
 
-	// Recursively wrap the output if the component is wrapped
 	output, err = h.wrapOutput(ctx, r, output)
 	if err != nil {
-		return 0, err
+		return "", false, h.renderError(err)
+	}
+	output, err = postProcessPage(r, HeadFromContext(ctx).resolve(output, false))
+	if err != nil {
+		return "", false, err
 	}
 
-	// Write the final output
-	return h.WriteHTML(output)
+	if isDev() && hasDebugParam(h.r) {
+		output = withDebugOverlay(ctx, output)
+	}
+
+	return output, false, nil
+}
+
+// renderContext adds the request scoped values that are used by the context functions to the context
+func (h *Handler) renderContext(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(localeKey{}).(string); !ok {
+		ctx = WithLocale(ctx, LocaleFromRequest(h.r, SupportedLocales...))
+	}
+
+	if Nonce(ctx) == "" {
+		if nonce := Nonce(h.r.Context()); nonce != "" {
+			ctx = WithNonce(ctx, nonce)
+		}
+	}
+
+	if CSRFToken(ctx) == "" {
+		if token := CSRFToken(h.r.Context()); token != "" {
+			ctx = WithCSRFToken(ctx, token)
+		}
+	}
+
+	if RequestID(ctx) == "" {
+		if id := RequestID(h.r.Context()); id != "" {
+			ctx = WithRequestID(ctx, id)
+		}
+	}
+
+	if _, ok := ctx.Value(toastsKey{}).(http.Header); !ok {
+		ctx = withToasts(ctx, h.Header())
+	}
+
+	if HeadFromContext(ctx) == nil {
+		ctx = WithHead(ctx)
+	}
+	HeadFromContext(ctx).useSlot()
+
+	return ctx
 }
 
 // wrapOutput recursively wraps the output in its parent components
@@ -292,8 +404,14 @@ func (h *Handler) wrapOutput(ctx context.Context, r RenderableComponent, output
 	}
 
 	parent := r.wrapper()
-	parent.SetURL(h.r.URL)
+	parent.SetRequest(h.r)
 	parent.injectData(r.data())
+	parent.injectGlobalData(h.globalData())
+
+	if ValidateInheritance {
+		return h.wrapOutputValidated(ctx, r, parent, output)
+	}
+
 	parent.addPartial(r.target(), output)
 
 	// Render the parent component
@@ -305,3 +423,33 @@ func (h *Handler) wrapOutput(ctx context.Context, r RenderableComponent, output
 	// Recursively wrap the parent output if the parent is also wrapped
 	return h.wrapOutput(ctx, parent, parentOutput)
 }
+
+// wrapOutputValidated renders the parent with a placeholder in place of the output, so the attributes that the
+// output will inherit from the parent can be validated before the output is injected.
+func (h *Handler) wrapOutputValidated(ctx context.Context, r, parent RenderableComponent, output template.HTML) (template.HTML, error) {
+	parent.addPartial(r.target(), template.HTML(inheritanceSlot))
+
+	parentOutput, err := parent.Render(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	issues, err := CheckInheritance(parentOutput, inheritanceSlot, output)
+	if err != nil {
+		h.log.Warn("unable to validate attribute inheritance", "error", err)
+	}
+
+	for _, issue := range issues {
+		h.log.Warn("fragment inherits htmx attribute from layout",
+			"attribute", issue.Attribute,
+			"value", issue.Value,
+			"source", issue.Source,
+			"element", issue.Element,
+		)
+	}
+
+	parentOutput = template.HTML(strings.Replace(string(parentOutput), inheritanceSlot, string(output), 1))
+	parent.addPartial(r.target(), output)
+
+	return h.wrapOutput(ctx, parent, parentOutput)
+}
@@ -0,0 +1,209 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// headSlot marks the place of the head elements in the output of a handler, it is replaced once all components
+// contributed their elements
+const headSlot = "<!--htmx-head-->"
+
+type (
+	// Head collects the title, meta and link elements that the components of a render contribute to the head of the
+	// page, for the htmx head-support extension. Templates contribute with headTitle, headMeta and headLink, and the
+	// layout places the elements with headTags:
+	//
+	//	<head hx-head="merge"><meta charset="utf-8">{{ headTags }}</head>
+	//
+	// The first contribution of an element wins: a component renders before its layout, so a page overrides the
+	// defaults of its layout. Fragment renders without a headTags slot get the elements prepended as a
+	// <head hx-head="merge"> block.
+	Head struct {
+		mu    sync.Mutex
+		slot  bool
+		title string
+		meta  []string
+		links []string
+		seen  map[string]bool
+	}
+
+	headKey struct{}
+)
+
+// WithHead returns a context with a new head collector, the Handler adds one to every render
+func WithHead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, headKey{}, &Head{seen: make(map[string]bool)})
+}
+
+// HeadFromContext returns the head collector of the context, or nil when it has none
+func HeadFromContext(ctx context.Context) *Head {
+	head, _ := ctx.Value(headKey{}).(*Head)
+	return head
+}
+
+// Title sets the title of the page, unless it was set already
+func (h *Head) Title(title string) *Head {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.title == "" {
+		h.title = title
+	}
+
+	return h
+}
+
+// Meta adds a meta element, names with a colon like og:title are written as a property, except the twitter: names
+func (h *Head) Meta(name, content string) *Head {
+	attr := "name"
+	if strings.Contains(name, ":") && !strings.HasPrefix(name, "twitter:") {
+		attr = "property"
+	}
+
+	return h.add(&h.meta, "meta:"+name, fmt.Sprintf(`<meta %s="%s" content="%s">`,
+		attr, template.HTMLEscapeString(name), template.HTMLEscapeString(content)))
+}
+
+// Link adds a link element, e.g. a stylesheet or the canonical url
+func (h *Head) Link(rel, href string) *Head {
+	key := "link:" + rel + " " + href
+	if rel == "canonical" {
+		key = "link:canonical"
+	}
+
+	return h.add(&h.links, key, fmt.Sprintf(`<link rel="%s" href="%s">`,
+		template.HTMLEscapeString(rel), template.HTMLEscapeString(href)))
+}
+
+// Tags returns the title, the meta elements and the link elements
+func (h *Head) Tags() template.HTML {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var sb strings.Builder
+	if h.title != "" {
+		sb.WriteString("<title>" + template.HTMLEscapeString(h.title) + "</title>")
+	}
+
+	for _, tag := range slices.Concat(h.meta, h.links) {
+		sb.WriteString(tag)
+	}
+
+	return template.HTML(sb.String())
+}
+
+// HTML returns the elements in a head block for the head-support extension, or nothing when there are no elements
+func (h *Head) HTML() template.HTML {
+	tags := h.Tags()
+	if tags == "" {
+		return ""
+	}
+
+	return `<head hx-head="merge">` + tags + `</head>`
+}
+
+// add adds the element to the tags, unless an element with the key was added already
+func (h *Head) add(tags *[]string, key, tag string) *Head {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.seen[key] {
+		h.seen[key] = true
+		*tags = append(*tags, tag)
+	}
+
+	return h
+}
+
+// useSlot makes headTags return the slot, so the elements of components that render later are placed as well
+func (h *Head) useSlot() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.slot = true
+}
+
+// resolve replaces the slot of the output with the elements, or prepends the head block to fragments without a slot
+func (h *Head) resolve(output template.HTML, fragment bool) template.HTML {
+	if strings.Contains(string(output), headSlot) {
+		return template.HTML(strings.Replace(string(output), headSlot, string(h.Tags()), 1))
+	}
+
+	if fragment {
+		return h.HTML() + output
+	}
+
+	return output
+}
+
+// headTagsFunc is the headTags template function, it places the head elements. In a handler render it returns a slot
+// that is filled once all components rendered.
+//
+//	<head hx-head="merge">{{ headTags }}</head>
+func headTagsFunc(ctx context.Context, _ ...any) (any, error) {
+	head := HeadFromContext(ctx)
+	if head == nil {
+		return template.HTML(""), nil
+	}
+
+	head.mu.Lock()
+	slot := head.slot
+	head.mu.Unlock()
+
+	if slot {
+		return template.HTML(headSlot), nil
+	}
+
+	return head.Tags(), nil
+}
+
+// headTitleFunc is the headTitle template function
+//
+//	{{ headTitle "Users" }}
+func headTitleFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("headTitle requires a title")
+	}
+
+	if head := HeadFromContext(ctx); head != nil {
+		head.Title(fmt.Sprint(args[0]))
+	}
+
+	return "", nil
+}
+
+// headMetaFunc is the headMeta template function
+//
+//	{{ headMeta "description" "All users" }}
+func headMetaFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("headMeta requires a name and a content")
+	}
+
+	if head := HeadFromContext(ctx); head != nil {
+		head.Meta(fmt.Sprint(args[0]), fmt.Sprint(args[1]))
+	}
+
+	return "", nil
+}
+
+// headLinkFunc is the headLink template function
+//
+//	{{ headLink "stylesheet" "/assets/users.css" }}
+func headLinkFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("headLink requires a rel and a href")
+	}
+
+	if head := HeadFromContext(ctx); head != nil {
+		head.Link(fmt.Sprint(args[0]), fmt.Sprint(args[1]))
+	}
+
+	return "", nil
+}
@@ -0,0 +1,52 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHead(t *testing.T) {
+	fsys := fstest.MapFS{
+		"head-layout.html": {Data: []byte(`{{ headTitle "App" }}<html><head hx-head="merge"><meta charset="utf-8">{{ headTags }}</head><body>{{ .Partials.content }}</body></html>`)},
+		"head-users.html": {Data: []byte(`{{ headTitle "Users" }}{{ headMeta "description" "All <users>" }}{{ headMeta "og:title" "Users" }}` +
+			`{{ headLink "stylesheet" "/users.css" }}<ul>{{ .Partials.row }}</ul>`)},
+		"head-row.html": {Data: []byte(`{{ headLink "stylesheet" "/users.css" }}<li></li>`)},
+	}
+
+	tags := `<title>Users</title><meta name="description" content="All &lt;users&gt;"><meta property="og:title" content="Users">` +
+		`<link rel="stylesheet" href="/users.css">`
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"full page load", nil,
+			`<html><head hx-head="merge"><meta charset="utf-8">` + tags + `</head><body><ul><li></li></ul></body></html>`},
+		{"htmx request", map[string]string{"HX-Request": "true"},
+			`<head hx-head="merge">` + tags + `</head><ul><li></li></ul>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			page := NewComponent("head-users.html").FS(fsys).
+				With(NewComponent("head-row.html").FS(fsys), "row").
+				Wrap(NewComponent("head-layout.html").FS(fsys), "content")
+
+			w := httptest.NewRecorder()
+			if _, err := New().NewHandler(w, r).Render(context.Background(), page); err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jkc-2/go-htmx/sse"
@@ -30,14 +31,20 @@ type (
 	}
 
 	HTMX struct {
-		log Logger
+		log        Logger
+		mu         sync.RWMutex
+		components map[string]ComponentFactory
+		routes     []*ComponentRoute
+		services   []Service
+		flash      FlashStore
 	}
 )
 
 // New returns a new htmx instance.
 func New() *HTMX {
 	return &HTMX{
-		log: slog.Default().WithGroup("htmx"),
+		log:        slog.Default().WithGroup("htmx"),
+		components: make(map[string]ComponentFactory),
 	}
 }
 
@@ -54,6 +61,7 @@ func (h *HTMX) NewHandler(w http.ResponseWriter, r *http.Request) *Handler {
 		request:  h.HxHeader(r),
 		response: h.HxResponseHeader(w.Header()),
 		log:      h.log,
+		flash:    h.flash,
 	}
 }
 
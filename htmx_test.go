@@ -267,3 +267,24 @@ func equalInt(t *testing.T, expected, actual int) {
 		t.Errorf("expected %d, got %d", expected, actual)
 	}
 }
+
+func TestParseRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Current-URL", "https://example.com/pets")
+	r.Header.Set("HX-Target", "list")
+	r.Header.Set("HX-Trigger-Name", "q")
+	r.Header.Set("HX-Prompt", "J%C3%BCrgen%20M%C3%BCller")
+	r.Header.Set("HX-Prompt-URI-AutoEncoded", "true")
+
+	req := ParseRequest(r)
+
+	equalBool(t, true, req.IsHTMX)
+	equalBool(t, false, req.Boosted)
+	equalBool(t, false, req.HistoryRestoreRequest)
+	equal(t, "https://example.com/pets", req.CurrentURL)
+	equal(t, "list", req.Target)
+	equal(t, "q", req.TriggerName)
+	equal(t, "", req.Trigger)
+	equal(t, "Jürgen Müller", req.Prompt)
+}
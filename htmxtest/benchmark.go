@@ -0,0 +1,302 @@
+package htmxtest
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+const (
+	// PhaseOther is the part of a render outside of the parse, partials and execute phases, e.g. sanitizing and post
+	// processing the output
+	PhaseOther htmx.RenderPhase = "other"
+
+	// maxPhaseRuns caps the renders that measure the duration of the phases
+	maxPhaseRuns = 1000
+
+	// maxAllocRuns caps the renders that measure the allocations of the phases, they stop the world at every phase
+	maxAllocRuns = 100
+)
+
+type (
+	// BenchmarkResult is the cost of rendering a component, in total and per phase. The phases are exclusive: the
+	// partials phase is the cost of rendering the partials without their own parse and execute phases, so the phases
+	// add up to the total.
+	BenchmarkResult struct {
+		Component   string        // the name of the component
+		N           int           // the number of renders of the benchmark
+		NsPerOp     int64         // the nanoseconds per render
+		AllocsPerOp int64         // the allocations per render
+		BytesPerOp  int64         // the allocated bytes per render
+		Phases      []PhaseResult // the parse, partials, execute and other phases
+	}
+
+	// PhaseResult is the cost of a phase per render
+	PhaseResult struct {
+		Phase       htmx.RenderPhase
+		NsPerOp     int64
+		AllocsPerOp int64
+		BytesPerOp  int64
+	}
+
+	// phaseRecorder measures the exclusive cost of the phases of renders, it is installed as the htmx.RenderMetrics
+	phaseRecorder struct {
+		mu        sync.Mutex
+		allocs    bool
+		base      time.Time
+		component string
+		stack     []phaseFrame
+		totals    map[htmx.RenderPhase]*phaseCost
+		end       func()
+	}
+
+	// phaseFrame is a started phase with the cost of its nested phases
+	phaseFrame struct {
+		phase    htmx.RenderPhase
+		start    phaseCost
+		children phaseCost
+	}
+
+	// phaseCost is the duration and allocations of a phase, or the counters at its start
+	phaseCost struct {
+		ns, allocs, bytes int64
+	}
+)
+
+// Benchmark renders the component with the Context until the measurements are stable and returns the cost per render
+// split across the parse, partials and execute phases. The template cache stays warm, so parsing is a cache lookup;
+// use BenchmarkCold for the cost of parsing.
+//
+//	func BenchmarkDashboard(b *testing.B) {
+//		result, err := htmxtest.Benchmark(views.NewDashboard())
+//		if err != nil {
+//			b.Fatal(err)
+//		}
+//		result.Report(b)
+//	}
+//
+// Benchmark replaces htmx.RenderMetrics while it runs, it must not run in parallel with other renders.
+func Benchmark(c htmx.RenderableComponent) (BenchmarkResult, error) {
+	return benchmark(c, false)
+}
+
+// BenchmarkCold is like Benchmark but clears the template cache before every render, so the parse phase parses the
+// templates
+func BenchmarkCold(c htmx.RenderableComponent) (BenchmarkResult, error) {
+	return benchmark(c, true)
+}
+
+// Report reports the phases of the result as custom metrics of the benchmark, e.g. parse-ns/op and parse-allocs/op
+func (r BenchmarkResult) Report(b *testing.B) {
+	b.Helper()
+
+	for _, p := range r.Phases {
+		b.ReportMetric(float64(p.NsPerOp), string(p.Phase)+"-ns/op")
+		b.ReportMetric(float64(p.AllocsPerOp), string(p.Phase)+"-allocs/op")
+	}
+}
+
+// String returns the result as a table with a row per phase
+func (r BenchmarkResult) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d renders\n", r.Component, r.N)
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "phase\tns/op\tallocs/op\tB/op\t")
+	for _, p := range r.Phases {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t\n", p.Phase, p.NsPerOp, p.AllocsPerOp, p.BytesPerOp)
+	}
+	fmt.Fprintf(w, "total\t%d\t%d\t%d\t\n", r.NsPerOp, r.AllocsPerOp, r.BytesPerOp)
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// benchmark measures the renders of the component, with a cold template cache when cold is true
+func benchmark(c htmx.RenderableComponent, cold bool) (BenchmarkResult, error) {
+	ctx := Context()
+
+	if _, err := c.Render(ctx); err != nil {
+		return BenchmarkResult{}, fmt.Errorf("htmxtest: render: %w", err)
+	}
+
+	previous := htmx.RenderMetrics
+	defer func() { htmx.RenderMetrics = previous }()
+	htmx.RenderMetrics = nil
+
+	var renderErr error
+	total := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if cold {
+				b.StopTimer()
+				htmx.ClearTemplateCache()
+				b.StartTimer()
+			}
+			if _, err := c.Render(ctx); err != nil {
+				renderErr = err
+				b.SkipNow()
+			}
+		}
+	})
+	if renderErr != nil {
+		return BenchmarkResult{}, fmt.Errorf("htmxtest: render: %w", renderErr)
+	}
+
+	result := BenchmarkResult{
+		N:           total.N,
+		NsPerOp:     total.NsPerOp(),
+		AllocsPerOp: total.AllocsPerOp(),
+		BytesPerOp:  total.AllocedBytesPerOp(),
+	}
+
+	durations, elapsed, component, err := measurePhases(ctx, c, cold, false, min(max(total.N, 1), maxPhaseRuns))
+	if err != nil {
+		return BenchmarkResult{}, err
+	}
+
+	result.Component = component
+
+	allocs, _, _, err := measurePhases(ctx, c, cold, true, min(max(total.N, 1), maxAllocRuns))
+	if err != nil {
+		return BenchmarkResult{}, err
+	}
+
+	other := PhaseResult{Phase: PhaseOther, NsPerOp: elapsed, AllocsPerOp: result.AllocsPerOp, BytesPerOp: result.BytesPerOp}
+	for _, phase := range []htmx.RenderPhase{htmx.PhaseParse, htmx.PhasePartials, htmx.PhaseExecute} {
+		p := PhaseResult{
+			Phase:       phase,
+			NsPerOp:     durations[phase].ns,
+			AllocsPerOp: allocs[phase].allocs,
+			BytesPerOp:  allocs[phase].bytes,
+		}
+		result.Phases = append(result.Phases, p)
+
+		other.NsPerOp -= p.NsPerOp
+		other.AllocsPerOp -= p.AllocsPerOp
+		other.BytesPerOp -= p.BytesPerOp
+	}
+	other.NsPerOp = max(other.NsPerOp, 0)
+	other.AllocsPerOp = max(other.AllocsPerOp, 0)
+	other.BytesPerOp = max(other.BytesPerOp, 0)
+	result.Phases = append(result.Phases, other)
+
+	return result, nil
+}
+
+// measurePhases renders the component n times with a phase recorder and returns the cost of the phases, the
+// nanoseconds per render and the name of the component. Allocations are counted when allocs is true, durations
+// otherwise.
+func measurePhases(ctx context.Context, c htmx.RenderableComponent, cold, allocs bool, n int) (map[htmx.RenderPhase]phaseCost, int64, string, error) {
+	recorder := newPhaseRecorder(allocs)
+	htmx.RenderMetrics = recorder
+	defer func() { htmx.RenderMetrics = nil }()
+
+	var elapsed time.Duration
+	for i := 0; i < n; i++ {
+		if cold {
+			htmx.ClearTemplateCache()
+		}
+
+		start := time.Now()
+		if _, err := c.Render(ctx); err != nil {
+			return nil, 0, "", fmt.Errorf("htmxtest: render: %w", err)
+		}
+		elapsed += time.Since(start)
+	}
+
+	costs := make(map[htmx.RenderPhase]phaseCost, len(recorder.totals))
+	for phase, total := range recorder.totals {
+		costs[phase] = phaseCost{ns: total.ns / int64(n), allocs: total.allocs / int64(n), bytes: total.bytes / int64(n)}
+	}
+
+	return costs, elapsed.Nanoseconds() / int64(n), recorder.component, nil
+}
+
+// newPhaseRecorder returns a recorder of durations, or of allocations when allocs is true
+func newPhaseRecorder(allocs bool) *phaseRecorder {
+	r := &phaseRecorder{
+		allocs: allocs,
+		base:   time.Now(),
+		stack:  make([]phaseFrame, 0, 64),
+		totals: map[htmx.RenderPhase]*phaseCost{
+			htmx.PhaseParse:    {},
+			htmx.PhasePartials: {},
+			htmx.PhaseExecute:  {},
+		},
+	}
+	// the end function is bound once, so ending a phase doesn't allocate
+	r.end = r.endPhase
+
+	return r
+}
+
+// ObserveRender implements htmx.MetricsObserver
+func (r *phaseRecorder) ObserveRender(htmx.RenderInfo, htmx.RenderStats) {}
+
+// ObserveCacheEvictions implements htmx.MetricsObserver
+func (r *phaseRecorder) ObserveCacheEvictions(int) {}
+
+// StartPhase implements htmx.PhaseObserver
+func (r *phaseRecorder) StartPhase(info htmx.RenderInfo, phase htmx.RenderPhase) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the first phase of a render is the partials phase of the rendered component
+	if r.component == "" && len(r.stack) == 0 {
+		r.component = info.Component
+	}
+
+	r.stack = append(r.stack, phaseFrame{phase: phase})
+	r.stack[len(r.stack)-1].start = r.now()
+
+	return r.end
+}
+
+// endPhase ends the phase that was started last and adds its exclusive cost to the totals
+func (r *phaseRecorder) endPhase() {
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.stack) == 0 {
+		return
+	}
+
+	frame := r.stack[len(r.stack)-1]
+	r.stack = r.stack[:len(r.stack)-1]
+
+	inclusive := phaseCost{ns: now.ns - frame.start.ns, allocs: now.allocs - frame.start.allocs, bytes: now.bytes - frame.start.bytes}
+
+	total := r.totals[frame.phase]
+	total.ns += inclusive.ns - frame.children.ns
+	total.allocs += inclusive.allocs - frame.children.allocs
+	total.bytes += inclusive.bytes - frame.children.bytes
+
+	if len(r.stack) > 0 {
+		parent := &r.stack[len(r.stack)-1].children
+		parent.ns += inclusive.ns
+		parent.allocs += inclusive.allocs
+		parent.bytes += inclusive.bytes
+	}
+}
+
+// now returns the current time, or the allocation counters when the recorder counts allocations
+func (r *phaseRecorder) now() phaseCost {
+	if !r.allocs {
+		return phaseCost{ns: int64(time.Since(r.base))}
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return phaseCost{allocs: int64(m.Mallocs), bytes: int64(m.TotalAlloc)}
+}
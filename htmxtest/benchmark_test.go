@@ -0,0 +1,50 @@
+package htmxtest
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestBenchmark(t *testing.T) {
+	if testing.Short() {
+		t.Skip("benchmarks the renders")
+	}
+
+	fsys := fstest.MapFS{
+		"bench-page.html": {Data: []byte(`<main>{{ .Partials.list }}</main>`)},
+		"bench-list.html": {Data: []byte(`<ul>{{ range .Data.Items }}<li>{{ . }}</li>{{ end }}</ul>`)},
+	}
+
+	list := htmx.NewComponent("bench-list.html").FS(fsys).AddData("Items", []string{"a", "b", "c"})
+	page := htmx.NewComponent("bench-page.html").FS(fsys).With(list, "list")
+
+	result, err := BenchmarkCold(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "bench-page.html", result.Component)
+	equal(t, 4, len(result.Phases))
+	equal(t, true, result.N > 0 && result.NsPerOp > 0 && result.AllocsPerOp > 0)
+
+	var phases []string
+	for _, p := range result.Phases {
+		phases = append(phases, string(p.Phase))
+	}
+	equal(t, "parse partials execute other", strings.Join(phases, " "))
+
+	// parsing allocates the most on a cold cache
+	parse := result.Phases[0]
+	equal(t, true, parse.AllocsPerOp > result.Phases[2].AllocsPerOp)
+	equal(t, true, strings.Contains(result.String(), "parse"))
+
+	equal(t, true, htmx.RenderMetrics == nil)
+}
+
+func TestBenchmarkError(t *testing.T) {
+	_, err := Benchmark(htmx.NewComponent("bench-missing.html").FS(fstest.MapFS{}))
+	equal(t, true, err != nil)
+}
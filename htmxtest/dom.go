@@ -0,0 +1,216 @@
+package htmxtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jkc-2/go-htmx/internal/selector"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Markup is the rendered html, the output of a component or the body of a response
+type Markup interface {
+	~string | ~[]byte
+}
+
+// Query returns the elements of the html that match the CSS selector in document order. Selectors support tag
+// names, #id, .class, attributes with [attr], [attr=value], [attr~=value], [attr^=value], [attr$=value] and
+// [attr*=value], and the descendant and child (>) combinators.
+func Query[M Markup](out M, sel string) ([]*html.Node, error) {
+	s, err := selector.Parse(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, err := parse(string(out))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*html.Node
+	for _, root := range roots {
+		selector.Walk(root, func(n *html.Node) {
+			if n.Type == html.ElementNode && s.Match(n) {
+				matches = append(matches, n)
+			}
+		})
+	}
+
+	return matches, nil
+}
+
+// AssertExists asserts that an element of the html matches the selector
+func AssertExists[M Markup](t testing.TB, out M, sel string) {
+	t.Helper()
+
+	if nodes, ok := query(t, out, sel); ok && len(nodes) == 0 {
+		t.Errorf("htmxtest: no element matches %q in\n%s", sel, out)
+	}
+}
+
+// AssertNotExists asserts that no element of the html matches the selector
+func AssertNotExists[M Markup](t testing.TB, out M, sel string) {
+	t.Helper()
+
+	if nodes, ok := query(t, out, sel); ok && len(nodes) > 0 {
+		t.Errorf("htmxtest: expected no element to match %q, got %d in\n%s", sel, len(nodes), out)
+	}
+}
+
+// AssertCount asserts the number of elements of the html that match the selector
+func AssertCount[M Markup](t testing.TB, out M, sel string, count int) {
+	t.Helper()
+
+	if nodes, ok := query(t, out, sel); ok && len(nodes) != count {
+		t.Errorf("htmxtest: expected %d elements to match %q, got %d in\n%s", count, sel, len(nodes), out)
+	}
+}
+
+// AssertAttr asserts the value of the attribute of the first element that matches the selector
+//
+//	htmxtest.AssertAttr(t, out, "#list", "hx-get", "/users?page=2")
+func AssertAttr[M Markup](t testing.TB, out M, sel, attr, value string) {
+	t.Helper()
+
+	n, ok := first(t, out, sel)
+	if !ok {
+		return
+	}
+
+	actual, ok := selector.Attribute(n, attr)
+	switch {
+	case !ok:
+		t.Errorf("htmxtest: %q has no %s attribute: %s", sel, attr, render(n))
+	case actual != value:
+		t.Errorf("htmxtest: expected %s=%q on %q, got %q", attr, value, sel, actual)
+	}
+}
+
+// AssertText asserts the text of the first element that matches the selector, its whitespace collapsed
+func AssertText[M Markup](t testing.TB, out M, sel, text string) {
+	t.Helper()
+
+	n, ok := first(t, out, sel)
+	if !ok {
+		return
+	}
+
+	if actual := textContent(n); actual != text {
+		t.Errorf("htmxtest: expected the text %q in %q, got %q", text, sel, actual)
+	}
+}
+
+// AssertOOB asserts that the html contains an out-of-band swap into the target, e.g. #count, with the swap style,
+// e.g. outerHTML. Elements with their own id and hx-swap-oob="true" swap outerHTML into themselves, an empty swap
+// matches any style.
+func AssertOOB[M Markup](t testing.TB, out M, target, swap string) {
+	t.Helper()
+
+	nodes, ok := query(t, out, "[hx-swap-oob]")
+	if !ok {
+		return
+	}
+
+	var found []string
+	for _, n := range nodes {
+		style, into := oobTarget(n)
+		if into == target && (swap == "" || style == swap) {
+			return
+		}
+		found = append(found, style+" "+into)
+	}
+
+	t.Errorf("htmxtest: no out-of-band swap %s into %q, found %v", swap, target, found)
+}
+
+// oobTarget returns the swap style and the target selector of the out-of-band element
+func oobTarget(n *html.Node) (string, string) {
+	value, _ := selector.Attribute(n, "hx-swap-oob")
+	style, target, ok := strings.Cut(value, ":")
+
+	if style == "true" || style == "" {
+		style = "outerHTML"
+	}
+
+	if !ok {
+		id, _ := selector.Attribute(n, "id")
+		target = "#" + id
+	}
+
+	return style, target
+}
+
+// query returns the elements that match the selector, it reports invalid html and selectors to the test
+func query[M Markup](t testing.TB, out M, sel string) ([]*html.Node, bool) {
+	t.Helper()
+
+	nodes, err := Query(out, sel)
+	if err != nil {
+		t.Errorf("htmxtest: %v", err)
+		return nil, false
+	}
+
+	return nodes, true
+}
+
+// first returns the first element that matches the selector, it reports a missing element to the test
+func first[M Markup](t testing.TB, out M, sel string) (*html.Node, bool) {
+	t.Helper()
+
+	nodes, ok := query(t, out, sel)
+	if !ok {
+		return nil, false
+	}
+
+	if len(nodes) == 0 {
+		t.Errorf("htmxtest: no element matches %q in\n%s", sel, out)
+		return nil, false
+	}
+
+	return nodes[0], true
+}
+
+// parse parses a document or a fragment, fragments that start with table rows or cells are parsed in their table
+// context so the parser doesn't drop them
+func parse(out string) ([]*html.Node, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(out))
+	if strings.HasPrefix(trimmed, "<!doctype") || strings.HasPrefix(trimmed, "<html") {
+		doc, err := html.Parse(strings.NewReader(out))
+		if err != nil {
+			return nil, err
+		}
+		return []*html.Node{doc}, nil
+	}
+
+	tag := atom.Body
+	z := html.NewTokenizer(strings.NewReader(out))
+	for tt := z.Next(); tt != html.ErrorToken; tt = z.Next() {
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Tr:
+				tag = atom.Tbody
+			case atom.Td, atom.Th:
+				tag = atom.Tr
+			case atom.Thead, atom.Tbody, atom.Tfoot, atom.Caption, atom.Colgroup:
+				tag = atom.Table
+			}
+			break
+		}
+	}
+
+	return html.ParseFragment(strings.NewReader(out), &html.Node{Type: html.ElementNode, Data: tag.String(), DataAtom: tag})
+}
+
+// textContent returns the text of the element and its descendants, its whitespace collapsed
+func textContent(n *html.Node) string {
+	return strings.Join(strings.Fields(selector.Text(n)), " ")
+}
+
+// render returns the html of the element, for error messages
+func render(n *html.Node) string {
+	var sb strings.Builder
+	_ = html.Render(&sb, n)
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package htmxtest
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+// recorder records the failures of an assertion
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+const list = template.HTML(`<ul id="users" class="list striped">
+	<li class="user"><a href="/users/1" hx-get="/users/1" hx-target="#main">Ada  Lovelace</a></li>
+	<li class="user admin"><a href="/users/2">Alan</a></li>
+	<li id="more" hx-get="/users?page=2" hx-trigger="revealed" hx-swap="outerHTML"></li>
+</ul>
+<span id="count" hx-swap-oob="true">2</span>
+<div hx-swap-oob="innerHTML:#status">ok</div>`)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		selector string
+		count    int
+	}{
+		{"li", 3},
+		{"#users > li.user", 2},
+		{"ul a", 2},
+		{"ul > a", 0},
+		{".user.admin", 1},
+		{"[hx-get]", 2},
+		{`a[hx-target="#main"]`, 1},
+		{"[hx-get^='/users?']", 1},
+		{"[class~=striped]", 1},
+		{"*[hx-swap-oob]", 2},
+	}
+
+	for _, tt := range tests {
+		nodes, err := Query(list, tt.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(nodes) != tt.count {
+			t.Errorf("%s: expected %d elements, got %d", tt.selector, tt.count, len(nodes))
+		}
+	}
+
+	for _, invalid := range []string{"", "> li", "li >", "li[", "li#"} {
+		if _, err := Query(list, invalid); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+
+	rows, err := Query("<tr><td>1</td></tr><tr><td>2</td></tr>", "tr > td")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, 2, len(rows))
+}
+
+func TestAssertions(t *testing.T) {
+	AssertExists(t, list, "#more")
+	AssertNotExists(t, list, "#missing")
+	AssertCount(t, list, "li.user", 2)
+	AssertAttr(t, list, "#more", "hx-get", "/users?page=2")
+	AssertText(t, list, "li.user a", "Ada Lovelace")
+	AssertOOB(t, list, "#count", "outerHTML")
+	AssertOOB(t, list, "#status", "innerHTML")
+	AssertOOB(t, []byte(list), "#status", "")
+
+	r := &recorder{TB: t}
+	AssertAttr(r, list, "#more", "hx-get", "/users?page=3")
+	AssertAttr(r, list, "#more", "hx-post", "/users")
+	AssertText(r, list, "#missing", "")
+	AssertOOB(r, list, "#status", "outerHTML")
+
+	expected := []string{
+		`htmxtest: expected hx-get="/users?page=3" on "#more", got "/users?page=2"`,
+		`htmxtest: "#more" has no hx-post attribute`,
+		`htmxtest: no element matches "#missing"`,
+		`htmxtest: no out-of-band swap outerHTML into "#status"`,
+	}
+
+	if len(r.errors) != len(expected) {
+		t.Fatalf("expected %d failures, got %v", len(expected), r.errors)
+	}
+
+	for i, want := range expected {
+		if !strings.HasPrefix(r.errors[i], want) {
+			t.Errorf("expected %s, got %s", want, r.errors[i])
+		}
+	}
+}
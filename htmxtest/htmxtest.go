@@ -0,0 +1,141 @@
+// Package htmxtest helps testing components with snapshots: RenderSnapshot renders a component with deterministic
+// request data and compares its normalized output with a golden file in testdata.
+//
+//	func TestUserCard(t *testing.T) {
+//		htmxtest.RenderSnapshot(t, views.NewUserCard().AddData("Name", "Ada"))
+//	}
+//
+// Run the tests with HTMXTEST_UPDATE=1 to write the golden files after changing a template:
+//
+//	HTMXTEST_UPDATE=1 go test ./views
+//
+// Test packages with an -update flag of their own set Update from it:
+//
+//	var update = flag.Bool("update", false, "write the golden files")
+//
+//	func TestMain(m *testing.M) {
+//		flag.Parse()
+//		htmxtest.Update = *update
+//		os.Exit(m.Run())
+//	}
+package htmxtest
+
+import (
+	"context"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+const (
+	// Nonce is the CSP nonce of the snapshot renders
+	Nonce = "htmxtest-nonce"
+
+	// RequestID is the request id of the snapshot renders
+	RequestID = "htmxtest-request"
+
+	// Locale is the locale of the snapshot renders
+	Locale = "en"
+)
+
+var (
+	// Update writes the golden files instead of comparing with them, it's set when the environment variable
+	// HTMXTEST_UPDATE is set to a value other than empty or 0
+	Update = os.Getenv("HTMXTEST_UPDATE") != "" && os.Getenv("HTMXTEST_UPDATE") != "0"
+
+	whitespace   = regexp.MustCompile(`\s+`)
+	betweenTags  = regexp.MustCompile(`>\s+<`)
+	unsafeInName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// Context returns a context with deterministic request data: the Nonce, the RequestID and the Locale
+func Context() context.Context {
+	ctx := htmx.WithNonce(context.Background(), Nonce)
+	ctx = htmx.WithRequestID(ctx, RequestID)
+
+	return htmx.WithLocale(ctx, Locale)
+}
+
+// RenderSnapshot renders the component with the Context and compares its normalized output with the golden file
+// testdata/<name of the test>.golden. The golden file is written instead when Update is set.
+func RenderSnapshot(t testing.TB, c htmx.RenderableComponent) {
+	t.Helper()
+
+	RenderSnapshotNamed(t, t.Name(), c)
+}
+
+// RenderSnapshotNamed is like RenderSnapshot with the golden file testdata/<name>.golden, for tests with several
+// snapshots
+func RenderSnapshotNamed(t testing.TB, name string, c htmx.RenderableComponent) {
+	t.Helper()
+
+	output, err := c.Render(Context())
+	if err != nil {
+		t.Fatalf("htmxtest: render: %v", err)
+	}
+
+	MatchSnapshot(t, name, output)
+}
+
+// MatchSnapshot compares the normalized html with the golden file testdata/<name>.golden, or writes the golden file
+// when Update is set
+func MatchSnapshot(t testing.TB, name string, html template.HTML) {
+	t.Helper()
+
+	actual := Normalize(string(html))
+	golden := filepath.Join("testdata", unsafeInName.ReplaceAllString(name, "_")+".golden")
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("htmxtest: %v", err)
+		}
+
+		if err := os.WriteFile(golden, []byte(actual), 0o644); err != nil {
+			t.Fatalf("htmxtest: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("htmxtest: %v, run the test with HTMXTEST_UPDATE=1 to write the golden file", err)
+	}
+
+	if string(expected) != actual {
+		t.Errorf("htmxtest: the output doesn't match %s, run the test with HTMXTEST_UPDATE=1 to accept it\n%s", golden,
+			diff(string(expected), actual))
+	}
+}
+
+// Normalize collapses the whitespace of the html into single spaces, and breaks the line where whitespace separates two
+// tags, so golden files don't change with the indentation of the templates and diff line by line. The whitespace of
+// pre and textarea elements is collapsed as well.
+func Normalize(html string) string {
+	html = whitespace.ReplaceAllString(strings.TrimSpace(html), " ")
+	return betweenTags.ReplaceAllString(html, ">\n<") + "\n"
+}
+
+// diff returns the lines of the expected and actual output starting at the first line that differs
+func diff(expected, actual string) string {
+	e, a := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+
+	first := 0
+	for first < len(e) && first < len(a) && e[first] == a[first] {
+		first++
+	}
+
+	var sb strings.Builder
+	for i := first; i < len(e) && i < first+5; i++ {
+		sb.WriteString("- " + e[i] + "\n")
+	}
+	for i := first; i < len(a) && i < first+5; i++ {
+		sb.WriteString("+ " + a[i] + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package htmxtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestRenderSnapshot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"htmxtest-card.html": {Data: []byte(`<div class="card">
+	<h2>{{ .Data.Name }}</h2>
+	<script nonce="{{ nonce }}"></script>
+</div>`)},
+	}
+
+	RenderSnapshot(t, htmx.NewComponent("htmxtest-card.html").FS(fsys).AddData("Name", "Ada"))
+}
+
+func TestUpdate(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(dir) }()
+
+	Update = true
+	MatchSnapshot(t, "updated", "<p>a</p>")
+	Update = false
+
+	golden, err := os.ReadFile(filepath.Join("testdata", "updated.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<p>a</p>\n", string(golden))
+
+	MatchSnapshot(t, "updated", "<p>a</p>")
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"<p>a</p>", "<p>a</p>\n"},
+		{"\n\t<ul>\n\t\t<li>a  b</li>\n\t\t<li>c</li>\n\t</ul>\n", "<ul>\n<li>a b</li>\n<li>c</li>\n</ul>\n"},
+		{"text <b>bold</b> text", "text <b>bold</b> text\n"},
+	}
+
+	for _, tt := range tests {
+		equal(t, tt.expected, Normalize(tt.input))
+	}
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
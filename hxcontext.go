@@ -0,0 +1,47 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+)
+
+type hxContextKey struct{}
+
+// Middleware parses the htmx request headers once and stores them in the context of the request, handlers and
+// components read them with FromContext.
+//
+//	http.ListenAndServe(":8080", htmx.Middleware(mux))
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), hxContextKey{}, ParseRequest(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FromContext returns the htmx request headers stored by Middleware. Without the middleware, the headers of the
+// request of the component that is being rendered are parsed. It returns false when the context has neither.
+//
+//	if req, ok := htmx.FromContext(ctx); ok && req.Target == "results" { ... }
+func FromContext(ctx context.Context) (Request, bool) {
+	if req, ok := ctx.Value(hxContextKey{}).(Request); ok {
+		return req, true
+	}
+
+	if r, ok := RequestFromContext(ctx); ok && r != nil {
+		if req, ok := r.Context().Value(hxContextKey{}).(Request); ok {
+			return req, true
+		}
+
+		return ParseRequest(r), true
+	}
+
+	return Request{}, false
+}
+
+// hxRequestFunc is the hxRequest template function, it returns the htmx request headers of the render
+//
+//	{{ if eq (hxRequest).Target "results" }}...{{ end }}
+func hxRequestFunc(ctx context.Context, _ ...any) (any, error) {
+	req, _ := FromContext(ctx)
+	return req, nil
+}
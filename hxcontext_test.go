@@ -0,0 +1,42 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMiddleware(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hxcontext.html": {Data: []byte(`{{ if eq (hxRequest).Target "results" }}<li></li>{{ else }}<ul></ul>{{ end }}`)},
+	}
+
+	var fromMiddleware Request
+	var fromHandler HxRequestHeader
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromMiddleware, _ = FromContext(r.Context())
+		h := New().NewHandler(w, r)
+		fromHandler = h.Request()
+		_, _ = h.Render(context.Background(), NewComponent("hxcontext.html").FS(fsys))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/search", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "results")
+	r.Header.Set("HX-Trigger", "q")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	equalBool(t, true, fromMiddleware.IsHTMX)
+	equal(t, "q", fromMiddleware.Trigger)
+	equalBool(t, true, fromHandler.HxRequest)
+	equal(t, "results", fromHandler.HxTarget)
+	equal(t, "<li></li>", w.Body.String())
+
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("expected no htmx request headers in an empty context")
+	}
+}
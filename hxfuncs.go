@@ -0,0 +1,52 @@
+package htmx
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+)
+
+// hxAttrName matches the attribute names that can be set with hx, including the hx-on:event attributes
+var hxAttrName = regexp.MustCompile(`^[a-z][a-z0-9-]*(:[a-zA-Z0-9:._-]+)?$`)
+
+// hxAttr appends the attribute hx-name, for the hx-* attributes without a function of their own
+//
+//	<div {{ hx "ext" "sse" | hx "on::after-request" "this.reset()" }}></div>
+func hxAttr(name string, value any, prev ...template.HTMLAttr) (template.HTMLAttr, error) {
+	if !hxAttrName.MatchString(name) {
+		return "", fmt.Errorf("htmx: invalid attribute name hx-%s", name)
+	}
+
+	return appendAttr(prev, "hx-"+name, fmt.Sprint(value)), nil
+}
+
+// hxVerb returns the function of an attribute that issues a request to the url
+func hxVerb(name string) func(url string, prev ...template.HTMLAttr) template.HTMLAttr {
+	return func(url string, prev ...template.HTMLAttr) template.HTMLAttr {
+		return appendAttr(prev, name, url)
+	}
+}
+
+// hxString returns the function of an attribute with a string value
+func hxString(name string) func(value string, prev ...template.HTMLAttr) template.HTMLAttr {
+	return func(value string, prev ...template.HTMLAttr) template.HTMLAttr {
+		return appendAttr(prev, name, value)
+	}
+}
+
+// hxValue returns the function of an attribute whose value is a string or a builder like Swap
+func hxValue(name string) func(value any, prev ...template.HTMLAttr) template.HTMLAttr {
+	return func(value any, prev ...template.HTMLAttr) template.HTMLAttr {
+		return appendAttr(prev, name, fmt.Sprint(value))
+	}
+}
+
+// appendAttr appends the escaped attribute to the attributes of the previous function
+func appendAttr(prev []template.HTMLAttr, name, value string) template.HTMLAttr {
+	attr := NewAttributes().Set(name, value).HTMLAttr()
+	if len(prev) == 0 || prev[0] == "" {
+		return attr
+	}
+
+	return prev[0] + " " + attr
+}
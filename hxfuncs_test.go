@@ -0,0 +1,46 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHxFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"pipeline", `<button {{ hxGet "/users" | hxTarget "#list" | hxSwap "outerHTML" }}>`,
+			`<button hx-get="/users" hx-target="#list" hx-swap="outerHTML">`},
+		{"escaping", `<form {{ hxPost .Data.URL | hxConfirm .Data.Confirm }}>`,
+			`<form hx-post="/users?a=1&amp;b=&#34;2&#34;" hx-confirm="Delete &#34;Ada&#34; &lt;admin&gt;?">`},
+		{"swap builder", `<div {{ hxSwap ((swap "beforeend").ScrollBottom) | hxTrigger "load" }}>`,
+			`<div hx-swap="beforeend scroll:bottom" hx-trigger="load">`},
+		{"generic", `<div {{ hx "ext" "sse" | hx "on::after-request" "this.reset()" }}>`,
+			`<div hx-ext="sse" hx-on::after-request="this.reset()">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"hxfuncs.html": {Data: []byte(tt.template)}}
+
+			c := NewComponent("hxfuncs.html").FS(fsys)
+			c.AddData("URL", `/users?a=1&b="2"`)
+			c.AddData("Confirm", `Delete "Ada" <admin>?`)
+
+			output, err := c.Render(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, string(output))
+		})
+	}
+
+	fsys := fstest.MapFS{"hxfuncs.html": {Data: []byte(`<div {{ hx "on click" "alert(1)" }}>`)}}
+	if _, err := NewComponent("hxfuncs.html").FS(fsys).Render(context.Background()); err == nil {
+		t.Error("expected an error for an invalid attribute name")
+	}
+}
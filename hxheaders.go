@@ -0,0 +1,97 @@
+package htmx
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// AttrHeaders adds headers to the requests of the element and its children, as a JSON object
+// https://htmx.org/attributes/hx-headers/
+const AttrHeaders = "hx-headers"
+
+// Headers sets hx-headers to the JSON object of the headers
+func (a *Attributes) Headers(headers map[string]string) *Attributes {
+	//nolint:errchkjson // a map of strings always encodes
+	value, _ := json.Marshal(headers)
+	return a.Set(AttrHeaders, string(value))
+}
+
+// hxHeaders returns the hx-headers attribute of the header names and values, or of a map of headers. The value is
+// encoded as JSON and escaped for the attribute, so dynamic values like the CSRF token can't break its quotes.
+//
+//	<div {{ hxHeaders "X-Tenant" .Data.Tenant "X-CSRF-Token" csrfToken }}></div>
+//	<button {{ hxPost "/save" | hxHeaders .Data.Headers }}>Save</button>
+func hxHeaders(args ...any) (template.HTMLAttr, error) {
+	args, prev := splitPrevAttr(args)
+
+	headers := make(map[string]string)
+	switch {
+	case len(args) == 1:
+		switch m := args[0].(type) {
+		case map[string]string:
+			for key, value := range m {
+				headers[key] = value
+			}
+		case map[string]any:
+			for key, value := range m {
+				headers[key] = fmt.Sprint(value)
+			}
+		default:
+			return "", fmt.Errorf("hxHeaders: %T is not a map of headers", args[0])
+		}
+	case len(args)%2 == 0:
+		m, err := pairs("hxHeaders", args)
+		if err != nil {
+			return "", err
+		}
+		for key, value := range m {
+			headers[key] = fmt.Sprint(value)
+		}
+	default:
+		return "", fmt.Errorf("hxHeaders: header %v has no value", args[len(args)-1])
+	}
+
+	for key, value := range headers {
+		if key == "" || strings.IndexFunc(key, func(r rune) bool { return !isHeaderTokenRune(r) }) >= 0 {
+			return "", fmt.Errorf("hxHeaders: invalid header name %q", key)
+		}
+
+		if strings.IndexFunc(value, func(r rune) bool { return r < ' ' && r != '\t' || r == 0x7f }) >= 0 {
+			return "", fmt.Errorf("hxHeaders: invalid value of header %s", key)
+		}
+	}
+
+	return prev + NewAttributes().Headers(headers).HTMLAttr(), nil
+}
+
+// splitPrevAttr splits the attributes of the previous function of a pipeline from the arguments of a function with
+// variadic arguments, the returned attributes are followed by a space when present
+func splitPrevAttr(args []any) ([]any, template.HTMLAttr) {
+	if len(args) == 0 {
+		return args, ""
+	}
+
+	prev, ok := args[len(args)-1].(template.HTMLAttr)
+	if !ok {
+		return args, ""
+	}
+
+	if prev == "" {
+		return args[:len(args)-1], ""
+	}
+
+	return args[:len(args)-1], prev + " "
+}
+
+// isHeaderTokenRune returns true for the characters of a header name
+// https://www.rfc-editor.org/rfc/rfc9110#name-tokens
+func isHeaderTokenRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	default:
+		return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+	}
+}
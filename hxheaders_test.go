@@ -0,0 +1,32 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHxHeaders(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hxheaders.html": {Data: []byte(`<div {{ hxHeaders "X-Tenant" .Data.Tenant "X-Count" 2 }}></div>` +
+			`<button {{ hxPost "/save" | hxHeaders .Data.Headers }}></button>`)},
+	}
+
+	output, err := NewComponent("hxheaders.html").FS(fsys).
+		AddData("Tenant", `a"b'c</div>`).
+		AddData("Headers", map[string]string{"X-Mode": "draft"}).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-headers="{&#34;X-Count&#34;:&#34;2&#34;,&#34;X-Tenant&#34;:&#34;a\&#34;b&#39;c\u003c/div\u003e&#34;}"></div>`+
+		`<button hx-post="/save" hx-headers="{&#34;X-Mode&#34;:&#34;draft&#34;}"></button>`, string(output))
+
+	for _, args := range [][]any{{"X-Tenant"}, {"X Tenant", "a"}, {"X-Tenant", "a\nb"}, {"X-Tenant", "a", template.HTMLAttr(`hx-get="/"`), "b"}} {
+		if _, err := hxHeaders(args...); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
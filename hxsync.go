@@ -0,0 +1,105 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"html/template"
+)
+
+// AttrSync synchronizes the requests of an element with the requests of another element
+// https://htmx.org/attributes/hx-sync/
+const AttrSync = "hx-sync"
+
+// SyncStrategy is the strategy of hx-sync when a request is issued while another request is in flight
+type SyncStrategy string
+
+const (
+	// SyncDrop drops the new request while a request is in flight, this is the default strategy
+	SyncDrop SyncStrategy = "drop"
+
+	// SyncAbort drops the new request while a request is in flight, and aborts the in flight request
+	// when a request of the synchronized element is issued
+	SyncAbort SyncStrategy = "abort"
+
+	// SyncReplace aborts the request in flight and replaces it with the new request
+	SyncReplace SyncStrategy = "replace"
+
+	// SyncQueue queues the new request, it is the same as SyncQueueLast
+	SyncQueue SyncStrategy = "queue"
+
+	// SyncQueueFirst queues the first request issued while a request is in flight
+	SyncQueueFirst SyncStrategy = "queue first"
+
+	// SyncQueueLast queues the last request issued while a request is in flight
+	SyncQueueLast SyncStrategy = "queue last"
+
+	// SyncQueueAll queues all requests issued while a request is in flight
+	SyncQueueAll SyncStrategy = "queue all"
+)
+
+// ErrRequestAborted is returned by Handler.Render when the client aborted the request, e.g. because hx-sync replaced it
+var ErrRequestAborted = errors.New("htmx: request aborted by the client")
+
+// Sync sets hx-sync to synchronize the requests with the element matching the selector, e.g. "closest form" or "this"
+func (a *Attributes) Sync(selector string, strategy SyncStrategy) *Attributes {
+	return a.Set(AttrSync, syncValue(selector, strategy))
+}
+
+// hxSync returns the hx-sync attribute for the selector and strategy
+//
+//	<input name="q" hx-get="/search" hx-trigger="keyup changed" {{ hxSync "this" "replace" }}>
+func hxSync(selector string, strategy SyncStrategy) template.HTMLAttr {
+	return NewAttributes().Sync(selector, strategy).HTMLAttr()
+}
+
+// syncValue returns the value of hx-sync
+func syncValue(selector string, strategy SyncStrategy) string {
+	if strategy == "" {
+		return selector
+	}
+
+	return selector + ":" + string(strategy)
+}
+
+// Aborted returns true if the client aborted the request or disconnected
+func (h *Handler) Aborted() bool {
+	return h.r.Context().Err() != nil
+}
+
+// abortContext returns a context that is canceled with ErrRequestAborted when the client aborts the request,
+// so the render of a request that was replaced by hx-sync stops instead of wasting work.
+func (h *Handler) abortContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if h.Aborted() {
+		cancel(ErrRequestAborted)
+		return ctx, func() {}
+	}
+
+	stop := context.AfterFunc(h.r.Context(), func() {
+		cancel(ErrRequestAborted)
+	})
+
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// renderError returns ErrRequestAborted for renders that failed because the client aborted the request, the error
+// carries the request id when the request has one
+func (h *Handler) renderError(err error) error {
+	if h.Aborted() {
+		err = ErrRequestAborted
+	}
+
+	return withRequestIDError(h.r.Context(), err)
+}
+
+// renderCanceled returns the cause of the cancellation when the render context is canceled
+func renderCanceled(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	return context.Cause(ctx)
+}
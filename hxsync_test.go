@@ -0,0 +1,44 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSync(t *testing.T) {
+	equal(t, `hx-sync="closest form:abort"`, NewAttributes().Sync("closest form", SyncAbort).String())
+	equal(t, `hx-sync="this:queue first"`, NewAttributes().Sync("this", SyncQueueFirst).String())
+	equal(t, `hx-sync="this"`, NewAttributes().Sync("this", "").String())
+
+	fsys := fstest.MapFS{"search.html": {Data: []byte(`<input {{ hxSync "this" "replace" }}>`)}}
+	out, err := NewComponent("search.html").FS(fsys).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<input hx-sync="this:replace">`, string(out))
+}
+
+func TestRenderAborted(t *testing.T) {
+	fsys := fstest.MapFS{"page.html": {Data: []byte(`page`)}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	handler := New().NewHandler(w, r)
+
+	equal(t, "true", HxBoolToStr(handler.Aborted()))
+
+	// the render context is independent of the request context, the abort still stops the render
+	_, err := handler.Render(context.Background(), NewComponent("page.html").FS(fsys))
+	if !errors.Is(err, ErrRequestAborted) {
+		t.Errorf("expected ErrRequestAborted, got %v", err)
+	}
+
+	equal(t, "", w.Body.String())
+}
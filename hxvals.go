@@ -0,0 +1,143 @@
+package htmx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// AttrVals adds values to the parameters of the requests of the element and its children, as a JSON object
+// https://htmx.org/attributes/hx-vals/
+const AttrVals = "hx-vals"
+
+// Vals sets hx-vals to the JSON object of the map or struct, an error is returned when v doesn't encode to an object
+func (a *Attributes) Vals(v any) (*Attributes, error) {
+	value, err := valsJSON(v)
+	if err != nil {
+		return a, err
+	}
+
+	return a.Set(AttrVals, value), nil
+}
+
+// MustVals sets hx-vals like Vals and panics when v doesn't encode to a JSON object
+//
+//	attrs := htmx.NewAttributes().Set("hx-post", "/cart").MustVals(map[string]any{"id": 7})
+func (a *Attributes) MustVals(v any) *Attributes {
+	if _, err := a.Vals(v); err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+// ValsUnsafeJS sets hx-vals to a js: object of the names and javascript expressions, which htmx evaluates on every
+// request. The expressions are not escaped, never build them from user input.
+//
+//	attrs.ValsUnsafeJS(map[string]string{"lastKey": "event.key"})
+//	// hx-vals="js:{&#34;lastKey&#34;:event.key}"
+func (a *Attributes) ValsUnsafeJS(expressions map[string]string) *Attributes {
+	return a.Set(AttrVals, valsJS(expressions))
+}
+
+// hxVals returns the hx-vals attribute of a map or struct, or of the names and values. The value is encoded as JSON
+// and escaped for the attribute.
+//
+//	<button {{ hxPost "/cart" | hxVals "id" .Data.ID "qty" 1 }}>Add</button>
+//	<button {{ hxVals .Data.Item }}>Add</button>
+func hxVals(args ...any) (template.HTMLAttr, error) {
+	args, prev := splitPrevAttr(args)
+
+	var v any
+	switch {
+	case len(args) == 1:
+		v = args[0]
+	case len(args)%2 == 0:
+		m, err := pairs("hxVals", args)
+		if err != nil {
+			return "", err
+		}
+		v = m
+	default:
+		return "", fmt.Errorf("hxVals: value %v has no name", args[len(args)-1])
+	}
+
+	attrs, err := NewAttributes().Vals(v)
+	if err != nil {
+		return "", err
+	}
+
+	return prev + attrs.HTMLAttr(), nil
+}
+
+// hxValsUnsafe returns the hx-vals attribute of a js: object of the names and javascript expressions. The expressions
+// are not escaped, never build them from user input.
+//
+//	<input name="q" {{ hxGet "/search" | hxValsUnsafe "lastKey" "event.key" }}>
+func hxValsUnsafe(args ...any) (template.HTMLAttr, error) {
+	args, prev := splitPrevAttr(args)
+	if len(args)%2 != 0 {
+		return "", fmt.Errorf("hxValsUnsafe: expression %v has no name", args[len(args)-1])
+	}
+
+	m, err := pairs("hxValsUnsafe", args)
+	if err != nil {
+		return "", err
+	}
+
+	expressions := make(map[string]string, len(m))
+	for key, value := range m {
+		expressions[key] = fmt.Sprint(value)
+	}
+
+	return prev + NewAttributes().ValsUnsafeJS(expressions).HTMLAttr(), nil
+}
+
+// valsJSON returns the JSON object of the map or struct
+func valsJSON(v any) (string, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("htmx: unable to encode hx-vals: %w", err)
+	}
+
+	if !bytes.HasPrefix(value, []byte("{")) {
+		return "", fmt.Errorf("htmx: hx-vals of %T is not a JSON object", v)
+	}
+
+	return string(value), nil
+}
+
+// valsJS returns the js: object of the names and expressions, sorted by name
+func valsJS(expressions map[string]string) string {
+	keys := make([]string, 0, len(expressions))
+	for key := range expressions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		//nolint:errchkjson // a string always encodes
+		name, _ := json.Marshal(key)
+		fields[i] = string(name) + ":" + expressions[key]
+	}
+
+	return "js:{" + strings.Join(fields, ",") + "}"
+}
+
+// pairs returns the map of the name and value pairs of the arguments of the function
+func pairs(function string, args []any) (map[string]any, error) {
+	m := make(map[string]any, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: name %v is not a string", function, args[i])
+		}
+		m[key] = args[i+1]
+	}
+
+	return m, nil
+}
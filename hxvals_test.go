@@ -0,0 +1,54 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHxVals(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Note string `json:"note"`
+	}
+
+	fsys := fstest.MapFS{
+		"hxvals.html": {Data: []byte(`<button {{ hxPost "/cart" | hxVals "id" .Data.ID "qty" 1 }}></button>` +
+			`<button {{ hxVals .Data.Item }}></button>` +
+			`<input {{ hxValsUnsafe "lastKey" "event.key" }}>`)},
+	}
+
+	output, err := NewComponent("hxvals.html").FS(fsys).
+		AddData("ID", 7).
+		AddData("Item", item{ID: 7, Note: `"gift"`}).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<button hx-post="/cart" hx-vals="{&#34;id&#34;:7,&#34;qty&#34;:1}"></button>`+
+		`<button hx-vals="{&#34;id&#34;:7,&#34;note&#34;:&#34;\&#34;gift\&#34;&#34;}"></button>`+
+		`<input hx-vals="js:{&#34;lastKey&#34;:event.key}">`, string(output))
+
+	for _, args := range [][]any{{[]int{1}}, {"id"}, {1, 2}} {
+		if _, err := hxVals(args...); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestAttributesVals(t *testing.T) {
+	attrs := NewAttributes().Set("hx-post", "/cart").MustVals(map[string]any{"id": 7}).Set("hx-target", "#cart")
+	equal(t, `hx-post="/cart" hx-vals="{&#34;id&#34;:7}" hx-target="#cart"`, string(attrs.HTMLAttr()))
+
+	if _, err := NewAttributes().Vals([]int{1}); err == nil {
+		t.Error("expected an error for a value that is not a JSON object")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustVals to panic")
+		}
+	}()
+	NewAttributes().MustVals("id")
+}
@@ -0,0 +1,61 @@
+package htmx
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// hydration is a JSON script element of the data of a component, see Hydrate
+type hydration struct {
+	id   string
+	keys []string
+}
+
+// Hydrate renders the data of the keys as a JSON script element with the id after the output of the component, so
+// client-side code like Alpine or plain JavaScript picks up the initial state without a second request. Keys without
+// data are left out, the keys are written in the casing of JSONKeyCase.
+//
+//	c.AddData("User", user).AddData("Cart", cart)
+//	c.Hydrate("state", "User", "Cart")
+//
+//	const state = JSON.parse(document.getElementById("state").textContent)
+func (c *Component) Hydrate(id string, keys ...string) *Component {
+	c.hydration = append(c.hydration, hydration{id: id, keys: keys})
+	return c
+}
+
+// writeHydration writes the JSON script elements of the component
+func (c *Component) writeHydration(buf *bytes.Buffer, data map[string]any) error {
+	for _, h := range c.hydration {
+		values := make(map[string]any, len(h.keys))
+		for _, key := range h.keys {
+			if value, ok := data[key]; ok {
+				values[key] = value
+			}
+		}
+
+		script, err := hydrate(h.id, values)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(string(script))
+	}
+
+	return nil
+}
+
+// hydrate is the hydrate template function, it returns the value as a JSON script element with the id. The JSON is
+// escaped for script elements, it can't end the element.
+//
+//	{{ hydrate "todos" .Data.Todos }}
+func hydrate(id string, value any) (template.HTML, error) {
+	payload, err := marshalJSONKeys(value, JSONKeyCase)
+	if err != nil {
+		return "", err
+	}
+
+	// encoding/json escapes <, > and & unless HTML escaping is disabled, which the key casing doesn't do
+	//nolint:gosec // the id is escaped and the JSON can't contain <
+	return template.HTML(`<script type="application/json" id="` + template.HTMLEscapeString(id) + `">` +
+		string(payload) + `</script>`), nil
+}
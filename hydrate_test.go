@@ -0,0 +1,26 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHydrate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hydrate-page.html": {Data: []byte(`<div x-data="todos">{{ hydrate "todos" .Data.Todos }}</div>`)},
+	}
+
+	c := NewComponent("hydrate-page.html").FS(fsys).Hydrate("state", "User", "Missing")
+	c.AddData("Todos", []string{"</script><script>alert(1)</script>"})
+	c.AddData("User", map[string]any{"Name": "Ada & Grace"})
+	c.AddData("Secret", "hidden")
+
+	out, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div x-data="todos"><script type="application/json" id="todos">["\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"]</script></div>`+
+		`<script type="application/json" id="state">{"User":{"Name":"Ada \u0026 Grace"}}</script>`, string(out))
+}
@@ -0,0 +1,264 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// DefaultTranslator is used by the t and tn template functions when the context has no translator,
+	// the message keys are rendered as they are when no translator is set.
+	DefaultTranslator Translator
+
+	// DefaultLocale is the locale used when the request has no supported locale
+	DefaultLocale = "en"
+
+	// SupportedLocales are the locales matched against the Accept-Language header when rendering through the Handler,
+	// the first preferred language of the request is used when empty.
+	SupportedLocales []string
+)
+
+type (
+	// Translator translates message keys for a locale
+	Translator interface {
+		// Translate returns the message for the key
+		Translate(locale, key string, args ...any) string
+		// TranslatePlural returns the message for the key in the plural form of n
+		TranslatePlural(locale, key string, n int, args ...any) string
+	}
+
+	// Messages is a Translator backed by a map of locale to message key to message.
+	// Messages are formatted with fmt.Sprintf when arguments are given, plural forms are stored under the key with a
+	// .zero, .one or .other suffix and {n} is replaced by the count. Regional locales like en-US fall back to their
+	// language, and to DefaultLocale after that.
+	//
+	//	htmx.Messages{
+	//		"en": {"greeting": "Hello %s", "items.one": "one item", "items.other": "{n} items"},
+	//		"nl": {"greeting": "Hallo %s", "items.one": "één item", "items.other": "{n} items"},
+	//	}
+	Messages map[string]map[string]string
+
+	localeKey     struct{}
+	translatorKey struct{}
+)
+
+// WithLocale returns a context with the locale used for translations
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale returns the locale of the context, or DefaultLocale when it has none
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+
+	return DefaultLocale
+}
+
+// WithTranslator returns a context with the translator used for translations, e.g. to use a translator per tenant
+func WithTranslator(ctx context.Context, t Translator) context.Context {
+	return context.WithValue(ctx, translatorKey{}, t)
+}
+
+// Translate translates the key for the locale of the context
+func Translate(ctx context.Context, key string, args ...any) string {
+	t := translator(ctx)
+	if t == nil {
+		return key
+	}
+
+	return t.Translate(Locale(ctx), key, args...)
+}
+
+// TranslatePlural translates the key in the plural form of n for the locale of the context
+func TranslatePlural(ctx context.Context, key string, n int, args ...any) string {
+	t := translator(ctx)
+	if t == nil {
+		return key
+	}
+
+	return t.TranslatePlural(Locale(ctx), key, n, args...)
+}
+
+// LocaleFromRequest returns the best match of the Accept-Language header with the supported locales, the first preferred
+// language is returned when no locales are given. DefaultLocale is returned when nothing matches.
+func LocaleFromRequest(r *http.Request, supported ...string) string {
+	for _, lang := range acceptLanguages(r.Header.Get("Accept-Language")) {
+		if len(supported) == 0 {
+			return lang
+		}
+
+		for _, s := range supported {
+			if strings.EqualFold(s, lang) {
+				return s
+			}
+		}
+
+		// fall back from a regional language to the base language
+		base, _, _ := strings.Cut(lang, "-")
+		for _, s := range supported {
+			if strings.EqualFold(s, base) {
+				return s
+			}
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Translate returns the formatted message for the key, the key itself is returned when there is no message
+func (m Messages) Translate(locale, key string, args ...any) string {
+	msg, ok := m.lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	return format(msg, args)
+}
+
+// TranslatePlural returns the formatted message for the plural form of n
+func (m Messages) TranslatePlural(locale, key string, n int, args ...any) string {
+	var forms []string
+	switch n {
+	case 0:
+		forms = []string{".zero", ".other"}
+	case 1:
+		forms = []string{".one", ".other"}
+	default:
+		forms = []string{".other"}
+	}
+
+	for _, form := range forms {
+		if msg, ok := m.lookup(locale, key+form); ok {
+			return strings.ReplaceAll(format(msg, args), "{n}", strconv.Itoa(n))
+		}
+	}
+
+	return key
+}
+
+// lookup returns the message for the key in the locale, its language or the default locale
+func (m Messages) lookup(locale, key string) (string, bool) {
+	base, _, _ := strings.Cut(locale, "-")
+
+	for _, l := range []string{locale, base, DefaultLocale} {
+		if msg, ok := m[l][key]; ok {
+			return msg, true
+		}
+	}
+
+	return "", false
+}
+
+// format formats the message when arguments are given
+func format(msg string, args []any) string {
+	if len(args) == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, args...)
+}
+
+// translator returns the translator of the context or the default translator
+func translator(ctx context.Context) Translator {
+	if t, ok := ctx.Value(translatorKey{}).(Translator); ok {
+		return t
+	}
+
+	return DefaultTranslator
+}
+
+// acceptLanguages returns the languages of the Accept-Language header ordered by preference
+func acceptLanguages(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if q > 0 {
+			languages = append(languages, language{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+
+	out := make([]string, len(languages))
+	for i, l := range languages {
+		out[i] = l.tag
+	}
+
+	return out
+}
+
+// translateFunc is the t template function
+//
+//	{{ t "greeting" .Data.Name }}
+func translateFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, errors.New("t requires a message key")
+	}
+
+	key, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("t: message key %v is not a string", args[0])
+	}
+
+	return Translate(ctx, key, args[1:]...), nil
+}
+
+// translatePluralFunc is the tn template function
+//
+//	{{ tn "items" (len .Data.Items) }}
+func translatePluralFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) < 2 {
+		return nil, errors.New("tn requires a message key and a count")
+	}
+
+	key, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("tn: message key %v is not a string", args[0])
+	}
+
+	n, err := toInt(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("tn: %w", err)
+	}
+
+	return TranslatePlural(ctx, key, n, args[2:]...), nil
+}
+
+// toInt converts an integer of any type to an int
+func toInt(v any) (int, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), nil
+	}
+
+	return 0, fmt.Errorf("count %v is not an integer", v)
+}
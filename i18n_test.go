@@ -0,0 +1,45 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTranslate(t *testing.T) {
+	messages := Messages{
+		"en": {"greeting": "Hello %s", "items.one": "one item", "items.other": "{n} items"},
+		"nl": {"greeting": "Hallo %s", "items.zero": "geen items", "items.one": "één item", "items.other": "{n} items"},
+	}
+
+	fsys := fstest.MapFS{
+		"i18n.html": {Data: []byte(`{{ t "greeting" .Data.Name }}, {{ tn "items" .Data.Count }}, {{ t "missing" }}`)},
+	}
+
+	render := func(ctx context.Context, count int) string {
+		out, err := NewComponent("i18n.html").FS(fsys).
+			AddData("Name", "<Ann>").
+			AddData("Count", count).
+			Render(WithTranslator(ctx, messages))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	equal(t, "Hello &lt;Ann&gt;, one item, missing", render(context.Background(), 1))
+	equal(t, "Hallo &lt;Ann&gt;, geen items, missing", render(WithLocale(context.Background(), "nl-BE"), 0))
+	equal(t, "Hallo &lt;Ann&gt;, 3 items, missing", render(WithLocale(context.Background(), "nl"), 3))
+}
+
+func TestLocaleFromRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "fr;q=0.5, nl-BE, en;q=0.8")
+
+	equal(t, "nl-BE", LocaleFromRequest(r))
+	equal(t, "nl", LocaleFromRequest(r, "en", "nl"))
+	equal(t, "fr", LocaleFromRequest(r, "fr"))
+	equal(t, DefaultLocale, LocaleFromRequest(r, "de"))
+}
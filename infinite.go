@@ -0,0 +1,148 @@
+package htmx
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+//go:embed templates/infinite.html
+var infiniteTemplates embed.FS
+
+// CursorParam is the query parameter of the cursor of an infinite list
+var CursorParam = "cursor"
+
+// InfiniteList renders a page of items followed by a sentinel, an element that requests the next page once it was
+// scrolled into view and is replaced by it. The next page ends with a sentinel of its own, until there is no next
+// cursor. The handler of the url serves the first page and the following ones alike:
+//
+//	func (a *App) Feed(w http.ResponseWriter, r *http.Request) {
+//		posts, next := a.posts.After(htmx.Cursor(r), 20)
+//
+//		list := htmx.NewInfiniteList(r.URL.Path).Next(next).Tag("li")
+//		for _, post := range posts {
+//			list.Add(htmx.NewComponent("post.html").AddData("Post", post))
+//		}
+//
+//		c, err := list.Component()
+//		...
+//		// feed.html: <ul id="feed">{{ .Partials.content }}</ul>
+//		_, _ = a.htmx.NewHandler(w, r).Render(r.Context(), c.AutoWrap(htmx.NewComponent("feed.html"), "content"))
+//	}
+//
+// The component renders only the items and the sentinel. AutoWrap it in the container of the list, e.g. <ul id="feed">,
+// so full page loads get the container and htmx requests get the items and the sentinel.
+type InfiniteList struct {
+	url       string
+	cursor    string
+	tag       string
+	indicator template.HTML
+	items     []RenderableComponent
+}
+
+// infiniteSentinelTags are the elements a sentinel can be, it must be a valid child of the container of the items
+var infiniteSentinelTags = map[string]bool{"div": true, "li": true, "tr": true, "span": true, "article": true}
+
+// NewInfiniteList returns an infinite list that loads the next pages from the url
+func NewInfiniteList(url string) *InfiniteList {
+	return &InfiniteList{url: url, tag: "div"}
+}
+
+// Add adds items to the page
+func (l *InfiniteList) Add(items ...RenderableComponent) *InfiniteList {
+	l.items = append(l.items, items...)
+	return l
+}
+
+// Next sets the cursor of the next page, the list ends without a sentinel when the cursor is empty
+func (l *InfiniteList) Next(cursor string) *InfiniteList {
+	l.cursor = cursor
+	return l
+}
+
+// Tag sets the element of the sentinel, it must fit the container of the items: li in lists and tr in table bodies.
+// It defaults to div.
+func (l *InfiniteList) Tag(tag string) *InfiniteList {
+	l.tag = tag
+	return l
+}
+
+// Indicator sets the content of the sentinel, e.g. a spinner that shows while the next page loads
+func (l *InfiniteList) Indicator(html template.HTML) *InfiniteList {
+	l.indicator = html
+	return l
+}
+
+// NextURL returns the url of the next page, with the cursor query parameter set
+func (l *InfiniteList) NextURL() (string, error) {
+	u, err := url.Parse(l.url)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set(CursorParam, l.cursor)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Component returns the component that renders the items and the sentinel of the next page, it fails for an invalid
+// url or sentinel tag
+func (l *InfiniteList) Component() (*Component, error) {
+	sentinel, err := l.sentinel()
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewComponent("templates/infinite.html").FS(infiniteTemplates)
+
+	withItems(c, l.items)
+	c.AddData("Sentinel", sentinel)
+
+	return c, nil
+}
+
+// sentinel returns the element that loads the next page
+func (l *InfiniteList) sentinel() (template.HTML, error) {
+	if l.cursor == "" {
+		return "", nil
+	}
+
+	if !infiniteSentinelTags[l.tag] {
+		return "", fmt.Errorf("htmx: invalid infinite list sentinel %q", l.tag)
+	}
+
+	next, err := l.NextURL()
+	if err != nil {
+		return "", err
+	}
+
+	attrs := NewAttributes().
+		Set("class", "infinite-sentinel").
+		Set("hx-get", next).
+		Set("hx-trigger", "revealed").
+		Set("hx-swap", "outerHTML")
+
+	//nolint:gosec // the tag is one of infiniteSentinelTags and the attributes are escaped
+	return template.HTML("<" + l.tag + " " + attrs.String() + ">" + string(l.indicator) + "</" + l.tag + ">"), nil
+}
+
+// Cursor returns the cursor of the page the request asks for, empty for the first page
+func Cursor(r *http.Request) string {
+	return r.URL.Query().Get(CursorParam)
+}
+
+// withItems adds the items as partials of the component, the Items data lists their targets in order
+func withItems(c *Component, items []RenderableComponent) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = "item-" + strconv.Itoa(i)
+		c.With(item, keys[i])
+	}
+
+	c.AddData("Items", keys)
+}
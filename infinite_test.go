@@ -0,0 +1,82 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInfiniteList(t *testing.T) {
+	fsys := fstest.MapFS{"infinite-item.html": {Data: []byte(`<li>{{ .Data.Title }}</li>`)}}
+
+	r := httptest.NewRequest(http.MethodGet, "/feed?tag=go&cursor=10", nil)
+	equal(t, "10", Cursor(r))
+
+	list := NewInfiniteList("/feed?tag=go").Next("20").Tag("li").Indicator("Loading…")
+	for _, title := range []string{"first", "second"} {
+		list.Add(NewComponent("infinite-item.html").FS(fsys).AddData("Title", title))
+	}
+
+	c, err := list.Component()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<li>first</li><li>second</li>`+
+		`<li class="infinite-sentinel" hx-get="/feed?cursor=20&amp;tag=go" hx-trigger="revealed" hx-swap="outerHTML">Loading…</li>`,
+		string(output))
+
+	// the last page has no sentinel
+	c, err = NewInfiniteList("/feed").Add(NewComponent("infinite-item.html").FS(fsys).AddData("Title", "last")).Component()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err = c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<li>last</li>`, string(output))
+
+	if _, err := NewInfiniteList("/feed").Next("1").Tag("script").Component(); err == nil {
+		t.Error("expected an error for an invalid sentinel tag")
+	}
+}
+
+func TestInfiniteListContainer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"infinite-feed.html": {Data: []byte(`<ul id="feed">{{ .Partials.content }}</ul>`)},
+		"infinite-post.html": {Data: []byte(`<li>post</li>`)},
+	}
+
+	for _, hx := range []bool{false, true} {
+		r := httptest.NewRequest(http.MethodGet, "/feed", nil)
+		if hx {
+			r.Header.Set("HX-Request", "true")
+		}
+
+		c, err := NewInfiniteList("/feed").Add(NewComponent("infinite-post.html").FS(fsys)).Component()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w := httptest.NewRecorder()
+		if _, err := New().NewHandler(w, r).Render(context.Background(), c.AutoWrap(NewComponent("infinite-feed.html").FS(fsys), "content")); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := `<ul id="feed"><li>post</li></ul>`
+		if hx {
+			expected = `<li>post</li>`
+		}
+		equal(t, expected, w.Body.String())
+	}
+}
@@ -0,0 +1,204 @@
+package htmx
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// ValidateInheritance enables the inheritance validation pass when wrapping components in the Handler,
+	// issues are reported through the logger of the htmx instance.
+	ValidateInheritance = false
+
+	// requestAttributes are the attributes that make an element issue a request
+	requestAttributes = []string{"hx-get", "hx-post", "hx-put", "hx-patch", "hx-delete"}
+)
+
+// inheritanceSlot is the placeholder used to locate the fragment inside the layout
+const inheritanceSlot = "<!--htmx-inheritance-slot-->"
+
+type (
+	// InheritanceIssue describes an attribute declared in a layout that will be inherited by an element of an injected fragment.
+	InheritanceIssue struct {
+		Attribute string // the inherited attribute, e.g. hx-target
+		Value     string // the value of the inherited attribute
+		Source    string // the layout element declaring the attribute
+		Element   string // the fragment element inheriting the attribute
+	}
+
+	element struct {
+		tag   string
+		attrs map[string]string
+	}
+
+	inheritedAttribute struct {
+		value  string
+		source *element
+	}
+)
+
+// String returns a human-readable description of the issue
+func (i InheritanceIssue) String() string {
+	return fmt.Sprintf("%s inherits %s=%q from %s", i.Element, i.Attribute, i.Value, i.Source)
+}
+
+// CheckInheritance reports the attributes of the layout that will unintentionally apply to the requesting elements of the fragment.
+// slot is a unique marker within the layout where the fragment is injected.
+func CheckInheritance(layout template.HTML, slot string, fragment template.HTML) ([]InheritanceIssue, error) {
+	var ancestors []*element
+
+	err := walkElements(string(layout), func([]*element, *element) {}, func(stack []*element, text string) {
+		if ancestors == nil && strings.Contains(text, slot) {
+			ancestors = slices.Clone(stack)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ancestors == nil {
+		return nil, fmt.Errorf("slot %q not found in layout", slot)
+	}
+
+	if len(inheritedAttributes(ancestors)) == 0 {
+		return nil, nil
+	}
+
+	var issues []InheritanceIssue
+	err = walkElements(string(fragment), func(stack []*element, el *element) {
+		if !el.requests() {
+			return
+		}
+
+		// attributes declared within the fragment take precedence over the layout
+		chain := slices.Concat(ancestors, stack, []*element{el})
+		inherited := inheritedAttributes(chain)
+
+		for _, name := range InheritableAttributes {
+			attr, ok := inherited[name]
+			if !ok || !slices.Contains(ancestors, attr.source) {
+				continue
+			}
+
+			issues = append(issues, InheritanceIssue{
+				Attribute: name,
+				Value:     attr.value,
+				Source:    attr.source.String(),
+				Element:   el.String(),
+			})
+		}
+	}, nil)
+
+	return issues, err
+}
+
+// inheritedAttributes resolves the inheritable attributes of the innermost element of the stack
+func inheritedAttributes(stack []*element) map[string]inheritedAttribute {
+	inherited := make(map[string]inheritedAttribute)
+
+	for _, el := range stack {
+		disinherit := strings.Fields(el.attrs[AttrDisinherit])
+
+		for _, name := range InheritableAttributes {
+			// htmx stops looking for an attribute at the closest ancestor that disinherits it
+			if slices.Contains(disinherit, InheritAll) || slices.Contains(disinherit, name) {
+				delete(inherited, name)
+				continue
+			}
+
+			if value, ok := el.attrs[name]; ok {
+				inherited[name] = inheritedAttribute{value: value, source: el}
+			}
+		}
+	}
+
+	return inherited
+}
+
+// walkElements tokenizes the html and calls fn for every start tag with the stack of its open ancestors,
+// and text for every text and comment token.
+func walkElements(src string, fn func(stack []*element, el *element), text func(stack []*element, text string)) error {
+	z := html.NewTokenizer(strings.NewReader(src))
+	var stack []*element
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			el := &element{tag: tok.Data, attrs: make(map[string]string, len(tok.Attr))}
+			for _, a := range tok.Attr {
+				el.attrs[a.Key] = a.Val
+			}
+
+			fn(stack, el)
+
+			if tt == html.StartTagToken && !isVoidElement(el.tag) {
+				stack = append(stack, el)
+			}
+
+		case html.EndTagToken:
+			tok := z.Token()
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].tag == tok.Data {
+					stack = stack[:i]
+					break
+				}
+			}
+
+		case html.TextToken, html.CommentToken:
+			if text != nil {
+				text(stack, string(z.Raw()))
+			}
+		}
+	}
+}
+
+// requests returns true if the element issues an htmx request
+func (e *element) requests() bool {
+	for _, a := range requestAttributes {
+		if _, ok := e.attrs[a]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns a short css-like description of the element
+func (e *element) String() string {
+	out := e.tag
+	if id := e.attrs["id"]; id != "" {
+		out += "#" + id
+	}
+
+	for _, a := range requestAttributes {
+		if v, ok := e.attrs[a]; ok {
+			out += fmt.Sprintf("[%s=%q]", a, v)
+			break
+		}
+	}
+
+	return out
+}
+
+// isVoidElement returns true for elements that never have a closing tag
+func isVoidElement(tag string) bool {
+	switch tag {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr":
+		return true
+	}
+
+	return false
+}
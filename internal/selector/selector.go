@@ -0,0 +1,325 @@
+// Package selector matches CSS selectors against parsed html, for the assertions of htmxtest and the styles that the
+// email package inlines. Selectors support tag names, #id, .class, attributes with [attr], [attr=value],
+// [attr~=value], [attr^=value], [attr$=value] and [attr*=value], and the descendant and child (>) combinators.
+// Pseudo-classes and the sibling combinators are not supported.
+package selector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type (
+	// Selector is a parsed CSS selector, a chain of compound selectors and their combinators
+	Selector struct {
+		steps []step
+	}
+
+	// step is a compound selector, child is true when it is a direct child of the previous step
+	step struct {
+		tag     string
+		id      string
+		classes []string
+		attrs   []attrSelector
+		child   bool
+	}
+
+	// attrSelector matches an attribute, op is one of "", =, ~=, ^=, $= and *=
+	attrSelector struct {
+		key, op, value string
+	}
+)
+
+// Parse parses the selector into its compound selectors and combinators
+func Parse(sel string) (*Selector, error) {
+	s := &Selector{}
+	child := false
+
+	for _, token := range tokens(sel) {
+		if token == ">" {
+			if len(s.steps) == 0 || child {
+				return nil, fmt.Errorf("invalid selector %q", sel)
+			}
+			child = true
+			continue
+		}
+
+		st, err := parseCompound(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", sel, err)
+		}
+
+		st.child = child
+		s.steps = append(s.steps, st)
+		child = false
+	}
+
+	if len(s.steps) == 0 || child {
+		return nil, fmt.Errorf("invalid selector %q", sel)
+	}
+
+	return s, nil
+}
+
+// Match returns true if the element matches the selector: the last step matches the element and the previous steps
+// match its ancestors
+func (s *Selector) Match(n *html.Node) bool {
+	return s.matchStep(n, len(s.steps)-1)
+}
+
+// Specificity returns the specificity of the selector: the ids, the classes and attributes, and the tags
+func (s *Selector) Specificity() [3]int {
+	var spec [3]int
+	for _, st := range s.steps {
+		if st.id != "" {
+			spec[0]++
+		}
+		spec[1] += len(st.classes) + len(st.attrs)
+		if st.tag != "" {
+			spec[2]++
+		}
+	}
+
+	return spec
+}
+
+// Walk calls fn for the node and its descendants in document order
+func Walk(n *html.Node, fn func(n *html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		Walk(c, fn)
+	}
+}
+
+// Attribute returns the value of the attribute of the element
+func Attribute(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+// Text returns the text of the element and its descendants
+func Text(n *html.Node) string {
+	var sb strings.Builder
+	Walk(n, func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+	})
+
+	return sb.String()
+}
+
+// tokens splits the selector into compound selectors and > combinators, whitespace within attribute selectors is kept
+func tokens(sel string) []string {
+	var tokens []string
+	var current strings.Builder
+	depth := 0
+	quote := byte(0)
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(sel); i++ {
+		c := sel[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\n'):
+			flush()
+			continue
+		case depth == 0 && c == '>':
+			flush()
+			tokens = append(tokens, ">")
+			continue
+		}
+
+		current.WriteByte(c)
+	}
+	flush()
+
+	return tokens
+}
+
+// parseCompound parses a compound selector like div#list.items[hx-get]
+func parseCompound(token string) (step, error) {
+	var st step
+
+	i := 0
+	if strings.HasPrefix(token, "*") {
+		i = 1
+	} else {
+		for i < len(token) && isNameChar(token[i]) {
+			i++
+		}
+		st.tag = strings.ToLower(token[:i])
+	}
+
+	for i < len(token) {
+		switch token[i] {
+		case '#', '.':
+			start := i + 1
+			i = start
+			for i < len(token) && isNameChar(token[i]) {
+				i++
+			}
+			if i == start {
+				return st, fmt.Errorf("empty name at %d", start)
+			}
+
+			if token[start-1] == '#' {
+				st.id = token[start:i]
+			} else {
+				st.classes = append(st.classes, token[start:i])
+			}
+		case '[':
+			end := strings.IndexByte(token[i:], ']')
+			if end < 0 {
+				return st, fmt.Errorf("unclosed attribute selector")
+			}
+
+			attr, err := parseAttr(token[i+1 : i+end])
+			if err != nil {
+				return st, err
+			}
+			st.attrs = append(st.attrs, attr)
+			i += end + 1
+		default:
+			return st, fmt.Errorf("unexpected %q", token[i])
+		}
+	}
+
+	return st, nil
+}
+
+// parseAttr parses the content of an attribute selector like hx-get="/users"
+func parseAttr(s string) (attrSelector, error) {
+	i := strings.IndexAny(s, "~^$*=")
+	if i < 0 {
+		key := strings.TrimSpace(s)
+		if key == "" {
+			return attrSelector{}, fmt.Errorf("empty attribute selector")
+		}
+		return attrSelector{key: strings.ToLower(key)}, nil
+	}
+
+	key := strings.ToLower(strings.TrimSpace(s[:i]))
+	op := "="
+	rest := s[i+1:]
+	if s[i] != '=' {
+		if !strings.HasPrefix(rest, "=") {
+			return attrSelector{}, fmt.Errorf("invalid attribute selector [%s]", s)
+		}
+		op = s[i:i+1] + "="
+		rest = rest[1:]
+	}
+
+	value := strings.TrimSpace(rest)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	if key == "" {
+		return attrSelector{}, fmt.Errorf("invalid attribute selector [%s]", s)
+	}
+
+	return attrSelector{key: key, op: op, value: value}, nil
+}
+
+// isNameChar returns true for the characters of tag names, ids and classes
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+// matchStep returns true if the element matches the step and its ancestors match the previous steps
+func (s *Selector) matchStep(n *html.Node, i int) bool {
+	st := s.steps[i]
+	if !st.match(n) {
+		return false
+	}
+
+	if i == 0 {
+		return true
+	}
+
+	for parent := n.Parent; parent != nil && parent.Type == html.ElementNode; parent = parent.Parent {
+		if s.matchStep(parent, i-1) {
+			return true
+		}
+
+		if st.child {
+			return false
+		}
+	}
+
+	return false
+}
+
+// match returns true if the element matches the compound selector
+func (st step) match(n *html.Node) bool {
+	if st.tag != "" && n.Data != st.tag {
+		return false
+	}
+
+	if st.id != "" {
+		if id, _ := Attribute(n, "id"); id != st.id {
+			return false
+		}
+	}
+
+	if len(st.classes) > 0 {
+		class, _ := Attribute(n, "class")
+		fields := strings.Fields(class)
+		for _, c := range st.classes {
+			if !slices.Contains(fields, c) {
+				return false
+			}
+		}
+	}
+
+	for _, a := range st.attrs {
+		value, ok := Attribute(n, a.key)
+		if !ok || !a.match(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// match returns true if the attribute value matches
+func (a attrSelector) match(value string) bool {
+	switch a.op {
+	case "=":
+		return value == a.value
+	case "~=":
+		return slices.Contains(strings.Fields(value), a.value)
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	}
+
+	return true
+}
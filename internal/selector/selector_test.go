@@ -0,0 +1,57 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestSelector(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<ul id="list" class="items wide"><li data-id="1"><a href="/a">A</a></li>` +
+		`<li data-id="2" lang="en-GB"><span><a href="/b">B</a></span></li></ul>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selector    string
+		matches     string
+		specificity [3]int
+	}{
+		{"a", "A,B", [3]int{0, 0, 1}},
+		{"ul#list.items li > a", "A", [3]int{1, 1, 3}},
+		{"li a", "A,B", [3]int{0, 0, 2}},
+		{`li[data-id="2"] a`, "B", [3]int{0, 1, 2}},
+		{"[lang^=en] a[href$='/b']", "B", [3]int{0, 2, 1}},
+		{".wide *", "A,B", [3]int{0, 1, 0}},
+		{"ul.narrow a", "", [3]int{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.selector)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.selector, err)
+		}
+
+		var matches []string
+		Walk(doc, func(n *html.Node) {
+			if n.Type == html.ElementNode && n.Data == "a" && s.Match(n) {
+				matches = append(matches, Text(n))
+			}
+		})
+
+		if got := strings.Join(matches, ","); got != tt.matches {
+			t.Errorf("%s: expected %q, got %q", tt.selector, tt.matches, got)
+		}
+		if s.Specificity() != tt.specificity {
+			t.Errorf("%s: expected the specificity %v, got %v", tt.selector, tt.specificity, s.Specificity())
+		}
+	}
+
+	for _, invalid := range []string{"", "> a", "a >", "a > > b", "a#", "a[href", "a[=x]", "a:hover"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package htmx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// JournalOutput is where the render journal is dumped when a render panics
+	JournalOutput io.Writer = os.Stderr
+
+	journal atomic.Pointer[renderJournal]
+)
+
+type (
+	// JournalEntry is a render operation recorded by the render journal
+	JournalEntry struct {
+		Time      time.Time     `json:"time"`
+		Chain     []string      `json:"chain"` // the first template of the component and its ancestors, outermost first
+		Templates []string      `json:"templates"`
+		DataKeys  []string      `json:"dataKeys"`
+		Route     string        `json:"route,omitempty"`
+		Duration  time.Duration `json:"duration"`
+		Error     string        `json:"error,omitempty"`
+	}
+
+	// renderJournal is a ring buffer with the last render operations
+	renderJournal struct {
+		mu      sync.Mutex
+		entries []JournalEntry
+		next    int
+		full    bool
+	}
+
+	journalChainKey struct{}
+)
+
+// EnableJournal records the last size render operations of all components, so production rendering crashes can be
+// analysed post-mortem. The journal is served by JournalHandler and dumped to JournalOutput when a render panics.
+// Enabling the journal again clears it.
+func EnableJournal(size int) {
+	if size <= 0 {
+		DisableJournal()
+		return
+	}
+
+	journal.Store(&renderJournal{entries: make([]JournalEntry, size)})
+}
+
+// DisableJournal stops recording and drops the journal
+func DisableJournal() {
+	journal.Store(nil)
+}
+
+// Journal returns the recorded render operations, oldest first
+func Journal() []JournalEntry {
+	j := journal.Load()
+	if j == nil {
+		return nil
+	}
+
+	return j.snapshot()
+}
+
+// DumpJournal writes the recorded render operations to w, one line per operation
+func DumpJournal(w io.Writer) error {
+	for _, e := range Journal() {
+		line := fmt.Sprintf("%s %s %s templates=%s data=%s",
+			e.Time.Format(time.RFC3339Nano), strings.Join(e.Chain, " > "), e.Duration,
+			strings.Join(e.Templates, ","), strings.Join(e.DataKeys, ","))
+
+		if e.Route != "" {
+			line += " route=" + e.Route
+		}
+
+		if e.Error != "" {
+			line += fmt.Sprintf(" error=%q", e.Error)
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// JournalHandler serves the recorded render operations as JSON, mount it on a debug route that isn't public
+func JournalHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entries := Journal()
+		if entries == nil {
+			entries = []JournalEntry{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(entries)
+	})
+}
+
+// render renders the component and records the operation, a panic is recorded and the journal is dumped before
+// the panic continues
+func (j *renderJournal) render(ctx context.Context, c *Component) (output template.HTML, err error) {
+	name := "(no templates)"
+	if len(c.templates) > 0 {
+		name = c.templates[0]
+	}
+
+	parent, _ := ctx.Value(journalChainKey{}).([]string)
+	chain := append(parent[:len(parent):len(parent)], name)
+	ctx = context.WithValue(ctx, journalChainKey{}, chain)
+
+	start := time.Now()
+	entry := JournalEntry{
+		Time:      start,
+		Chain:     chain,
+		Templates: append([]string(nil), c.templates...),
+		DataKeys:  dataKeys(c.data()),
+	}
+
+	if r, ok := RequestFromContext(ctx); ok {
+		entry.Route = routeName(r)
+	} else if c.request != nil {
+		entry.Route = routeName(c.request)
+	}
+
+	defer func() {
+		entry.Duration = time.Since(start)
+
+		if p := recover(); p != nil {
+			entry.Error = fmt.Sprintf("panic: %v", p)
+			j.record(entry)
+
+			// only the outermost component dumps the journal, the panic passes all of its ancestors
+			if len(parent) == 0 && JournalOutput != nil {
+				_, _ = fmt.Fprintln(JournalOutput, "htmx: render panicked, render journal:")
+				_ = DumpJournal(JournalOutput)
+			}
+
+			panic(p)
+		}
+
+		if err != nil {
+			entry.Error = err.Error()
+		}
+
+		j.record(entry)
+	}()
+
+	return c.render(ctx)
+}
+
+// record adds the entry to the ring buffer
+func (j *renderJournal) record(e JournalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries[j.next] = e
+	j.next = (j.next + 1) % len(j.entries)
+	if j.next == 0 {
+		j.full = true
+	}
+}
+
+// snapshot returns a copy of the entries, oldest first
+func (j *renderJournal) snapshot() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.full {
+		return append([]JournalEntry(nil), j.entries[:j.next]...)
+	}
+
+	return append(append([]JournalEntry(nil), j.entries[j.next:]...), j.entries[:j.next]...)
+}
+
+// dataKeys returns the sorted keys of the data, the values are not recorded
+func dataKeys(data map[string]any) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
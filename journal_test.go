@@ -0,0 +1,93 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestJournal(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ .Partials.nav }}{{ .Data.Title }}`)},
+		"nav.html":  {Data: []byte(`nav`)},
+	}
+
+	EnableJournal(3)
+	defer DisableJournal()
+
+	for i := 0; i < 2; i++ {
+		page := NewComponent("page.html").FS(fsys).AddData("Title", "home").AddData("User", "ada")
+		page.With(NewComponent("nav.html").FS(fsys), "nav")
+
+		if _, err := page.Render(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := Journal()
+	if len(entries) != 3 {
+		t.Fatalf("expected the journal to keep the last 3 renders, got %d", len(entries))
+	}
+
+	// partials finish before their parent
+	equal(t, "page.html > nav.html", strings.Join(entries[1].Chain, " > "))
+	equal(t, "page.html", strings.Join(entries[2].Chain, " > "))
+	equal(t, "Title,User", strings.Join(entries[2].DataKeys, ","))
+
+	// partials see the data of their parent
+	equal(t, "Title,User", strings.Join(entries[1].DataKeys, ","))
+
+	var out bytes.Buffer
+	previous := JournalOutput
+	JournalOutput = &out
+	defer func() { JournalOutput = previous }()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to continue")
+			}
+		}()
+
+		page := NewComponent("page.html").FS(fsys)
+		page.With(panickingComponent{NewComponent("boom.html")}, "nav")
+		_, _ = page.Render(context.Background())
+	}()
+
+	entries = Journal()
+	last := entries[len(entries)-1]
+	equal(t, "page.html", strings.Join(last.Chain, " > "))
+	if !strings.Contains(last.Error, "kaboom") {
+		t.Errorf("expected the panic to be recorded, got %q", last.Error)
+	}
+
+	if !strings.Contains(out.String(), "render journal") || !strings.Contains(out.String(), `error="panic:`) {
+		t.Errorf("expected the journal to be dumped, got %s", out.String())
+	}
+
+	w := httptest.NewRecorder()
+	JournalHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/journal", nil))
+
+	var served []JournalEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if len(served) != 3 {
+		t.Errorf("expected 3 served entries, got %d", len(served))
+	}
+}
+
+// panickingComponent is a partial whose render panics, template functions can't panic as the template package recovers them
+type panickingComponent struct {
+	*Component
+}
+
+func (panickingComponent) Render(context.Context) (template.HTML, error) {
+	panic("kaboom")
+}
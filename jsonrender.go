@@ -0,0 +1,239 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// KeyCaseDefault keeps the keys as they were added, Data, Global and Partials like in the templates
+	KeyCaseDefault KeyCase = iota
+
+	// KeyCaseCamel writes the keys in camel case, e.g. userName
+	KeyCaseCamel
+
+	// KeyCaseSnake writes the keys in snake case, e.g. user_name
+	KeyCaseSnake
+)
+
+const (
+	// IncludeGlobal writes the global data of the component, it's left out by default as it holds the data of the
+	// global data providers, like the current user or the CSRF token
+	IncludeGlobal JSONOption = iota + 1
+)
+
+// JSONKeyCase is the casing of the keys written by RenderJSON, the keys of nested maps and structs included
+var JSONKeyCase = KeyCaseDefault
+
+type (
+	// KeyCase is the casing of the keys of the JSON output
+	KeyCase int
+
+	// JSONOption changes the output of RenderJSON
+	JSONOption int
+
+	// jsonRenderer is implemented by the components that can be rendered as JSON
+	jsonRenderer interface {
+		jsonData(ctx context.Context, includeGlobal bool) (map[string]any, error)
+	}
+)
+
+// RenderJSON renders the data and partials of the component as JSON instead of executing its templates, for the htmx
+// client-side-templates extension or other API clients. Partials of components are rendered as nested objects, other
+// partials as their html. The global data is written with the IncludeGlobal option.
+//
+//	{"Data":{"Name":"Ada"},"Partials":{"Address":{"Data":{"City":"London"},"Partials":{}}}}
+//
+// The keys are written in the casing of JSONKeyCase.
+func (c *Component) RenderJSON(ctx context.Context, opts ...JSONOption) ([]byte, error) {
+	data, err := c.jsonData(ctx, slices.Contains(opts, IncludeGlobal))
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSONKeys(data, JSONKeyCase)
+}
+
+// jsonData returns the data and partials of the component, and its global data if included
+func (c *Component) jsonData(ctx context.Context, includeGlobal bool) (map[string]any, error) {
+	if err := renderCanceled(ctx); err != nil {
+		return nil, err
+	}
+
+	if ctx.Value(c) != nil {
+		return nil, errors.New("circular reference detected in partials")
+	}
+
+	ctx = context.WithValue(ctx, c, true)
+	ctx = c.requestContext(ctx)
+
+	ctx, err := tenantContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partials := make(map[string]any, len(c.partial)+len(c.with))
+	for key, value := range c.partial {
+		partials[key] = value
+	}
+
+	data, global := c.data(), c.global()
+	for key, value := range c.partials() {
+		value.injectData(data)
+		value.injectGlobalData(global)
+
+		if j, ok := value.(jsonRenderer); ok {
+			data, err := j.jsonData(ctx, includeGlobal)
+			if err != nil {
+				return nil, err
+			}
+			partials[key] = data
+			continue
+		}
+
+		output, err := value.Render(ctx)
+		if err != nil {
+			if output, err = c.recoverPartial(ctx, key, err); err != nil {
+				return nil, err
+			}
+		}
+		partials[key] = output
+	}
+
+	output := map[string]any{"Data": data, "Partials": partials}
+	if includeGlobal {
+		output["Global"] = renderTenant(ctx).global(global)
+	}
+
+	return output, nil
+}
+
+// RenderJSON renders the component as JSON, see Component.RenderJSON. Components that can't be rendered as JSON,
+// like deferred partials, return an error.
+func (h *Handler) RenderJSON(ctx context.Context, r RenderableComponent, opts ...JSONOption) (int, error) {
+	j, ok := r.(jsonRenderer)
+	if !ok {
+		return 0, errors.New("htmx: component can't be rendered as JSON")
+	}
+
+	ctx, cancel := h.abortContext(h.renderContext(ctx))
+	defer cancel()
+
+	r.SetRequest(h.r)
+	r.injectGlobalData(h.globalData())
+
+	data, err := j.jsonData(ctx, slices.Contains(opts, IncludeGlobal))
+	if err != nil {
+		return 0, h.renderError(err)
+	}
+
+	payload, err := marshalJSONKeys(data, JSONKeyCase)
+	if err != nil {
+		return 0, err
+	}
+
+	h.w.Header().Set("Content-Type", "application/json")
+	return h.Write(payload)
+}
+
+// marshalJSONKeys marshals the value with its keys in the casing
+func marshalJSONKeys(v any, keyCase KeyCase) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil || keyCase == KeyCaseDefault {
+		return payload, err
+	}
+
+	// decode the marshaled value, so the keys of structs and their json tags are recased as well
+	var decoded any
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(recaseKeys(decoded, keyCase))
+}
+
+// recaseKeys returns the decoded JSON value with its keys in the casing
+func recaseKeys(v any, keyCase KeyCase) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			out[recaseKey(key, keyCase)] = recaseKeys(value, keyCase)
+		}
+		return out
+	case []any:
+		for i, value := range v {
+			v[i] = recaseKeys(value, keyCase)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// recaseKey returns the key in the casing, words are separated by case changes, underscores, hyphens and spaces
+func recaseKey(key string, keyCase KeyCase) string {
+	words := splitWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	if keyCase == KeyCaseSnake {
+		return strings.Join(words, "_")
+	}
+
+	for i := 1; i < len(words); i++ {
+		r := []rune(words[i])
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+
+	return strings.Join(words, "")
+}
+
+// splitWords splits the key into its words, an acronym like the ID of UserID is a single word
+func splitWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := -1
+
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		lowerToUpper := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+		acronymEnd := unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
@@ -0,0 +1,70 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderJSON(t *testing.T) {
+	newUser := func() RenderableComponent {
+		address := NewComponent("address.html").AddData("PostalCode", "NW1")
+		return NewComponent("user.html").
+			AddData("UserID", 7).
+			AddData("first_name", "Ada").
+			AddGlobalData("SiteName", "Users").
+			With(address, "HomeAddress")
+	}
+
+	tests := []struct {
+		name     string
+		keyCase  KeyCase
+		opts     []JSONOption
+		expected string
+	}{
+		{"default", KeyCaseDefault, nil,
+			`{"Data":{"UserID":7,"first_name":"Ada"},"Partials":{"HomeAddress":{"Data":{"PostalCode":"NW1","UserID":7,"first_name":"Ada"},"Partials":{}}}}`},
+		{"global", KeyCaseDefault, []JSONOption{IncludeGlobal},
+			`{"Data":{"UserID":7,"first_name":"Ada"},"Global":{"SiteName":"Users"},"Partials":{"HomeAddress":{"Data":{"PostalCode":"NW1","UserID":7,"first_name":"Ada"},"Global":{"SiteName":"Users"},"Partials":{}}}}`},
+		{"camel", KeyCaseCamel, []JSONOption{IncludeGlobal},
+			`{"data":{"firstName":"Ada","userId":7},"global":{"siteName":"Users"},"partials":{"homeAddress":{"data":{"firstName":"Ada","postalCode":"NW1","userId":7},"global":{"siteName":"Users"},"partials":{}}}}`},
+		{"snake", KeyCaseSnake, []JSONOption{IncludeGlobal},
+			`{"data":{"first_name":"Ada","user_id":7},"global":{"site_name":"Users"},"partials":{"home_address":{"data":{"first_name":"Ada","postal_code":"NW1","user_id":7},"global":{"site_name":"Users"},"partials":{}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(keyCase KeyCase) { JSONKeyCase = keyCase }(JSONKeyCase)
+			JSONKeyCase = tt.keyCase
+
+			output, err := newUser().(*Component).RenderJSON(context.Background(), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, string(output))
+		})
+	}
+
+	// the global data of providers isn't written unless included
+	app := New()
+	RegisterGlobalDataProvider(func(*http.Request) map[string]any { return map[string]any{"User": "ada"} })
+	defer ResetGlobalDataProviders()
+
+	w := httptest.NewRecorder()
+	h := app.NewHandler(w, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if _, err := h.RenderJSON(context.Background(), NewComponent("user.html").AddData("Name", "Ada")); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "application/json", w.Header().Get("Content-Type"))
+	equal(t, `{"Data":{"Name":"Ada"},"Partials":{}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h = app.NewHandler(w, httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if _, err := h.RenderJSON(context.Background(), NewComponent("user.html").AddData("Name", "Ada"), IncludeGlobal); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"Data":{"Name":"Ada"},"Global":{"User":"ada"},"Partials":{}}`, w.Body.String())
+}
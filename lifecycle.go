@@ -0,0 +1,113 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type (
+	// Service is a long-running part of the application that is started and stopped with the htmx instance,
+	// like a background job that refreshes a cache. The package doesn't register services of its own.
+	Service interface {
+		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+	}
+
+	// Hook is a Service built from functions, the field names match the lifecycle hooks of uber/fx.
+	Hook struct {
+		OnStart func(ctx context.Context) error
+		OnStop  func(ctx context.Context) error
+	}
+)
+
+// Start calls OnStart when it is set
+func (hk Hook) Start(ctx context.Context) error {
+	if hk.OnStart == nil {
+		return nil
+	}
+
+	return hk.OnStart(ctx)
+}
+
+// Stop calls OnStop when it is set
+func (hk Hook) Stop(ctx context.Context) error {
+	if hk.OnStop == nil {
+		return nil
+	}
+
+	return hk.OnStop(ctx)
+}
+
+// AddService adds a service that is started and stopped with the htmx instance
+func (h *HTMX) AddService(s Service) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.services = append(h.services, s)
+}
+
+// Start warms the template cache with the templates of all registered components and starts the services.
+// When a service fails to start, the services that were already started are stopped again.
+// Start and Stop can be used as lifecycle hooks of a dependency injection container:
+//
+//	lc.Append(fx.Hook{OnStart: h.Start, OnStop: h.Stop})
+func (h *HTMX) Start(ctx context.Context) error {
+	if err := h.warmComponents(ctx); err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	services := append([]Service(nil), h.services...)
+	h.mu.RUnlock()
+
+	for i, s := range services {
+		if err := s.Start(ctx); err != nil {
+			return errors.Join(err, stopServices(ctx, services[:i]))
+		}
+	}
+
+	return nil
+}
+
+// Stop stops all services in reverse order
+func (h *HTMX) Stop(ctx context.Context) error {
+	h.mu.RLock()
+	services := append([]Service(nil), h.services...)
+	h.mu.RUnlock()
+
+	return stopServices(ctx, services)
+}
+
+// warmComponents parses the templates of all registered components
+func (h *HTMX) warmComponents(ctx context.Context) error {
+	var errs []error
+
+	for _, name := range h.Components() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		c, err := h.Component(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := c.parseTemplates(); err != nil {
+			errs = append(errs, fmt.Errorf("component %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// stopServices stops the services in reverse order and joins the errors
+func stopServices(ctx context.Context, services []Service) error {
+	var errs []error
+	for i := len(services) - 1; i >= 0; i-- {
+		errs = append(errs, services[i].Stop(ctx))
+	}
+
+	return errors.Join(errs...)
+}
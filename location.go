@@ -0,0 +1,63 @@
+package htmx
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Location is a client-side navigation for the HX-Location header, htmx requests the path and swaps the response
+// like a hx-get would, without a full page reload.
+// https://htmx.org/headers/hx-location/
+type Location struct {
+	Path    string            `json:"path"`              // url to load the response from
+	Target  string            `json:"target,omitempty"`  // target to swap the response into, defaults to the body
+	Swap    string            `json:"swap,omitempty"`    // how the response is swapped, see Swap for a builder
+	Values  map[string]any    `json:"values,omitempty"`  // values to submit with the request
+	Headers map[string]string `json:"headers,omitempty"` // headers to submit with the request
+	Select  string            `json:"select,omitempty"`  // part of the response that is swapped
+	Event   string            `json:"event,omitempty"`   // name of the event that triggered the request
+	Source  string            `json:"source,omitempty"`  // source element of the request
+	Handler string            `json:"handler,omitempty"` // callback that handles the response html
+}
+
+// ErrLocationPath is returned for a location without a path
+var ErrLocationPath = errors.New("htmx: location without a path")
+
+// NewLocation returns a location for the path
+func NewLocation(path string) *Location {
+	return &Location{Path: path}
+}
+
+// header returns the value of the HX-Location header, a location with only a path is sent as the plain path
+func (l *Location) header() (string, error) {
+	if l.Path == "" {
+		return "", ErrLocationPath
+	}
+
+	if l.Target == "" && l.Swap == "" && l.Values == nil && l.Headers == nil && l.Select == "" &&
+		l.Event == "" && l.Source == "" && l.Handler == "" {
+		return headerURL(l.Path), nil
+	}
+
+	location := *l
+	location.Path = headerURL(l.Path)
+
+	payload, err := json.Marshal(location)
+	if err != nil {
+		return "", err
+	}
+
+	return asciiJSON(string(payload)), nil
+}
+
+// LocationWithObject does a client-side navigation to the location without reloading the whole page
+// https://htmx.org/headers/hx-location/
+func (h *Handler) LocationWithObject(l *Location) error {
+	value, err := l.header()
+	if err != nil {
+		return err
+	}
+
+	h.response.Set(HXLocation, value)
+	return nil
+}
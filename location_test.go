@@ -0,0 +1,36 @@
+package htmx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocation(t *testing.T) {
+	w := httptest.NewRecorder()
+	h := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if err := h.LocationWithObject(NewLocation("/pets")); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "/pets", w.Header().Get("HX-Location"))
+
+	err := h.LocationWithObject(&Location{
+		Path:    "/pets/7",
+		Target:  "#main",
+		Swap:    NewSwap().Style(SwapOuterHTML).String(),
+		Values:  map[string]any{"tab": "history"},
+		Headers: map[string]string{"X-Origin": "list"},
+		Select:  "#pet",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"path":"/pets/7","target":"#main","swap":"outerHTML","values":{"tab":"history"},"headers":{"X-Origin":"list"},"select":"#pet"}`,
+		w.Header().Get("HX-Location"))
+
+	if err := h.LocationWithObject(&Location{Target: "#main"}); !errors.Is(err, ErrLocationPath) {
+		t.Errorf("expected ErrLocationPath, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var (
+	// DefaultLogger logs the render errors, slow renders, template cache misses and template reloads of the package
+	// with structured fields when it is set. Cache misses are logged at the debug level.
+	//
+	//	htmx.DefaultLogger = slog.Default().WithGroup("htmx")
+	DefaultLogger *slog.Logger
+
+	// SlowRenderThreshold is the render duration of a component, its partials included, above which the render is
+	// logged as slow, 0 disables the log
+	SlowRenderThreshold = 250 * time.Millisecond
+)
+
+// logRenderError logs the error of the component, renders that were canceled are not logged
+func (c *Component) logRenderError(ctx context.Context, err error) {
+	if DefaultLogger == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrRequestAborted) {
+		return
+	}
+
+	DefaultLogger.LogAttrs(ctx, slog.LevelError, "render failed", append(c.logAttrs(ctx), slog.Any("error", err))...)
+}
+
+// logSlowRender logs the render of the component when it took longer than the SlowRenderThreshold
+func (c *Component) logSlowRender(ctx context.Context, duration time.Duration) {
+	if DefaultLogger == nil || SlowRenderThreshold <= 0 || duration <= SlowRenderThreshold {
+		return
+	}
+
+	DefaultLogger.LogAttrs(ctx, slog.LevelWarn, "slow render",
+		append(c.logAttrs(ctx), slog.Duration("duration", duration), slog.Int("partials", len(c.with)))...)
+}
+
+// logCacheMiss logs that the templates of the component were parsed because they weren't cached
+func (c *Component) logCacheMiss(namespace string) {
+	if DefaultLogger == nil {
+		return
+	}
+
+	DefaultLogger.LogAttrs(context.Background(), slog.LevelDebug, "template cache miss",
+		slog.Any("templates", c.templates), slog.String("namespace", namespace))
+}
+
+// logAttrs returns the fields identifying the component and its request
+func (c *Component) logAttrs(ctx context.Context) []slog.Attr {
+	attrs := []slog.Attr{slog.Any("templates", c.templates)}
+	if id := RequestID(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	return attrs
+}
+
+// logReload logs that cached templates were dropped, so they are parsed again
+func logReload(msg string, attrs ...slog.Attr) {
+	if DefaultLogger == nil {
+		return
+	}
+
+	DefaultLogger.LogAttrs(context.Background(), slog.LevelInfo, msg, attrs...)
+}
@@ -0,0 +1,63 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	DefaultLogger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	defer func() { DefaultLogger = nil }()
+
+	defer func(threshold time.Duration) { SlowRenderThreshold = threshold }(SlowRenderThreshold)
+	SlowRenderThreshold = time.Nanosecond
+
+	fsys := fstest.MapFS{
+		"logging-ok.html":    {Data: []byte(`<p>ok</p>`)},
+		"logging-error.html": {Data: []byte(`{{ .Data.Missing.Name }}`)},
+	}
+
+	InvalidateTemplate("logging-ok.html")
+
+	if _, err := NewComponent("logging-ok.html").FS(fsys).Render(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	if _, err := NewComponent("logging-error.html").FS(fsys).AddData("Missing", 1).Render(ctx); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		`level=INFO msg="template invalidated" file=logging-ok.html dropped=0`,
+		`level=DEBUG msg="template cache miss" templates=[logging-ok.html] namespace=""`,
+		`level=WARN msg="slow render" templates=[logging-ok.html] partials=0`,
+		`level=DEBUG msg="template cache miss" templates=[logging-error.html] namespace=""`,
+		`level=ERROR msg="render failed" templates=[logging-error.html] request_id=abc123 error=`,
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %s", len(expected), buf.String())
+	}
+
+	for i, want := range expected {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("expected %s, got %s", want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"io"
+)
+
+// Meta is the title, description and social meta of a page: the Open Graph properties and the Twitter card. Urls are
+// absolute, crawlers don't resolve them against the page.
+type Meta struct {
+	Title       string
+	Description string
+	URL         string // the canonical url of the page, og:url
+	Image       string
+	ImageAlt    string
+	Type        string // og:type, website if empty
+	SiteName    string
+	Locale      string // og:locale, like en_US
+
+	TwitterCard    string // summary_large_image with an image and summary without one if empty
+	TwitterSite    string // the @username of the site
+	TwitterCreator string // the @username of the author
+}
+
+// AddMeta adds the title, meta and link elements of the meta. Twitter reads the title, description and image from
+// the Open Graph properties, only the card fields are added as twitter: meta.
+func (h *Head) AddMeta(m Meta) *Head {
+	if m.Title != "" {
+		h.Title(m.Title)
+	}
+
+	typ := m.Type
+	if typ == "" {
+		typ = "website"
+	}
+
+	card := m.TwitterCard
+	if card == "" {
+		card = "summary"
+		if m.Image != "" {
+			card = "summary_large_image"
+		}
+	}
+
+	for _, meta := range [][2]string{
+		{"description", m.Description},
+		{"og:type", typ},
+		{"og:title", m.Title},
+		{"og:description", m.Description},
+		{"og:url", m.URL},
+		{"og:image", m.Image},
+		{"og:image:alt", m.ImageAlt},
+		{"og:site_name", m.SiteName},
+		{"og:locale", m.Locale},
+		{"twitter:card", card},
+		{"twitter:site", m.TwitterSite},
+		{"twitter:creator", m.TwitterCreator},
+	} {
+		if meta[1] != "" {
+			h.Meta(meta[0], meta[1])
+		}
+	}
+
+	if m.URL != "" {
+		h.Link("canonical", m.URL)
+	}
+
+	return h
+}
+
+// HTML returns the title, meta and link elements of the meta
+func (m Meta) HTML() template.HTML {
+	head := &Head{seen: make(map[string]bool)}
+	return head.AddMeta(m).Tags()
+}
+
+// NewMetaComponent returns a component that adds the meta to the head of the page, so any page component sets the
+// social meta that its layout places with headTags. Rendered without a head, like by Render outside of a Handler,
+// the component renders the elements in place.
+//
+//	page.With(htmx.NewMetaComponent(htmx.Meta{
+//		Title:       post.Title,
+//		Description: post.Summary,
+//		URL:         "https://example.com/blog/" + post.Slug,
+//		Image:       "https://example.com/images/" + post.Cover,
+//	}), "meta")
+//
+// The first contribution of an element wins, the meta of a page overrides the defaults of its layout.
+func NewMetaComponent(m Meta) *Component {
+	return NewWriterComponent(func(ctx context.Context, w io.Writer) error {
+		if head := HeadFromContext(ctx); head != nil {
+			head.AddMeta(m)
+			return nil
+		}
+
+		_, err := io.WriteString(w, string(m.HTML()))
+		return err
+	})
+}
@@ -0,0 +1,80 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMeta(t *testing.T) {
+	meta := Meta{
+		Title:       "Hello & welcome",
+		Description: "The first post",
+		URL:         "https://example.com/blog/hello",
+		Image:       "https://example.com/hello.png",
+		TwitterSite: "@example",
+	}
+
+	equal(t, `<title>Hello &amp; welcome</title><meta name="description" content="The first post">`+
+		`<meta property="og:type" content="website"><meta property="og:title" content="Hello &amp; welcome">`+
+		`<meta property="og:description" content="The first post"><meta property="og:url" content="https://example.com/blog/hello">`+
+		`<meta property="og:image" content="https://example.com/hello.png"><meta name="twitter:card" content="summary_large_image">`+
+		`<meta name="twitter:site" content="@example"><link rel="canonical" href="https://example.com/blog/hello">`, string(meta.HTML()))
+}
+
+func TestNewMetaComponent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"meta-layout.html": {Data: []byte(`<html><head>{{ headTags }}</head><body>{{ .Partials.content }}</body></html>`)},
+		"meta-page.html":   {Data: []byte(`<p>post</p>`)},
+	}
+
+	page := NewComponent("meta-page.html").FS(fsys).
+		With(NewMetaComponent(Meta{Title: "Post", Description: "A post"}), "meta").
+		Wrap(NewComponent("meta-layout.html").FS(fsys), "content")
+
+	w := httptest.NewRecorder()
+	if _, err := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/", nil)).Render(context.Background(), page); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<html><head><title>Post</title><meta name="description" content="A post"><meta property="og:type" content="website">`+
+		`<meta property="og:title" content="Post"><meta property="og:description" content="A post">`+
+		`<meta name="twitter:card" content="summary"></head><body><p>post</p></body></html>`, w.Body.String())
+
+	out, err := NewMetaComponent(Meta{Title: "Post"}).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, string(Meta{Title: "Post"}.HTML()), string(out))
+}
+
+func TestNewMetaComponentDevMode(t *testing.T) {
+	SetMode(Dev)
+	defer SetMode(Prod)
+
+	out, err := NewMetaComponent(Meta{Title: "x"}).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<!-- htmx:begin component="(writer)" templates="" -->`+string(Meta{Title: "x"}.HTML())+
+		`<!-- htmx:end component="(writer)" -->`, string(out))
+
+	fsys := fstest.MapFS{
+		"meta-dev-layout.html": {Data: []byte(`<html><head>{{ headTags }}</head><body>{{ .Partials.content }}</body></html>`)},
+		"meta-dev-page.html":   {Data: []byte(`<p>post</p>`)},
+	}
+	page := NewComponent("meta-dev-page.html").FS(fsys).
+		With(NewMetaComponent(Meta{Title: "Post"}), "meta").
+		Wrap(NewComponent("meta-dev-layout.html").FS(fsys), "content")
+
+	w := httptest.NewRecorder()
+	if _, err := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/?htmx-debug", nil)).Render(context.Background(), page); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.Body.String(), "<title>Post</title>") {
+		t.Errorf("expected the title in %s", w.Body.String())
+	}
+}
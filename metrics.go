@@ -0,0 +1,67 @@
+package htmx
+
+import (
+	"time"
+)
+
+const (
+	// PhaseParse parses the templates of a component, or looks them up in the template cache
+	PhaseParse RenderPhase = "parse"
+
+	// PhasePartials renders the partials of a component, their own phases included
+	PhasePartials RenderPhase = "partials"
+
+	// PhaseExecute executes the parsed templates of a component
+	PhaseExecute RenderPhase = "execute"
+)
+
+// RenderMetrics receives the renders of all components and the evictions of the template cache when set, e.g. the
+// Prometheus collector of adapters/prometheus
+var RenderMetrics MetricsObserver
+
+type (
+	// MetricsObserver observes renders and template cache evictions, it is called concurrently
+	MetricsObserver interface {
+		// ObserveRender observes the render of a component, its partials included
+		ObserveRender(info RenderInfo, stats RenderStats)
+
+		// ObserveCacheEvictions observes the removal of n parsed templates from the template cache
+		ObserveCacheEvictions(n int)
+	}
+
+	// PhaseObserver is optionally implemented by the RenderMetrics to observe the phases of every render, e.g. by
+	// htmxtest.Benchmark. The phases of partials run within the partials phase of their parent.
+	PhaseObserver interface {
+		// StartPhase starts a phase of the render of the component, the returned function ends it
+		StartPhase(info RenderInfo, phase RenderPhase) func()
+	}
+
+	// RenderPhase is a phase of the render of a component
+	RenderPhase string
+
+	// RenderStats are the measurements of a render
+	RenderStats struct {
+		Duration    time.Duration // the duration of the render, its partials included
+		CacheStatus string        // the template cache status: hit, miss or off when the cache is disabled
+		Err         error         // the error of the render
+	}
+)
+
+// observeEvictions reports the evicted templates to the RenderMetrics
+func observeEvictions(n int) {
+	if RenderMetrics != nil && n > 0 {
+		RenderMetrics.ObserveCacheEvictions(n)
+	}
+}
+
+// startPhase starts a phase of the render when the RenderMetrics observe phases, the returned function ends it
+func (c *Component) startPhase(phase RenderPhase) func() {
+	if o, ok := RenderMetrics.(PhaseObserver); ok {
+		return o.StartPhase(c.renderInfo(), phase)
+	}
+
+	return endNoPhase
+}
+
+// endNoPhase ends a phase that isn't observed
+func endNoPhase() {}
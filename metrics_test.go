@@ -0,0 +1,47 @@
+package htmx
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testMetrics struct {
+	mu        sync.Mutex
+	renders   []string
+	evictions int
+}
+
+func (m *testMetrics) ObserveRender(info RenderInfo, stats RenderStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.renders = append(m.renders, info.Component+" "+stats.CacheStatus)
+}
+
+func (m *testMetrics) ObserveCacheEvictions(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictions += n
+}
+
+func TestRenderMetrics(t *testing.T) {
+	ClearTemplateCache()
+
+	metrics := &testMetrics{}
+	RenderMetrics = metrics
+	defer func() { RenderMetrics = nil }()
+
+	for range 2 {
+		if _, err := benchComponent().Render(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	InvalidateTemplate("list.html")
+
+	equal(t, "list.html miss,page.html miss,list.html hit,page.html hit", strings.Join(metrics.renders, ","))
+	equalInt(t, 1, metrics.evictions)
+}
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+// ConditionalOptions configures the Conditional middleware
+type ConditionalOptions struct {
+	// CacheControl is the Cache-Control header of the responses, defaults to no-cache, which makes the browser
+	// revalidate its copy on every request. Handlers can still set their own.
+	CacheControl string
+
+	// Key returns the version of the resource of the request, e.g. the id and update counter of a record. The ETag is
+	// derived from the key and the htmx request headers before the handler runs, so matching requests are answered
+	// without rendering. An empty key, or no Key function, derives the ETag from the rendered response instead.
+	Key func(r *http.Request) string
+
+	// LastModified returns the time the resource of the request was modified, it is sent as Last-Modified and checked
+	// against If-Modified-Since before the handler runs. The zero time skips the check.
+	LastModified func(r *http.Request) time.Time
+}
+
+// Conditional answers GET and HEAD requests with 304 Not Modified when the client's copy is still current, so polling
+// and revisited fragments don't transfer or render the same html again. It sets ETag, Last-Modified and Cache-Control
+// and handles If-None-Match and If-Modified-Since.
+//
+//	mux.Handle("GET /stats", middleware.Conditional(middleware.ConditionalOptions{
+//		Key: func(r *http.Request) string { return strconv.FormatInt(stats.Version(), 10) },
+//	})(statsHandler))
+//
+// Without a Key, GET responses with status 200 are buffered and hashed, streamed responses like event streams and
+// responses that are flushed pass through.
+func Conditional(opts ConditionalOptions) func(http.Handler) http.Handler {
+	if opts.CacheControl == "" {
+		opts.CacheControl = "no-cache"
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("Cache-Control", opts.CacheControl)
+
+			etag := ""
+			if opts.Key != nil {
+				if key := opts.Key(r); key != "" {
+					etag = weakETag(key, r.Header.Get(htmx.HxRequestHeaderRequest.String()),
+						r.Header.Get(htmx.HxRequestHeaderBoosted.String()), r.Header.Get(htmx.HxRequestHeaderTarget.String()))
+					header.Set("ETag", etag)
+				}
+			}
+
+			var modified time.Time
+			if opts.LastModified != nil {
+				if modified = opts.LastModified(r).UTC().Truncate(time.Second); !modified.IsZero() {
+					header.Set("Last-Modified", modified.Format(http.TimeFormat))
+				}
+			}
+
+			if (etag != "" || !modified.IsZero()) && notModified(r, etag, modified) {
+				writeNotModified(w)
+				return
+			}
+
+			if etag != "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &conditionalWriter{ResponseWriter: w}
+			next.ServeHTTP(cw, r)
+			cw.finish(r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// conditionalWriter buffers a response with status 200 to derive its ETag, other responses pass through
+type conditionalWriter struct {
+	http.ResponseWriter
+
+	buf         bytes.Buffer
+	wroteHeader bool
+	passthrough bool
+}
+
+// WriteHeader passes the status through unless it is 200 and the response isn't an event stream
+func (w *conditionalWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if status != http.StatusOK || strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// Write buffers the body of a response with status 200
+func (w *conditionalWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.buf.Write(b)
+}
+
+// Flush writes the buffered body and passes the rest of the response through, a flushed response has no ETag
+func (w *conditionalWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.passthrough {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped response writer for http.ResponseController
+func (w *conditionalWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish sets the ETag of the buffered response and writes it, or 304 Not Modified when the client's copy matches
+func (w *conditionalWriter) finish(r *http.Request) {
+	if w.passthrough || !w.wroteHeader {
+		return
+	}
+
+	header := w.Header()
+	etag := header.Get("ETag")
+	if etag == "" {
+		sum := sha256.Sum256(w.buf.Bytes())
+		etag = weakETag(hex.EncodeToString(sum[:]))
+		header.Set("ETag", etag)
+	}
+
+	if notModified(r, etag, time.Time{}) {
+		writeNotModified(w.ResponseWriter)
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(http.StatusOK)
+	_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+}
+
+// weakETag returns a weak ETag of the parts
+func weakETag(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified returns true if the client's copy is current. If-None-Match takes precedence over If-Modified-Since.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etag != "" && matchETag(inm, etag)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !modified.After(t)
+	}
+
+	return false
+}
+
+// matchETag returns true if the If-None-Match header lists the ETag, compared weakly, or is *
+func matchETag(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeNotModified writes 304 Not Modified, without the headers that describe a body
+func writeNotModified(w http.ResponseWriter) {
+	header := w.Header()
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	header.Del("Content-Encoding")
+	w.WriteHeader(http.StatusNotModified)
+}
@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditional(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	body := "<p>stats</p>"
+	bodySum := sha256.Sum256([]byte(body))
+	bodyETag := weakETag(hex.EncodeToString(bodySum[:]))
+	keyETag := weakETag("v7", "", "", "")
+
+	page := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	})
+
+	tests := []struct {
+		name         string
+		opts         ConditionalOptions
+		handler      http.Handler
+		method       string
+		headers      map[string]string
+		status       int
+		body         string
+		etag         string
+		rendered     bool
+		cacheControl string
+	}{
+		{
+			name:     "key",
+			opts:     ConditionalOptions{Key: func(*http.Request) string { return "v7" }},
+			status:   http.StatusOK,
+			body:     body,
+			etag:     keyETag,
+			rendered: true,
+		},
+		{
+			name:    "key match",
+			opts:    ConditionalOptions{Key: func(*http.Request) string { return "v7" }},
+			headers: map[string]string{"If-None-Match": keyETag},
+			status:  http.StatusNotModified,
+			etag:    keyETag,
+		},
+		{
+			name:     "key varies by htmx request",
+			opts:     ConditionalOptions{Key: func(*http.Request) string { return "v7" }},
+			headers:  map[string]string{"If-None-Match": keyETag, "HX-Request": "true"},
+			status:   http.StatusOK,
+			body:     body,
+			etag:     weakETag("v7", "true", "", ""),
+			rendered: true,
+		},
+		{
+			name:     "hashed body",
+			status:   http.StatusOK,
+			body:     body,
+			etag:     bodyETag,
+			rendered: true,
+		},
+		{
+			name:     "hashed body match",
+			headers:  map[string]string{"If-None-Match": bodyETag},
+			status:   http.StatusNotModified,
+			etag:     bodyETag,
+			rendered: true,
+		},
+		{
+			name:     "hashed body mismatch",
+			headers:  map[string]string{"If-None-Match": `W/"other"`},
+			status:   http.StatusOK,
+			body:     body,
+			etag:     bodyETag,
+			rendered: true,
+		},
+		{
+			name:    "weak comparison",
+			opts:    ConditionalOptions{Key: func(*http.Request) string { return "v7" }},
+			headers: map[string]string{"If-None-Match": `"other", ` + keyETag[2:]},
+			status:  http.StatusNotModified,
+			etag:    keyETag,
+		},
+		{
+			name:     "any",
+			headers:  map[string]string{"If-None-Match": "*"},
+			status:   http.StatusNotModified,
+			etag:     bodyETag,
+			rendered: true,
+		},
+		{
+			name:    "any with key",
+			opts:    ConditionalOptions{Key: func(*http.Request) string { return "v7" }},
+			headers: map[string]string{"If-None-Match": "*"},
+			status:  http.StatusNotModified,
+			etag:    keyETag,
+		},
+		{
+			name:    "not modified since",
+			opts:    ConditionalOptions{LastModified: func(*http.Request) time.Time { return modified }},
+			headers: map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			status:  http.StatusNotModified,
+		},
+		{
+			name:     "modified since",
+			opts:     ConditionalOptions{LastModified: func(*http.Request) time.Time { return modified }},
+			headers:  map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			status:   http.StatusOK,
+			body:     body,
+			etag:     bodyETag,
+			rendered: true,
+		},
+		{
+			name: "if-none-match takes precedence",
+			opts: ConditionalOptions{LastModified: func(*http.Request) time.Time { return modified }},
+			headers: map[string]string{
+				"If-None-Match":     `W/"other"`,
+				"If-Modified-Since": modified.Format(http.TimeFormat),
+			},
+			status:   http.StatusOK,
+			body:     body,
+			etag:     bodyETag,
+			rendered: true,
+		},
+		{
+			name: "not found passes through",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "missing", http.StatusNotFound)
+			}),
+			headers:  map[string]string{"If-None-Match": "*"},
+			status:   http.StatusNotFound,
+			body:     "missing\n",
+			rendered: true,
+		},
+		{
+			name: "event stream passes through",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = io.WriteString(w, "data: 1\n\n")
+			}),
+			headers:  map[string]string{"If-None-Match": "*"},
+			status:   http.StatusOK,
+			body:     "data: 1\n\n",
+			rendered: true,
+		},
+		{
+			name:     "head",
+			method:   http.MethodHead,
+			status:   http.StatusOK,
+			body:     body,
+			rendered: true,
+		},
+		{
+			name:    "head key match",
+			opts:    ConditionalOptions{Key: func(*http.Request) string { return "v7" }},
+			method:  http.MethodHead,
+			headers: map[string]string{"If-None-Match": keyETag},
+			status:  http.StatusNotModified,
+			etag:    keyETag,
+		},
+		{
+			name:     "post passes through",
+			method:   http.MethodPost,
+			headers:  map[string]string{"If-None-Match": "*"},
+			status:   http.StatusOK,
+			body:     body,
+			rendered: true,
+		},
+		{
+			name:         "cache control",
+			opts:         ConditionalOptions{CacheControl: "private, max-age=60"},
+			status:       http.StatusOK,
+			body:         body,
+			etag:         bodyETag,
+			rendered:     true,
+			cacheControl: "private, max-age=60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.handler
+			if handler == nil {
+				handler = page
+			}
+
+			rendered := false
+			counted := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rendered = true
+				handler.ServeHTTP(w, r)
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			r := httptest.NewRequest(method, "/stats", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			Conditional(tt.opts)(counted).ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, w.Body.String())
+			}
+			if method != http.MethodPost && w.Header().Get("ETag") != tt.etag {
+				t.Errorf("expected ETag %q, got %q", tt.etag, w.Header().Get("ETag"))
+			}
+			if rendered != tt.rendered {
+				t.Errorf("expected rendered %t, got %t", tt.rendered, rendered)
+			}
+
+			cacheControl := tt.cacheControl
+			if cacheControl == "" && method != http.MethodPost {
+				cacheControl = "no-cache"
+			}
+			if w.Header().Get("Cache-Control") != cacheControl {
+				t.Errorf("expected Cache-Control %q, got %q", cacheControl, w.Header().Get("Cache-Control"))
+			}
+			if tt.status == http.StatusNotModified && w.Header().Get("Content-Type") != "" {
+				t.Errorf("expected no Content-Type on 304, got %q", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestConditionalFlush(t *testing.T) {
+	handler := Conditional(ConditionalOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "<p>first</p>")
+		http.NewResponseController(w).Flush()
+		_, _ = io.WriteString(w, "<p>second</p>")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/report", nil)
+	r.Header.Set("If-None-Match", "*")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !w.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+	if w.Body.String() != "<p>first</p><p>second</p>" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Errorf("expected no ETag for a flushed response, got %q", w.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+// CSP generates a nonce for every request, stores it in the request context for the nonce template function
+// and sets the Content-Security-Policy header. The {nonce} placeholders in the policy are replaced by the nonce,
+// htmx.DefaultCSP is used when the policy is empty.
+func CSP(policy string) func(http.Handler) http.Handler {
+	return csp("Content-Security-Policy", policy)
+}
+
+// CSPReportOnly is like CSP, but sets the Content-Security-Policy-Report-Only header so violations are only reported
+func CSPReportOnly(policy string) func(http.Handler) http.Handler {
+	return csp("Content-Security-Policy-Report-Only", policy)
+}
+
+func csp(header, policy string) func(http.Handler) http.Handler {
+	if policy == "" {
+		policy = htmx.DefaultCSP
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			nonce, err := htmx.NewNonce()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set(header, htmx.CSPHeader(policy, nonce))
+
+			next.ServeHTTP(w, r.WithContext(htmx.WithNonce(r.Context(), nonce)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
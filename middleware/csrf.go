@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"mime"
+	"net/http"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+// CSRFOptions configures the CSRF middleware
+type CSRFOptions struct {
+	// Secure marks the cookie as secure, it should be set when the site is served over https
+	Secure bool
+
+	// Path is the path of the cookie, defaults to /
+	Path string
+
+	// MaxAge is the max age of the cookie in seconds, 0 makes it a session cookie
+	MaxAge int
+
+	// SameSite is the SameSite attribute of the cookie, defaults to http.SameSiteLaxMode
+	SameSite http.SameSite
+
+	// ErrorHandler handles requests that fail the verification, defaults to a 403 response
+	ErrorHandler http.Handler
+}
+
+// CSRF protects unsafe requests with a double submit token. The token is stored in a cookie and in the request
+// context for the csrfToken, csrfField and csrfHeaders template functions. POST, PUT, PATCH and DELETE requests
+// have to send the token in the htmx.CSRFHeaderName header or the htmx.CSRFFieldName form field. Multipart requests
+// have to send the header: reading the field would parse the body before the handler, which breaks the streaming of
+// htmx.StreamUpload and htmx.UploadHandler. hx-headers sends it with the uploads of htmx forms.
+//
+//	<body hx-headers='{{ csrfHeaders }}'>
+func CSRF(opts CSRFOptions) func(http.Handler) http.Handler {
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+
+	if opts.SameSite == 0 {
+		opts.SameSite = http.SameSiteLaxMode
+	}
+
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		})
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Cookie")
+
+			token := ""
+			if cookie, err := r.Cookie(htmx.CSRFCookieName); err == nil && validCSRFToken(cookie.Value) {
+				token = cookie.Value
+			}
+
+			if token == "" {
+				var err error
+				if token, err = htmx.NewCSRFToken(); err != nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+
+				http.SetCookie(w, &http.Cookie{
+					Name:     htmx.CSRFCookieName,
+					Value:    token,
+					Path:     opts.Path,
+					MaxAge:   opts.MaxAge,
+					Secure:   opts.Secure,
+					HttpOnly: true,
+					SameSite: opts.SameSite,
+				})
+			}
+
+			r = r.WithContext(htmx.WithCSRFToken(r.Context(), token))
+
+			if !safeMethod(r.Method) && !htmx.VerifyCSRFToken(token, submittedCSRFToken(r)) {
+				opts.ErrorHandler.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// submittedCSRFToken returns the token of the header, or of the form field when the header isn't set. The body of
+// multipart requests is left to the handler, they only submit the token with the header.
+func submittedCSRFToken(r *http.Request) string {
+	if token := r.Header.Get(htmx.CSRFHeaderName); token != "" {
+		return token
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		return ""
+	}
+
+	return r.PostFormValue(htmx.CSRFFieldName)
+}
+
+// validCSRFToken returns true if the cookie holds a token that was generated by htmx.NewCSRFToken
+func validCSRFToken(token string) bool {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(raw) == 32
+}
+
+// safeMethod returns true for the methods that must not change state
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
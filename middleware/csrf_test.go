@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+func TestCSRFUpload(t *testing.T) {
+	token, err := htmx.NewCSRFToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	masked, err := htmx.MaskCSRFToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored string
+	upload := htmx.New().UploadHandler(htmx.UploadOptions{}, func(_ context.Context, file *htmx.UploadedFile, content io.Reader) error {
+		b, err := io.ReadAll(content)
+		stored = file.Name + ":" + string(b)
+		return err
+	})
+	handler := CSRF(CSRFOptions{})(upload)
+
+	request := func(field, header string) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		if field != "" {
+			_ = mw.WriteField(htmx.CSRFFieldName, field)
+		}
+		fw, err := mw.CreateFormFile("file", "notes.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write([]byte("hello world"))
+		_ = mw.Close()
+
+		r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+		r.Header.Set("HX-Request", "true")
+		r.AddCookie(&http.Cookie{Name: htmx.CSRFCookieName, Value: token})
+		if header != "" {
+			r.Header.Set(htmx.CSRFHeaderName, header)
+		}
+		return r
+	}
+
+	// the header leaves the body to the streaming upload handler
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request("", masked))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if stored != "notes.txt:hello world" {
+		t.Errorf("expected the upload to be streamed, got %q", stored)
+	}
+	if !strings.Contains(w.Body.String(), "notes.txt") {
+		t.Errorf("expected the file in the result, got %q", w.Body.String())
+	}
+
+	// the form field of multipart requests is not read
+	stored = ""
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, request(masked, ""))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+	if stored != "" {
+		t.Errorf("expected the upload to be rejected, got %q", stored)
+	}
+}
+
+func TestCSRFForm(t *testing.T) {
+	token, err := htmx.NewCSRFToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	masked, err := htmx.MaskCSRFToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := CSRF(CSRFOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.PostFormValue("title"))
+	}))
+
+	for _, submitted := range []string{masked, "invalid"} {
+		r := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("title=Notes&"+htmx.CSRFFieldName+"="+submitted))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(&http.Cookie{Name: htmx.CSRFCookieName, Value: token})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		expected := http.StatusOK
+		if submitted == "invalid" {
+			expected = http.StatusForbidden
+		}
+		if w.Code != expected {
+			t.Errorf("expected status %d, got %d", expected, w.Code)
+		}
+	}
+}
@@ -7,18 +7,16 @@ import (
 	"github.com/jkc-2/go-htmx"
 )
 
-// MiddleWare is a middleware that adds the htmx request header to the context
-// deprecated: htmx will retrieve the headers from the request by itself using htmx.NewHandler(w, r)
+// MiddleWare is a middleware that adds the htmx request header to the context. It runs htmx.Middleware and also
+// stores the headers under htmx.ContextRequestHeader for code that reads that key.
+//
+// Deprecated: use htmx.Middleware and read the headers with htmx.FromContext, htmx.NewHandler(w, r) reads them too.
 func MiddleWare(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-
-		hxh := htmx.HxRequestHeaderFromRequest(r)
-
 		//nolint:staticcheck
-		ctx = context.WithValue(ctx, htmx.ContextRequestHeader, hxh)
+		ctx := context.WithValue(r.Context(), htmx.ContextRequestHeader, htmx.HxRequestHeaderFromRequest(r))
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
-	return http.HandlerFunc(fn)
+	return htmx.Middleware(http.HandlerFunc(fn))
 }
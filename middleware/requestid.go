@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+// RequestID propagates the request id of the htmx.RequestIDHeader header, or generates a new one when the header is
+// missing or invalid. The id is stored in the request context and written to the response header, components rendered
+// by a Handler find it in their global data, and their render errors carry it.
+//
+//	<footer>Request {{ .Global.RequestID }}</footer>
+func RequestID(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(htmx.RequestIDHeader)
+		if !htmx.ValidRequestID(id) {
+			var err error
+			if id, err = htmx.NewRequestID(); err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(htmx.RequestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(htmx.WithRequestID(r.Context(), id)))
+	}
+	return http.HandlerFunc(fn)
+}
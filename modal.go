@@ -0,0 +1,93 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"io"
+	"net/http"
+)
+
+//go:embed templates/modal.html
+var modalTemplates embed.FS
+
+var (
+	// DefaultModalTarget is the id of the element the modal dialog is rendered into, the layout provides it empty:
+	// <div id="modal"></div>
+	DefaultModalTarget = "modal"
+
+	// ModalOpenEvent is the event that is triggered when a modal dialog was opened
+	ModalOpenEvent = "modal-open"
+
+	// ModalCloseEvent is the event that is triggered when the modal dialog was closed, e.g. to reload a list
+	ModalCloseEvent = "modal-close"
+)
+
+// NewModal returns a component that renders the content in a dialog, which opens as a modal once it was swapped in
+func NewModal(content RenderableComponent) *Component {
+	c := NewComponent("templates/modal.html").FS(modalTemplates)
+	c.With(content, "content")
+
+	return c
+}
+
+// ShowModal renders the component in a dialog and writes it as an out-of-band swap into the DefaultModalTarget, the
+// element that issued the request is left as it is. The ModalOpenEvent is triggered. Use Handler.ShowModal when the
+// response is rendered by the Handler.
+//
+//	<button hx-get="/users/7/edit">Edit</button>
+func ShowModal(w http.ResponseWriter, r *http.Request, c RenderableComponent) error {
+	fragment := modalFragment(NewModal(c))
+	fragment.SetRequest(r)
+
+	output, err := fragment.Render(r.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := setModalHeaders(w.Header(), ModalOpenEvent); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, string(output))
+	return err
+}
+
+// CloseModal writes an empty out-of-band swap into the DefaultModalTarget, which closes the dialog, and triggers the
+// ModalCloseEvent. Use Handler.CloseModal when the response is rendered by the Handler.
+func CloseModal(w http.ResponseWriter) error {
+	if err := setModalHeaders(w.Header(), ModalCloseEvent); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, string(oobWrap("", DefaultModalTarget, NewSwap(SwapInnerHTML))))
+	return err
+}
+
+// ShowModal renders the component in a dialog as an out-of-band swap into the DefaultModalTarget, see ShowModal
+func (h *Handler) ShowModal(ctx context.Context, c RenderableComponent) (int, error) {
+	if err := setModalHeaders(h.Header(), ModalOpenEvent); err != nil {
+		return 0, err
+	}
+
+	return h.Render(ctx, modalFragment(NewModal(c)))
+}
+
+// CloseModal closes the dialog of the DefaultModalTarget, see CloseModal
+func (h *Handler) CloseModal() (int, error) {
+	if err := setModalHeaders(h.Header(), ModalCloseEvent); err != nil {
+		return 0, err
+	}
+
+	return h.WriteHTML(oobWrap("", DefaultModalTarget, NewSwap(SwapInnerHTML)))
+}
+
+// modalFragment returns the out-of-band swap of the modal into the DefaultModalTarget
+func modalFragment(modal RenderableComponent) *MultiFragment {
+	return NewMultiFragment(nil).OOB(DefaultModalTarget, modal, NewSwap(SwapInnerHTML))
+}
+
+// setModalHeaders keeps the element that issued the request as it is and adds the event to the triggered events
+func setModalHeaders(header http.Header, event string) error {
+	header.Set(HXReswap.String(), string(SwapNone))
+	return triggerEvents(header, HXTrigger, map[string]any{event: ""})
+}
@@ -0,0 +1,57 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestShowModal(t *testing.T) {
+	fsys := fstest.MapFS{"modal-form.html": {Data: []byte(`<form>{{ .Data.Name }}</form>`)}}
+
+	r := httptest.NewRequest(http.MethodGet, "/users/7/edit", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	if err := ShowModal(w, r, NewComponent("modal-form.html").FS(fsys).AddData("Name", "Ann")); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-swap-oob="innerHTML:#modal"><dialog class="modal" hx-on::load="this.showModal()" hx-on:close="this.remove()"><form>Ann</form></dialog></div>`, w.Body.String())
+	equal(t, "none", w.Header().Get("HX-Reswap"))
+	equal(t, `{"modal-open":""}`, w.Header().Get("HX-Trigger"))
+
+	// the handler renders the same fragment
+	w = httptest.NewRecorder()
+	if _, err := New().NewHandler(w, r).ShowModal(context.Background(), NewComponent("modal-form.html").FS(fsys).AddData("Name", "Ann")); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-swap-oob="innerHTML:#modal"><dialog class="modal" hx-on::load="this.showModal()" hx-on:close="this.remove()"><form>Ann</form></dialog></div>`, w.Body.String())
+}
+
+func TestCloseModal(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("HX-Trigger", `{"saved":"user 7"}`)
+
+	if err := CloseModal(w); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-swap-oob="innerHTML:#modal"></div>`, w.Body.String())
+	equal(t, `{"modal-close":"","saved":"user 7"}`, w.Header().Get("HX-Trigger"))
+
+	r := httptest.NewRequest(http.MethodPost, "/users/7", nil)
+	w = httptest.NewRecorder()
+
+	h := New().NewHandler(w, r)
+	h.Trigger("saved")
+	if _, err := h.CloseModal(); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `{"modal-close":"","saved":""}`, w.Header().Get("HX-Trigger"))
+	equal(t, "none", w.Header().Get("HX-Reswap"))
+}
@@ -0,0 +1,180 @@
+package htmx
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Mode is the mode the package is running in
+type Mode int32
+
+const (
+	// Prod caches parsed templates and renders components as they are
+	Prod Mode = iota
+
+	// Dev disables the template cache and wraps the output of every component in html comments
+	// identifying the component and its template files. Requests with the DebugParam query parameter
+	// show the debug overlay.
+	Dev
+)
+
+// DebugParam is the query parameter that shows the debug overlay in Dev mode, e.g. /users?htmx-debug
+var DebugParam = "htmx-debug"
+
+var mode atomic.Int32
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	switch m {
+	case Prod:
+		return "prod"
+	case Dev:
+		return "dev"
+	}
+
+	return fmt.Sprintf("Mode(%d)", int32(m))
+}
+
+// SetMode sets the mode of the package, Prod is the default
+func SetMode(m Mode) {
+	mode.Store(int32(m))
+}
+
+// CurrentMode returns the mode of the package
+func CurrentMode() Mode {
+	return Mode(mode.Load())
+}
+
+// isDev returns true when running in Dev mode
+func isDev() bool {
+	return CurrentMode() == Dev
+}
+
+// debugComments wraps the output of the component in html comments identifying the component and its template files
+func (c *Component) debugComments(output template.HTML) template.HTML {
+	name := debugCommentText(c.debugName())
+	files := make([]string, len(c.templates))
+	for i, t := range c.templates {
+		files[i] = debugCommentText(t)
+	}
+
+	//nolint:gosec // output is already trusted html and the comment text can't close the comment
+	return template.HTML(fmt.Sprintf("<!-- htmx:begin component=%q templates=%q -->%s<!-- htmx:end component=%q -->",
+		name, strings.Join(files, ","), output, name))
+}
+
+// debugName returns the name of the component in the debug annotations, its first template file, writer components
+// have none
+func (c *Component) debugName() string {
+	if len(c.templates) == 0 {
+		return "(writer)"
+	}
+
+	return c.templates[0]
+}
+
+// debugCommentText makes sure the text can't end the html comment it is placed in
+func debugCommentText(s string) string {
+	return strings.ReplaceAll(s, "--", "- -")
+}
+
+// debugRequested returns true if the request of the render, or the page of an htmx request, has the DebugParam query
+// parameter
+func debugRequested(ctx context.Context) bool {
+	r, ok := RequestFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	return hasDebugParam(r)
+}
+
+// hasDebugParam returns true if the url of the request, or the page of an htmx request, has the DebugParam query
+// parameter
+func hasDebugParam(r *http.Request) bool {
+	if r.URL != nil && r.URL.Query().Has(DebugParam) {
+		return true
+	}
+
+	current, err := url.Parse(r.Header.Get(HxRequestHeaderCurrentURL.String()))
+	return err == nil && current.Query().Has(DebugParam)
+}
+
+// debugAttributes adds data attributes identifying the component, its template files, its render duration and the
+// template cache status to the root element of the output. Outputs that don't start with an element and root elements
+// that were annotated by a partial are left as they are, the innermost component is shown.
+func (c *Component) debugAttributes(output template.HTML, duration time.Duration) template.HTML {
+	s := string(output)
+	start := len(s) - len(strings.TrimLeft(s, " \t\r\n"))
+	if start+1 >= len(s) || s[start] != '<' || !isASCIILetter(s[start+1]) {
+		return output
+	}
+
+	nameEnd := start + 1
+	for nameEnd < len(s) && (isASCIILetter(s[nameEnd]) || s[nameEnd] >= '0' && s[nameEnd] <= '9' || s[nameEnd] == '-') {
+		nameEnd++
+	}
+
+	if tagEnd := strings.IndexByte(s[nameEnd:], '>'); tagEnd < 0 || strings.Contains(s[nameEnd:nameEnd+tagEnd], "data-htmx-component=") {
+		return output
+	}
+
+	attrs := fmt.Sprintf(` data-htmx-component="%s" data-htmx-templates="%s" data-htmx-duration="%s" data-htmx-cache="%s"`,
+		template.HTMLEscapeString(c.debugName()), template.HTMLEscapeString(strings.Join(c.templates, ",")),
+		duration.Round(time.Microsecond), c.cacheStatus)
+
+	//nolint:gosec // output is already trusted html and the attribute values are escaped
+	return template.HTML(s[:nameEnd] + attrs + s[nameEnd:])
+}
+
+// isASCIILetter returns true for the letters a tag name starts with
+func isASCIILetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// debugOverlay returns the overlay that outlines the element under the mouse pointer and shows the component that
+// rendered it, from the data attributes added by debugAttributes
+func debugOverlay(nonce string) template.HTML {
+	//nolint:gosec // the nonce is escaped
+	return template.HTML(`<style nonce="` + template.HTMLEscapeString(nonce) + `">
+#htmx-debug{position:fixed;z-index:2147483647;pointer-events:none;display:none;padding:2px 6px;font:12px/1.4 monospace;background:#111;color:#fff;border-radius:3px}
+.htmx-debug-outline{outline:2px dashed #e44 !important}
+</style>
+<script nonce="` + template.HTMLEscapeString(nonce) + `">
+(function () {
+	var label = document.createElement("div"), current;
+	label.id = "htmx-debug";
+	document.addEventListener("DOMContentLoaded", function () { document.body.appendChild(label); });
+	document.addEventListener("mouseover", function (e) {
+		var el = e.target.closest && e.target.closest("[data-htmx-component]");
+		if (current) current.classList.remove("htmx-debug-outline");
+		current = el;
+		if (!el) { label.style.display = "none"; return; }
+		el.classList.add("htmx-debug-outline");
+		var d = el.dataset, r = el.getBoundingClientRect();
+		label.textContent = d.htmxTemplates + " · " + d.htmxDuration + " · cache " + d.htmxCache;
+		label.style.top = Math.max(0, r.top - 22) + "px";
+		label.style.left = Math.max(0, r.left) + "px";
+		label.style.display = "block";
+	});
+})();
+</script>`)
+}
+
+// withDebugOverlay adds the debug overlay to the page, before the closing body tag when it has one
+func withDebugOverlay(ctx context.Context, output template.HTML) template.HTML {
+	overlay := string(debugOverlay(Nonce(ctx)))
+
+	s := string(output)
+	if i := strings.LastIndex(strings.ToLower(s), "</body>"); i >= 0 {
+		return template.HTML(s[:i] + overlay + s[i:])
+	}
+
+	return template.HTML(s + overlay)
+}
@@ -0,0 +1,72 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDevMode(t *testing.T) {
+	SetMode(Dev)
+	defer SetMode(Prod)
+
+	ClearTemplateCache()
+
+	out, err := benchComponent().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<!-- htmx:begin component="page.html" templates="page.html" --><main><!-- htmx:begin component="list.html" templates="list.html" --><ul>`
+	if !strings.HasPrefix(string(out), expected) {
+		t.Errorf("unexpected output %s", out)
+	}
+
+	templateCache.Range(func(any, any) bool {
+		t.Fatal("expected the template cache to be disabled in dev mode")
+		return false
+	})
+}
+
+func TestDebugOverlay(t *testing.T) {
+	SetMode(Dev)
+	defer SetMode(Prod)
+
+	layout := NewComponent("debug-layout.html").FS(fstest.MapFS{
+		"debug-layout.html": {Data: []byte(`<html><body>{{ .Partials.Content }}</body></html>`)},
+	})
+	page := func() RenderableComponent { return benchComponent().Wrap(layout, "Content") }
+
+	w := httptest.NewRecorder()
+	if _, err := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/?htmx-debug", nil)).Render(context.Background(), page()); err != nil {
+		t.Fatal(err)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`<html data-htmx-component="debug-layout.html" data-htmx-templates="debug-layout.html" data-htmx-duration="`,
+		`<main data-htmx-component="page.html" data-htmx-templates="page.html" data-htmx-duration="`,
+		`data-htmx-cache="off"`,
+		`<script nonce="">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in %s", want, body)
+		}
+	}
+
+	if !strings.HasSuffix(body, "</script></body></html><!-- htmx:end component=\"debug-layout.html\" -->") {
+		t.Errorf("expected the overlay before the closing body tag, got %s", body[len(body)-80:])
+	}
+
+	w = httptest.NewRecorder()
+	if _, err := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/", nil)).Render(context.Background(), page()); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(w.Body.String(), "data-htmx-component") || strings.Contains(w.Body.String(), "<script") {
+		t.Errorf("expected no debug overlay without %s, got %s", DebugParam, w.Body.String())
+	}
+}
@@ -0,0 +1,113 @@
+package htmx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SwapMorph morphs the target element into the response with idiomorph, keeping focus and state of the elements
+	// that match by id. It requires the htmx morph extension.
+	// https://github.com/bigskysoftware/idiomorph
+	SwapMorph SwapStyle = "morph"
+
+	// SwapMorphOuterHTML morphs the target element into the response, like SwapMorph
+	SwapMorphOuterHTML SwapStyle = "morph:outerHTML"
+
+	// SwapMorphInnerHTML morphs the children of the target element into the response
+	SwapMorphInnerHTML SwapStyle = "morph:innerHTML"
+)
+
+// KeyedItem is an item of a keyed list with the stable id of its element
+type KeyedItem struct {
+	ID   string
+	Item any
+}
+
+// keyedList returns the items of the list with an id of the prefix and the key of the item, so idiomorph matches the
+// elements of the items between renders. The key is the field or map key of the items, or the item itself when no key
+// is given. Duplicate ids return an error, as idiomorph can't tell the elements apart.
+//
+//	<ul>{{ range keyed "user" .Data.Users "ID" }}<li id="{{ .ID }}">{{ .Item.Name }}</li>{{ end }}</ul>
+func keyedList(prefix string, list any, key ...string) ([]KeyedItem, error) {
+	v := reflect.ValueOf(list)
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("keyed: %T is not a list", list)
+	}
+
+	items := make([]KeyedItem, v.Len())
+	seen := make(map[string]bool, v.Len())
+
+	for i := range items {
+		item := v.Index(i)
+
+		k := item
+		if len(key) > 0 {
+			var err error
+			if k, err = keyOf(item, key[0]); err != nil {
+				return nil, err
+			}
+		}
+
+		id := keyedID(prefix, fmt.Sprint(k.Interface()))
+		if seen[id] {
+			return nil, fmt.Errorf("keyed: duplicate id %s", id)
+		}
+		seen[id] = true
+
+		items[i] = KeyedItem{ID: id, Item: item.Interface()}
+	}
+
+	return items, nil
+}
+
+// keyOf returns the field or map key of the item
+func keyOf(item reflect.Value, key string) (reflect.Value, error) {
+	for item.Kind() == reflect.Pointer || item.Kind() == reflect.Interface {
+		if item.IsNil() {
+			return reflect.Value{}, fmt.Errorf("keyed: nil item has no key %s", key)
+		}
+		item = item.Elem()
+	}
+
+	switch item.Kind() {
+	case reflect.Struct:
+		if field := item.FieldByName(key); field.IsValid() && field.CanInterface() {
+			return field, nil
+		}
+	case reflect.Map:
+		if item.Type().Key().Kind() == reflect.String {
+			if value := item.MapIndex(reflect.ValueOf(key).Convert(item.Type().Key())); value.IsValid() {
+				return value, nil
+			}
+		}
+	default:
+	}
+
+	return reflect.Value{}, fmt.Errorf("keyed: %s has no key %s", item.Type(), key)
+}
+
+// keyedID returns the id of the prefix and key, characters that aren't letters, digits or hyphens are replaced by
+// their code point between underscores, so different keys never share an id
+func keyedID(prefix, key string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteByte('-')
+
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			sb.WriteRune(r)
+		default:
+			sb.WriteString("_" + strconv.FormatInt(int64(r), 16) + "_")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestKeyedList(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	fsys := fstest.MapFS{
+		"keyed-users.html": {Data: []byte(`<ul>{{ range keyed "user" .Data.Users "ID" }}<li id="{{ .ID }}">{{ .Item.Name }}</li>{{ end }}</ul>`)},
+		"keyed-tags.html":  {Data: []byte(`{{ range keyed "tag" .Data.Tags }}<li id="{{ .ID }}"></li>{{ end }}`)},
+	}
+
+	output, err := NewComponent("keyed-users.html").FS(fsys).
+		AddData("Users", []user{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Grace"}}).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<ul><li id="user-1">Ada</li><li id="user-2">Grace</li></ul>`, string(output))
+
+	output, err = NewComponent("keyed-tags.html").FS(fsys).
+		AddData("Tags", []string{"go", "a b", "a_20_b"}).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<li id="tag-go"></li><li id="tag-a_20_b"></li><li id="tag-a_5f_20_5f_b"></li>`, string(output))
+
+	if _, err := keyedList("tag", []string{"go", "go"}); err == nil {
+		t.Error("expected an error for duplicate keys")
+	}
+
+	if _, err := keyedList("user", []user{{ID: 1}}, "Email"); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+
+	equal(t, "morph:innerHTML", NewSwap(SwapMorphInnerHTML).String())
+}
@@ -0,0 +1,92 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PrefersJSON returns true if the Accept header of the request prefers application/json over text/html. htmx requests
+// always prefer html, as do requests without an Accept header or with equal preferences, like */*.
+func PrefersJSON(r *http.Request) bool {
+	if IsHxRequest(r) {
+		return false
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return false
+	}
+
+	return acceptQuality(accept, "application/json") > acceptQuality(accept, "text/html")
+}
+
+// Negotiate renders the component as html, or marshals its data as JSON when the request prefers JSON, so the same
+// handler serves htmx clients and API consumers. The keys are written in the casing of JSONKeyCase.
+//
+//	c := htmx.NewComponent("user.html").AddData("User", user).Wrap(layout, "Content")
+//	_, err := h.Negotiate(r.Context(), c)
+//	// Accept: application/json → {"User":{"Name":"Ada"}}
+//
+// The response varies by the Accept and HX-Request headers.
+func (h *Handler) Negotiate(ctx context.Context, r RenderableComponent) (int, error) {
+	h.Vary("Accept", HxRequestHeaderRequest.String())
+
+	if !PrefersJSON(h.r) {
+		return h.Render(ctx, r)
+	}
+
+	if err := renderCanceled(ctx); err != nil {
+		return 0, h.renderError(err)
+	}
+
+	payload, err := marshalJSONKeys(r.data(), JSONKeyCase)
+	if err != nil {
+		return 0, err
+	}
+
+	h.w.Header().Set("Content-Type", "application/json")
+	return h.Write(payload)
+}
+
+// acceptQuality returns the quality value of the media type in the Accept header, the most specific matching range
+// decides: type/subtype before type/* before */*. Media types that don't match any range have a quality of 0.
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	specificity, quality := -1, 0.0
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+
+		var s int
+		switch mediaRange {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		specificity, quality = s, q
+	}
+
+	return quality
+}
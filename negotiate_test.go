@@ -0,0 +1,64 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPrefersJSON(t *testing.T) {
+	tests := []struct {
+		accept    string
+		hxRequest bool
+		expected  bool
+	}{
+		{"", false, false},
+		{"*/*", false, false},
+		{"application/json", false, true},
+		{"application/json", true, false},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", false, false},
+		{"application/json, text/html;q=0.9", false, true},
+		{"text/html;q=0.5, application/*", false, true},
+		{"application/json;q=0.1, */*", false, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+		r.Header.Set("Accept", tt.accept)
+		if tt.hxRequest {
+			r.Header.Set("HX-Request", "true")
+		}
+
+		equalBool(t, tt.expected, PrefersJSON(r))
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	fsys := fstest.MapFS{"negotiate-user.html": {Data: []byte(`<p>{{ .Data.Name }}</p>`)}}
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"text/html", "text/html; charset=utf-8", "<p>Ada</p>"},
+		{"application/json", "application/json", `{"Name":"Ada"}`},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+		r.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+
+		h := New().NewHandler(w, r)
+		if _, err := h.Negotiate(context.Background(), NewComponent("negotiate-user.html").FS(fsys).AddData("Name", "Ada")); err != nil {
+			t.Fatal(err)
+		}
+
+		equal(t, tt.body, w.Body.String())
+		equal(t, tt.contentType, w.Header().Get("Content-Type"))
+		equal(t, "Accept, HX-Request", w.Header().Get("Vary"))
+	}
+}
@@ -0,0 +1,187 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type (
+	// MultiFragment renders a primary fragment followed by out-of-band fragments, so a single response updates
+	// multiple targets. It is a RenderableComponent that can be rendered by the Handler, it is meant for htmx requests
+	// as full page loads render the out-of-band fragments in place.
+	//
+	//	h.Render(ctx, htmx.NewMultiFragment(row).
+	//		OOB("count", countComponent, nil).
+	//		OOB("log", logEntry, htmx.NewSwap(htmx.SwapBeforeEnd)))
+	MultiFragment struct {
+		RenderableComponent
+
+		oob     []oobFragment
+		oobOnly bool // the multi fragment has no primary fragment
+	}
+
+	// oobFragment is a component that is swapped out-of-band into the element with the id
+	oobFragment struct {
+		id        string
+		component RenderableComponent
+		swap      *Swap
+	}
+)
+
+// NewMultiFragment returns a multi fragment with the primary fragment, a nil primary renders the out-of-band fragments only
+func NewMultiFragment(primary RenderableComponent) *MultiFragment {
+	if primary == nil {
+		return &MultiFragment{RenderableComponent: NewComponent(), oobOnly: true}
+	}
+
+	return &MultiFragment{RenderableComponent: primary}
+}
+
+// OOB adds the component as an out-of-band swap into the element with the id, see RenderOOB
+func (m *MultiFragment) OOB(id string, c RenderableComponent, swap *Swap) *MultiFragment {
+	m.oob = append(m.oob, oobFragment{id: id, component: c, swap: swap})
+	return m
+}
+
+// Render renders the primary fragment followed by the out-of-band fragments
+func (m *MultiFragment) Render(ctx context.Context) (template.HTML, error) {
+	var sb strings.Builder
+
+	if !m.oobOnly {
+		output, err := m.RenderableComponent.Render(ctx)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(output))
+	}
+
+	for _, f := range m.oob {
+		output, err := f.component.Render(ctx)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(oobWrap(output, f.id, f.swap)))
+	}
+
+	//nolint:gosec // the fragments are rendered templates
+	return template.HTML(sb.String()), nil
+}
+
+// SetRequest sets the request of the primary and the out-of-band fragments
+func (m *MultiFragment) SetRequest(r *http.Request) {
+	m.RenderableComponent.SetRequest(r)
+	for _, f := range m.oob {
+		f.component.SetRequest(r)
+	}
+}
+
+// SetGlobalData sets the global data of the primary and the out-of-band fragments
+func (m *MultiFragment) SetGlobalData(input map[string]any) RenderableComponent {
+	m.RenderableComponent.SetGlobalData(input)
+	for _, f := range m.oob {
+		f.component.SetGlobalData(input)
+	}
+	return m
+}
+
+// AddGlobalData adds global data to the primary and the out-of-band fragments
+func (m *MultiFragment) AddGlobalData(key string, value any) RenderableComponent {
+	m.RenderableComponent.AddGlobalData(key, value)
+	for _, f := range m.oob {
+		f.component.AddGlobalData(key, value)
+	}
+	return m
+}
+
+// injectGlobalData injects the global data into the primary and the out-of-band fragments
+func (m *MultiFragment) injectGlobalData(input map[string]any) {
+	m.RenderableComponent.injectGlobalData(input)
+	for _, f := range m.oob {
+		f.component.injectGlobalData(input)
+	}
+}
+
+// RenderOOB renders the component as an out-of-band swap into the element with the id. A nil swap replaces the
+// element. Only the style of the swap is used, htmx doesn't support modifiers for out-of-band swaps.
+//
+// An outerHTML swap of a component with a single root element marks the root element, which receives the id when it
+// has none. Any other component is wrapped in a div.
+func (c *Component) RenderOOB(ctx context.Context, id string, swap *Swap) (template.HTML, error) {
+	output, err := c.Render(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oobWrap(output, id, swap), nil
+}
+
+// oobWrap turns the output into an out-of-band swap into the element with the id
+func oobWrap(output template.HTML, id string, swap *Swap) template.HTML {
+	style := SwapOuterHTML
+	if swap != nil {
+		style = swap.style
+	}
+
+	escaped := template.HTMLEscapeString(id)
+
+	if style == SwapOuterHTML {
+		if end, rootID, ok := singleRoot(string(output)); ok {
+			attrs := ` hx-swap-oob="outerHTML"`
+			if rootID == "" {
+				attrs = ` id="` + escaped + `"` + attrs
+			} else if rootID != id {
+				attrs = ` hx-swap-oob="outerHTML:#` + escaped + `"`
+			}
+
+			//nolint:gosec // the attributes are escaped
+			return template.HTML(string(output)[:end] + attrs + string(output)[end:])
+		}
+	}
+
+	//nolint:gosec // the output is a rendered template and the id is escaped
+	return template.HTML(`<div hx-swap-oob="` + string(style) + `:#` + escaped + `">` + string(output) + `</div>`)
+}
+
+// singleRoot returns the offset after the tag name of the root element and its id when the output consists of a
+// single element
+func singleRoot(output string) (end int, id string, ok bool) {
+	roots, depth, offset := 0, 0, 0
+
+	z := html.NewTokenizer(strings.NewReader(output))
+	for {
+		tt := z.Next()
+		raw := len(z.Raw())
+		if tt == html.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			if depth == 0 {
+				roots++
+				end, id = offset+1+len(token.Data), tokenAttr(token, "id")
+			}
+
+			if tt == html.StartTagToken && !voidElements[token.Data] {
+				depth++
+			}
+		case html.EndTagToken:
+			if depth > 0 {
+				depth--
+			}
+		case html.TextToken:
+			if depth == 0 && strings.TrimSpace(string(z.Text())) != "" {
+				return 0, "", false
+			}
+		}
+
+		offset += raw
+	}
+
+	return end, id, roots == 1
+}
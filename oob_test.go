@@ -0,0 +1,88 @@
+package htmx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRenderOOB(t *testing.T) {
+	fsys := fstest.MapFS{
+		"count.html": {Data: []byte(`<span class="count">{{ .Data.Count }}</span>`)},
+		"named.html": {Data: []byte(`<span id="total">3</span>`)},
+		"row.html":   {Data: []byte(`<li>{{ .Data.Name }}</li>`)},
+		"text.html":  {Data: []byte(`3 items`)},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		swap     *Swap
+		expected string
+	}{
+		{"root gets the id", "count.html", nil, `<span id="count" hx-swap-oob="outerHTML" class="count">3</span>`},
+		{"root with another id", "named.html", nil, `<span hx-swap-oob="outerHTML:#count" id="total">3</span>`},
+		{"text is wrapped", "text.html", nil, `<div hx-swap-oob="outerHTML:#count">3 items</div>`},
+		{"styles are wrapped", "row.html", NewSwap(SwapBeforeEnd).Transition(true), `<div hx-swap-oob="beforeend:#count"><li>Ada</li></div>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewComponent(tt.template).FS(fsys)
+			c.AddData("Count", 3)
+			c.AddData("Name", "Ada")
+
+			output, err := c.RenderOOB(context.Background(), "count", tt.swap)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, string(output))
+		})
+	}
+}
+
+func TestMultiFragment(t *testing.T) {
+	fsys := fstest.MapFS{
+		"row.html":   {Data: []byte(`<li>{{ .Data.Name }}</li>`)},
+		"count.html": {Data: []byte(`<span>{{ .Data.Count }} {{ .Global.Unit }}</span>`)},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/items", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	h := New().NewHandler(w, r)
+
+	multi := NewMultiFragment(NewComponent("row.html").FS(fsys).AddData("Name", "Ada")).
+		OOB("count", NewComponent("count.html").FS(fsys).AddData("Count", 4), nil)
+	multi.AddGlobalData("Unit", "items")
+
+	if _, err := h.Render(context.Background(), multi); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<li>Ada</li><span id="count" hx-swap-oob="outerHTML">4 items</span>`, w.Body.String())
+
+	output, err := NewMultiFragment(nil).
+		OOB("count", NewComponent("count.html").FS(fsys).AddData("Count", 5), NewSwap(SwapInnerHTML)).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-swap-oob="innerHTML:#count"><span>5 </span></div>`, string(output))
+
+	// primaries without templates are rendered
+	output, err = NewMultiFragment(NewWriterComponent(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<li>written</li>")
+		return err
+	})).OOB("count", NewComponent("count.html").FS(fsys).AddData("Count", 6), nil).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<li>written</li><span id="count" hx-swap-oob="outerHTML">6 </span>`, string(output))
+}
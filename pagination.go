@@ -0,0 +1,230 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+//go:embed templates/pagination.html
+var paginationTemplates embed.FS
+
+var (
+	// PageParam is the query parameter of the page
+	PageParam = "page"
+
+	// PerPageParam is the query parameter of the number of items per page
+	PerPageParam = "per_page"
+
+	// DefaultPerPage is the number of items per page when the request doesn't ask for one
+	DefaultPerPage = 20
+
+	// MaxPerPage is the largest number of items per page a request can ask for
+	MaxPerPage = 100
+
+	// DefaultPageWindow is the number of pages linked on each side of the current page
+	DefaultPageWindow = 2
+)
+
+type (
+	// Paginator splits a list of Total items into pages of PerPage items. Its links keep the path and query of URL,
+	// with the page parameter replaced.
+	//
+	//	p := htmx.PaginatorFromRequest(r, a.users.Count())
+	//	users := a.users.List(p.Offset(), p.PerPage)
+	Paginator struct {
+		Page    int
+		PerPage int
+		Total   int
+		Window  int
+		URL     *url.URL
+	}
+
+	// PageLink is a link of the pagination component, a gap stands for the pages that were left out
+	PageLink struct {
+		Page       int
+		Current    bool
+		Gap        bool
+		Attributes template.HTMLAttr
+	}
+)
+
+// NewPaginator returns a paginator for the page, pages out of range are clamped to the first and last page
+func NewPaginator(page, perPage, total int) *Paginator {
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+
+	p := &Paginator{PerPage: perPage, Total: max(total, 0), Window: DefaultPageWindow, URL: &url.URL{}}
+	p.Page = min(max(page, 1), p.Pages())
+
+	return p
+}
+
+// PaginatorFromRequest returns a paginator for the page and per page query parameters of the request, its links keep
+// the other query parameters, e.g. the filters of the list
+func PaginatorFromRequest(r *http.Request, total int) *Paginator {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get(PageParam))
+	perPage, _ := strconv.Atoi(query.Get(PerPageParam))
+
+	p := NewPaginator(page, min(perPage, MaxPerPage), total)
+	p.URL = r.URL
+	return p
+}
+
+// Pages returns the number of pages, a list without items has one empty page
+func (p *Paginator) Pages() int {
+	return max((p.Total+p.PerPage-1)/p.PerPage, 1)
+}
+
+// Offset returns the index of the first item of the page
+func (p *Paginator) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// First returns the number of the first item of the page, counting from 1, or 0 without items
+func (p *Paginator) First() int {
+	if p.Total == 0 {
+		return 0
+	}
+
+	return p.Offset() + 1
+}
+
+// Last returns the number of the last item of the page
+func (p *Paginator) Last() int {
+	return min(p.Offset()+p.PerPage, p.Total)
+}
+
+// HasPrev returns true when there is a page before the current page
+func (p *Paginator) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true when there is a page after the current page
+func (p *Paginator) HasNext() bool {
+	return p.Page < p.Pages()
+}
+
+// Prev returns the page before the current page
+func (p *Paginator) Prev() int {
+	return max(p.Page-1, 1)
+}
+
+// Next returns the page after the current page
+func (p *Paginator) Next() int {
+	return min(p.Page+1, p.Pages())
+}
+
+// Range returns the pages to link: the first and last page and the window around the current page, 0 marks a gap
+func (p *Paginator) Range() []int {
+	return pageRange(p.Page, p.Pages(), p.Window)
+}
+
+// PageURL returns the url of the page, with the query parameters of URL
+func (p *Paginator) PageURL(page int) string {
+	return pageURL(p.URL, page)
+}
+
+// NewPaginationComponent returns a component that renders the links to the pages of the paginator. The links get the
+// page with hx-get into the target and push its url, so the browser history and reloads keep the page. The labels
+// can be changed with AddData: Label, PrevLabel and NextLabel.
+//
+//	page.With(htmx.NewPaginationComponent(p, "#users"), "pagination")
+func NewPaginationComponent(p *Paginator, target string) *Component {
+	attributes := func(page int) template.HTMLAttr {
+		u := p.PageURL(page)
+		a := NewAttributes().Set("href", u).Set("hx-get", u)
+		if target != "" {
+			a.Set("hx-target", target)
+		}
+
+		return a.Set("hx-push-url", "true").HTMLAttr()
+	}
+
+	var links []PageLink
+	for _, page := range p.Range() {
+		if page == 0 {
+			links = append(links, PageLink{Gap: true})
+			continue
+		}
+
+		links = append(links, PageLink{Page: page, Current: page == p.Page, Attributes: attributes(page)})
+	}
+
+	c := NewComponent("templates/pagination.html").FS(paginationTemplates)
+	c.AddData("Links", links)
+	c.AddData("Label", "Pagination")
+	c.AddData("PrevLabel", "Previous")
+	c.AddData("NextLabel", "Next")
+
+	if p.HasPrev() {
+		c.AddData("Prev", PageLink{Page: p.Prev(), Attributes: attributes(p.Prev())})
+	}
+	if p.HasNext() {
+		c.AddData("Next", PageLink{Page: p.Next(), Attributes: attributes(p.Next())})
+	}
+
+	return c
+}
+
+// pageRange returns the first and last page and the pages of the window around the current page, 0 marks a gap.
+// A gap of a single page is filled with that page.
+//
+//	{{ range pageRange 5 10 }}{{ if eq . 0 }}…{{ else }}{{ . }}{{ end }}{{ end }}
+func pageRange(current, pages int, window ...int) []int {
+	w := DefaultPageWindow
+	if len(window) > 0 {
+		w = max(window[0], 0)
+	}
+
+	var r []int
+	for page := 1; page <= pages; page++ {
+		switch {
+		case page == 1 || page == pages || page >= current-w && page <= current+w:
+			r = append(r, page)
+		case page == 2 && current-w == 3 || page == pages-1 && current+w == pages-2:
+			r = append(r, page)
+		case r[len(r)-1] != 0:
+			r = append(r, 0)
+		}
+	}
+
+	return r
+}
+
+// pageURL returns the url with the page parameter set to the page
+func pageURL(u *url.URL, page int) string {
+	query := u.Query()
+	query.Set(PageParam, strconv.Itoa(page))
+
+	return (&url.URL{Path: u.Path, RawQuery: query.Encode()}).String()
+}
+
+// pageURLFunc is the pageURL template function, it returns the url of the component with the page parameter set
+//
+//	<a href="{{ pageURL 2 }}">2</a>
+func pageURLFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("pageURL requires a page")
+	}
+
+	page, ok := args[0].(int)
+	if !ok {
+		return nil, fmt.Errorf("pageURL: page %v is not an int", args[0])
+	}
+
+	u, ok := renderURL(ctx)
+	if !ok {
+		u = &url.URL{}
+	}
+
+	return pageURL(u, page), nil
+}
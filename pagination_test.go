@@ -0,0 +1,79 @@
+package htmx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPaginator(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?q=ann&page=3&per_page=10", nil)
+
+	p := PaginatorFromRequest(r, 95)
+
+	equalInt(t, 3, p.Page)
+	equalInt(t, 10, p.Pages())
+	equalInt(t, 20, p.Offset())
+	equalInt(t, 21, p.First())
+	equalInt(t, 30, p.Last())
+	equal(t, "/users?page=4&per_page=10&q=ann", p.PageURL(p.Next()))
+
+	// out of range pages are clamped
+	equalInt(t, 10, NewPaginator(42, 10, 95).Page)
+	equalInt(t, 1, NewPaginator(-1, 0, 0).Page)
+	equalInt(t, 0, NewPaginator(1, 10, 0).First())
+}
+
+func TestPageRange(t *testing.T) {
+	tests := []struct {
+		current, pages int
+		expected       string
+	}{
+		{1, 1, "[1]"},
+		{1, 10, "[1 2 3 0 10]"},
+		{4, 10, "[1 2 3 4 5 6 0 10]"},
+		{6, 12, "[1 0 4 5 6 7 8 0 12]"},
+		{10, 10, "[1 0 8 9 10]"},
+	}
+
+	for _, tt := range tests {
+		equal(t, tt.expected, fmt.Sprint(pageRange(tt.current, tt.pages)))
+	}
+}
+
+func TestPaginationComponent(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?q=ann&page=2", nil)
+
+	output, err := NewPaginationComponent(PaginatorFromRequest(r, 45), "#users").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	link := func(page int) string {
+		u := fmt.Sprintf("/users?page=%d&amp;q=ann", page)
+		return fmt.Sprintf(`href="%s" hx-get="%s" hx-target="#users" hx-push-url="true"`, u, u)
+	}
+
+	equal(t, `<nav class="pagination" aria-label="Pagination">`+
+		`<a rel="prev" `+link(1)+`>Previous</a>`+
+		`<a `+link(1)+`>1</a><span aria-current="page">2</span><a `+link(3)+`>3</a>`+
+		`<a rel="next" `+link(3)+`>Next</a></nav>`, string(output))
+}
+
+func TestPageURLFunc(t *testing.T) {
+	fsys := fstest.MapFS{"page-url.html": {Data: []byte(`<a href="{{ pageURL 2 }}">{{ range pageRange 2 3 }}{{ . }}{{ end }}</a>`)}}
+
+	r := httptest.NewRequest(http.MethodGet, "/users?q=ann", nil)
+	c := NewComponent("page-url.html").FS(fsys)
+	c.SetRequest(r)
+
+	output, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<a href="/users?page=2&amp;q=ann">123</a>`, string(output))
+}
@@ -0,0 +1,41 @@
+package htmx
+
+import (
+	"embed"
+	"net/http"
+	"time"
+)
+
+//go:embed templates/polling.html
+var pollingTemplates embed.FS
+
+// WriteStopPolling writes the status 286, which stops the polling of the element that issued the request. The body
+// that is written afterwards is still swapped. Use Handler.StopPolling when the response is rendered by the Handler.
+func WriteStopPolling(w http.ResponseWriter) {
+	w.WriteHeader(StatusStopPolling)
+}
+
+// NewPollingComponent returns a component that renders the content in an element that requests the url every
+// interval and is replaced by the response. The handler of the url responds with the polling component as long as
+// the polling continues, and stops it with WriteStopPolling and the final content.
+//
+//	func (a *App) Export(w http.ResponseWriter, r *http.Request) {
+//		h := a.htmx.NewHandler(w, r)
+//		if job := a.jobs.Get(r.PathValue("id")); !job.Done() {
+//			_, _ = h.Render(r.Context(), htmx.NewPollingComponent(r.URL.Path, 2*time.Second, progress(job)))
+//			return
+//		}
+//		h.StopPolling()
+//		_, _ = h.Render(r.Context(), finished(job))
+//	}
+func NewPollingComponent(url string, interval time.Duration, content RenderableComponent) *Component {
+	c := NewComponent("templates/polling.html").FS(pollingTemplates)
+	c.AddData("Attributes", NewAttributes().
+		Set("hx-get", url).
+		Trigger(NewPollingSpec(interval)).
+		Swap(NewSwap(SwapOuterHTML)).
+		HTMLAttr())
+	c.With(content, "content")
+
+	return c
+}
@@ -0,0 +1,80 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestPollingComponent(t *testing.T) {
+	fsys := fstest.MapFS{"progress.html": {Data: []byte(`<progress value="{{ .Data.Done }}" max="100"></progress>`)}}
+
+	output, err := NewPollingComponent("/jobs/7", 2*time.Second, NewComponent("progress.html").FS(fsys).AddData("Done", 40)).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-get="/jobs/7" hx-trigger="every 2s" hx-swap="outerHTML"><progress value="40" max="100"></progress></div>`, string(output))
+}
+
+func TestWriteStopPolling(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteStopPolling(w)
+	if w.Code != StatusStopPolling {
+		t.Errorf("expected %d, got %d", StatusStopPolling, w.Code)
+	}
+
+	// the status is written right away, the headers set before it are sent and the content is swapped
+	UseCompression, CompressionMinSize = true, 0
+	defer func() { UseCompression, CompressionMinSize = false, 1024 }()
+
+	r := httptest.NewRequest(http.MethodGet, "/jobs/7", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	r.Header.Set("HX-Request", "true")
+	w = httptest.NewRecorder()
+
+	h := New().NewHandler(w, r)
+	h.Trigger("done")
+	h.StopPolling()
+	fsys := fstest.MapFS{"done.html": {Data: []byte(`done`)}}
+	if _, err := h.Render(context.Background(), NewComponent("done.html").FS(fsys)); err != nil {
+		t.Fatal(err)
+	}
+
+	equalInt(t, StatusStopPolling, w.Code)
+	equal(t, "done", w.Header().Get("HX-Trigger"))
+	equal(t, "", w.Header().Get("Content-Encoding"))
+	equal(t, "done", w.Body.String())
+}
+
+func TestHandlerStatusWithoutBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		fn     func(h *Handler, r *http.Request)
+		status int
+	}{
+		{"stop polling", http.MethodGet, func(h *Handler, _ *http.Request) { h.StopPolling() }, StatusStopPolling},
+		{"no content", http.MethodDelete, func(h *Handler, _ *http.Request) { h.WriteHeader(http.StatusNoContent) }, http.StatusNoContent},
+		{"not found", http.MethodGet, func(h *Handler, _ *http.Request) { h.WriteHeader(http.StatusNotFound) }, http.StatusNotFound},
+		{"redirect", http.MethodPost, func(h *Handler, r *http.Request) { Redirect(h, r, "/items") }, http.StatusSeeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/items/7", nil)
+			w := httptest.NewRecorder()
+
+			tt.fn(New().NewHandler(w, r), r)
+
+			equalInt(t, tt.status, w.Code)
+			if tt.name == "redirect" {
+				equal(t, "/items", w.Header().Get("Location"))
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DefaultPostProcessor post-processes the output of components that don't have their own post-processor,
+// nil disables post-processing. It runs once on the outermost render, the page of a Handler render with its layouts.
+var DefaultPostProcessor *PostProcessor
+
+// outerRenderKey marks the context of renders within an outer render
+type outerRenderKey struct{}
+
+// urlAttributes are the attributes holding a url that are rewritten by RewriteURLs
+var urlAttributes = []string{"href", "src", "action", "formaction", "poster", "hx-get", "hx-post", "hx-put", "hx-patch", "hx-delete"}
+
+type (
+	// Transform transforms an element of the rendered output, it is called once for every element in document order
+	Transform func(n *html.Node)
+
+	// PostProcessor parses the rendered output once and runs its transforms on every element in a single pass
+	PostProcessor struct {
+		transforms []Transform
+	}
+
+	// AssetRefs are the assets referenced by the rendered output, collected by CollectAssets
+	AssetRefs struct {
+		Scripts     []string
+		Stylesheets []string
+		Images      []string
+	}
+)
+
+// NewPostProcessor returns a post-processor running the transforms in the given order
+func NewPostProcessor(transforms ...Transform) *PostProcessor {
+	return &PostProcessor{transforms: transforms}
+}
+
+// Process parses the output, runs the transforms and renders the result. Full documents are parsed as a document,
+// everything else as a fragment. The output is re-serialized, so it is normalized by the html renderer.
+func (p *PostProcessor) Process(output template.HTML) (template.HTML, error) {
+	if p == nil || len(p.transforms) == 0 || output == "" {
+		return output, nil
+	}
+
+	nodes, err := parseOutput(string(output))
+	if err != nil {
+		return "", err
+	}
+
+	for _, n := range nodes {
+		p.walk(n)
+	}
+
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		if err := html.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+
+	//nolint:gosec // the output was rendered by html/template
+	return template.HTML(buf.String()), nil
+}
+
+// walk runs the transforms on the element and its descendants
+func (p *PostProcessor) walk(n *html.Node) {
+	if n.Type == html.ElementNode {
+		for _, transform := range p.transforms {
+			transform(n)
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		p.walk(child)
+	}
+}
+
+// PostProcess sets the post-processor of the component, DefaultPostProcessor is used when none is set
+func (c *Component) PostProcess(p *PostProcessor) *Component {
+	c.postProcessor = p
+	return c
+}
+
+// outputPostProcessor returns the post-processor of the component, or DefaultPostProcessor for the outermost render.
+// The output of partials and layouts is part of the output of the outermost render, which is processed in one pass.
+func (c *Component) outputPostProcessor(outermost bool) *PostProcessor {
+	if c.postProcessor != nil || !outermost {
+		return c.postProcessor
+	}
+
+	return DefaultPostProcessor
+}
+
+// postProcessPage runs DefaultPostProcessor on the output of a page, unless the component doesn't render html
+func postProcessPage(r RenderableComponent, output template.HTML) (template.HTML, error) {
+	if c, ok := r.(*Component); DefaultPostProcessor == nil || ok && c.contentType != "" {
+		return output, nil
+	}
+
+	return DefaultPostProcessor.Process(output)
+}
+
+// withOuterRender marks the renders of the context as nested, DefaultPostProcessor processes the outer output once
+func withOuterRender(ctx context.Context) context.Context {
+	return context.WithValue(ctx, outerRenderKey{}, true)
+}
+
+// withinRender returns true when the context belongs to a render whose output is post-processed as a whole
+func withinRender(ctx context.Context) bool {
+	within, _ := ctx.Value(outerRenderKey{}).(bool)
+	return within
+}
+
+// detachRender returns the context for renders whose output is written on its own, like streamed partials
+func detachRender(ctx context.Context) context.Context {
+	return context.WithValue(ctx, outerRenderKey{}, false)
+}
+
+// AddMissingIDs gives the elements that issue htmx requests, or the elements with one of the given tags,
+// an id when they don't have one. The ids are the prefix followed by a counter that is shared by all renders.
+func AddMissingIDs(prefix string, tags ...string) Transform {
+	var count atomic.Uint64
+
+	return func(n *html.Node) {
+		if _, ok := getAttr(n, "id"); ok {
+			return
+		}
+
+		if len(tags) > 0 && !slices.Contains(tags, n.Data) {
+			return
+		}
+
+		if len(tags) == 0 && !slices.ContainsFunc(requestAttributes, func(attr string) bool {
+			_, ok := getAttr(n, attr)
+			return ok
+		}) {
+			return
+		}
+
+		setAttr(n, "id", fmt.Sprintf("%s%d", prefix, count.Add(1)))
+	}
+}
+
+// RewriteURLs resolves the relative urls of links, sources, forms and htmx requests against the base url
+func RewriteURLs(base *url.URL) Transform {
+	return func(n *html.Node) {
+		for i, attr := range n.Attr {
+			if attr.Namespace != "" || !slices.Contains(urlAttributes, attr.Key) {
+				continue
+			}
+
+			value := strings.TrimSpace(attr.Val)
+			if value == "" || strings.HasPrefix(value, "#") {
+				continue
+			}
+
+			ref, err := url.Parse(value)
+			if err != nil || ref.IsAbs() {
+				continue
+			}
+
+			n.Attr[i].Val = base.ResolveReference(ref).String()
+		}
+	}
+}
+
+// LazyLoad adds loading="lazy" to images and iframes that don't set the loading attribute
+func LazyLoad() Transform {
+	return func(n *html.Node) {
+		if n.DataAtom != atom.Img && n.DataAtom != atom.Iframe {
+			return
+		}
+
+		if _, ok := getAttr(n, "loading"); !ok {
+			setAttr(n, "loading", "lazy")
+		}
+	}
+}
+
+// CollectAssets adds the scripts, stylesheets and images referenced by the output to refs. The refs are written
+// during Process, so a post-processor collecting assets belongs to a single render.
+func CollectAssets(refs *AssetRefs) Transform {
+	return func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Script:
+			if src, ok := getAttr(n, "src"); ok {
+				refs.Scripts = appendUnique(refs.Scripts, src)
+			}
+		case atom.Link:
+			rel, _ := getAttr(n, "rel")
+			if href, ok := getAttr(n, "href"); ok && slices.Contains(strings.Fields(strings.ToLower(rel)), "stylesheet") {
+				refs.Stylesheets = appendUnique(refs.Stylesheets, href)
+			}
+		case atom.Img:
+			if src, ok := getAttr(n, "src"); ok {
+				refs.Images = appendUnique(refs.Images, src)
+			}
+		}
+	}
+}
+
+// parseOutput parses a full document as a document and everything else as a fragment
+func parseOutput(output string) ([]*html.Node, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(output))
+	if strings.HasPrefix(trimmed, "<!doctype") || strings.HasPrefix(trimmed, "<html") {
+		doc, err := html.Parse(strings.NewReader(output))
+		if err != nil {
+			return nil, err
+		}
+		return []*html.Node{doc}, nil
+	}
+
+	return html.ParseFragment(strings.NewReader(output), fragmentContext(output))
+}
+
+// fragmentContext returns the element the fragment is parsed in, so table rows and cells are not dropped by the parser
+func fragmentContext(output string) *html.Node {
+	tag := atom.Body
+
+	z := html.NewTokenizer(strings.NewReader(output))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Tr:
+				tag = atom.Tbody
+			case atom.Td, atom.Th:
+				tag = atom.Tr
+			case atom.Thead, atom.Tbody, atom.Tfoot, atom.Caption, atom.Colgroup:
+				tag = atom.Table
+			case atom.Col:
+				tag = atom.Colgroup
+			}
+			break
+		}
+	}
+
+	return &html.Node{Type: html.ElementNode, Data: tag.String(), DataAtom: tag}
+}
+
+// getAttr returns the value of the attribute of the element
+func getAttr(n *html.Node, key string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+// setAttr sets the attribute of the element
+func setAttr(n *html.Node, key, value string) {
+	for i, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+// appendUnique appends the value when the slice doesn't contain it
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
@@ -0,0 +1,122 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestPostProcessor(t *testing.T) {
+	base, _ := url.Parse("https://example.com/app/")
+
+	var refs AssetRefs
+	p := NewPostProcessor(
+		AddMissingIDs("auto-"),
+		RewriteURLs(base),
+		LazyLoad(),
+		CollectAssets(&refs),
+	)
+
+	out, err := p.Process(`<div hx-get="items"><img src="a.png"><img src="/b.png" loading="eager"><a href="#top" id="top">top</a></div><link rel="stylesheet" href="app.css">`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div hx-get="https://example.com/app/items" id="auto-1"><img src="https://example.com/app/a.png" loading="lazy"/><img src="https://example.com/b.png" loading="eager"/><a href="#top" id="top">top</a></div><link rel="stylesheet" href="https://example.com/app/app.css"/>`, string(out))
+	if !slices.Equal(refs.Images, []string{"https://example.com/app/a.png", "https://example.com/b.png"}) {
+		t.Errorf("unexpected images %v", refs.Images)
+	}
+	if !slices.Equal(refs.Stylesheets, []string{"https://example.com/app/app.css"}) {
+		t.Errorf("unexpected stylesheets %v", refs.Stylesheets)
+	}
+
+	// table rows are parsed in the context of a table
+	out, err = NewPostProcessor(AddMissingIDs("row-", "tr")).Process(`<tr><td>1</td></tr><tr id="x"><td>2</td></tr>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<tr id="row-1"><td>1</td></tr><tr id="x"><td>2</td></tr>`, string(out))
+
+	// full documents keep their html, head and body
+	out, err = NewPostProcessor(LazyLoad()).Process(`<!DOCTYPE html><html><head></head><body><iframe src="/x"></iframe></body></html>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<!DOCTYPE html><html><head></head><body><iframe src="/x" loading="lazy"></iframe></body></html>`, string(out))
+}
+
+func TestComponentPostProcess(t *testing.T) {
+	fsys := fstest.MapFS{"img.html": {Data: []byte(`<img src="{{ .Data.Src }}">`)}}
+
+	out, err := NewComponent("img.html").FS(fsys).PostProcess(NewPostProcessor(LazyLoad())).
+		AddData("Src", "/a.png").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<img src="/a.png" loading="lazy"/>`, string(out))
+}
+
+func TestDefaultPostProcessorOnce(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pp-layout.html": {Data: []byte(`<main>{{ .Partials.content }}</main>`)},
+		"pp-page.html":   {Data: []byte(`<section>{{ .Partials.image }}</section>`)},
+		"pp-image.html":  {Data: []byte(`<img src="/a.png">`)},
+	}
+
+	var images int
+	DefaultPostProcessor = NewPostProcessor(func(n *html.Node) {
+		if n.DataAtom == atom.Img {
+			images++
+		}
+	}, LazyLoad())
+	t.Cleanup(func() { DefaultPostProcessor = nil })
+
+	page := func() RenderableComponent {
+		return NewComponent("pp-page.html").FS(fsys).AutoWrap(NewComponent("pp-layout.html").FS(fsys), "content").
+			With(NewComponent("pp-image.html").FS(fsys), "image")
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"full page load", nil, `<main><section><img src="/a.png" loading="lazy"/></section></main>`},
+		{"htmx request", map[string]string{"HX-Request": "true"}, `<section><img src="/a.png" loading="lazy"/></section>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images = 0
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			if _, err := New().NewHandler(w, r).Render(context.Background(), page()); err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, w.Body.String())
+			equalInt(t, 1, images)
+		})
+	}
+
+	// components rendered on their own are processed with their partials
+	images = 0
+	out, err := page().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<section><img src="/a.png" loading="lazy"/></section>`, string(out))
+	equalInt(t, 1, images)
+}
@@ -0,0 +1,64 @@
+package htmx
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// preloader is implemented by the components that may be preloaded by the htmx preload extension
+type preloader interface {
+	preloadMaxAge() time.Duration
+}
+
+// Preload marks the component as safe to preload: GET requests that render it are answered with a private
+// Cache-Control header of the max age, so the browser reuses the preloaded response when the link is followed.
+// Only mark components that have no side effects and can be stale for the max age.
+//
+//	h.Render(ctx, htmx.NewComponent("templates/user.html").Preload(30 * time.Second))
+func (c *Component) Preload(maxAge time.Duration) *Component {
+	c.preload = maxAge
+	return c
+}
+
+// preloadMaxAge returns the max age set by Preload
+func (c *Component) preloadMaxAge() time.Duration {
+	return c.preload
+}
+
+// preloadMaxAge returns the max age of the primary fragment
+func (m *MultiFragment) preloadMaxAge() time.Duration {
+	if p, ok := m.RenderableComponent.(preloader); ok {
+		return p.preloadMaxAge()
+	}
+
+	return 0
+}
+
+// setPreloadCache sets the Cache-Control header of preloadable components, unless the handler set one already
+func (h *Handler) setPreloadCache(r RenderableComponent) {
+	p, ok := r.(preloader)
+	if !ok || p.preloadMaxAge() <= 0 {
+		return
+	}
+
+	if h.r.Method != http.MethodGet && h.r.Method != http.MethodHead {
+		return
+	}
+
+	if h.w.Header().Get("Cache-Control") != "" {
+		return
+	}
+
+	h.w.Header().Set("Cache-Control", "private, max-age="+strconv.FormatInt(int64(p.preloadMaxAge()/time.Second), 10))
+}
+
+// preloadAttr returns the preload attribute of the htmx preload extension, which preloads the link or hx-get of the
+// element on the event: mousedown, mouseover, init or a custom event
+//
+//	<a href="/users/1" {{ preload "mouseover" }}>Ada</a>
+//	<button {{ hxGet "/users/1" | preload "mousedown" }}>Ada</button>
+func preloadAttr(event string, prev ...template.HTMLAttr) template.HTMLAttr {
+	return appendAttr(prev, "preload", event)
+}
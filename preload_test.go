@@ -0,0 +1,45 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestPreload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"preload-user.html": {Data: []byte(`<a href="/users/1" {{ preload "mouseover" }}>Ada</a><button {{ hxGet "/users/1" | preload "init" }}></button>`)},
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		expected string
+	}{
+		{"get", http.MethodGet, "", "private, max-age=30"},
+		{"post", http.MethodPost, "", ""},
+		{"cache control of the handler", http.MethodGet, "no-store", "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if tt.header != "" {
+				w.Header().Set("Cache-Control", tt.header)
+			}
+
+			r := httptest.NewRequest(tt.method, "/users/1", nil)
+			c := NewComponent("preload-user.html").FS(fsys).Preload(30 * time.Second)
+			if _, err := New().NewHandler(w, r).Render(context.Background(), c); err != nil {
+				t.Fatal(err)
+			}
+
+			equal(t, tt.expected, w.Header().Get("Cache-Control"))
+			equal(t, `<a href="/users/1" preload="mouseover">Ada</a><button hx-get="/users/1" preload="init"></button>`, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package htmx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// progressive is a partial that renders in the background while the page is flushed, see Progressive
+	progressive struct {
+		RenderableComponent
+
+		placeholder template.HTML
+	}
+
+	// progressiveStream collects the progressive partials of a RenderProgressive render
+	progressiveStream struct {
+		ctx     context.Context
+		prefix  string // the prefix of the placeholder ids, unique per response as placeholders stay in the DOM
+		results chan progressiveResult
+
+		mu      sync.Mutex
+		started int
+	}
+
+	// progressiveResult is the output of a progressive partial and the id of its placeholder
+	progressiveResult struct {
+		id     string
+		output template.HTML
+	}
+
+	progressiveStreamKey struct{}
+)
+
+// Progressive marks the component as progressive: rendered by Handler.RenderProgressive, the parent renders the
+// placeholder and the component renders in the background while the page is flushed to the client. Its output is
+// streamed into the placeholder once it was rendered. A failing render is replaced by DefaultErrorFallback and
+// reported to OnPartialError. Rendered any other way, the component is rendered in place of the placeholder.
+//
+//	page.With(htmx.Progressive(htmx.NewComponent("revenue.html"), "<p>Loading…</p>"), "Revenue")
+func Progressive(c RenderableComponent, placeholder template.HTML) RenderableComponent {
+	return &progressive{
+		RenderableComponent: c,
+		placeholder:         placeholder,
+	}
+}
+
+// Render starts rendering the partial in the background and returns the placeholder, or renders the partial when the
+// render isn't progressive
+func (p *progressive) Render(ctx context.Context) (template.HTML, error) {
+	stream, _ := ctx.Value(progressiveStreamKey{}).(*progressiveStream)
+	if stream == nil {
+		return p.RenderableComponent.Render(ctx)
+	}
+
+	if err := renderCanceled(ctx); err != nil {
+		return "", err
+	}
+
+	id := stream.start(ctx, p.RenderableComponent)
+
+	//nolint:gosec // the id is generated
+	return template.HTML(`<div id="`+id+`">`) + p.placeholder + "</div>", nil
+}
+
+// RenderProgressive renders the component like Render, but flushes the page to the client before its Progressive
+// partials were rendered, which cuts the time to first byte of heavy pages. Full pages are flushed up to </body>, the
+// partials are streamed in as they finish and the end of the page is written last. htmx requests receive the partials
+// as out-of-band swaps at the end of the response.
+//
+//	h.RenderProgressive(ctx, dashboard.With(htmx.Progressive(revenue, "<p>Loading…</p>"), "Revenue"))
+//
+// The response isn't compressed, as compression buffers it. The partials render after the response headers were
+// written, they can't set headers, toasts or head elements. Progressive partials within a progressive partial render
+// in place. Partials that take longer should be deferred with a DeferredStream instead, which pushes them after the
+// response is complete.
+func (h *Handler) RenderProgressive(ctx context.Context, r RenderableComponent) (int, error) {
+	ctx, cancel := h.abortContext(h.renderContext(ctx))
+	defer cancel()
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return 0, err
+	}
+
+	stream := &progressiveStream{ctx: ctx, prefix: "htmx-progressive-" + hex.EncodeToString(b) + "-",
+		results: make(chan progressiveResult)}
+	output, fragment, err := h.renderPage(context.WithValue(ctx, progressiveStreamKey{}, stream), r)
+	if err != nil {
+		return 0, err
+	}
+
+	h.setFragmentManifest(output)
+	h.setPreloadCache(r)
+
+	page, end := output, template.HTML("")
+	if !fragment {
+		if i := strings.LastIndex(string(output), "</body>"); i >= 0 {
+			page, end = output[:i], output[i:]
+		}
+	}
+
+	written, err := h.Write(htmlBytes(page))
+	if err != nil {
+		return written, err
+	}
+	flush := http.NewResponseController(h.w).Flush
+	_ = flush()
+
+	nonce := Nonce(ctx)
+	for done := 0; done < stream.count(); done++ {
+		var result progressiveResult
+		select {
+		case result = <-stream.results:
+		case <-ctx.Done():
+			return written, h.renderError(context.Cause(ctx))
+		}
+
+		var chunk template.HTML
+		if fragment {
+			chunk = oobWrap(result.output, result.id, NewSwap(SwapInnerHTML))
+		} else {
+			chunk = progressiveChunk(result, nonce)
+		}
+
+		n, err := h.Write(htmlBytes(chunk))
+		written += n
+		if err != nil {
+			return written, err
+		}
+		_ = flush()
+	}
+
+	n, err := h.Write(htmlBytes(end))
+	written += n
+
+	h.recordStats(r, written)
+
+	return written, err
+}
+
+// start renders the component in the background and returns the id of its placeholder. Progressive partials of the
+// component render in place, their placeholders would not be on the page before the component is. The component
+// renders with a copy of the queued toasts, the response header is written while it renders.
+func (s *progressiveStream) start(ctx context.Context, c RenderableComponent) string {
+	s.mu.Lock()
+	s.started++
+	id := s.prefix + strconv.Itoa(s.started)
+	s.mu.Unlock()
+
+	ctx = context.WithValue(ctx, progressiveStreamKey{}, (*progressiveStream)(nil))
+	if header, ok := ctx.Value(toastsKey{}).(http.Header); ok {
+		ctx = withToasts(ctx, header.Clone())
+	}
+	ctx = detachRender(ctx)
+
+	go func() {
+		output, err := c.Render(ctx)
+		if err != nil {
+			if OnPartialError != nil {
+				OnPartialError(ctx, id, err)
+			}
+			output = DefaultErrorFallback(ctx, id, err)
+		}
+
+		select {
+		case s.results <- progressiveResult{id: id, output: output}:
+		case <-s.ctx.Done():
+		}
+	}()
+
+	return id
+}
+
+// count returns the number of progressive partials that were started
+func (s *progressiveStream) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.started
+}
+
+// progressiveChunk returns the output of a progressive partial of a full page, in a template that an inline script
+// moves into its placeholder
+func progressiveChunk(result progressiveResult, nonce string) template.HTML {
+	nonceAttr := ""
+	if nonce != "" {
+		nonceAttr = ` nonce="` + template.HTMLEscapeString(nonce) + `"`
+	}
+
+	//nolint:gosec // the id is generated and the nonce is escaped
+	return template.HTML(`<template id="`+result.id+`-content">`) + result.output + template.HTML(`</template>`+
+		`<script`+nonceAttr+`>(function(){var t=document.getElementById("`+result.id+`-content"),`+
+		`p=document.getElementById("`+result.id+`");if(p){p.replaceChildren(t.content);`+
+		`if(window.htmx)htmx.process(p)}t.remove()})()</script>`)
+}
@@ -0,0 +1,102 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// progressiveID matches the id of the first progressive placeholder
+var progressiveID = regexp.MustCompile(`htmx-progressive-[0-9a-f]{16}-1`)
+
+// flushRecorder records the body at every flush
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+
+	flushed []string
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushed = append(f.flushed, f.Body.String())
+	f.ResponseRecorder.Flush()
+}
+
+func TestRenderProgressive(t *testing.T) {
+	fsys := fstest.MapFS{
+		"progressive-layout.html": {Data: []byte(`<html><body>{{ .Partials.Content }}</body></html>`)},
+		"progressive-page.html":   {Data: []byte(`<main>{{ .Partials.Stats }}</main>`)},
+		"progressive-stats.html":  {Data: []byte(`<p>{{ .Data.Visits }} visits</p>`)},
+	}
+
+	page := func() RenderableComponent {
+		stats := NewComponent("progressive-stats.html").FS(fsys).AddData("Visits", 42)
+		layout := NewComponent("progressive-layout.html").FS(fsys)
+
+		return NewComponent("progressive-page.html").FS(fsys).
+			With(Progressive(stats, "<p>Loading…</p>"), "Stats").
+			Wrap(layout, "Content")
+	}
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := New().NewHandler(w, r).RenderProgressive(WithNonce(context.Background(), "abc"), page()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the page is flushed with the placeholder before the partial is streamed in
+	id := progressiveID.FindString(w.flushed[0])
+	equal(t, `<html><body><main><div id="`+id+`"><p>Loading…</p></div></main>`, w.flushed[0])
+
+	body := w.Body.String()
+	equalBool(t, true, strings.HasPrefix(body[len(w.flushed[0]):],
+		`<template id="`+id+`-content"><p>42 visits</p></template><script nonce="abc">`))
+	equalBool(t, true, strings.HasSuffix(body, `</script></body></html>`))
+
+	// htmx requests receive the partial as an out-of-band swap
+	w = &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("HX-Request", "true")
+	if _, err := New().NewHandler(w, r).RenderProgressive(context.Background(), page()); err != nil {
+		t.Fatal(err)
+	}
+	// the placeholder ids of every response are unique, placeholders of earlier responses stay in the DOM
+	hxID := progressiveID.FindString(w.Body.String())
+	if hxID == id {
+		t.Errorf("expected a new placeholder id, got %s again", id)
+	}
+	equal(t, `<main><div id="`+hxID+`"><p>Loading…</p></div></main>`+
+		`<div hx-swap-oob="innerHTML:#`+hxID+`"><p>42 visits</p></div>`, w.Body.String())
+
+	// other renders render the partial in place
+	out, err := page().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<main><p>42 visits</p></main>`, string(out))
+}
+
+func TestRenderProgressiveToasts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"progressive-toasts-page.html": {Data: []byte(`<main>{{ .Partials.Toasts }}</main>`)},
+		"progressive-toasts.html":      {Data: []byte(`{{ range toasts }}<p>{{ .Message }}</p>{{ end }}`)},
+	}
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := h.Toast(FlashSuccess, "Saved"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the partial reads a copy of the queued toasts while the response header is written
+	page := NewComponent("progressive-toasts-page.html").FS(fsys).
+		With(Progressive(NewComponent("progressive-toasts.html").FS(fsys), ""), "Toasts")
+	if _, err := h.RenderProgressive(context.Background(), page); err != nil {
+		t.Fatal(err)
+	}
+
+	equalBool(t, true, strings.Contains(w.Body.String(), `-content"><p>Saved</p></template>`))
+}
@@ -0,0 +1,59 @@
+package htmx
+
+import (
+	"net/http"
+)
+
+// Redirect redirects the request to the url. htmx requests get the HX-Redirect header, which makes the browser load
+// the url, as htmx follows a 3xx response transparently and would swap the page of the url into the target. Other
+// requests get a 303 See Other after a form post, so the browser loads the url with GET, and a 302 Found otherwise.
+//
+//	func (a *App) Save(w http.ResponseWriter, r *http.Request) {
+//		// ... save the form
+//		htmx.Redirect(w, r, "/items")
+//	}
+func Redirect(w http.ResponseWriter, r *http.Request, url string) {
+	addVary(w.Header(), HxRequestHeaderRequest.String())
+
+	if IsHxRequest(r) {
+		NewHxResponse(w).Redirect(url)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	http.Redirect(w, r, url, redirectStatus(r))
+}
+
+// RedirectLocation redirects the request to the path of the location. htmx requests navigate with the HX-Location
+// header, without a full page reload: htmx loads the path and swaps it into the target of the location. Other requests
+// are redirected like Redirect does.
+//
+//	htmx.RedirectLocation(w, r, &htmx.Location{Path: "/items", Target: "#main"})
+func RedirectLocation(w http.ResponseWriter, r *http.Request, l *Location) error {
+	if l.Path == "" {
+		return ErrLocationPath
+	}
+
+	addVary(w.Header(), HxRequestHeaderRequest.String())
+
+	if IsHxRequest(r) {
+		if err := NewHxResponse(w).Location(l); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	http.Redirect(w, r, l.Path, redirectStatus(r))
+	return nil
+}
+
+// redirectStatus returns 302 Found for GET and HEAD requests and 303 See Other for all others
+func redirectStatus(r *http.Request) int {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return http.StatusFound
+	}
+
+	return http.StatusSeeOther
+}
@@ -0,0 +1,61 @@
+package htmx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	tests := []struct {
+		method    string
+		hxRequest bool
+		status    int
+		location  string
+		redirect  string
+	}{
+		{http.MethodPost, true, http.StatusOK, "", "/items"},
+		{http.MethodPost, false, http.StatusSeeOther, "/items", ""},
+		{http.MethodGet, false, http.StatusFound, "/items", ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/items/new", nil)
+		if tt.hxRequest {
+			r.Header.Set("HX-Request", "true")
+		}
+		w := httptest.NewRecorder()
+
+		Redirect(w, r, "/items")
+
+		equalInt(t, tt.status, w.Code)
+		equal(t, tt.location, w.Header().Get("Location"))
+		equal(t, tt.redirect, w.Header().Get("HX-Redirect"))
+		equal(t, "HX-Request", w.Header().Get("Vary"))
+	}
+}
+
+func TestRedirectLocation(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/items/new", nil)
+	r.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	if err := RedirectLocation(w, r, &Location{Path: "/items", Target: "#main"}); err != nil {
+		t.Fatal(err)
+	}
+
+	equalInt(t, http.StatusOK, w.Code)
+	equal(t, `{"path":"/items","target":"#main"}`, w.Header().Get("HX-Location"))
+
+	w = httptest.NewRecorder()
+	if err := RedirectLocation(w, httptest.NewRequest(http.MethodPost, "/items/new", nil), &Location{Path: "/items"}); err != nil {
+		t.Fatal(err)
+	}
+
+	equalInt(t, http.StatusSeeOther, w.Code)
+	equal(t, "/items", w.Header().Get("Location"))
+
+	if err := RedirectLocation(httptest.NewRecorder(), r, &Location{}); err != ErrLocationPath {
+		t.Errorf("expected ErrLocationPath, got %v", err)
+	}
+}
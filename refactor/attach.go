@@ -0,0 +1,77 @@
+package refactor
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// AttachTemplate adds the partial to the template list of every NewComponent call in the Go source
+// that is constructed with the given template. It returns the updated source and the number of updated calls.
+func AttachTemplate(src []byte, template, partial string) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var inserts []int
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isNewComponent(call.Fun) || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return true
+		}
+
+		if !hasStringArg(call.Args, template) || hasStringArg(call.Args, partial) {
+			return true
+		}
+
+		inserts = append(inserts, fset.Position(call.Args[len(call.Args)-1].End()).Offset)
+		return true
+	})
+
+	if len(inserts) == 0 {
+		return src, 0, nil
+	}
+
+	var out bytes.Buffer
+	last := 0
+	for _, offset := range inserts {
+		out.Write(src[last:offset])
+		out.WriteString(", " + strconv.Quote(partial))
+		last = offset
+	}
+	out.Write(src[last:])
+
+	return out.Bytes(), len(inserts), nil
+}
+
+// isNewComponent returns true for NewComponent and <pkg>.NewComponent
+func isNewComponent(fun ast.Expr) bool {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name == "NewComponent"
+	case *ast.SelectorExpr:
+		return f.Sel.Name == "NewComponent"
+	}
+
+	return false
+}
+
+// hasStringArg returns true if one of the arguments is the string literal s
+func hasStringArg(args []ast.Expr, s string) bool {
+	for _, arg := range args {
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+
+		if v, err := strconv.Unquote(lit.Value); err == nil && v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package refactor
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// Registration is a component registered with RegisterComponent
+type Registration struct {
+	Name string // the name the component is registered under
+	Line int    // the line of the RegisterComponent call
+}
+
+// RegisteredComponents returns the components registered in the Go source by RegisterComponent calls with a constant
+// name, in source order
+func RegisteredComponents(src []byte) ([]Registration, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var registrations []Registration
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+
+		if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "RegisterComponent" {
+			return true
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+
+		registrations = append(registrations, Registration{Name: name, Line: fset.Position(call.Pos()).Line})
+		return true
+	})
+
+	return registrations, nil
+}
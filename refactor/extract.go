@@ -0,0 +1,269 @@
+// Package refactor contains tools to mechanically refactor the templates of components.
+package refactor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Selection selects the part of a template that is extracted, either a named block or a line range.
+	Selection struct {
+		Block string // name of a {{define}} or {{block}} to extract
+		Start int    // first line of the range to extract, 1-based
+		End   int    // last line of the range to extract, inclusive
+	}
+
+	// Extraction is the result of extracting a partial out of a template.
+	Extraction struct {
+		Name    string // name of the template defined in the partial
+		Source  []byte // the rewritten source template
+		Partial []byte // the contents of the new partial file
+	}
+
+	// action is a single {{ ... }} action within a template
+	action struct {
+		start, end int    // byte offsets of the action including the delimiters
+		keyword    string // the first word of the action
+		args       string // the remainder of the action
+	}
+)
+
+// Extract extracts the selection of the template source into a new partial that defines a template with the given name.
+// The selection is replaced by a {{template}} call in the source, a named {{define}} is moved as is.
+func Extract(src []byte, sel Selection, name string) (*Extraction, error) {
+	if sel.Block != "" {
+		return extractBlock(src, sel.Block)
+	}
+
+	if name == "" {
+		return nil, errors.New("a name is required when extracting a line range")
+	}
+
+	return extractLines(src, sel.Start, sel.End, name)
+}
+
+// extractBlock moves a {{define}} or {{block}} into its own partial
+func extractBlock(src []byte, block string) (*Extraction, error) {
+	actions, err := scanActions(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, a := range actions {
+		if a.keyword != "define" && a.keyword != "block" {
+			continue
+		}
+
+		name, pipeline, err := blockName(a.args)
+		if err != nil {
+			return nil, err
+		}
+
+		if name != block {
+			continue
+		}
+
+		end, err := matchingEnd(actions, i)
+		if err != nil {
+			return nil, err
+		}
+
+		body := src[a.end:end.start]
+		partial := fmt.Sprintf("{{define %q}}%s{{end}}\n", name, body)
+
+		replacement := ""
+		if a.keyword == "block" {
+			replacement = fmt.Sprintf("{{template %q %s}}", name, pipeline)
+		}
+
+		var out bytes.Buffer
+		out.Write(src[:a.start])
+		out.WriteString(replacement)
+		out.Write(src[end.end:])
+
+		return &Extraction{Name: name, Source: out.Bytes(), Partial: []byte(partial)}, nil
+	}
+
+	return nil, fmt.Errorf("block %q not found", block)
+}
+
+// extractLines moves a range of lines into a new partial
+func extractLines(src []byte, start, end int, name string) (*Extraction, error) {
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	if start < 1 || end < start || end > len(lines) {
+		return nil, fmt.Errorf("invalid line range %d-%d", start, end)
+	}
+
+	selected := bytes.Join(lines[start-1:end], nil)
+
+	// the selection must be balanced, otherwise the extracted partial would not parse
+	actions, err := scanActions(selected)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := 0
+	for _, a := range actions {
+		switch {
+		case opensScope(a.keyword):
+			depth++
+		case a.keyword == "end":
+			depth--
+		}
+
+		if depth < 0 {
+			break
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("lines %d-%d contain unbalanced actions", start, end)
+	}
+
+	first := lines[start-1]
+	indent := first[:len(first)-len(bytes.TrimLeft(first, " \t"))]
+
+	var out bytes.Buffer
+	for _, l := range lines[:start-1] {
+		out.Write(l)
+	}
+
+	out.Write(indent)
+	fmt.Fprintf(&out, "{{template %q .}}", name)
+	if bytes.HasSuffix(selected, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+
+	for _, l := range lines[end:] {
+		out.Write(l)
+	}
+
+	partial := fmt.Sprintf("{{define %q}}\n%s{{end}}\n", name, ensureNewline(selected))
+
+	return &Extraction{Name: name, Source: out.Bytes(), Partial: []byte(partial)}, nil
+}
+
+// scanActions returns all actions of the template source
+func scanActions(src []byte) ([]action, error) {
+	var actions []action
+
+	for i := 0; i < len(src); {
+		open := bytes.Index(src[i:], []byte("{{"))
+		if open < 0 {
+			break
+		}
+		open += i
+
+		end, err := actionEnd(src, open+2)
+		if err != nil {
+			return nil, err
+		}
+
+		inner := string(src[open+2 : end-2])
+		inner = strings.TrimPrefix(inner, "-")
+		inner = strings.TrimSuffix(inner, "-")
+		inner = strings.TrimSpace(inner)
+
+		keyword, args, _ := strings.Cut(inner, " ")
+		actions = append(actions, action{
+			start:   open,
+			end:     end,
+			keyword: keyword,
+			args:    strings.TrimSpace(args),
+		})
+
+		i = end
+	}
+
+	return actions, nil
+}
+
+// actionEnd returns the offset after the closing delimiter of the action, skipping quoted strings and comments
+func actionEnd(src []byte, i int) (int, error) {
+	var quote byte
+
+	for ; i < len(src); i++ {
+		c := src[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`' || c == '\'':
+			quote = c
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i:], []byte("*/"))
+			if end < 0 {
+				return 0, errors.New("unclosed comment")
+			}
+			i += end + 1
+		case c == '}' && i+1 < len(src) && src[i+1] == '}':
+			return i + 2, nil
+		}
+	}
+
+	return 0, errors.New("unclosed action")
+}
+
+// matchingEnd returns the {{end}} action that closes the scope opened by actions[i]
+func matchingEnd(actions []action, i int) (action, error) {
+	depth := 0
+	for _, a := range actions[i:] {
+		switch {
+		case opensScope(a.keyword):
+			depth++
+		case a.keyword == "end":
+			depth--
+			if depth == 0 {
+				return a, nil
+			}
+		}
+	}
+
+	return action{}, errors.New("missing {{end}}")
+}
+
+// opensScope returns true for the actions that are closed with {{end}}
+func opensScope(keyword string) bool {
+	switch keyword {
+	case "if", "range", "with", "define", "block":
+		return true
+	}
+
+	return false
+}
+
+// blockName parses the name and the pipeline of a define or block action
+func blockName(args string) (string, string, error) {
+	if len(args) < 2 || (args[0] != '"' && args[0] != '`') {
+		return "", "", fmt.Errorf("invalid template name in %q", args)
+	}
+
+	end := strings.IndexByte(args[1:], args[0])
+	if end < 0 {
+		return "", "", fmt.Errorf("invalid template name in %q", args)
+	}
+
+	name := args[1 : end+1]
+	pipeline := strings.TrimSpace(args[end+2:])
+	if pipeline == "" {
+		pipeline = "."
+	}
+
+	return name, pipeline, nil
+}
+
+// ensureNewline appends a newline to b when it doesn't end with one
+func ensureNewline(b []byte) []byte {
+	if bytes.HasSuffix(b, []byte("\n")) {
+		return b
+	}
+
+	return append(b, '\n')
+}
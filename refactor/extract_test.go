@@ -0,0 +1,115 @@
+package refactor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractBlock(t *testing.T) {
+	src := `<main>{{block "sidebar" .Data}}<aside>{{if .Open}}open{{end}}</aside>{{end}}</main>`
+
+	result, err := Extract([]byte(src), Selection{Block: "sidebar"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "sidebar", result.Name)
+	equal(t, `<main>{{template "sidebar" .Data}}</main>`, string(result.Source))
+	equal(t, "{{define \"sidebar\"}}<aside>{{if .Open}}open{{end}}</aside>{{end}}\n", string(result.Partial))
+}
+
+func TestExtractDefine(t *testing.T) {
+	src := "{{define \"row\"}}<tr>{{.}}</tr>{{end}}\n<table>{{range .Data.Rows}}{{template \"row\" .}}{{end}}</table>"
+
+	result, err := Extract([]byte(src), Selection{Block: "row"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "\n<table>{{range .Data.Rows}}{{template \"row\" .}}{{end}}</table>", string(result.Source))
+	equal(t, "{{define \"row\"}}<tr>{{.}}</tr>{{end}}\n", string(result.Partial))
+}
+
+func TestExtractLines(t *testing.T) {
+	src := "<ul>\n  {{range .Data.Items}}\n  <li>{{.}}</li>\n  {{end}}\n</ul>\n"
+
+	result, err := Extract([]byte(src), Selection{Start: 2, End: 4}, "items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "<ul>\n  {{template \"items\" .}}\n</ul>\n", string(result.Source))
+	equal(t, "{{define \"items\"}}\n  {{range .Data.Items}}\n  <li>{{.}}</li>\n  {{end}}\n{{end}}\n", string(result.Partial))
+
+	if _, err := Extract([]byte(src), Selection{Start: 2, End: 3}, "items"); err == nil {
+		t.Error("expected an error for an unbalanced selection")
+	}
+}
+
+func TestAttachTemplate(t *testing.T) {
+	src := `package views
+
+import "github.com/jkc-2/go-htmx"
+
+func Page() *htmx.Component {
+	return htmx.NewComponent("templates/page.html", "templates/nav.html")
+}
+
+func Other() *htmx.Component {
+	return htmx.NewComponent("templates/other.html")
+}
+`
+
+	updated, n, err := AttachTemplate([]byte(src), "templates/page.html", "templates/sidebar.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Fatalf("expected 1 updated call, got %d", n)
+	}
+
+	expected := `htmx.NewComponent("templates/page.html", "templates/nav.html", "templates/sidebar.html")`
+	if !strings.Contains(string(updated), expected) {
+		t.Errorf("expected %s in %s", expected, updated)
+	}
+
+	// attaching twice is a no-op
+	_, n, _ = AttachTemplate(updated, "templates/page.html", "templates/sidebar.html")
+	if n != 0 {
+		t.Errorf("expected no updates, got %d", n)
+	}
+}
+
+func TestRegisteredComponents(t *testing.T) {
+	src := `package views
+
+func Register(h *htmx.HTMX, name string) {
+	h.RegisterComponent("user-list", func() htmx.RenderableComponent { return NewUserList() })
+	h.RegisterComponent(name, nil)
+	h.RegisterComponent("user-card", NewUserCard)
+}
+`
+
+	registrations, err := RegisteredComponents([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(registrations) != 2 {
+		t.Fatalf("expected 2 registrations, got %v", registrations)
+	}
+
+	equal(t, "user-list", registrations[0].Name)
+	equal(t, "user-card", registrations[1].Name)
+	if registrations[1].Line != 6 {
+		t.Errorf("expected line 6, got %d", registrations[1].Line)
+	}
+}
+
+func equal(t *testing.T, expected, actual string) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
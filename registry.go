@@ -0,0 +1,56 @@
+package htmx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ComponentFactory returns a new instance of a component, components are not thread-safe,
+// so a registry holds factories instead of component instances.
+type ComponentFactory func() RenderableComponent
+
+// RegisterComponent registers a component factory under the given name, registering a name twice replaces the factory.
+// This allows dependency injection containers to construct components with their resolved services:
+//
+//	func NewUserViews(h *htmx.HTMX, users *UserService) {
+//		h.RegisterComponent("user-list", func() htmx.RenderableComponent {
+//			return htmx.NewComponent("templates/users.html").AddData("Users", users.All())
+//		})
+//	}
+func (h *HTMX) RegisterComponent(name string, factory ComponentFactory) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.components == nil {
+		h.components = make(map[string]ComponentFactory)
+	}
+
+	h.components[name] = factory
+}
+
+// Component returns a new instance of the registered component
+func (h *HTMX) Component(name string) (RenderableComponent, error) {
+	h.mu.RLock()
+	factory, ok := h.components[name]
+	h.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("component %q is not registered", name)
+	}
+
+	return factory(), nil
+}
+
+// Components returns the sorted names of all registered components
+func (h *HTMX) Components() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	names := make([]string, 0, len(h.components))
+	for name := range h.components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
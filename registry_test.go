@@ -0,0 +1,78 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegistryLifecycle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ok.html":     {Data: []byte(`<p>{{ .Data.Name }}</p>`)},
+		"broken.html": {Data: []byte(`<p>{{ .Data.Name </p>`)},
+	}
+
+	h := New()
+	h.RegisterComponent("ok", func() RenderableComponent {
+		return NewComponent("ok.html").FS(fsys)
+	})
+
+	var calls []string
+	h.AddService(Hook{
+		OnStart: func(context.Context) error { calls = append(calls, "start"); return nil },
+		OnStop:  func(context.Context) error { calls = append(calls, "stop"); return nil },
+	})
+
+	if err := h.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "start,stop", calls[0]+","+calls[1])
+
+	c, err := h.Component("ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := c.AddData("Name", "registered").Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<p>registered</p>", string(out))
+
+	if _, err := h.Component("missing"); err == nil {
+		t.Error("expected an error for an unregistered component")
+	}
+
+	// broken templates fail the start before any service is started
+	h.RegisterComponent("broken", func() RenderableComponent {
+		return NewComponent("broken.html").FS(fsys)
+	})
+
+	calls = nil
+	if err := h.Start(context.Background()); err == nil {
+		t.Error("expected an error for a broken template")
+	}
+	equalInt(t, 0, len(calls))
+
+	equal(t, "broken,ok", h.Components()[0]+","+h.Components()[1])
+}
+
+func TestLifecycleRollback(t *testing.T) {
+	h := New()
+
+	var stopped bool
+	h.AddService(Hook{OnStop: func(context.Context) error { stopped = true; return nil }})
+	h.AddService(Hook{OnStart: func(context.Context) error { return errors.New("failed") }})
+
+	if err := h.Start(context.Background()); err == nil {
+		t.Error("expected an error")
+	}
+
+	equalBool(t, true, stopped)
+}
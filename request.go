@@ -2,10 +2,13 @@ package htmx
 
 import (
 	"net/http"
+	"net/url"
 )
 
 const (
 	// ContextRequestHeader is the context key for the htmx request header.
+	//
+	// Deprecated: Middleware stores the htmx request headers in the context, read them with FromContext.
 	ContextRequestHeader = "htmx-request-header"
 
 	HxRequestHeaderBoosted               HxRequestHeaderKey = "HX-Boosted"
@@ -31,8 +34,49 @@ type (
 		HxTriggerName           string
 		HxTrigger               string
 	}
+
+	// Request holds the htmx request headers of a request
+	Request struct {
+		IsHTMX                bool   // HX-Request, the request was made by htmx
+		Boosted               bool   // HX-Boosted, the request was made by an element using hx-boost
+		CurrentURL            string // HX-Current-URL, the current url of the browser
+		HistoryRestoreRequest bool   // HX-History-Restore-Request, the request restores history after a cache miss
+		Prompt                string // HX-Prompt, the response of the user to an hx-prompt
+		Target                string // HX-Target, the id of the target element
+		TriggerName           string // HX-Trigger-Name, the name of the triggered element
+		Trigger               string // HX-Trigger, the id of the triggered element
+	}
 )
 
+// ParseRequest returns the htmx request headers of the request. Values that htmx had to URI encode, as they contain
+// characters that aren't allowed in headers, are decoded.
+func ParseRequest(r *http.Request) Request {
+	return Request{
+		IsHTMX:                HxStrToBool(r.Header.Get(HxRequestHeaderRequest.String())),
+		Boosted:               HxStrToBool(r.Header.Get(HxRequestHeaderBoosted.String())),
+		CurrentURL:            requestHeader(r, HxRequestHeaderCurrentURL),
+		HistoryRestoreRequest: HxStrToBool(r.Header.Get(HxRequestHeaderHistoryRestoreRequest.String())),
+		Prompt:                requestHeader(r, HxRequestHeaderPrompt),
+		Target:                requestHeader(r, HxRequestHeaderTarget),
+		TriggerName:           requestHeader(r, HxRequestHeaderTriggerName),
+		Trigger:               requestHeader(r, HxRequestHeaderTrigger),
+	}
+}
+
+// requestHeader returns the value of the header, decoded when htmx marked it as URI encoded
+func requestHeader(r *http.Request, key HxRequestHeaderKey) string {
+	value := r.Header.Get(key.String())
+	if !HxStrToBool(r.Header.Get(key.String() + "-URI-AutoEncoded")) {
+		return value
+	}
+
+	if decoded, err := url.PathUnescape(value); err == nil {
+		return decoded
+	}
+
+	return value
+}
+
 func HxRequestHeaderFromRequest(r *http.Request) HxRequestHeader {
 	return HxRequestHeader{
 		HxBoosted:               HxStrToBool(r.Header.Get(HxRequestHeaderBoosted.String())),
@@ -53,10 +97,28 @@ func (h *HTMX) HxHeader(r *http.Request) HxRequestHeader {
 		return val
 	}
 
+	if req, ok := r.Context().Value(hxContextKey{}).(Request); ok {
+		return req.hxRequestHeader()
+	}
+
 	// if the header is not found from the middleware, try and populate it from the request
 	return HxRequestHeaderFromRequest(r)
 }
 
+// hxRequestHeader returns the headers as the HxRequestHeader of the Handler
+func (req Request) hxRequestHeader() HxRequestHeader {
+	return HxRequestHeader{
+		HxBoosted:               req.Boosted,
+		HxCurrentURL:            req.CurrentURL,
+		HxHistoryRestoreRequest: req.HistoryRestoreRequest,
+		HxPrompt:                req.Prompt,
+		HxRequest:               req.IsHTMX,
+		HxTarget:                req.Target,
+		HxTriggerName:           req.TriggerName,
+		HxTrigger:               req.Trigger,
+	}
+}
+
 func (x HxRequestHeaderKey) String() string {
 	return string(x)
 }
@@ -0,0 +1,96 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type (
+	requestKey struct{}
+	urlKey     struct{}
+)
+
+// requestContext adds the request, url and form of the component to the render context
+func (c *Component) requestContext(ctx context.Context) context.Context {
+	if c.form != nil {
+		ctx = WithForm(ctx, c.form)
+	}
+
+	if c.request != nil {
+		ctx = context.WithValue(ctx, requestKey{}, c.request)
+	}
+
+	if c.url != nil {
+		ctx = context.WithValue(ctx, urlKey{}, c.url)
+	}
+
+	return ctx
+}
+
+// RequestFromContext returns the request of the component that is being rendered
+func RequestFromContext(ctx context.Context) (*http.Request, bool) {
+	r, ok := ctx.Value(requestKey{}).(*http.Request)
+	return r, ok
+}
+
+// renderURL returns the url of the component that is being rendered
+func renderURL(ctx context.Context) (*url.URL, bool) {
+	if u, ok := ctx.Value(urlKey{}).(*url.URL); ok && u != nil {
+		return u, true
+	}
+
+	if r, ok := RequestFromContext(ctx); ok && r.URL != nil {
+		return r.URL, true
+	}
+
+	return nil, false
+}
+
+// queryFunc is the query template function, it returns the first value of the query parameter
+//
+//	{{ query "page" }}
+func queryFunc(ctx context.Context, args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("query requires a parameter name")
+	}
+
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("query: parameter name %v is not a string", args[0])
+	}
+
+	u, ok := renderURL(ctx)
+	if !ok {
+		return "", nil
+	}
+
+	return u.Query().Get(name), nil
+}
+
+// pathFunc is the path template function, it returns the path of the url
+//
+//	<a href="/users" {{ if eq path "/users" }}aria-current="page"{{ end }}>Users</a>
+func pathFunc(ctx context.Context, _ ...any) (any, error) {
+	u, ok := renderURL(ctx)
+	if !ok {
+		return "", nil
+	}
+
+	return u.Path, nil
+}
+
+// hostFunc is the host template function, it returns the host of the request
+func hostFunc(ctx context.Context, _ ...any) (any, error) {
+	if r, ok := RequestFromContext(ctx); ok {
+		return r.Host, nil
+	}
+
+	if u, ok := renderURL(ctx); ok {
+		return u.Host, nil
+	}
+
+	return "", nil
+}
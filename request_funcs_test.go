@@ -0,0 +1,29 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSetRequest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ host }}{{ path }}?page={{ query "page" }}|{{ .Partials.nav }}`)},
+		"nav.html":  {Data: []byte(`{{ if eq path "/users" }}current{{ end }}`)},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "http://example.com/users?page=2", nil)
+
+	page := NewComponent("page.html").FS(fsys)
+	page.With(NewComponent("nav.html").FS(fsys), "nav")
+	page.SetRequest(r)
+
+	out, err := page.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "example.com/users?page=2|current", string(out))
+}
@@ -0,0 +1,81 @@
+package htmx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+var (
+	// RequestIDHeader is the header the request id is read from and written to by the RequestID middleware
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDGlobalKey is the key of the request id in the global data of the components rendered by a Handler
+	RequestIDGlobalKey = "RequestID"
+)
+
+type requestIDKey struct{}
+
+// RequestIDError is a render error of a request with a request id, so the error in the logs can be correlated with
+// the request
+type RequestIDError struct {
+	RequestID string
+	Err       error
+}
+
+// Error returns the error message prefixed with the request id
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("request %s: %v", e.RequestID, e.Err)
+}
+
+// Unwrap returns the render error
+func (e *RequestIDError) Unwrap() error {
+	return e.Err
+}
+
+// NewRequestID returns a new random request id
+func NewRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// WithRequestID returns a copy of the context with the request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id of the context, or an empty string when it has none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ValidRequestID returns true if the id can be propagated from a request header: 1 to 128 printable ASCII characters
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withRequestIDError wraps the error into a RequestIDError when the context has a request id
+func withRequestIDError(ctx context.Context, err error) error {
+	id := RequestID(ctx)
+	if id == "" || err == nil {
+		return err
+	}
+
+	return &RequestIDError{RequestID: id, Err: err}
+}
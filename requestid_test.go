@@ -0,0 +1,53 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRequestID(t *testing.T) {
+	fsys := fstest.MapFS{
+		"requestid-page.html":  {Data: []byte(`<p>{{ .Global.RequestID }}</p>`)},
+		"requestid-error.html": {Data: []byte(`{{ .Data.Missing.Name }}`)},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(WithRequestID(r.Context(), "abc123"))
+	w := httptest.NewRecorder()
+
+	h := New().NewHandler(w, r)
+	if _, err := h.Render(context.Background(), NewComponent("requestid-page.html").FS(fsys)); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<p>abc123</p>", w.Body.String())
+
+	_, err := New().NewHandler(httptest.NewRecorder(), r).
+		Render(context.Background(), NewComponent("requestid-error.html").FS(fsys).AddData("Missing", 1))
+
+	var idErr *RequestIDError
+	if !errors.As(err, &idErr) {
+		t.Fatalf("expected a RequestIDError, got %v", err)
+	}
+	equal(t, "abc123", idErr.RequestID)
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected bool
+	}{
+		{"", false},
+		{"abc-123", true},
+		{"abc 123", false},
+		{"abc\n123", false},
+		{string(make([]byte, 129)), false},
+	}
+
+	for _, tt := range tests {
+		equalBool(t, tt.expected, ValidRequestID(tt.id))
+	}
+}
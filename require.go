@@ -0,0 +1,27 @@
+package htmx
+
+import (
+	"net/http"
+)
+
+// RequireHTMX serves fragment-only routes to htmx requests only. Full page loads, boosted navigation and history
+// restores, which would show a naked fragment, are served by the fallback, or answered with 404 Not Found when the
+// fallback is nil. Redirect them to the page of the fragment with http.RedirectHandler:
+//
+//	mux.Handle("GET /users/rows", htmx.RequireHTMX(rows, http.RedirectHandler("/users", http.StatusSeeOther)))
+func RequireHTMX(next http.Handler, fallback http.Handler) http.Handler {
+	if fallback == nil {
+		fallback = http.NotFoundHandler()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addVary(w.Header(), fragmentVary...)
+
+		if !WantsFragment(r) {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
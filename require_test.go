@@ -0,0 +1,44 @@
+package htmx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireHTMX(t *testing.T) {
+	rows := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<tr></tr>"))
+	})
+
+	tests := []struct {
+		name     string
+		fallback http.Handler
+		headers  map[string]string
+		status   int
+		location string
+	}{
+		{"htmx request", nil, map[string]string{"HX-Request": "true"}, http.StatusOK, ""},
+		{"full page load", nil, nil, http.StatusNotFound, ""},
+		{"boosted", http.RedirectHandler("/users", http.StatusSeeOther),
+			map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, http.StatusSeeOther, "/users"},
+		{"history restore", http.RedirectHandler("/users", http.StatusSeeOther),
+			map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, http.StatusSeeOther, "/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/rows", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			RequireHTMX(rows, tt.fallback).ServeHTTP(w, r)
+
+			equalInt(t, tt.status, w.Code)
+			equal(t, tt.location, w.Header().Get("Location"))
+			equal(t, "HX-Request, HX-Boosted, HX-History-Restore-Request", w.Header().Get("Vary"))
+		})
+	}
+}
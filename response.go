@@ -1,7 +1,10 @@
 package htmx
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
+	"unicode/utf16"
 )
 
 type (
@@ -43,3 +46,143 @@ func (h *HxResponseHeader) Set(k HxResponseKey, val string) {
 func (h *HxResponseHeader) Get(k HxResponseKey) string {
 	return h.headers.Get(k.String())
 }
+
+// HxResponse wraps a http.ResponseWriter and sets the htmx response headers. Values are encoded to survive the trip
+// through the headers: urls are percent encoded, selectors are CSS escaped and JSON is escaped to ASCII.
+type HxResponse struct {
+	http.ResponseWriter
+}
+
+// NewHxResponse returns a HxResponse writing the headers of w
+func NewHxResponse(w http.ResponseWriter) *HxResponse {
+	return &HxResponse{ResponseWriter: w}
+}
+
+// Redirect does a client-side redirect to the url with a full page reload
+// https://htmx.org/headers/hx-redirect/
+func (r *HxResponse) Redirect(url string) {
+	r.set(HXRedirect, headerURL(url))
+}
+
+// Refresh does a full refresh of the page
+func (r *HxResponse) Refresh() {
+	r.set(HXRefresh, "true")
+}
+
+// PushURL pushes the url into the history stack, "false" prevents the history update
+// https://htmx.org/headers/hx-push-url/
+func (r *HxResponse) PushURL(url string) {
+	r.set(HXPushUrl, headerURL(url))
+}
+
+// ReplaceURL replaces the current url in the location bar, "false" prevents the history update
+// https://htmx.org/headers/hx-replace-url/
+func (r *HxResponse) ReplaceURL(url string) {
+	r.set(HXReplaceUrl, headerURL(url))
+}
+
+// Retarget swaps the response into the element of the CSS selector instead of the target
+func (r *HxResponse) Retarget(selector string) {
+	r.set(HXRetarget, headerSelector(selector))
+}
+
+// Reselect swaps the part of the response matched by the CSS selector, it overrides hx-select
+func (r *HxResponse) Reselect(selector string) {
+	r.set(HXReselect, headerSelector(selector))
+}
+
+// Reswap specifies how the response is swapped, including the swap and settle delays, the scroll and show targets
+// and the view transition of the swap
+//
+//	res.Reswap(htmx.NewSwap(htmx.SwapOuterHTML).Swap(time.Second).ShowTop("#list").Transition(true))
+//	// HX-Reswap: outerHTML show:#list:top transition:true swap:1s
+//
+// https://htmx.org/attributes/hx-swap/
+func (r *HxResponse) Reswap(s *Swap) {
+	r.set(HXReswap, s.String())
+}
+
+// Location does a client-side navigation to the location without a full page reload
+// https://htmx.org/headers/hx-location/
+func (r *HxResponse) Location(l *Location) error {
+	value, err := l.header()
+	if err != nil {
+		return err
+	}
+
+	r.set(HXLocation, value)
+	return nil
+}
+
+// Trigger triggers the events as soon as the response is received
+// https://htmx.org/headers/hx-trigger/
+func (r *HxResponse) Trigger(t *Trigger) {
+	r.set(HXTrigger, asciiJSON(t.String()))
+}
+
+// TriggerAfterSwap triggers the events after the swap step
+// https://htmx.org/headers/hx-trigger/
+func (r *HxResponse) TriggerAfterSwap(t *Trigger) {
+	r.set(HXTriggerAfterSwap, asciiJSON(t.String()))
+}
+
+// TriggerAfterSettle triggers the events after the settle step
+// https://htmx.org/headers/hx-trigger/
+func (r *HxResponse) TriggerAfterSettle(t *Trigger) {
+	r.set(HXTriggerAfterSettle, asciiJSON(t.String()))
+}
+
+// set sets the header
+func (r *HxResponse) set(k HxResponseKey, val string) {
+	r.Header().Set(k.String(), val)
+}
+
+// headerURL percent encodes the bytes of the url that aren't printable ASCII, already encoded parts are kept
+func headerURL(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c >= 0x7f {
+			fmt.Fprintf(&sb, "%%%02X", c)
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+
+	return sb.String()
+}
+
+// headerSelector CSS escapes the characters of the selector that aren't printable ASCII
+func headerSelector(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		if c < ' ' || c >= 0x7f {
+			fmt.Fprintf(&sb, `\%x `, c)
+			continue
+		}
+		sb.WriteRune(c)
+	}
+
+	return sb.String()
+}
+
+// asciiJSON escapes the characters of the JSON that aren't printable ASCII, values that aren't JSON are returned as is
+func asciiJSON(s string) string {
+	if !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, "\"") {
+		return s
+	}
+
+	var sb strings.Builder
+	for _, c := range s {
+		switch {
+		case c < 0x7f:
+			sb.WriteRune(c)
+		case c > 0xffff:
+			r1, r2 := utf16.EncodeRune(c)
+			fmt.Fprintf(&sb, `\u%04x\u%04x`, r1, r2)
+		default:
+			fmt.Fprintf(&sb, `\u%04x`, c)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+package htmx
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHxResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	res := NewHxResponse(w)
+
+	res.Redirect("/login")
+	res.Refresh()
+	res.PushURL("/städte?q=köln")
+	res.ReplaceURL("false")
+	res.Retarget("#straße")
+	res.Reselect("#list")
+	res.Reswap(NewSwap().Style(SwapOuterHTML))
+	res.Trigger(NewTrigger().AddEventDetailed("saved", "Gespeichert ✓"))
+	res.TriggerAfterSwap(NewTrigger().AddEvent("swapped"))
+	if err := res.Location(&Location{Path: "/dashboard", Target: "#main"}); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "/login", w.Header().Get("HX-Redirect"))
+	equal(t, "true", w.Header().Get("HX-Refresh"))
+	equal(t, "/st%C3%A4dte?q=k%C3%B6ln", w.Header().Get("HX-Push-Url"))
+	equal(t, "false", w.Header().Get("HX-Replace-Url"))
+	equal(t, `#stra\df e`, w.Header().Get("HX-Retarget"))
+	equal(t, "#list", w.Header().Get("HX-Reselect"))
+	equal(t, "outerHTML", w.Header().Get("HX-Reswap"))
+	equal(t, `{"saved":"Gespeichert \u2713"}`, w.Header().Get("HX-Trigger"))
+	equal(t, "swapped", w.Header().Get("HX-Trigger-After-Swap"))
+	equal(t, `{"path":"/dashboard","target":"#main"}`, w.Header().Get("HX-Location"))
+}
+
+func TestHxResponseReswap(t *testing.T) {
+	w := httptest.NewRecorder()
+	res := NewHxResponse(w)
+
+	res.Reswap(NewSwap(SwapOuterHTML).Swap(time.Minute).Settle(1500*time.Millisecond).Show("", "#list").Transition(true))
+	equal(t, "outerHTML show:#list:top transition:true swap:60s settle:1500ms", w.Header().Get("HX-Reswap"))
+
+	res.Reswap(NewSwap(SwapBeforeEnd).Swap().ScrollBottom("window"))
+	equal(t, "beforeend scroll:window:bottom swap:0s", w.Header().Get("HX-Reswap"))
+}
@@ -0,0 +1,158 @@
+package htmx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ParamsKey is the data key of the path parameters of a route
+var ParamsKey = "Params"
+
+// ComponentRoute maps a path to a page component, and the HX-Target values of htmx requests on the path to fragment
+// components. The path parameters are bound to the Params data of the components.
+//
+//	h.Mount(mux,
+//		htmx.Route("/users/{id}", UserPage).Layout("main").Fragment("row", UserRow),
+//	)
+type ComponentRoute struct {
+	pattern      string
+	page         ComponentFactory
+	layout       string
+	layoutTarget string
+	fragments    map[string]ComponentFactory
+	staticPaths  StaticPaths
+	staticPages  StaticPages
+	changeFreq   ChangeFreq
+	unlisted     bool
+}
+
+// Route returns a route that renders a new component of the page factory on GET requests of the pattern, a pattern
+// of http.ServeMux without a method
+func Route(pattern string, page ComponentFactory) *ComponentRoute {
+	return &ComponentRoute{
+		pattern:   pattern,
+		page:      page,
+		fragments: make(map[string]ComponentFactory),
+	}
+}
+
+// Layout wraps the page in the registered component of the name, with the page rendered into the target or content.
+// The page is auto wrapped: htmx requests get the page without the layout.
+func (rt *ComponentRoute) Layout(name string, target ...string) *ComponentRoute {
+	rt.layout = name
+	rt.layoutTarget = "content"
+	if len(target) > 0 {
+		rt.layoutTarget = target[0]
+	}
+
+	return rt
+}
+
+// Fragment renders a new component of the factory, instead of the page, for htmx requests that target the element
+// with the id
+func (rt *ComponentRoute) Fragment(target string, fragment ComponentFactory) *ComponentRoute {
+	rt.fragments[target] = fragment
+	return rt
+}
+
+// ChangeFreq sets the change frequency of the pages of the route in sitemaps
+func (rt *ComponentRoute) ChangeFreq(freq ChangeFreq) *ComponentRoute {
+	rt.changeFreq = freq
+	return rt
+}
+
+// Unlisted leaves the pages of the route out of sitemaps, see Sitemap.Collect
+func (rt *ComponentRoute) Unlisted() *ComponentRoute {
+	rt.unlisted = true
+	return rt
+}
+
+// Mount registers the routes on the mux, Generate renders the mounted routes to static files
+func (h *HTMX) Mount(mux *http.ServeMux, routes ...*ComponentRoute) {
+	h.mu.Lock()
+	h.routes = append(h.routes, routes...)
+	h.mu.Unlock()
+
+	for _, rt := range routes {
+		mux.Handle("GET "+rt.pattern, rt.handler(h))
+	}
+}
+
+// handler returns the handler of the route
+func (rt *ComponentRoute) handler(h *HTMX) http.Handler {
+	params := patternParams(rt.pattern)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler := h.NewHandler(w, r)
+
+		c, err := rt.component(h, handler, r)
+		if err != nil {
+			h.log.Warn("unable to create the component of the route", "pattern", rt.pattern, "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		values := make(map[string]string, len(params))
+		for _, name := range params {
+			values[name] = r.PathValue(name)
+		}
+		c.AddData(ParamsKey, values)
+		for key, value := range staticData(r.Context()) {
+			c.AddData(key, value)
+		}
+
+		if _, err := handler.Render(r.Context(), c); err != nil {
+			h.log.Warn("unable to render the route", "pattern", rt.pattern, "error", err)
+			status := ErrorStatus(err)
+			http.Error(w, http.StatusText(status), status)
+		}
+	})
+}
+
+// component returns the fragment of the target of the request, or the page in its layout
+func (rt *ComponentRoute) component(h *HTMX, handler *Handler, r *http.Request) (RenderableComponent, error) {
+	if len(rt.fragments) > 0 {
+		handler.Vary(HxRequestHeaderTarget.String())
+		handler.Vary(fragmentVary...)
+
+		if fragment, ok := rt.fragments[ParseRequest(r).Target]; ok && WantsFragment(r) {
+			return fragment(), nil
+		}
+	}
+
+	page := rt.page()
+	if rt.layout == "" {
+		return page, nil
+	}
+
+	layout, err := h.Component(rt.layout)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := page.(*Component)
+	if !ok {
+		return nil, fmt.Errorf("page of %s is a %T, only a *Component can be wrapped in a layout", rt.pattern, page)
+	}
+
+	return c.AutoWrap(layout, rt.layoutTarget), nil
+}
+
+// patternParams returns the names of the wildcards of the pattern, e.g. id of /users/{id}
+func patternParams(pattern string) []string {
+	var params []string
+
+	for _, segment := range strings.Split(pattern, "/") {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+		if name != "$" && name != "" {
+			params = append(params, name)
+		}
+	}
+
+	return params
+}
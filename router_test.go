@@ -0,0 +1,54 @@
+package htmx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRoute(t *testing.T) {
+	fsys := fstest.MapFS{
+		"router-main.html": {Data: []byte(`<main>{{ .Partials.content }}</main>`)},
+		"router-user.html": {Data: []byte(`<table><tr id="row">{{ .Data.Params.id }}</tr></table>`)},
+		"router-row.html":  {Data: []byte(`<tr id="row">{{ .Data.Params.id }} {{ .Data.Params.tab }}</tr>`)},
+	}
+
+	h := New()
+	h.RegisterComponent("main", func() RenderableComponent { return NewComponent("router-main.html").FS(fsys) })
+
+	mux := http.NewServeMux()
+	h.Mount(mux,
+		Route("/users/{id}/{tab...}", func() RenderableComponent { return NewComponent("router-user.html").FS(fsys) }).
+			Layout("main").
+			Fragment("row", func() RenderableComponent { return NewComponent("router-row.html").FS(fsys) }),
+	)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"full page load", nil, `<main><table><tr id="row">7</tr></table></main>`},
+		{"htmx request", map[string]string{"HX-Request": "true"}, `<table><tr id="row">7</tr></table>`},
+		{"fragment", map[string]string{"HX-Request": "true", "HX-Target": "row"}, `<tr id="row">7 orders/open</tr>`},
+		{"boosted", map[string]string{"HX-Request": "true", "HX-Boosted": "true", "HX-Target": "row"},
+			`<main><table><tr id="row">7</tr></table></main>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/7/orders/open", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			equalInt(t, http.StatusOK, w.Code)
+			equal(t, tt.expected, w.Body.String())
+			equal(t, "HX-Target, HX-Request, HX-Boosted, HX-History-Restore-Request", w.Header().Get("Vary"))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package htmx
+
+import (
+	"html"
+	"html/template"
+)
+
+// DefaultSanitizer is used by the sanitize template function and the output sanitization of components
+// that don't have their own sanitizer. Plug in a sanitizer like bluemonday here once for the whole application:
+//
+//	p := bluemonday.UGCPolicy()
+//	htmx.DefaultSanitizer = p.Sanitize
+var DefaultSanitizer SanitizeFunc
+
+// SanitizeFunc sanitizes untrusted html, only the html it returns is considered safe.
+type SanitizeFunc func(input string) string
+
+// sanitize sanitizes the input with the sanitizer and marks it as safe html,
+// the input is escaped when no sanitizer is available.
+func sanitize(sanitizer SanitizeFunc, input string) template.HTML {
+	if sanitizer == nil {
+		return template.HTML(html.EscapeString(input))
+	}
+
+	//nolint:gosec // the input is sanitized
+	return template.HTML(sanitizer(input))
+}
+
+// sanitizeFunc is the sanitize template function that uses the default sanitizer
+func sanitizeFunc(input string) template.HTML {
+	return sanitize(DefaultSanitizer, input)
+}
+
+// SetSanitizer sets the sanitizer that is used when sanitizing the output of the component,
+// DefaultSanitizer is used when no sanitizer is set.
+func (c *Component) SetSanitizer(fn SanitizeFunc) *Component {
+	c.sanitizer = fn
+	return c
+}
+
+// SanitizeOutput enables the sanitization of the rendered output of the component,
+// this is meant for components that render user provided templates or content as a whole.
+func (c *Component) SanitizeOutput(enabled bool) *Component {
+	c.sanitizeOutput = enabled
+	return c
+}
+
+// sanitizeRendered sanitizes the output of the component when output sanitization is enabled
+func (c *Component) sanitizeRendered(output template.HTML) template.HTML {
+	if !c.sanitizeOutput {
+		return output
+	}
+
+	sanitizer := c.sanitizer
+	if sanitizer == nil {
+		sanitizer = DefaultSanitizer
+	}
+
+	return sanitize(sanitizer, string(output))
+}
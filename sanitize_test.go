@@ -0,0 +1,54 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSanitize(t *testing.T) {
+	fsys := fstest.MapFS{
+		"post.html": {Data: []byte(`<article>{{ sanitize .Data.Body }}</article>`)},
+	}
+
+	body := `<b>bold</b><script>alert(1)</script>`
+
+	// without a sanitizer the input is escaped
+	out, err := NewComponent("post.html").FS(fsys).AddData("Body", body).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<article>&lt;b&gt;bold&lt;/b&gt;&lt;script&gt;alert(1)&lt;/script&gt;</article>`, string(out))
+
+	DefaultSanitizer = func(s string) string {
+		return strings.ReplaceAll(s, "<script>alert(1)</script>", "")
+	}
+	defer func() { DefaultSanitizer = nil }()
+
+	out, err = NewComponent("post.html").FS(fsys).AddData("Body", body).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<article><b>bold</b></article>`, string(out))
+}
+
+func TestSanitizeOutput(t *testing.T) {
+	fsys := fstest.MapFS{
+		"raw.html": {Data: []byte(`<p>{{ .Partials.raw }}</p>`)},
+	}
+
+	c := NewComponent("raw.html").FS(fsys).
+		SetSanitizer(func(s string) string {
+			return strings.ReplaceAll(s, "<i>", "")
+		}).
+		SanitizeOutput(true)
+	c.addPartial("raw", template.HTML("<i>"))
+
+	out, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<p></p>`, string(out))
+}
@@ -0,0 +1,107 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"strings"
+	"unicode"
+)
+
+type (
+	// ComponentOptions configure a generated component
+	ComponentOptions struct {
+		Name        string // the exported Go name of the component, e.g. UserCard
+		Package     string // the package of the generated Go files, defaults to "components"
+		TemplateDir string // the directory of the template next to the Go files, defaults to "templates"
+		WithTest    bool   // generate a snapshot test of the component
+	}
+
+	// component is the data of the component generator templates
+	component struct {
+		ComponentOptions
+
+		File     string // the base name of the generated files, e.g. user_card
+		ID       string // the id of the root element, e.g. user-card
+		Var      string // the unexported Go name, e.g. userCard
+		Template string // the path of the template, e.g. templates/user_card.html
+	}
+)
+
+// Component returns the constructor, the template and optionally the snapshot test of a new component. The constructor
+// embeds the template, so the component renders from any working directory:
+//
+//	//go:embed templates/user_card.html
+//	var userCardTemplates embed.FS
+//
+//	func NewUserCard() *htmx.Component {
+//		return htmx.NewComponent("templates/user_card.html").FS(userCardTemplates)
+//	}
+func Component(opts ComponentOptions) ([]File, error) {
+	if !token.IsIdentifier(opts.Name) || !token.IsExported(opts.Name) {
+		return nil, fmt.Errorf("component name %q is not an exported Go identifier", opts.Name)
+	}
+
+	if opts.Package == "" {
+		opts.Package = "components"
+	}
+
+	if opts.TemplateDir == "" {
+		opts.TemplateDir = "templates"
+	}
+
+	parts := words(opts.Name)
+	for i, word := range parts {
+		parts[i] = strings.ToLower(word)
+	}
+
+	c := component{
+		ComponentOptions: opts,
+		File:             strings.Join(parts, "_"),
+		ID:               strings.Join(parts, "-"),
+		Var:              unexported(opts.Name),
+		Template:         path.Join(opts.TemplateDir, strings.Join(parts, "_")+".html"),
+	}
+
+	goFiles := []struct{ tmpl, path string }{{"component.go.tmpl", c.File + ".go"}}
+	if opts.WithTest {
+		goFiles = append(goFiles, struct{ tmpl, path string }{"component_test.go.tmpl", c.File + "_test.go"})
+	}
+
+	var files []File
+	for _, f := range goFiles {
+		src, err := execute(f.tmpl, c)
+		if err != nil {
+			return nil, err
+		}
+
+		formatted, err := format.Source(src)
+		if err != nil {
+			return nil, fmt.Errorf("formatting %s: %w", f.path, err)
+		}
+
+		files = append(files, File{Path: f.path, Content: formatted})
+	}
+
+	html, err := execute("component.html.tmpl", c)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, File{Path: c.Template, Content: html}), nil
+}
+
+// unexported returns the unexported Go name, e.g. UserCard becomes userCard and HTTPStatus becomes httpStatus
+func unexported(name string) string {
+	r := []rune(name)
+	for i := 0; i < len(r) && unicode.IsUpper(r[i]); i++ {
+		// the last upper case letter of an initialism starts the next word
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+
+	return string(r)
+}
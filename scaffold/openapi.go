@@ -0,0 +1,175 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// spec is the part of an OpenAPI 3 document the scaffolding is generated from
+	spec struct {
+		Paths      map[string]pathItem `json:"paths"`
+		Components struct {
+			Schemas map[string]*schema `json:"schemas"`
+		} `json:"components"`
+	}
+
+	pathItem struct {
+		Get *operation `json:"get"`
+	}
+
+	operation struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema *schema `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	}
+
+	schema struct {
+		Ref        string     `json:"$ref"`
+		Type       schemaType `json:"type"`
+		Format     string     `json:"format"`
+		Enum       []any      `json:"enum"`
+		Items      *schema    `json:"items"`
+		Properties properties `json:"properties"`
+		Required   []string   `json:"required"`
+		ReadOnly   bool       `json:"readOnly"`
+		Title      string     `json:"title"`
+	}
+
+	// schemaType is the type of a schema, OpenAPI 3.1 allows a list of types, e.g. ["string", "null"]
+	schemaType string
+
+	// properties are the properties of an object schema in the order of the document
+	properties []property
+
+	property struct {
+		Name   string
+		Schema *schema
+	}
+)
+
+// UnmarshalJSON reads a type or the first type of a list that is not null
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaType(single)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	for _, typ := range list {
+		if typ != "null" {
+			*t = schemaType(typ)
+			break
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON reads the properties and keeps their order, so the generated fields follow the document
+func (p *properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return errors.New("properties must be an object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected property name %v", tok)
+		}
+
+		var s schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+
+		*p = append(*p, property{Name: name, Schema: &s})
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+// parseSpec parses a JSON OpenAPI document
+func parseSpec(data []byte) (*spec, error) {
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing the OpenAPI document: %w", err)
+	}
+
+	if len(s.Paths) == 0 {
+		return nil, errors.New("the OpenAPI document has no paths")
+	}
+
+	return &s, nil
+}
+
+// resolve returns the schema a reference points to
+func (s *spec) resolve(sc *schema) (string, *schema, error) {
+	if sc == nil || sc.Ref == "" {
+		return "", sc, nil
+	}
+
+	name, ok := strings.CutPrefix(sc.Ref, "#/components/schemas/")
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported reference %q", sc.Ref)
+	}
+
+	target, ok := s.Components.Schemas[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown schema %q", name)
+	}
+
+	return name, target, nil
+}
+
+// listSchema returns the name of the item schema when the GET operation returns a JSON list of a named schema
+func (s *spec) listSchema(op *operation) (string, error) {
+	if op == nil {
+		return "", nil
+	}
+
+	for _, code := range []string{"200", "2XX", "default"} {
+		response, ok := op.Responses[code]
+		if !ok {
+			continue
+		}
+
+		media, ok := response.Content["application/json"]
+		if !ok || media.Schema == nil {
+			continue
+		}
+
+		_, list, err := s.resolve(media.Schema)
+		if err != nil || list == nil || list.Type != "array" || list.Items == nil {
+			return "", err
+		}
+
+		name, _, err := s.resolve(list.Items)
+		return name, err
+	}
+
+	return "", nil
+}
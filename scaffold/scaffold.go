@@ -0,0 +1,372 @@
+// Package scaffold generates CRUD component skeletons from an OpenAPI document: a list table, a detail view and an
+// edit form for every collection of the API, with a typed struct, a bind function validating the submitted form
+// and the handlers registering the routes on a http.ServeMux.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var (
+	// generator templates use [[ ]] so the html templates they emit can use {{ }}
+	generator = template.Must(template.New("").Delims("[[", "]]").Funcs(template.FuncMap{
+		"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+		"add":   func(a, b int) int { return a + b },
+	}).ParseFS(templates, "templates/*.tmpl"))
+
+	// collectionPath matches a path without parameters, e.g. /pets or /store/orders
+	collectionPath = regexp.MustCompile(`^(/[A-Za-z0-9_-]+)+$`)
+
+	// initialisms are written in upper case in Go names
+	initialisms = map[string]bool{"id": true, "url": true, "uri": true, "api": true, "http": true, "html": true, "ip": true, "uuid": true, "sku": true}
+)
+
+type (
+	// Options configure the generated code
+	Options struct {
+		Package     string // the package of the generated Go files, defaults to "admin"
+		TemplateDir string // the directory of the generated templates, defaults to "templates"
+		Source      string // the name of the OpenAPI document, mentioned in the generated files
+	}
+
+	// File is a generated file
+	File struct {
+		Path    string
+		Content []byte
+	}
+
+	// Resource is a collection of the API, e.g. /pets with /pets/{petId}
+	Resource struct {
+		Name    string  // the Go name of the schema, e.g. Pet
+		Plural  string  // the Go name of the collection, e.g. Pets
+		Var     string  // the lower case name of the collection, e.g. pets
+		Path    string  // the collection path, e.g. /pets
+		Param   string  // the name of the item path parameter, e.g. petId
+		IDField *Field  // the field identifying an item in urls
+		Fields  []Field // the fields of the schema in document order
+	}
+
+	// Field is a property of the schema of a resource
+	Field struct {
+		Name     string   // the Go name, e.g. FirstName
+		JSON     string   // the property name, e.g. first_name
+		Label    string   // the human readable name, e.g. First name
+		Kind     string   // string, int, float or bool
+		GoType   string   // the Go type
+		Input    string   // the type of the html input
+		Required bool     // the property is required
+		ReadOnly bool     // the property is not part of the form
+		Enum     []string // the allowed values of a string property
+	}
+)
+
+// Generate returns the Go files and templates for the resources of the JSON OpenAPI document
+func Generate(document []byte, opts Options) ([]File, error) {
+	if opts.Package == "" {
+		opts.Package = "admin"
+	}
+
+	if opts.TemplateDir == "" {
+		opts.TemplateDir = "templates"
+	}
+
+	resources, err := Resources(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("no collections with a list operation returning a schema found")
+	}
+
+	var files []File
+	for _, r := range resources {
+		data := struct {
+			Resource
+			Options
+			Templates string
+		}{r, opts, path.Join(opts.TemplateDir, r.Var)}
+
+		src, err := execute("handlers.go.tmpl", data)
+		if err != nil {
+			return nil, err
+		}
+
+		formatted, err := format.Source(src)
+		if err != nil {
+			return nil, fmt.Errorf("formatting the handlers of %s: %w", r.Name, err)
+		}
+
+		files = append(files, File{Path: strings.ToLower(r.Name) + ".go", Content: formatted})
+
+		for _, view := range []string{"list", "detail", "form"} {
+			html, err := execute(view+".html.tmpl", data)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, File{Path: path.Join(data.Templates, view+".html"), Content: html})
+		}
+	}
+
+	return files, nil
+}
+
+// Resources returns the collections of the OpenAPI document whose GET operation returns a list of a named object schema
+func Resources(document []byte) ([]Resource, error) {
+	s, err := parseSpec(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []Resource
+	for p, item := range s.Paths {
+		if !collectionPath.MatchString(p) {
+			continue
+		}
+
+		name, err := s.listSchema(item.Get)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		if name == "" {
+			continue
+		}
+
+		r, err := newResource(s, p, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		resources = append(resources, r)
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Path < resources[j].Path })
+	return resources, nil
+}
+
+// newResource returns the resource of the collection path with items of the named schema
+func newResource(s *spec, collection, schemaName string) (Resource, error) {
+	sc := s.Components.Schemas[schemaName]
+	if sc.Type != "" && sc.Type != "object" {
+		return Resource{}, fmt.Errorf("schema %s is not an object", schemaName)
+	}
+
+	segment := path.Base(collection)
+	r := Resource{
+		Name:   goName(schemaName),
+		Plural: goName(segment),
+		Var:    strings.ToLower(goName(segment)[:1]) + goName(segment)[1:],
+		Path:   collection,
+		Param:  "id",
+	}
+
+	// the item path is the collection path with a single parameter, e.g. /pets/{petId}
+	for p := range s.Paths {
+		if param, ok := strings.CutPrefix(p, collection+"/{"); ok && strings.HasSuffix(param, "}") && !strings.Contains(param, "/") {
+			r.Param = strings.TrimSuffix(param, "}")
+		}
+	}
+
+	for _, prop := range sc.Properties {
+		_, ps, err := s.resolve(prop.Schema)
+		if err != nil {
+			return Resource{}, err
+		}
+
+		field, ok := newField(prop.Name, ps, sc.Required)
+		if ok {
+			r.Fields = append(r.Fields, field)
+		}
+	}
+
+	if len(r.Fields) == 0 {
+		return Resource{}, fmt.Errorf("schema %s has no scalar properties", schemaName)
+	}
+
+	r.IDField = &r.Fields[0]
+	for i, f := range r.Fields {
+		if f.JSON == r.Param || f.JSON == "id" {
+			r.IDField = &r.Fields[i]
+			r.Fields[i].ReadOnly = true
+			break
+		}
+	}
+
+	return r, nil
+}
+
+// newField returns the field of a scalar property, arrays and nested objects are skipped
+func newField(name string, sc *schema, required []string) (Field, bool) {
+	f := Field{
+		Name:     goName(name),
+		JSON:     name,
+		Label:    label(name),
+		Required: slices.Contains(required, name),
+		ReadOnly: sc.ReadOnly,
+		Input:    "text",
+	}
+
+	if sc.Title != "" {
+		f.Label = sc.Title
+	}
+
+	switch sc.Type {
+	case "string", "":
+		if sc.Type == "" && len(sc.Enum) == 0 {
+			return f, false
+		}
+
+		f.Kind, f.GoType = "string", "string"
+		switch sc.Format {
+		case "email":
+			f.Input = "email"
+		case "uri", "url":
+			f.Input = "url"
+		case "date":
+			f.Input = "date"
+		case "date-time":
+			f.Input = "datetime-local"
+		case "password":
+			f.Input = "password"
+		}
+
+		for _, v := range sc.Enum {
+			f.Enum = append(f.Enum, fmt.Sprint(v))
+		}
+	case "integer":
+		f.Kind, f.GoType, f.Input = "int", "int64", "number"
+	case "number":
+		f.Kind, f.GoType, f.Input = "float", "float64", "number"
+	case "boolean":
+		f.Kind, f.GoType, f.Input = "bool", "bool", "checkbox"
+	default:
+		return f, false
+	}
+
+	return f, true
+}
+
+// NeedsStrconv returns true when the bind function parses numbers
+func (r Resource) NeedsStrconv() bool {
+	for _, f := range r.FormFields() {
+		if f.Kind == "int" || f.Kind == "float" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NeedsSlices returns true when the bind function validates enums
+func (r Resource) NeedsSlices() bool {
+	for _, f := range r.FormFields() {
+		if len(f.Enum) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FormFields returns the fields that are edited in the form
+func (r Resource) FormFields() []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if !f.ReadOnly {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// execute executes the generator template
+func execute(name string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := generator.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// goName returns the exported Go name of a property or schema name, e.g. first_name becomes FirstName
+func goName(name string) string {
+	var sb strings.Builder
+	for _, word := range words(name) {
+		if initialisms[strings.ToLower(word)] {
+			sb.WriteString(strings.ToUpper(word))
+			continue
+		}
+
+		sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	if sb.Len() == 0 || unicode.IsDigit(rune(sb.String()[0])) {
+		return "X" + sb.String()
+	}
+
+	return sb.String()
+}
+
+// label returns the human readable name of a property, e.g. firstName becomes First name
+func label(name string) string {
+	parts := words(name)
+	for i, word := range parts {
+		if initialisms[strings.ToLower(word)] {
+			parts[i] = strings.ToUpper(word)
+		} else {
+			parts[i] = strings.ToLower(word)
+		}
+	}
+
+	s := strings.Join(parts, " ")
+	if s == "" {
+		return name
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// words splits a name at underscores, dashes, spaces and camel case boundaries
+func words(name string) []string {
+	var parts []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && len(current) > 0 && !unicode.IsUpper(current[len(current)-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return parts
+}
@@ -0,0 +1,177 @@
+package scaffold
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+const petstore = `{
+	"openapi": "3.1.0",
+	"paths": {
+		"/pets": {
+			"get": {"responses": {"200": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}}}
+		},
+		"/pets/{petId}": {
+			"get": {"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}}
+		},
+		"/health": {
+			"get": {"responses": {"200": {"content": {"application/json": {"schema": {"type": "object"}}}}}}
+		}
+	},
+	"components": {
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"required": ["name", "age"],
+				"properties": {
+					"petId": {"type": "integer", "readOnly": true},
+					"name": {"type": "string"},
+					"owner_email": {"type": "string", "format": "email"},
+					"age": {"type": "integer"},
+					"weight": {"type": ["number", "null"]},
+					"status": {"type": "string", "enum": ["available", "sold"]},
+					"vaccinated": {"type": "boolean"},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				}
+			}
+		}
+	}
+}`
+
+func TestResources(t *testing.T) {
+	resources, err := Resources([]byte(petstore))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(resources))
+	}
+
+	r := resources[0]
+	equal(t, "Pet", r.Name)
+	equal(t, "Pets", r.Plural)
+	equal(t, "petId", r.Param)
+	equal(t, "PetID", r.IDField.Name)
+
+	var names []string
+	for _, f := range r.Fields {
+		names = append(names, f.Name+":"+f.GoType+":"+f.Input)
+	}
+	equal(t, "PetID:int64:number,Name:string:text,OwnerEmail:string:email,Age:int64:number,Weight:float64:number,Status:string:text,Vaccinated:bool:checkbox",
+		strings.Join(names, ","))
+
+	equal(t, "Owner email", r.Fields[2].Label)
+	equal(t, 6, len(r.FormFields()))
+}
+
+func TestGenerate(t *testing.T) {
+	files, err := Generate([]byte(petstore), Options{Package: "admin", Source: "petstore.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := map[string]string{}
+	for _, f := range files {
+		contents[f.Path] = string(f.Content)
+	}
+
+	src, ok := contents["pet.go"]
+	if !ok {
+		t.Fatalf("expected pet.go, got %v", files)
+	}
+
+	for _, want := range []string{
+		"package admin",
+		"// Code generated by htmx scaffold from petstore.json.",
+		"PetID      int64   `json:\"petId,omitempty\"`",
+		"func RegisterPets(mux *http.ServeMux, h *htmx.HTMX, store PetStore) {",
+		`mux.HandleFunc("GET /pets/{id}/edit", v.form)`,
+		`form.AddError("name", "Name is required")`,
+		`form.AddError("age", "Age must be a whole number")`,
+		`!slices.Contains([]string{"available", "sold"}, item.Status)`,
+		`item.Vaccinated = form.Get("vaccinated") != ""`,
+		`htmx.NewComponent("templates/pets/form.html")`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected pet.go to contain %q\n%s", want, src)
+		}
+	}
+
+	// the generated templates parse with the template functions of go-htmx
+	funcs := template.FuncMap{
+		"old":        func(string) string { return "" },
+		"fieldError": func(string) string { return "" },
+		"hasError":   func(string) bool { return false },
+	}
+
+	for _, view := range []string{"list", "detail", "form"} {
+		name := "templates/pets/" + view + ".html"
+		html, ok := contents[name]
+		if !ok {
+			t.Fatalf("expected %s", name)
+		}
+
+		tmpl, err := template.New(view).Funcs(funcs).Parse(html)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		data := map[string]any{"Data": map[string]any{
+			"Items":  []map[string]any{{"PetID": 7, "Name": "Rex"}},
+			"Item":   map[string]any{"PetID": 7, "Name": "Rex", "Status": "sold"},
+			"Action": "/pets/7",
+		}}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if !strings.Contains(buf.String(), "/pets/7") {
+			t.Errorf("%s: expected the item url, got %s", name, buf.String())
+		}
+	}
+
+	if !strings.Contains(contents["templates/pets/form.html"], `<option value="sold"{{ if eq (printf "%v" (or (old "status") $.Data.Item.Status)) "sold" }} selected{{ end }}>sold</option>`) {
+		t.Errorf("unexpected form %s", contents["templates/pets/form.html"])
+	}
+}
+
+func TestComponent(t *testing.T) {
+	files, err := Component(ComponentOptions{Name: "UserCard", Package: "views", WithTest: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := map[string]string{}
+	for _, f := range files {
+		contents[f.Path] = string(f.Content)
+	}
+
+	for path, want := range map[string]string{
+		"user_card.go":             "//go:embed templates/user_card.html\nvar userCardTemplates embed.FS",
+		"user_card_test.go":        `htmxtest.RenderSnapshot(t, NewUserCard().AddData("Title", "Hello"))`,
+		"templates/user_card.html": `<div id="user-card">`,
+	} {
+		if !strings.Contains(contents[path], want) {
+			t.Errorf("expected %s to contain %q\n%s", path, want, contents[path])
+		}
+	}
+
+	equal(t, "httpStatus", unexported("HTTPStatus"))
+
+	if _, err := Component(ComponentOptions{Name: "userCard"}); err == nil {
+		t.Error("expected an error for an unexported name")
+	}
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,153 @@
+package htmx
+
+import (
+	"embed"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//go:embed templates/search.html templates/search_results.html
+var searchTemplates embed.FS
+
+var (
+	// SearchParam is the parameter of the search query
+	SearchParam = "q"
+
+	// DefaultSearchDelay is the time a search input waits for the user to stop typing before it searches
+	DefaultSearchDelay = 300 * time.Millisecond
+)
+
+type (
+	// Search renders an active search input, it requests the results while the user types and swaps them into the
+	// results container below the input, or the target
+	//
+	//	page.With(htmx.NewSearch("user-search", "/users/search").Placeholder("Search users").Component(), "search")
+	Search struct {
+		id          string
+		url         string
+		target      string
+		label       string
+		placeholder string
+		indicator   string
+		query       string
+		delay       time.Duration
+	}
+
+	// SearchResultsFunc returns the results of the query of the request
+	SearchResultsFunc func(r *http.Request, query string) (RenderableComponent, error)
+)
+
+// NewSearch returns a search input with the id that requests its results from the url
+func NewSearch(id, url string) *Search {
+	return &Search{id: id, url: url, delay: DefaultSearchDelay}
+}
+
+// Target sets the CSS selector of the element the results are swapped into, the search renders no results container
+// of its own then
+func (s *Search) Target(selector string) *Search {
+	s.target = selector
+	return s
+}
+
+// Label sets the label of the input
+func (s *Search) Label(label string) *Search {
+	s.label = label
+	return s
+}
+
+// Placeholder sets the placeholder of the input
+func (s *Search) Placeholder(placeholder string) *Search {
+	s.placeholder = placeholder
+	return s
+}
+
+// Indicator sets the CSS selector of the element that is shown while the results load
+func (s *Search) Indicator(selector string) *Search {
+	s.indicator = selector
+	return s
+}
+
+// Delay sets the time the input waits for the user to stop typing
+func (s *Search) Delay(d time.Duration) *Search {
+	s.delay = d
+	return s
+}
+
+// Query sets the value of the input, e.g. the query of the request on a full page load
+func (s *Search) Query(query string) *Search {
+	s.query = query
+	return s
+}
+
+// Component returns the component of the search input, with the results container when no target was set
+func (s *Search) Component() *Component {
+	target := s.target
+	if target == "" {
+		target = "#" + s.id + "-results"
+	}
+
+	attrs := NewAttributes().
+		Set("hx-get", s.url).
+		Trigger(NewTriggerSpec("input").Changed().Delay(s.delay).Or("search")).
+		Set("hx-target", target).
+		Sync("this", SyncReplace)
+	if s.placeholder != "" {
+		attrs.Set("placeholder", s.placeholder)
+	}
+	if s.indicator != "" {
+		attrs.Set("hx-indicator", s.indicator)
+	}
+
+	c := NewComponent("templates/search.html").FS(searchTemplates)
+	c.AddData("ID", s.id)
+	c.AddData("Param", SearchParam)
+	c.AddData("Query", s.query)
+	c.AddData("Label", s.label)
+	c.AddData("Attributes", attrs.HTMLAttr())
+	c.AddData("Results", s.target == "")
+
+	return c
+}
+
+// NewSearchResults returns a component that renders the items found for the query, or a message that nothing was
+// found. The message can be changed with AddData("Empty", …).
+func NewSearchResults(query string, items ...RenderableComponent) *Component {
+	c := NewComponent("templates/search_results.html").FS(searchTemplates)
+	c.AddData("Query", query)
+	c.AddData("Empty", template.HTML(`No results for “`+template.HTMLEscapeString(query)+`”`))
+	withItems(c, items)
+
+	return c
+}
+
+// SearchQuery returns the trimmed search query of the request
+func SearchQuery(r *http.Request) string {
+	return strings.TrimSpace(r.FormValue(SearchParam))
+}
+
+// SearchHandler returns a handler that renders the results of the search query of the request
+//
+//	mux.Handle("GET /users/search", h.SearchHandler(func(r *http.Request, q string) (htmx.RenderableComponent, error) {
+//		var items []htmx.RenderableComponent
+//		for _, u := range users.Search(r.Context(), q) {
+//			items = append(items, htmx.NewComponent("user.html").AddData("User", u))
+//		}
+//		return htmx.NewSearchResults(q, items...), nil
+//	}))
+func (h *HTMX) SearchHandler(results SearchResultsFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := results(r, SearchQuery(r))
+		if err != nil {
+			h.log.Warn("unable to search", "query", SearchQuery(r), "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if _, err := h.NewHandler(w, r).Render(r.Context(), c); err != nil {
+			h.log.Warn("unable to render the search results", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSearch(t *testing.T) {
+	output, err := NewSearch("user-search", "/users/search").Label("Users").Placeholder("Search users").Query("ann").
+		Component().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div class="search">
+	<label for="user-search">Users</label>
+	<input id="user-search" type="search" name="q" value="ann" autocomplete="off" hx-get="/users/search" hx-trigger="input changed delay:300ms, search" hx-target="#user-search-results" hx-sync="this:replace" placeholder="Search users">
+	<div id="user-search-results" class="search-results" aria-live="polite"></div>
+</div>`, string(output))
+
+	output, err = NewSearch("s", "/search").Target("#main").Component().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<div class="search">
+	<input id="s" type="search" name="q" value="" autocomplete="off" hx-get="/search" hx-trigger="input changed delay:300ms, search" hx-target="#main" hx-sync="this:replace">
+</div>`, string(output))
+}
+
+func TestSearchHandler(t *testing.T) {
+	fsys := fstest.MapFS{"search-user.html": {Data: []byte(`<li>{{ .Data.Name }}</li>`)}}
+
+	handler := New().SearchHandler(func(_ *http.Request, q string) (RenderableComponent, error) {
+		if q == "fail" {
+			return nil, errors.New("search failed")
+		}
+
+		var items []RenderableComponent
+		if q == "ann" {
+			items = append(items, NewComponent("search-user.html").FS(fsys).AddData("Name", "Ann"))
+		}
+
+		return NewSearchResults(q, items...), nil
+	})
+
+	tests := []struct {
+		query    string
+		status   int
+		expected string
+	}{
+		{"+ann+", http.StatusOK, `<li>Ann</li>`},
+		{"bob", http.StatusOK, `<p class="search-empty">No results for “bob”</p>`},
+		{"", http.StatusOK, ``},
+		{"fail", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q="+tt.query, nil))
+
+		equalInt(t, tt.status, w.Code)
+		equal(t, tt.expected, w.Body.String())
+	}
+}
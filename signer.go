@@ -0,0 +1,87 @@
+package htmx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when a signed value has been tampered with or was signed with another key
+var ErrInvalidSignature = errors.New("htmx: invalid signature")
+
+// Signer signs values with HMAC-SHA256 so they can be handed to the client and verified when they come back,
+// e.g. upload tokens, flash messages or state kept in hidden fields. Each use signs with a signer derived for its
+// purpose, so a token signed for one purpose is rejected by the others.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner returns a signer for the key, the key should be at least 32 random bytes
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Derive returns a signer whose key is derived from the key of s and the purpose, tokens signed by it don't verify
+// with s or with signers derived for other purposes
+func (s *Signer) Derive(purpose string) *Signer {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte("htmx/signer/" + purpose))
+
+	return &Signer{key: mac.Sum(nil)}
+}
+
+// Sign returns the value with its signature appended, the value itself is not encrypted
+func (s *Signer) Sign(value string) string {
+	return value + "." + s.signature(value)
+}
+
+// Verify returns the value of a signed token
+func (s *Signer) Verify(token string) (string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return "", ErrInvalidSignature
+	}
+
+	value, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.signature(value))) {
+		return "", ErrInvalidSignature
+	}
+
+	return value, nil
+}
+
+// SignJSON encodes the value as json and returns it as a signed, url safe, token
+func (s *Signer) SignJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Sign(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// VerifyJSON verifies a token created with SignJSON and decodes its value into v
+func (s *Signer) VerifyJSON(token string, v any) error {
+	value, err := s.Verify(token)
+	if err != nil {
+		return err
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// signature returns the url safe signature of the value
+func (s *Signer) signature(value string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package htmx
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner([]byte("secret"))
+
+	value, err := s.Verify(s.Sign("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "hello", value)
+
+	if _, err := NewSigner([]byte("other")).Verify(s.Sign("hello")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	token, err := s.SignJSON(map[string]int{"n": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out map[string]int
+	if err := s.VerifyJSON(token, &out); err != nil {
+		t.Fatal(err)
+	}
+	equalInt(t, 1, out["n"])
+
+	if err := s.VerifyJSON(token+"x", &out); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestSignerDerive(t *testing.T) {
+	s := NewSigner([]byte("secret"))
+	flash, upload := s.Derive("flash"), s.Derive("upload")
+
+	value, err := flash.Verify(s.Derive("flash").Sign("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "hello", value)
+
+	for _, other := range []*Signer{s, upload, NewSigner([]byte("other")).Derive("flash")} {
+		if _, err := other.Verify(flash.Sign("hello")); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	}
+
+	if _, err := flash.Verify(s.Sign("hello")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
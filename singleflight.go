@@ -0,0 +1,54 @@
+package htmx
+
+import (
+	"context"
+	"html/template"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// renderGroup deduplicates the concurrent renders of components with the same Singleflight key
+var renderGroup singleflight.Group
+
+// Singleflight shares the render of the component with the concurrent renders of components with the same templates
+// and key: only one of them renders, the others wait for its output or error. It protects popular fragments that are
+// expensive to render from a thundering herd of requests.
+//
+//	htmx.NewComponent("leaderboard.html").Singleflight("leaderboard:" + season).AddData("Season", season)
+//
+// The output of one request is sent to all others, so the key must identify all the data the output depends on and
+// the fragment must not contain request specific data, like CSRF tokens, CSP nonces or the name of the user. Renders
+// of different tenants are never shared.
+func (c *Component) Singleflight(key string) *Component {
+	c.flightKey = key
+	return c
+}
+
+// renderShared renders the component once for all concurrent renders with the same flight key. The shared render
+// isn't canceled when the request that started it is, the others still need its output.
+func (c *Component) renderShared(ctx context.Context) (template.HTML, error) {
+	ctx, err := tenantContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tenant := ""
+	if t := renderTenant(ctx); t != nil {
+		tenant = t.Key
+	}
+	key := tenant + "\x00" + strings.Join(c.templates, "\x00") + "\x00" + c.flightKey
+
+	shared := context.WithoutCancel(ctx)
+	ch := renderGroup.DoChan(key, func() (any, error) {
+		return c.renderUnshared(shared)
+	})
+
+	select {
+	case res := <-ch:
+		output, _ := res.Val.(template.HTML)
+		return output, res.Err
+	case <-ctx.Done():
+		return "", renderCanceled(ctx)
+	}
+}
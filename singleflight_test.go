@@ -0,0 +1,83 @@
+package htmx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// blockingValue blocks its template until it is released
+type blockingValue struct {
+	calls   *atomic.Int32
+	release chan struct{}
+}
+
+func (b blockingValue) Value() string {
+	b.calls.Add(1)
+	<-b.release
+	return "scores"
+}
+
+func TestSingleflight(t *testing.T) {
+	fsys := fstest.MapFS{
+		"singleflight.html": {Data: []byte(`<ol>{{ .Data.Scores.Value }}</ol>`)},
+	}
+
+	scores := blockingValue{calls: &atomic.Int32{}, release: make(chan struct{})}
+
+	render := func() (string, error) {
+		out, err := NewComponent("singleflight.html").FS(fsys).Singleflight("leaderboard").
+			AddData("Scores", scores).Render(context.Background())
+		return string(out), err
+	}
+
+	var wg sync.WaitGroup
+	outputs := make([]string, 5)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		outputs[0], _ = render()
+	}()
+
+	for scores.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 1; i < len(outputs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], _ = render()
+		}()
+	}
+
+	// the renders join the render in flight
+	time.Sleep(50 * time.Millisecond)
+	close(scores.release)
+	wg.Wait()
+
+	equalInt(t, 1, int(scores.calls.Load()))
+	for _, out := range outputs {
+		equal(t, "<ol>scores</ol>", out)
+	}
+}
+
+func TestSingleflightCanceled(t *testing.T) {
+	fsys := fstest.MapFS{
+		"singleflight-canceled.html": {Data: []byte(`{{ .Data.Scores.Value }}`)},
+	}
+
+	scores := blockingValue{calls: &atomic.Int32{}, release: make(chan struct{})}
+	defer close(scores.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := NewComponent("singleflight-canceled.html").FS(fsys).Singleflight("canceled").
+		AddData("Scores", scores).Render(ctx)
+	equalBool(t, true, err == context.DeadlineExceeded)
+}
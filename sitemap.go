@@ -0,0 +1,259 @@
+package htmx
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SitemapMaxURLs is the number of urls of a sitemap file, the sitemap of a site with more urls is an index of files
+var SitemapMaxURLs = 50000
+
+// ChangeFreq is how frequently a page is likely to change, a hint for crawlers
+type ChangeFreq string
+
+const (
+	ChangeAlways  ChangeFreq = "always"
+	ChangeHourly  ChangeFreq = "hourly"
+	ChangeDaily   ChangeFreq = "daily"
+	ChangeWeekly  ChangeFreq = "weekly"
+	ChangeMonthly ChangeFreq = "monthly"
+	ChangeYearly  ChangeFreq = "yearly"
+	ChangeNever   ChangeFreq = "never"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type (
+	// SitemapURL is a url of a sitemap
+	SitemapURL struct {
+		Loc        string // the url of the page, root relative urls are resolved against the base url of the sitemap
+		LastMod    time.Time
+		ChangeFreq ChangeFreq
+		Priority   float64 // between 0 and 1, left out if 0
+	}
+
+	// Sitemap collects the urls of a site and renders them as sitemap.xml, see NewSitemap
+	Sitemap struct {
+		base string
+		htmx *HTMX
+		urls []SitemapURL
+	}
+
+	sitemapURLSet struct {
+		XMLName xml.Name         `xml:"urlset"`
+		NS      string           `xml:"xmlns,attr"`
+		URLs    []sitemapURLElem `xml:"url"`
+	}
+
+	sitemapURLElem struct {
+		Loc        string `xml:"loc"`
+		LastMod    string `xml:"lastmod,omitempty"`
+		ChangeFreq string `xml:"changefreq,omitempty"`
+		Priority   string `xml:"priority,omitempty"`
+	}
+
+	sitemapIndex struct {
+		XMLName  xml.Name           `xml:"sitemapindex"`
+		NS       string             `xml:"xmlns,attr"`
+		Sitemaps []sitemapIndexElem `xml:"sitemap"`
+	}
+
+	sitemapIndexElem struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod,omitempty"`
+	}
+)
+
+// NewSitemap returns a sitemap of the site at the base url, like https://example.com. Its routes render the sitemap
+// at /sitemap.xml, which is an index of the files /sitemaps/1.xml, /sitemaps/2.xml etc. when the site has more than
+// SitemapMaxURLs urls. Mounted, Generate writes the sitemap with the pages of the site.
+//
+//	sitemap := htmx.NewSitemap("https://example.com").Collect(h).Add(htmx.SitemapURL{Loc: "/legacy/"})
+//	h.Mount(mux, sitemap.Routes()...)
+func NewSitemap(baseURL string) *Sitemap {
+	return &Sitemap{base: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Collect lists the pages of the mounted routes of h: routes without wildcards and the static paths of routes with
+// wildcards, with the last modification time of their StaticPath and the change frequency of their route. Paginated
+// pages are listed with their first page, paths with an extension like /feed.xml and Unlisted routes are left out.
+func (s *Sitemap) Collect(h *HTMX) *Sitemap {
+	s.htmx = h
+	return s
+}
+
+// Add adds the urls to the sitemap, they take precedence over collected urls with the same location
+func (s *Sitemap) Add(urls ...SitemapURL) *Sitemap {
+	s.urls = append(s.urls, urls...)
+	return s
+}
+
+// Routes returns the Unlisted routes of /sitemap.xml and the files of a sitemap index, /sitemaps/{file}
+func (s *Sitemap) Routes() []*ComponentRoute {
+	return []*ComponentRoute{
+		Route("/sitemap.xml", s.component).Unlisted(),
+		Route("/sitemaps/{file}", s.component).Unlisted().Static(s.staticFiles),
+	}
+}
+
+// URLs returns the urls of the sitemap with absolute locations, the collected urls first
+func (s *Sitemap) URLs(ctx context.Context) ([]SitemapURL, error) {
+	var urls []SitemapURL
+	if s.htmx != nil {
+		s.htmx.mu.RLock()
+		routes := slices.Clone(s.htmx.routes)
+		s.htmx.mu.RUnlock()
+
+		for _, rt := range routes {
+			if rt.unlisted {
+				continue
+			}
+
+			pages, err := rt.staticPageList(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, p := range pages {
+				if strings.Contains(p.url, "?") || path.Ext(p.url) != "" {
+					continue
+				}
+				urls = append(urls, SitemapURL{Loc: p.url, LastMod: p.path.LastMod, ChangeFreq: rt.changeFreq})
+			}
+		}
+	}
+
+	index := make(map[string]int)
+	result := make([]SitemapURL, 0, len(urls)+len(s.urls))
+	for _, u := range append(urls, s.urls...) {
+		u.Loc = s.resolve(u.Loc)
+		if i, ok := index[u.Loc]; ok {
+			result[i] = u
+			continue
+		}
+		index[u.Loc] = len(result)
+		result = append(result, u)
+	}
+
+	return result, nil
+}
+
+// component returns the component of the sitemap routes, the index without a file parameter
+func (s *Sitemap) component() RenderableComponent {
+	c := NewComponent()
+	c.contentType = "application/xml; charset=utf-8"
+	c.writer = func(ctx context.Context, w io.Writer) error {
+		urls, err := s.URLs(ctx)
+		if err != nil {
+			return err
+		}
+
+		params, _ := c.data()[ParamsKey].(map[string]string)
+		file, ok := params["file"]
+		if !ok {
+			return writeSitemap(w, s.index(urls))
+		}
+
+		n, err := strconv.Atoi(strings.TrimSuffix(file, ".xml"))
+		parts := sitemapParts(urls)
+		if err != nil || !strings.HasSuffix(file, ".xml") || parts < 2 || n < 1 || n > parts {
+			return NewHTTPError(http.StatusNotFound, "The sitemap doesn't exist", nil)
+		}
+
+		return writeSitemap(w, urlSet(urls[(n-1)*SitemapMaxURLs:min(n*SitemapMaxURLs, len(urls))]))
+	}
+
+	return c
+}
+
+// index returns the url set of the urls, or the index of their files if they don't fit into one
+func (s *Sitemap) index(urls []SitemapURL) any {
+	parts := sitemapParts(urls)
+	if parts < 2 {
+		return urlSet(urls)
+	}
+
+	index := sitemapIndex{NS: sitemapNS}
+	for n := 1; n <= parts; n++ {
+		var lastMod time.Time
+		for _, u := range urls[(n-1)*SitemapMaxURLs : min(n*SitemapMaxURLs, len(urls))] {
+			if u.LastMod.After(lastMod) {
+				lastMod = u.LastMod
+			}
+		}
+
+		elem := sitemapIndexElem{Loc: s.resolve("/sitemaps/" + strconv.Itoa(n) + ".xml")}
+		if !lastMod.IsZero() {
+			elem.LastMod = lastMod.Format(time.RFC3339)
+		}
+		index.Sitemaps = append(index.Sitemaps, elem)
+	}
+
+	return index
+}
+
+// staticFiles returns the files of the sitemap index for Generate, none if the urls fit into sitemap.xml
+func (s *Sitemap) staticFiles(ctx context.Context) ([]StaticPath, error) {
+	urls, err := s.URLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := sitemapParts(urls)
+	if parts < 2 {
+		return nil, nil
+	}
+
+	paths := make([]StaticPath, parts)
+	for i := range paths {
+		paths[i] = StaticPath{Params: map[string]string{"file": strconv.Itoa(i+1) + ".xml"}}
+	}
+
+	return paths, nil
+}
+
+// resolve returns the root relative location as an absolute url of the site
+func (s *Sitemap) resolve(loc string) string {
+	if strings.HasPrefix(loc, "/") && !strings.HasPrefix(loc, "//") {
+		return s.base + loc
+	}
+
+	return loc
+}
+
+// sitemapParts returns the number of files the urls are split into
+func sitemapParts(urls []SitemapURL) int {
+	return (len(urls) + SitemapMaxURLs - 1) / SitemapMaxURLs
+}
+
+// urlSet returns the url set of the urls
+func urlSet(urls []SitemapURL) sitemapURLSet {
+	set := sitemapURLSet{NS: sitemapNS, URLs: make([]sitemapURLElem, len(urls))}
+	for i, u := range urls {
+		set.URLs[i] = sitemapURLElem{Loc: u.Loc, ChangeFreq: string(u.ChangeFreq)}
+		if !u.LastMod.IsZero() {
+			set.URLs[i].LastMod = u.LastMod.Format(time.RFC3339)
+		}
+		if u.Priority > 0 {
+			set.URLs[i].Priority = strconv.FormatFloat(u.Priority, 'f', -1, 64)
+		}
+	}
+
+	return set
+}
+
+// writeSitemap writes the url set or index as XML
+func writeSitemap(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,80 @@
+package htmx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSitemap(t *testing.T) {
+	page := func() RenderableComponent {
+		return NewWriterComponent(func(_ context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "<p>page</p>")
+			return err
+		})
+	}
+	modified := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	h, mux := New(), http.NewServeMux()
+	h.Mount(mux,
+		Route("/{$}", page).ChangeFreq(ChangeDaily),
+		Route("/docs/{slug}", page).Static(func(context.Context) ([]StaticPath, error) {
+			return []StaticPath{{Params: map[string]string{"slug": "intro"}, LastMod: modified}}, nil
+		}),
+		Route("/users/{id}", page),
+		Route("/admin", page).Unlisted(),
+		Route("/blog", page).Paginate(func(context.Context, map[string]string) (int, error) { return 3, nil }),
+		Route("/feed.xml", page),
+	)
+
+	sitemap := NewSitemap("https://example.com/").Collect(h).Add(
+		SitemapURL{Loc: "/blog", Priority: 0.8},
+		SitemapURL{Loc: "https://docs.example.com/"},
+	)
+	h.Mount(mux, sitemap.Routes()...)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		return w
+	}
+
+	w := get("/sitemap.xml")
+	equalInt(t, http.StatusOK, w.Code)
+	equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+		`<url><loc>https://example.com/</loc><changefreq>daily</changefreq></url>`+
+		`<url><loc>https://example.com/docs/intro</loc><lastmod>2024-05-01T00:00:00Z</lastmod></url>`+
+		`<url><loc>https://example.com/blog</loc><priority>0.8</priority></url>`+
+		`<url><loc>https://docs.example.com/</loc></url></urlset>`, w.Body.String())
+	equalInt(t, http.StatusNotFound, get("/sitemaps/1.xml").Code)
+
+	limit := SitemapMaxURLs
+	SitemapMaxURLs = 3
+	t.Cleanup(func() { SitemapMaxURLs = limit })
+
+	index := get("/sitemap.xml").Body.String()
+	equalBool(t, true, strings.Contains(index, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+		`<sitemap><loc>https://example.com/sitemaps/1.xml</loc><lastmod>2024-05-01T00:00:00Z</lastmod></sitemap>`+
+		`<sitemap><loc>https://example.com/sitemaps/2.xml</loc></sitemap></sitemapindex>`))
+	equalBool(t, true, strings.Contains(get("/sitemaps/2.xml").Body.String(), `<url><loc>https://docs.example.com/</loc></url></urlset>`))
+	equalInt(t, http.StatusNotFound, get("/sitemaps/3.xml").Code)
+
+	dir := t.TempDir()
+	if _, err := h.Generate(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sitemap.xml", "sitemaps/1.xml", "sitemaps/2.xml"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		equalBool(t, true, strings.HasPrefix(string(b), `<?xml`))
+	}
+}
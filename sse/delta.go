@@ -0,0 +1,169 @@
+package sse
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Delta computes the difference between consecutive renders of a fragment that is pushed over sse.
+// Instead of the complete fragment, only the children of the root element that changed since the last push are sent
+// as out-of-band swaps. This drastically reduces the bandwidth of high-frequency widgets like tickers and logs.
+//
+// The children of the root element must have an id, fragments that can't be diffed are sent as a whole.
+type Delta struct {
+	mu   sync.Mutex
+	last map[string]*deltaState
+}
+
+type deltaState struct {
+	ids      []string
+	children map[string]string
+}
+
+// NewDelta returns a new delta encoder
+func NewDelta() *Delta {
+	return &Delta{
+		last: make(map[string]*deltaState),
+	}
+}
+
+// Diff returns the out-of-band swaps for the children of the fragment that changed since the last push for the key,
+// the key is usually a topic, or a client and topic combination. The full fragment is returned for the first push of a key,
+// when children were added or when the fragment can't be diffed. An empty string is returned when nothing changed.
+func (d *Delta) Diff(key, fragment string) (string, error) {
+	state, nodes, err := parseDeltaState(fragment)
+	if err != nil {
+		return "", err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prev, ok := d.last[key]
+	if state == nil {
+		delete(d.last, key)
+		return fragment, nil
+	}
+
+	d.last[key] = state
+	if !ok {
+		return fragment, nil
+	}
+
+	var out strings.Builder
+	for _, id := range state.ids {
+		old, existed := prev.children[id]
+		if !existed {
+			// oob swaps can't insert elements at a position, so the fragment is sent as a whole
+			return fragment, nil
+		}
+
+		if old == state.children[id] {
+			continue
+		}
+
+		n := nodes[id]
+		n.Attr = append(n.Attr, html.Attribute{Key: "hx-swap-oob", Val: "true"})
+		if err := html.Render(&out, n); err != nil {
+			return "", err
+		}
+	}
+
+	for _, id := range prev.ids {
+		if _, ok := state.children[id]; !ok {
+			out.WriteString(`<div id="` + html.EscapeString(id) + `" hx-swap-oob="delete"></div>`)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// Message returns the message to send for the fragment, nil is returned when nothing changed
+func (d *Delta) Message(key, fragment string) (*Message, error) {
+	data, err := d.Diff(key, fragment)
+	if err != nil || data == "" {
+		return nil, err
+	}
+
+	return NewMessage(data), nil
+}
+
+// Forget removes the last pushed render of the key, the next push sends the full fragment
+func (d *Delta) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.last, key)
+}
+
+// parseDeltaState parses the children of the single root element of the fragment,
+// nil is returned when the fragment can't be diffed.
+func parseDeltaState(fragment string) (*deltaState, map[string]*html.Node, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var root *html.Node
+	for _, n := range nodes {
+		switch {
+		case n.Type == html.ElementNode && root == nil:
+			root = n
+		case n.Type == html.ElementNode, n.Type == html.TextNode && strings.TrimSpace(n.Data) != "":
+			return nil, nil, nil
+		}
+	}
+
+	if root == nil {
+		return nil, nil, nil
+	}
+
+	state := &deltaState{children: make(map[string]string)}
+	byID := make(map[string]*html.Node)
+
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) == "" {
+			continue
+		}
+
+		id := attr(c, "id")
+		if c.Type != html.ElementNode || id == "" {
+			return nil, nil, nil
+		}
+
+		if _, ok := state.children[id]; ok {
+			return nil, nil, errors.New("duplicate id " + id + " in fragment")
+		}
+
+		var buf bytes.Buffer
+		if err := html.Render(&buf, c); err != nil {
+			return nil, nil, err
+		}
+
+		state.ids = append(state.ids, id)
+		state.children[id] = buf.String()
+		byID[id] = c
+	}
+
+	return state, byID, nil
+}
+
+// attr returns the value of the attribute of the node
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
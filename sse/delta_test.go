@@ -0,0 +1,58 @@
+package sse
+
+import "testing"
+
+func TestDelta(t *testing.T) {
+	d := NewDelta()
+
+	first := `<ul id="ticker"><li id="a">1</li><li id="b">2</li><li id="c">3</li></ul>`
+	out, err := d.Diff("ticker", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != first {
+		t.Fatalf("expected the full fragment on the first push, got %q", out)
+	}
+
+	out, err = d.Diff("ticker", `<ul id="ticker"><li id="a">1</li><li id="b">5</li></ul>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<li id="b" hx-swap-oob="true">5</li><div id="c" hx-swap-oob="delete"></div>`
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	msg, err := d.Message("ticker", `<ul id="ticker"><li id="a">1</li><li id="b">5</li></ul>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg != nil {
+		t.Fatalf("expected no message when nothing changed, got %q", msg.String())
+	}
+
+	// added children can't be positioned with oob swaps
+	added := `<ul id="ticker"><li id="a">1</li><li id="d">4</li><li id="b">5</li></ul>`
+	if out, _ = d.Diff("ticker", added); out != added {
+		t.Fatalf("expected the full fragment when children are added, got %q", out)
+	}
+
+	d.Forget("ticker")
+	if out, _ = d.Diff("ticker", added); out != added {
+		t.Fatalf("expected the full fragment after forget, got %q", out)
+	}
+}
+
+func TestDeltaMessageMultiline(t *testing.T) {
+	d := NewDelta()
+
+	msg, err := d.Message("list", "<ul id=\"list\">\n<li id=\"a\">1</li>\r\n</ul>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "data: <ul id=\"list\">\ndata: <li id=\"a\">1</li>\ndata: </ul>\n\n"
+	if got := msg.String(); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
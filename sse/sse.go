@@ -50,6 +50,7 @@ func (c *Client) Chan() chan Envelope { return c.ch }
 
 // Message represents a simple message implementation.
 type Message struct {
+	ID    string
 	Event string
 	Time  time.Time
 	Data  string
@@ -67,12 +68,18 @@ func NewMessage(data string) *Message {
 func (m *Message) String() string {
 	sb := strings.Builder{}
 
+	if m.ID != "" {
+		sb.WriteString(fmt.Sprintf("id: %s\n", m.ID))
+	}
 	if m.Event != "" {
 		sb.WriteString(fmt.Sprintf("event: %s\n", m.Event))
 	}
-	sb.WriteString(fmt.Sprintf("data: %v\n\n", m.Data))
-
-	fmt.Println(sb.String())
+	// every line of the data needs its own data field, a newline would end the field early
+	data := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(m.Data)
+	for _, line := range strings.Split(data, "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+	sb.WriteString("\n")
 
 	return sb.String()
 }
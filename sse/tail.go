@@ -0,0 +1,262 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// TailOptions configures a Tail
+	TailOptions struct {
+		Target        string        // id of the element the lines are appended to
+		Event         string        // name of the sse event, defaults to "tail"
+		MaxLines      int           // maximum number of lines kept in the DOM and in memory, defaults to 1000
+		BatchSize     int           // maximum number of lines sent in a single event, defaults to 100
+		FlushInterval time.Duration // time lines are collected before they are sent, defaults to 100ms
+		PruneInterval time.Duration // interval of the swaps removing lines above MaxLines, defaults to 5s
+	}
+
+	// Tail streams appended lines, like a log or console output, to clients over sse.
+	// Lines are batched and appended to the target with out-of-band swaps, lines above MaxLines are periodically
+	// removed from the DOM. Every event carries the number of the last line as id, so reconnecting clients
+	// only receive the lines they missed. Writers never block on slow clients, a client that falls behind more than
+	// MaxLines lines receives the retained lines as a replacement of the target instead.
+	//
+	//	<div id="log" hx-ext="sse" sse-connect="/log" sse-swap="tail" hx-swap="none"></div>
+	Tail struct {
+		opts TailOptions
+
+		mu      sync.Mutex
+		lines   []tailLine
+		seq     uint64
+		waiting map[chan struct{}]struct{}
+	}
+
+	tailLine struct {
+		seq  uint64
+		text string
+	}
+)
+
+// NewTail returns a new tail for the given options
+func NewTail(opts TailOptions) *Tail {
+	if opts.Event == "" {
+		opts.Event = "tail"
+	}
+
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = 1000
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 100 * time.Millisecond
+	}
+
+	if opts.PruneInterval <= 0 {
+		opts.PruneInterval = 5 * time.Second
+	}
+
+	return &Tail{
+		opts:    opts,
+		waiting: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Append appends lines to the tail and notifies the connected clients
+func (t *Tail) Append(lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, line := range lines {
+		t.seq++
+		t.lines = append(t.lines, tailLine{seq: t.seq, text: line})
+	}
+
+	if len(t.lines) > t.opts.MaxLines {
+		t.lines = append(t.lines[:0:0], t.lines[len(t.lines)-t.opts.MaxLines:]...)
+	}
+
+	for ch := range t.waiting {
+		// the channels are buffered, a pending notification is enough for a client to catch up
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ReadFrom appends every line read from r until it returns an error or io.EOF
+func (t *Tail) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		n += int64(len(scanner.Bytes()) + 1)
+		t.Append(scanner.Text())
+	}
+
+	return n, scanner.Err()
+}
+
+// Consume appends the lines received from the channel until it is closed or the context is done
+func (t *Tail) Consume(ctx context.Context, lines <-chan string) {
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			t.Append(line)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ServeHTTP streams the lines to the client, starting after the Last-Event-ID header when the client reconnects
+func (t *Tail) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	notify := make(chan struct{}, 1)
+	t.mu.Lock()
+	t.waiting[notify] = struct{}{}
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.waiting, notify)
+		t.mu.Unlock()
+	}()
+
+	// the number of the last line the client received, and the first line that is still in its DOM
+	last, reset := uint64(0), true
+	if id, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+		last, reset = id, false
+	}
+	first := last + 1
+
+	prune := time.NewTicker(t.opts.PruneInterval)
+	defer prune.Stop()
+
+	notify <- struct{}{}
+	for {
+		select {
+		case <-notify:
+			// collect lines for the flush interval before sending them
+			select {
+			case <-time.After(t.opts.FlushInterval):
+			case <-r.Context().Done():
+				return
+			}
+
+			for {
+				msg, more := t.next(&last, &first, reset)
+				reset = false
+				if msg == nil {
+					break
+				}
+
+				if err := t.write(w, msg); err != nil {
+					return
+				}
+
+				if !more {
+					break
+				}
+			}
+
+		case <-prune.C:
+			if last >= uint64(t.opts.MaxLines) && first <= last-uint64(t.opts.MaxLines) {
+				var sb strings.Builder
+				for seq := first; seq <= last-uint64(t.opts.MaxLines); seq++ {
+					fmt.Fprintf(&sb, `<div id="%s" hx-swap-oob="delete"></div>`, t.lineID(seq))
+				}
+				first = last - uint64(t.opts.MaxLines) + 1
+
+				msg := NewMessage(sb.String())
+				msg.ID = strconv.FormatUint(last, 10)
+				msg.Event = t.opts.Event
+				if err := t.write(w, msg); err != nil {
+					return
+				}
+			}
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// next returns the message with the next batch of lines after last, the content of the target is replaced
+// when reset is set or when the client fell behind the retained lines.
+func (t *Tail) next(last, first *uint64, reset bool) (*Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.lines) == 0 || t.seq <= *last && !reset {
+		return nil, false
+	}
+
+	swap, start := "beforeend", 0
+	if reset || *last+1 < t.lines[0].seq || *last > t.seq {
+		swap = "innerHTML"
+		*first = t.lines[0].seq
+	} else {
+		start = int(*last + 1 - t.lines[0].seq)
+	}
+
+	end := min(len(t.lines), start+t.opts.BatchSize)
+	batch := t.lines[start:end]
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<div id="%s" hx-swap-oob="%s">`, html.EscapeString(t.opts.Target), swap)
+	for _, line := range batch {
+		fmt.Fprintf(&sb, `<div id="%s">%s</div>`, t.lineID(line.seq), html.EscapeString(line.text))
+	}
+	sb.WriteString(`</div>`)
+
+	*last = batch[len(batch)-1].seq
+
+	msg := NewMessage(sb.String())
+	msg.ID = strconv.FormatUint(*last, 10)
+	msg.Event = t.opts.Event
+
+	return msg, end < len(t.lines)
+}
+
+// write writes and flushes the message
+func (t *Tail) write(w http.ResponseWriter, msg *Message) error {
+	if _, err := io.WriteString(w, msg.String()); err != nil {
+		return err
+	}
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// lineID returns the element id of a line
+func (t *Tail) lineID(seq uint64) string {
+	return html.EscapeString(t.opts.Target) + "-" + strconv.FormatUint(seq, 10)
+}
@@ -0,0 +1,46 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTail(t *testing.T) {
+	tail := NewTail(TailOptions{Target: "log", MaxLines: 3, BatchSize: 2, FlushInterval: time.Millisecond})
+	_, _ = tail.ReadFrom(strings.NewReader("one\ntwo\nthree\n<four>\n"))
+
+	serve := func(lastEventID string) string {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		r := httptest.NewRequest(http.MethodGet, "/log", nil).WithContext(ctx)
+		if lastEventID != "" {
+			r.Header.Set("Last-Event-ID", lastEventID)
+		}
+
+		w := httptest.NewRecorder()
+		tail.ServeHTTP(w, r)
+
+		return w.Body.String()
+	}
+
+	// new clients receive the retained lines as a replacement of the target
+	expected := "id: 3\nevent: tail\ndata: " +
+		`<div id="log" hx-swap-oob="innerHTML"><div id="log-2">two</div><div id="log-3">three</div></div>` + "\n\n" +
+		"id: 4\nevent: tail\ndata: " +
+		`<div id="log" hx-swap-oob="beforeend"><div id="log-4">&lt;four&gt;</div></div>` + "\n\n"
+	if got := serve(""); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// reconnecting clients only receive the lines they missed
+	expected = "id: 4\nevent: tail\ndata: " +
+		`<div id="log" hx-swap-oob="beforeend"><div id="log-4">&lt;four&gt;</div></div>` + "\n\n"
+	if got := serve("3"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,325 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+type (
+	// StaticPaths returns the pages of a route with wildcards that are generated
+	StaticPaths func(ctx context.Context) ([]StaticPath, error)
+
+	// StaticPath is a generated page of a route, its path parameters and the data that is added to its component
+	StaticPath struct {
+		Params  map[string]string
+		Data    map[string]any
+		LastMod time.Time // the time the page was last modified, listed in sitemaps
+	}
+
+	// StaticPages returns the number of pages of the page of a route with the path parameters, see Paginate
+	StaticPages func(ctx context.Context, params map[string]string) (int, error)
+
+	// GeneratedFile is a file written by Generate
+	GeneratedFile struct {
+		URL  string // the url the file was rendered for
+		File string // the path of the file, relative to the output directory
+		Size int    // the size of the file in bytes
+	}
+
+	// staticPage is a page of a route to generate
+	staticPage struct {
+		route *ComponentRoute
+		path  StaticPath
+		url   string // the path of the page with the page parameter of paginated pages
+		file  string // the file of the page, relative to the output directory
+	}
+
+	// staticResponse records the response of a generated page
+	staticResponse struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+
+	staticDataKey struct{}
+)
+
+// Static sets the pages that Generate renders for a route with wildcards, routes with wildcards and without static
+// paths are not generated. The data of a path is added to the page component, next to the path parameters.
+//
+//	htmx.Route("/docs/{slug}", DocPage).Static(func(ctx context.Context) ([]htmx.StaticPath, error) {
+//		var paths []htmx.StaticPath
+//		for _, doc := range docs.All(ctx) {
+//			paths = append(paths, htmx.StaticPath{Params: map[string]string{"slug": doc.Slug}, Data: map[string]any{"Doc": doc}})
+//		}
+//		return paths, nil
+//	})
+func (rt *ComponentRoute) Static(paths StaticPaths) *ComponentRoute {
+	rt.staticPaths = paths
+	return rt
+}
+
+// Paginate sets the number of pages that Generate renders for the page of a route, the pages after the first are
+// rendered with the page query parameter, see PaginatorFromRequest, into page/2/index.html etc.
+func (rt *ComponentRoute) Paginate(pages StaticPages) *ComponentRoute {
+	rt.staticPages = pages
+	return rt
+}
+
+// Generate renders the pages of the mounted routes, as full page loads, to static files in the directory: /docs/intro
+// is written to docs/intro/index.html, paths with an extension like /feed.xml to the path. The root relative urls of
+// links, sources and htmx requests in the generated html are rewritten to relative urls, so the site can be served
+// from any directory. Links to generated pages point to their directory, e.g. ../intro/, and keep their fragment.
+//
+//	h.Mount(mux, htmx.Route("/blog", BlogPage).Layout("main").Paginate(postPages))
+//	files, err := h.Generate(ctx, "public")
+func (h *HTMX) Generate(ctx context.Context, dir string) ([]GeneratedFile, error) {
+	h.mu.RLock()
+	routes := slices.Clone(h.routes)
+	h.mu.RUnlock()
+
+	var pages []staticPage
+	for _, rt := range routes {
+		routePages, err := rt.staticPageList(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", rt.pattern, err)
+		}
+		pages = append(pages, routePages...)
+	}
+
+	files := make(map[string]string, len(pages))
+	for _, p := range pages {
+		files[p.url] = p.file
+	}
+
+	generated := make([]GeneratedFile, 0, len(pages))
+	for _, p := range pages {
+		if err := ctx.Err(); err != nil {
+			return generated, err
+		}
+
+		body, err := h.renderStatic(ctx, p, files)
+		if err != nil {
+			return generated, fmt.Errorf("%s: %w", p.url, err)
+		}
+
+		name := filepath.Join(dir, filepath.FromSlash(p.file))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return generated, err
+		}
+		if err := os.WriteFile(name, body, 0o644); err != nil {
+			return generated, err
+		}
+
+		generated = append(generated, GeneratedFile{URL: p.url, File: p.file, Size: len(body)})
+	}
+
+	return generated, nil
+}
+
+// staticPageList returns the pages of the route to generate
+func (rt *ComponentRoute) staticPageList(ctx context.Context) ([]staticPage, error) {
+	paths := []StaticPath{{}}
+	switch {
+	case rt.staticPaths != nil:
+		var err error
+		if paths, err = rt.staticPaths(ctx); err != nil {
+			return nil, err
+		}
+	case len(patternParams(rt.pattern)) > 0:
+		return nil, nil
+	}
+
+	var pages []staticPage
+	for _, static := range paths {
+		p, err := fillPattern(rt.pattern, static.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		count := 1
+		if rt.staticPages != nil {
+			if count, err = rt.staticPages(ctx, static.Params); err != nil {
+				return nil, err
+			}
+		}
+
+		for n := 1; n <= max(count, 1); n++ {
+			page := staticPage{route: rt, path: static, url: p, file: staticFile(p)}
+			if n > 1 {
+				page.url = staticPageURL(p, n)
+				page.file = path.Join(path.Dir(staticFile(p)), "page", strconv.Itoa(n), "index.html")
+			}
+			pages = append(pages, page)
+		}
+	}
+
+	return pages, nil
+}
+
+// renderStatic renders the page as a full page load and rewrites the urls of html responses
+func (h *HTMX) renderStatic(ctx context.Context, p staticPage, files map[string]string) ([]byte, error) {
+	r, err := http.NewRequestWithContext(context.WithValue(ctx, staticDataKey{}, p.path.Data), http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range p.path.Params {
+		r.SetPathValue(key, value)
+	}
+
+	w := &staticResponse{header: make(http.Header), status: http.StatusOK}
+	p.route.handler(h).ServeHTTP(w, r)
+
+	if w.status != http.StatusOK {
+		return nil, fmt.Errorf("status %d", w.status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(w.header.Get("Content-Type"))
+	if mediaType != "" && mediaType != "text/html" {
+		return w.body.Bytes(), nil
+	}
+
+	output, err := NewPostProcessor(relativeURLs(p.file, files)).Process(template.HTML(w.body.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(output), nil
+}
+
+// relativeURLs rewrites the root relative urls of the page in the file to urls relative to the file, generated pages
+// are linked by their directory
+func relativeURLs(file string, files map[string]string) Transform {
+	from := path.Dir("/" + file)
+
+	return func(n *html.Node) {
+		for i, attr := range n.Attr {
+			if attr.Namespace != "" || !slices.Contains(urlAttributes, attr.Key) {
+				continue
+			}
+
+			value := strings.TrimSpace(attr.Val)
+			if !strings.HasPrefix(value, "/") || strings.HasPrefix(value, "//") {
+				continue
+			}
+
+			u, err := url.Parse(value)
+			if err != nil {
+				continue
+			}
+
+			key := u.Path
+			query := u.Query()
+			if page, err := strconv.Atoi(query.Get(PageParam)); err == nil && query.Has(PageParam) {
+				candidate := key
+				if page > 1 {
+					candidate = staticPageURL(key, page)
+				}
+				if _, ok := files[candidate]; ok {
+					key = candidate
+					query.Del(PageParam)
+					u.RawQuery = query.Encode()
+				}
+			}
+
+			target, directory := u.Path, false
+			if file, ok := files[key]; ok {
+				target = "/" + file
+				if path.Base(file) == "index.html" {
+					target, directory = path.Dir(target), true
+				}
+			}
+
+			rel, err := filepath.Rel(from, target)
+			if err != nil {
+				continue
+			}
+
+			rel = filepath.ToSlash(rel)
+			if directory {
+				rel += "/"
+			}
+
+			n.Attr[i].Val = (&url.URL{Path: rel, RawQuery: u.RawQuery, Fragment: u.Fragment}).String()
+		}
+	}
+}
+
+// fillPattern returns the path of the pattern with its wildcards replaced by the parameters
+func fillPattern(pattern string, params map[string]string) (string, error) {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+		if name == "$" {
+			segments[i] = ""
+			continue
+		}
+
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("missing path parameter %q", name)
+		}
+
+		if strings.HasSuffix(segment, "...}") {
+			segments[i] = value
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+// staticFile returns the file of the path, relative to the output directory
+func staticFile(p string) string {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if path.Ext(p) != "" {
+		return p
+	}
+
+	return path.Join(p, "index.html")
+}
+
+// staticPageURL returns the url of the page of a paginated path
+func staticPageURL(p string, page int) string {
+	return p + "?" + url.Values{PageParam: {strconv.Itoa(page)}}.Encode()
+}
+
+// Header returns the header of the response
+func (w *staticResponse) Header() http.Header {
+	return w.header
+}
+
+// Write writes to the body of the response
+func (w *staticResponse) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteHeader sets the status of the response
+func (w *staticResponse) WriteHeader(status int) {
+	w.status = status
+}
+
+// staticData returns the data of the static path that is generated with the request
+func staticData(ctx context.Context) map[string]any {
+	data, _ := ctx.Value(staticDataKey{}).(map[string]any)
+	return data
+}
@@ -0,0 +1,70 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static-main.html": {Data: []byte(`<link rel="stylesheet" href="/static/app.css"><a href="/">Home</a>{{ .Partials.content }}`)},
+		"static-home.html": {Data: []byte(`<a href="/docs/intro">Docs</a>`)},
+		"static-doc.html":  {Data: []byte(`<h1>{{ .Data.Params.slug }} {{ .Data.Title }}</h1><a href="/docs/install#top">Install</a>`)},
+		"static-blog.html": {Data: []byte(`<p>{{ query "page" }}</p><a href="{{ pageURL 1 }}">1</a><a href="{{ pageURL 3 }}">3</a>`)},
+	}
+
+	h := New()
+	h.RegisterComponent("main", func() RenderableComponent { return NewComponent("static-main.html").FS(fsys) })
+
+	h.Mount(http.NewServeMux(),
+		Route("/{$}", func() RenderableComponent { return NewComponent("static-home.html").FS(fsys) }).Layout("main"),
+		Route("/docs/{slug}", func() RenderableComponent { return NewComponent("static-doc.html").FS(fsys) }).
+			Layout("main").
+			Static(func(context.Context) ([]StaticPath, error) {
+				return []StaticPath{
+					{Params: map[string]string{"slug": "intro"}, Data: map[string]any{"Title": "Introduction"}},
+					{Params: map[string]string{"slug": "install"}},
+				}, nil
+			}),
+		Route("/users/{id}", func() RenderableComponent { return NewComponent("static-doc.html").FS(fsys) }),
+		Route("/blog", func() RenderableComponent { return NewComponent("static-blog.html").FS(fsys) }).Paginate(func(context.Context, map[string]string) (int, error) { return 3, nil }),
+	)
+
+	dir := t.TempDir()
+	files, err := h.Generate(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.File)
+	}
+	equal(t, "index.html docs/intro/index.html docs/install/index.html blog/index.html blog/page/2/index.html blog/page/3/index.html",
+		strings.Join(names, " "))
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	home := read("index.html")
+	equalBool(t, true, strings.Contains(home, `<a href="docs/intro/">Docs</a>`))
+	equalBool(t, true, strings.Contains(home, `href="static/app.css"`))
+
+	intro := read("docs/intro/index.html")
+	equalBool(t, true, strings.Contains(intro, `<a href="../../">Home</a><h1>intro Introduction</h1><a href="../install/#top">Install</a>`))
+	equalBool(t, true, strings.Contains(intro, `href="../../static/app.css"`))
+
+	page2 := read("blog/page/2/index.html")
+	equalBool(t, true, strings.Contains(page2, `<p>2</p>`))
+	equalBool(t, true, strings.Contains(page2, `<a href="../../">1</a><a href="../3/">3</a>`))
+}
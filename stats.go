@@ -0,0 +1,135 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+var (
+	// UseStats enables the collection of response statistics when rendering through the Handler.
+	UseStats = false
+
+	routeStats   = map[routeStatsKey]*RouteStats{}
+	routeStatsMu sync.Mutex
+)
+
+type (
+	// RouteStats holds the response statistics of a single route and template combination.
+	RouteStats struct {
+		Route           string // the route pattern, or the route label of WithRoute
+		Template        string // the first template of the rendered component
+		Responses       int64  // number of responses written
+		Bytes           int64  // total number of body bytes written
+		MaxBytes        int64  // largest body written
+		HeaderCount     int64  // total number of response headers written
+		MaxHeaderCount  int64  // largest number of response headers written
+		HeaderBytes     int64  // total size of the response headers
+		MaxHeaderBytes  int64  // largest size of the response headers
+		MaxTriggerBytes int64  // largest size of the combined HX-Trigger headers
+	}
+
+	routeStatsKey struct {
+		route    string
+		template string
+	}
+
+	routeKey struct{}
+)
+
+// WithRoute returns the request with the route label that statistics and the render journal use for routers that
+// don't set the Pattern of the request, like the route pattern of a third-party router
+//
+//	r = htmx.WithRoute(r, chi.RouteContext(r.Context()).RoutePattern())
+func WithRoute(r *http.Request, route string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, route))
+}
+
+// Stats returns a snapshot of the collected response statistics, sorted by route and template.
+func Stats() []RouteStats {
+	routeStatsMu.Lock()
+	defer routeStatsMu.Unlock()
+
+	out := make([]RouteStats, 0, len(routeStats))
+	for _, s := range routeStats {
+		out = append(out, *s)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Route == out[j].Route {
+			return out[i].Template < out[j].Template
+		}
+		return out[i].Route < out[j].Route
+	})
+
+	return out
+}
+
+// ResetStats clears all collected response statistics.
+func ResetStats() {
+	routeStatsMu.Lock()
+	defer routeStatsMu.Unlock()
+
+	routeStats = map[routeStatsKey]*RouteStats{}
+}
+
+// recordStats records the size of the response body and headers for the current route
+func (h *Handler) recordStats(r RenderableComponent, n int) {
+	if !UseStats {
+		return
+	}
+
+	// requests without a route are left out, keying them by their path would add an entry per url
+	key := routeStatsKey{
+		route: routeName(h.r),
+	}
+	if key.route == "" {
+		return
+	}
+
+	if files := r.templateFiles(); len(files) > 0 {
+		key.template = files[0]
+	}
+
+	var headerCount, headerBytes, triggerBytes int64
+	for name, values := range h.w.Header() {
+		for _, v := range values {
+			headerCount++
+			// name + ": " + value + "\r\n"
+			headerBytes += int64(len(name) + len(v) + 4)
+		}
+	}
+
+	for _, k := range []HxResponseKey{HXTrigger, HXTriggerAfterSettle, HXTriggerAfterSwap} {
+		triggerBytes += int64(len(h.response.Get(k)))
+	}
+
+	routeStatsMu.Lock()
+	defer routeStatsMu.Unlock()
+
+	s, ok := routeStats[key]
+	if !ok {
+		s = &RouteStats{Route: key.route, Template: key.template}
+		routeStats[key] = s
+	}
+
+	s.Responses++
+	s.Bytes += int64(n)
+	s.MaxBytes = max(s.MaxBytes, int64(n))
+	s.HeaderCount += headerCount
+	s.MaxHeaderCount = max(s.MaxHeaderCount, headerCount)
+	s.HeaderBytes += headerBytes
+	s.MaxHeaderBytes = max(s.MaxHeaderBytes, headerBytes)
+	s.MaxTriggerBytes = max(s.MaxTriggerBytes, triggerBytes)
+}
+
+// routeName returns the matched route pattern of the request, or its route label, see WithRoute
+func routeName(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+
+	route, _ := r.Context().Value(routeKey{}).(string)
+	return route
+}
@@ -0,0 +1,86 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStats(t *testing.T) {
+	UseStats = true
+	defer func() {
+		UseStats = false
+		ResetStats()
+	}()
+
+	fsys := fstest.MapFS{
+		"row.html": {Data: []byte(`<tr><td>{{ .Data.Name }}</td></tr>`)},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rows/{id}", func(w http.ResponseWriter, r *http.Request) {
+		h := New().NewHandler(w, r)
+		h.Trigger("rowLoaded")
+
+		c := NewComponent("row.html").FS(fsys)
+		c.AddData("Name", r.PathValue("id"))
+
+		if _, err := h.Render(context.Background(), c); err != nil {
+			t.Error(err)
+		}
+	})
+
+	for _, id := range []string{"1", "12"} {
+		r := httptest.NewRequest(http.MethodGet, "/rows/"+id, nil)
+		r.Header.Set("HX-Request", "true")
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	stats := Stats()
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(stats))
+	}
+
+	s := stats[0]
+	equal(t, "GET /rows/{id}", s.Route)
+	equal(t, "row.html", s.Template)
+	equalInt(t, 2, int(s.Responses))
+	equalInt(t, len("<tr><td>1</td></tr>")+len("<tr><td>12</td></tr>"), int(s.Bytes))
+	equalInt(t, len("<tr><td>12</td></tr>"), int(s.MaxBytes))
+	equalInt(t, len("rowLoaded"), int(s.MaxTriggerBytes))
+}
+
+func TestStatsRouteLabel(t *testing.T) {
+	UseStats = true
+	defer func() {
+		UseStats = false
+		ResetStats()
+	}()
+
+	fsys := fstest.MapFS{
+		"label-row.html": {Data: []byte(`<tr></tr>`)},
+	}
+
+	render := func(r *http.Request) {
+		if _, err := New().NewHandler(httptest.NewRecorder(), r).Render(context.Background(), NewComponent("label-row.html").FS(fsys)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// requests without a pattern or a label aren't recorded
+	for _, id := range []string{"1", "2", "3"} {
+		render(httptest.NewRequest(http.MethodGet, "/rows/"+id, nil))
+	}
+	equalInt(t, 0, len(Stats()))
+
+	for _, id := range []string{"1", "2"} {
+		render(WithRoute(httptest.NewRequest(http.MethodGet, "/rows/"+id, nil), "/rows/{id}"))
+	}
+
+	stats := Stats()
+	equalInt(t, 1, len(stats))
+	equal(t, "/rows/{id}", stats[0].Route)
+	equalInt(t, 2, int(stats[0].Responses))
+}
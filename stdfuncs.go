@@ -0,0 +1,235 @@
+package htmx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StdFuncs returns the opt-in standard template function library, use RegisterStdFuncs to make them available in every component.
+// The functions take the piped value as their last argument.
+//
+//	{{ .Name | default "anonymous" }}
+//	{{ .Description | truncate 80 }}
+//	{{ .CreatedAt | formatDate "2006-01-02" }}
+//	{{ .Total | formatNumber 2 }}
+//	{{ template "card" dict "Title" .Title "Items" (list 1 2 3) }}
+func StdFuncs() template.FuncMap {
+	return template.FuncMap{
+		"dict":         dict,
+		"list":         list,
+		"default":      defaultValue,
+		"coalesce":     coalesce,
+		"empty":        empty,
+		"json":         toJSON,
+		"truncate":     truncate,
+		"title":        title,
+		"upper":        strings.ToUpper,
+		"lower":        strings.ToLower,
+		"trim":         strings.TrimSpace,
+		"join":         join,
+		"formatDate":   formatDate,
+		"formatNumber": formatNumber,
+		"add":          func(a, b int) int { return a + b },
+		"sub":          func(a, b int) int { return a - b },
+	}
+}
+
+// RegisterStdFuncs adds the standard template function library to DefaultTemplateFuncs,
+// existing functions with the same name are not overwritten.
+func RegisterStdFuncs() {
+	for name, fn := range StdFuncs() {
+		if _, ok := DefaultTemplateFuncs[name]; !ok {
+			DefaultTemplateFuncs[name] = fn
+		}
+	}
+}
+
+// dict returns a map of the key value pairs, this is useful to pass multiple values to a template
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict requires an even number of arguments")
+	}
+
+	out := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		out[key] = pairs[i+1]
+	}
+
+	return out, nil
+}
+
+// list returns the arguments as a slice
+func list(items ...any) []any {
+	return items
+}
+
+// defaultValue returns the value, or def when the value is empty
+func defaultValue(def, value any) any {
+	if empty(value) {
+		return def
+	}
+
+	return value
+}
+
+// coalesce returns the first value that is not empty
+func coalesce(values ...any) any {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// empty returns true for nil, zero values and empty collections
+func empty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String, reflect.Chan:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// toJSON returns the value encoded as json
+func toJSON(value any) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// truncate shortens the string to at most n characters, an ellipsis is added when the string is truncated
+func truncate(n int, s string) string {
+	if n <= 0 {
+		return ""
+	}
+
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	return strings.TrimRightFunc(string(runes[:n-1]), unicode.IsSpace) + "…"
+}
+
+// title capitalizes the first letter of every word
+func title(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '-' {
+			runes[i] = unicode.ToTitle(r)
+		}
+	}
+
+	return string(runes)
+}
+
+// join joins the elements of a slice with the separator
+func join(sep string, items any) string {
+	switch v := items.(type) {
+	case []string:
+		return strings.Join(v, sep)
+	case nil:
+		return ""
+	}
+
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Sprint(items)
+	}
+
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// formatDate formats a time.Time, or a pointer to it, with the layout. Zero times are formatted as an empty string.
+func formatDate(layout string, value any) (string, error) {
+	var t time.Time
+
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v != nil {
+			t = *v
+		}
+	default:
+		return "", fmt.Errorf("formatDate: unsupported type %T", value)
+	}
+
+	if t.IsZero() {
+		return "", nil
+	}
+
+	return t.Format(layout), nil
+}
+
+// formatNumber formats a number with the given number of decimals and a comma as thousands separator
+func formatNumber(decimals int, value any) (string, error) {
+	var f float64
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = rv.Float()
+	default:
+		return "", fmt.Errorf("formatNumber: unsupported type %T", value)
+	}
+
+	s := strconv.FormatFloat(f, 'f', max(decimals, 0), 64)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var sb strings.Builder
+	sb.WriteString(sign)
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(r)
+	}
+
+	if frac != "" {
+		sb.WriteByte('.')
+		sb.WriteString(frac)
+	}
+
+	return sb.String(), nil
+}
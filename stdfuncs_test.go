@@ -0,0 +1,29 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestStdFuncs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"std.html": {Data: []byte(`{{ .Data.Name | default "anonymous" }}|{{ .Data.Text | truncate 8 }}|{{ .Data.Date | formatDate "2006-01-02" }}|` +
+			`{{ .Data.Total | formatNumber 2 }}|{{ "hello world" | title }}|{{ join ", " (list 1 2 3) }}|{{ with dict "a" 1 }}{{ .a }}{{ end }}|` +
+			`{{ coalesce "" .Data.Name "x" }}|{{ json (dict "b" true) }}`)},
+	}
+
+	out, err := NewComponent("std.html").FS(fsys).
+		AddTemplateFunctions(StdFuncs()).
+		AddData("Name", "").
+		AddData("Text", "a long piece of text").
+		AddData("Date", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)).
+		AddData("Total", -1234567.5).
+		Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `anonymous|a long…|2024-03-01|-1,234,567.50|Hello World|1, 2, 3|1|x|{&#34;b&#34;:true}`, string(out))
+}
@@ -6,13 +6,18 @@ import (
 	"time"
 )
 
+// AttrSwap specifies how the response is swapped relative to the target
+// https://htmx.org/attributes/hx-swap/
+const AttrSwap = "hx-swap"
+
 type Swap struct {
-	style       SwapStyle
-	transition  *bool
-	timing      *SwapTiming
-	scrolling   *SwapScrolling
-	ignoreTitle *bool
-	focusScroll *bool
+	style        SwapStyle
+	transition   *bool
+	swapTiming   *SwapTiming
+	settleTiming *SwapTiming
+	scrolling    *SwapScrolling
+	ignoreTitle  *bool
+	focusScroll  *bool
 }
 
 type SwapTiming struct {
@@ -20,16 +25,9 @@ type SwapTiming struct {
 	duration time.Duration
 }
 
+// String returns the modifier, the duration is formatted as htmx parses it, e.g. swap:1s or settle:1500ms
 func (s *SwapTiming) String() string {
-	var out string
-
-	out = string(s.mode)
-
-	if s.duration != 0 {
-		out += ":" + s.duration.String()
-	}
-
-	return out
+	return string(s.mode) + ":" + htmxDuration(s.duration)
 }
 
 type SwapScrolling struct {
@@ -54,11 +52,20 @@ func (s *SwapScrolling) String() string {
 	return out
 }
 
-// NewSwap returns a new Swap
-func NewSwap() *Swap {
-	return &Swap{
+// NewSwap returns a new Swap with the given style, or innerHTML when no style is given. A Swap can be used for the
+// HX-Reswap header and, as it is a fmt.Stringer, for hx-swap attributes.
+//
+//	htmx.NewSwap(htmx.SwapOuterHTML).Transition(true).Swap(500 * time.Millisecond).Settle()
+func NewSwap(style ...SwapStyle) *Swap {
+	s := &Swap{
 		style: SwapInnerHTML,
 	}
+
+	if len(style) > 0 {
+		s.style = style[0]
+	}
+
+	return s
 }
 
 // Style sets the style of the swap, default is innerHTML and can be changed in htmx.config.defaultSwapStyle
@@ -67,8 +74,12 @@ func (s *Swap) Style(style SwapStyle) *Swap {
 	return s
 }
 
-// setScrolling sets the scrolling behavior
+// setScrolling sets the scrolling behavior, htmx requires a direction so it defaults to the top
 func (s *Swap) setScrolling(mode SwapScrollingMode, direction SwapDirection, target ...string) *Swap {
+	if direction == "" {
+		direction = SwapDirectionTop
+	}
+
 	scrolling := &SwapScrolling{
 		mode:      mode,
 		direction: direction,
@@ -126,10 +137,16 @@ func (s *Swap) setTiming(mode SwapTimingMode, swap ...time.Duration) *Swap {
 		}
 	}
 
-	s.timing = &SwapTiming{
+	timing := &SwapTiming{
 		mode:     mode,
 		duration: duration,
 	}
+
+	if mode == TimingSettle {
+		s.settleTiming = timing
+	} else {
+		s.swapTiming = timing
+	}
 	return s
 }
 
@@ -184,13 +201,29 @@ func (s *Swap) String() string {
 		parts = append(parts, fmt.Sprintf("focus-scroll:%s", HxBoolToStr(*s.focusScroll)))
 	}
 
-	if s.timing != nil {
-		parts = append(parts, s.timing.String())
+	if s.swapTiming != nil {
+		parts = append(parts, s.swapTiming.String())
+	}
+
+	if s.settleTiming != nil {
+		parts = append(parts, s.settleTiming.String())
 	}
 
 	return strings.Join(parts, " ")
 }
 
+// Swap sets hx-swap to the swap
+func (a *Attributes) Swap(s *Swap) *Attributes {
+	return a.Set(AttrSwap, s.String())
+}
+
+// newSwapFunc returns a swap for the style, to be used in templates
+//
+//	<div hx-get="/items" hx-swap="{{ (swap "outerHTML").Transition true }}"></div>
+func newSwapFunc(style SwapStyle) *Swap {
+	return NewSwap(style)
+}
+
 const (
 	// SwapInnerHTML replaces the inner html of the target element
 	SwapInnerHTML SwapStyle = "innerHTML"
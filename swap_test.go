@@ -1,7 +1,9 @@
 package htmx
 
 import (
+	"context"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -42,8 +44,8 @@ func TestTimingSwap(t *testing.T) {
 	duration := 100 * time.Millisecond
 	swap := NewSwap().Swap(duration)
 
-	if swap.timing == nil || swap.timing.duration != duration {
-		t.Errorf("expected timing swap to be %v, got %v", duration, swap.timing.duration)
+	if swap.swapTiming == nil || swap.swapTiming.duration != duration {
+		t.Errorf("expected timing swap to be %v, got %v", duration, swap.swapTiming.duration)
 	}
 }
 
@@ -52,8 +54,8 @@ func TestTimingSettle(t *testing.T) {
 	duration := 200 * time.Millisecond
 	swap := NewSwap().Settle(duration)
 
-	if swap.timing == nil || swap.timing.duration != duration {
-		t.Errorf("expected timing settle to be %v, got %v", duration, swap.timing.duration)
+	if swap.settleTiming == nil || swap.settleTiming.duration != duration {
+		t.Errorf("expected timing settle to be %v, got %v", duration, swap.settleTiming.duration)
 	}
 }
 
@@ -151,3 +153,23 @@ func TestScrollingShowBottom(t *testing.T) {
 		t.Errorf("expected scrolling mode to be ScrollingShow, direction to be SwapDirectionBottom, and target to be %v, got mode: %v, direction: %v, target: %v", target, swap.scrolling.mode, swap.scrolling.direction, swap.scrolling.target)
 	}
 }
+
+func TestSwapBuilder(t *testing.T) {
+	swap := NewSwap(SwapOuterHTML).Transition(true).Swap(500 * time.Millisecond).Settle().ScrollTop()
+	equal(t, "outerHTML scroll:top transition:true swap:500ms settle:20ms", swap.String())
+
+	equal(t, `hx-swap="beforeend show:#list:bottom"`, NewAttributes().Swap(NewSwap(SwapBeforeEnd).ShowBottom("#list")).String())
+}
+
+func TestSwapTemplateFunc(t *testing.T) {
+	fsys := fstest.MapFS{
+		"list.html": {Data: []byte(`<ul hx-get="/items" hx-swap="{{ (swap "outerHTML").Transition true }}"></ul>`)},
+	}
+
+	output, err := NewComponent("list.html").FS(fsys).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<ul hx-get="/items" hx-swap="outerHTML transition:true"></ul>`, string(output))
+}
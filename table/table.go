@@ -0,0 +1,294 @@
+// Package table renders sortable, filterable data tables.
+//
+// The sort and filter state is kept in the query parameters of the page, so sorted and filtered tables can be
+// bookmarked and reloaded. Header cells of sortable columns link to the table sorted by their column, toggling the
+// direction, and filter inputs reload the rows while typing.
+//
+//	users := table.Must(table.Options{
+//		ID:       "users",
+//		Endpoint: "/users",
+//		Columns: []table.Column{
+//			{Name: "Name", SortKey: "name", Filter: "name", Cell: `{{ .Name }}`},
+//			{Name: "Email", SortKey: "email", Cell: `<a href="mailto:{{ .Email }}">{{ .Email }}</a>`},
+//		},
+//	})
+//
+//	func (a *App) Users(w http.ResponseWriter, r *http.Request) {
+//		state := users.State(r)
+//		rows := a.db.Users(state.Sort, state.Dir == table.Desc, state.Filters["name"])
+//
+//		c, err := users.Component(state, rows)
+//		...
+//	}
+package table
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"github.com/jkc-2/go-htmx"
+)
+
+//go:embed templates
+var templates embed.FS
+
+const (
+	// Asc sorts in ascending order
+	Asc Direction = "asc"
+
+	// Desc sorts in descending order
+	Desc Direction = "desc"
+)
+
+var (
+	// SortParam is the query parameter of the sort key
+	SortParam = "sort"
+
+	// DirParam is the query parameter of the sort direction
+	DirParam = "dir"
+)
+
+type (
+	// Direction is the direction of a sort
+	Direction string
+
+	// Column describes a column of the table
+	Column struct {
+		Name    string // header of the column
+		SortKey string // key the column is sorted by, columns without a key can't be sorted
+		Filter  string // query parameter of the filter input of the column, columns without one have no filter
+		Cell    string // template of the cell, it is executed with the row, e.g. {{ .Email }}
+	}
+
+	// Options configures a Table
+	Options struct {
+		ID       string   // id of the table element
+		Endpoint string   // path the table is served on, e.g. /users
+		Columns  []Column // columns of the table
+		Empty    string   // text of the table without rows, defaults to "No results"
+	}
+
+	// State is the sort and filter state of a table, read from the query parameters of a request
+	State struct {
+		Sort    string            // sort key of the sorted column, empty when the table isn't sorted
+		Dir     Direction         // direction of the sort
+		Filters map[string]string // values of the filters that are set, by their query parameter
+		url     *url.URL
+	}
+
+	// Table renders tables of rows with its columns
+	Table struct {
+		opts  Options
+		cells []*template.Template
+	}
+
+	tableView struct {
+		ID        string
+		Endpoint  string
+		SortParam string
+		DirParam  string
+		Sort      string
+		Dir       Direction
+		Empty     string
+		Headers   []headerView
+		Filters   []filterView
+		Rows      [][]template.HTML
+	}
+
+	headerView struct {
+		Name       string
+		AriaSort   string
+		Attributes template.HTMLAttr
+	}
+
+	filterView struct {
+		Name  string
+		Label string
+		Value string
+	}
+)
+
+// New returns a table for the options, the cell templates are parsed with the functions of htmx.DefaultTemplateFuncs
+func New(opts Options) (*Table, error) {
+	if opts.Empty == "" {
+		opts.Empty = "No results"
+	}
+
+	t := &Table{opts: opts, cells: make([]*template.Template, len(opts.Columns))}
+
+	for i, col := range opts.Columns {
+		cell, err := template.New(col.Name).Funcs(htmx.DefaultTemplateFuncs).Parse(col.Cell)
+		if err != nil {
+			return nil, fmt.Errorf("table: cell of column %s: %w", col.Name, err)
+		}
+		t.cells[i] = cell
+	}
+
+	return t, nil
+}
+
+// Must returns the table for the options and panics when it can't be created, it is meant for package variables
+func Must(opts Options) *Table {
+	t, err := New(opts)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// State returns the sort and filter state of the request. Only the sort keys and filters of the columns are read, so
+// the state can be passed to a query safely.
+func (t *Table) State(r *http.Request) State {
+	query := r.URL.Query()
+
+	s := State{Dir: Asc, Filters: make(map[string]string), url: r.URL}
+
+	for _, col := range t.opts.Columns {
+		if col.SortKey != "" && col.SortKey == query.Get(SortParam) {
+			s.Sort = col.SortKey
+			if Direction(query.Get(DirParam)) == Desc {
+				s.Dir = Desc
+			}
+		}
+
+		if col.Filter != "" {
+			if value := query.Get(col.Filter); value != "" {
+				s.Filters[col.Filter] = value
+			}
+		}
+	}
+
+	return s
+}
+
+// Toggle returns the state sorted by the key: ascending, or descending when it was sorted ascending by the key already
+func (s State) Toggle(key string) State {
+	dir := Asc
+	if s.Sort == key && s.Dir == Asc {
+		dir = Desc
+	}
+
+	s.Sort = key
+	s.Dir = dir
+	return s
+}
+
+// URL returns the url of the state, with the other query parameters of the request. The page parameter is dropped, a
+// new sort or filter starts on the first page.
+func (s State) URL(endpoint string) string {
+	query := url.Values{}
+	if s.url != nil {
+		query = s.url.Query()
+	}
+	query.Del(htmx.PageParam)
+
+	for name, value := range s.Filters {
+		query.Set(name, value)
+	}
+
+	query.Del(SortParam)
+	query.Del(DirParam)
+	if s.Sort != "" {
+		query.Set(SortParam, s.Sort)
+		query.Set(DirParam, string(s.Dir))
+	}
+
+	return (&url.URL{Path: endpoint, RawQuery: query.Encode()}).String()
+}
+
+// Component returns the component of the table for the state, with a row for every element of rows, which must be a
+// slice. The rows are expected to be sorted and filtered by the state already.
+func (t *Table) Component(s State, rows any) (htmx.RenderableComponent, error) {
+	view := &tableView{
+		ID:        t.opts.ID,
+		Endpoint:  t.opts.Endpoint,
+		SortParam: SortParam,
+		DirParam:  DirParam,
+		Sort:      s.Sort,
+		Dir:       s.Dir,
+		Empty:     t.opts.Empty,
+	}
+
+	for _, col := range t.opts.Columns {
+		view.Headers = append(view.Headers, t.header(s, col))
+
+		if col.Filter != "" {
+			view.Filters = append(view.Filters, filterView{Name: col.Filter, Label: col.Name, Value: s.Filters[col.Filter]})
+		}
+	}
+
+	rv := reflect.ValueOf(rows)
+	if rows == nil {
+		rv = reflect.ValueOf([]any(nil))
+	}
+
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("table: rows must be a slice, got %T", rows)
+	}
+
+	for i := range rv.Len() {
+		row, err := t.row(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		view.Rows = append(view.Rows, row)
+	}
+
+	return htmx.NewComponent("templates/table.html").FS(templates).AddData("Table", view), nil
+}
+
+// header returns the header cell of the column, sortable columns link to the table sorted by them
+func (t *Table) header(s State, col Column) headerView {
+	h := headerView{Name: col.Name}
+	if col.SortKey == "" {
+		return h
+	}
+
+	if s.Sort == col.SortKey {
+		h.AriaSort = "ascending"
+		if s.Dir == Desc {
+			h.AriaSort = "descending"
+		}
+	}
+
+	toggled := s.Toggle(col.SortKey)
+
+	// the filters are included from the inputs, which may have changed since the table was rendered
+	a := htmx.NewAttributes().
+		Set("href", toggled.URL(t.opts.Endpoint)).
+		Set("hx-get", State{Sort: toggled.Sort, Dir: toggled.Dir}.URL(t.opts.Endpoint)).
+		Set("hx-target", "#"+t.opts.ID).
+		Set("hx-select", "#"+t.opts.ID).
+		Set("hx-swap", "outerHTML").
+		Set("hx-push-url", "true")
+	if slices.ContainsFunc(t.opts.Columns, func(c Column) bool { return c.Filter != "" }) {
+		a.Set("hx-include", "#"+t.opts.ID+"-filters input[type=search]")
+	}
+
+	h.Attributes = a.HTMLAttr()
+	return h
+}
+
+// row renders the cells of the row
+func (t *Table) row(row any) ([]template.HTML, error) {
+	cells := make([]template.HTML, len(t.cells))
+
+	for i, cell := range t.cells {
+		var buf bytes.Buffer
+		if err := cell.Execute(&buf, row); err != nil {
+			return nil, fmt.Errorf("table: cell of column %s: %w", t.opts.Columns[i].Name, err)
+		}
+
+		//nolint:gosec // the cell templates are html templates, they escape the row values
+		cells[i] = template.HTML(buf.String())
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,103 @@
+package table
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+func newTestTable(t *testing.T) *Table {
+	t.Helper()
+
+	tbl, err := New(Options{
+		ID:       "users",
+		Endpoint: "/users",
+		Columns: []Column{
+			{Name: "Name", SortKey: "name", Filter: "q", Cell: `{{ .Name }}`},
+			{Name: "Email", Cell: `<a href="mailto:{{ .Email }}">{{ .Email }}</a>`},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tbl
+}
+
+func TestState(t *testing.T) {
+	tbl := newTestTable(t)
+
+	s := tbl.State(httptest.NewRequest(http.MethodGet, "/users?sort=name&dir=desc&q=ann&tab=2&page=3", nil))
+	equal(t, "name", s.Sort)
+	equal(t, Desc, s.Dir)
+	equal(t, "ann", s.Filters["q"])
+
+	// the page is dropped and the other parameters are kept
+	equal(t, "/users?dir=asc&q=ann&sort=name&tab=2", s.Toggle("name").URL("/users"))
+
+	// unknown sort keys are ignored
+	s = tbl.State(httptest.NewRequest(http.MethodGet, "/users?sort=password", nil))
+	equal(t, "", s.Sort)
+	equal(t, Asc, s.Dir)
+}
+
+func TestComponent(t *testing.T) {
+	tbl := newTestTable(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/users?sort=name&q=a", nil)
+	c, err := tbl.Component(tbl.State(r), []user{{Name: "Ann", Email: "ann@example.com"}, {Name: "Bob <b>", Email: "bob@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(out)
+
+	for _, expected := range []string{
+		`<th scope="col" aria-sort="ascending"><a href="/users?dir=desc&amp;q=a&amp;sort=name" hx-get="/users?dir=desc&amp;sort=name" hx-target="#users" hx-select="#users" hx-swap="outerHTML" hx-push-url="true" hx-include="#users-filters input[type=search]">Name</a></th>`,
+		`<th scope="col">Email</th>`,
+		`<input type="hidden" name="sort" value="name">`,
+		`<input type="search" name="q" value="a" placeholder="Name" aria-label="Name">`,
+		`<tr><td>Ann</td><td><a href="mailto:ann@example.com">ann@example.com</a></td></tr>`,
+		`<td>Bob &lt;b&gt;</td>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %s in %s", expected, html)
+		}
+	}
+
+	c, err = tbl.Component(State{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(out), `<tr><td colspan="2">No results</td></tr>`) {
+		t.Errorf("expected the empty row in %s", out)
+	}
+
+	if _, err := tbl.Component(State{}, "rows"); err == nil {
+		t.Error("expected an error for rows that aren't a slice")
+	}
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,117 @@
+package htmx
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultTemplateSet is the name of the template set that is active by default, it loads templates from the working directory.
+const DefaultTemplateSet = "default"
+
+var (
+	templateSets      = map[string]*templateSet{}
+	templateSetsMu    sync.RWMutex
+	activeTemplateSet atomic.Pointer[templateSet]
+)
+
+// templateSet is a named filesystem with templates, each set has its own namespace in the template cache
+type templateSet struct {
+	name string
+	fs   fs.FS
+}
+
+func init() {
+	set := &templateSet{name: DefaultTemplateSet, fs: os.DirFS("./")}
+	templateSets[set.name] = set
+	activeTemplateSet.Store(set)
+}
+
+// LoadTemplateSet loads a complete set of templates under the given name, components that don't have their own
+// filesystem render the templates of the active set. Loading a set next to the active one and activating it
+// afterward allows template-only deploys without restarting the binary:
+//
+//	_ = htmx.LoadTemplateSet("green", os.DirFS("/srv/templates/v42"))
+//	_ = htmx.ActivateTemplateSet("green")
+func LoadTemplateSet(name string, fsys fs.FS) error {
+	if name == "" || fsys == nil {
+		return errors.New("a template set requires a name and a filesystem")
+	}
+
+	templateSetsMu.Lock()
+	defer templateSetsMu.Unlock()
+
+	if active := activeTemplateSet.Load(); active != nil && active.name == name {
+		return fmt.Errorf("template set %q is active and can't be replaced", name)
+	}
+
+	templateSets[name] = &templateSet{name: name, fs: fsys}
+	dropCacheNamespace(name)
+	logReload("template set loaded", slog.String("set", name))
+
+	return nil
+}
+
+// ActivateTemplateSet atomically switches the active template set, renders that are in progress finish with the previous set.
+func ActivateTemplateSet(name string) error {
+	templateSetsMu.RLock()
+	set, ok := templateSets[name]
+	templateSetsMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("template set %q is not loaded", name)
+	}
+
+	activeTemplateSet.Store(set)
+	logReload("template set activated", slog.String("set", name))
+
+	return nil
+}
+
+// ActiveTemplateSet returns the name of the active template set
+func ActiveTemplateSet() string {
+	return activeTemplateSet.Load().name
+}
+
+// TemplateSets returns the sorted names of all loaded template sets
+func TemplateSets() []string {
+	templateSetsMu.RLock()
+	defer templateSetsMu.RUnlock()
+
+	names := make([]string, 0, len(templateSets))
+	for name := range templateSets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// templateFS returns the filesystem to load the templates from and the namespace of the template cache,
+// components with their own filesystem don't belong to a template set. The theme of the tenant is laid over
+// the active template set and the templates of the tenant are cached in a namespace of their own.
+func (c *Component) templateFS(tenant *Tenant) (fs.FS, string) {
+	if c.fs != nil {
+		if tenant != nil {
+			return c.fs, tenantNamespace("", tenant.Key)
+		}
+		return c.fs, ""
+	}
+
+	set := activeTemplateSet.Load()
+	if tenant == nil {
+		return set.fs, set.name
+	}
+
+	fsys := set.fs
+	if tenant.FS != nil {
+		fsys = overlayFS{top: tenant.FS, base: set.fs}
+	}
+
+	return fsys, tenantNamespace(set.name, tenant.Key)
+}
@@ -0,0 +1,58 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplateSets(t *testing.T) {
+	blue := fstest.MapFS{"page.html": {Data: []byte(`blue`)}}
+	green := fstest.MapFS{"page.html": {Data: []byte(`green`)}}
+
+	if err := LoadTemplateSet("blue", blue); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadTemplateSet("green", green); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = ActivateTemplateSet(DefaultTemplateSet)
+	}()
+
+	render := func() string {
+		out, err := NewComponent("page.html").Render(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	if err := ActivateTemplateSet("blue"); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "blue", render())
+
+	if err := ActivateTemplateSet("green"); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "green", render())
+	equal(t, "green", ActiveTemplateSet())
+
+	if err := LoadTemplateSet("green", blue); err == nil {
+		t.Error("expected an error when replacing the active set")
+	}
+
+	if err := ActivateTemplateSet("missing"); err == nil {
+		t.Error("expected an error when activating an unknown set")
+	}
+
+	// components with their own filesystem don't use the active set
+	out, err := NewComponent("page.html").FS(blue).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "blue", string(out))
+}
@@ -0,0 +1,250 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrUnknownTenant is returned when a component is rendered for a tenant that isn't registered
+var ErrUnknownTenant = errors.New("htmx: unknown tenant")
+
+var (
+	tenants        = map[string]*Tenant{}
+	tenantsMu      sync.RWMutex
+	tenantResolver atomic.Pointer[TenantResolver]
+)
+
+type (
+	// Tenant is an isolated rendering environment. The functions, context functions and global data of a tenant are
+	// only available to components rendered for that tenant, and its parsed templates are cached in a namespace of
+	// their own, so one tenant's customizations can never observe another tenant's data or functions.
+	Tenant struct {
+		// Key identifies the tenant, it is returned by the TenantResolver
+		Key string
+
+		// FS holds the theme of the tenant, its templates take precedence over the templates of the active template set.
+		// Components with their own filesystem are not themed.
+		FS fs.FS
+
+		// Funcs are the template functions of the tenant, they override the default template functions
+		Funcs template.FuncMap
+
+		// ContextFuncs are the context functions of the tenant, they override the default context functions
+		ContextFuncs map[string]ContextFunc
+
+		// Global is the global data of the tenant, the global data of the component takes precedence
+		Global map[string]any
+	}
+
+	// TenantResolver returns the key of the tenant a component is rendered for, an empty key renders without a tenant.
+	// The request of the render is available through RequestFromContext.
+	TenantResolver interface {
+		ResolveTenant(ctx context.Context) (string, error)
+	}
+
+	// TenantResolverFunc is a function that implements TenantResolver
+	TenantResolverFunc func(ctx context.Context) (string, error)
+
+	tenantKey         struct{}
+	resolvedTenantKey struct{}
+
+	// resolvedTenant is the tenant that was resolved for a render, nil when rendering without a tenant
+	resolvedTenant struct {
+		tenant *Tenant
+	}
+
+	// overlayFS serves the files of the top filesystem and falls back to the base filesystem
+	overlayFS struct {
+		top  fs.FS
+		base fs.FS
+	}
+)
+
+// ResolveTenant calls f(ctx)
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// RegisterTenant registers the tenant, a tenant with the same key is replaced and its cached templates are dropped
+func RegisterTenant(t Tenant) error {
+	if t.Key == "" || strings.ContainsAny(t.Key, "#|") {
+		return errors.New("htmx: a tenant requires a key without # and |")
+	}
+
+	tenantsMu.Lock()
+	defer tenantsMu.Unlock()
+
+	tenants[t.Key] = &t
+	dropTenantNamespace(t.Key)
+
+	return nil
+}
+
+// UnregisterTenant removes the tenant and its cached templates
+func UnregisterTenant(key string) {
+	tenantsMu.Lock()
+	defer tenantsMu.Unlock()
+
+	delete(tenants, key)
+	dropTenantNamespace(key)
+}
+
+// Tenants returns the sorted keys of all registered tenants
+func Tenants() []string {
+	tenantsMu.RLock()
+	defer tenantsMu.RUnlock()
+
+	keys := make([]string, 0, len(tenants))
+	for key := range tenants {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// SetTenantResolver sets the resolver that returns the tenant of a render, by default the tenant is read from
+// the context with TenantFromContext.
+//
+//	htmx.SetTenantResolver(htmx.TenantResolverFunc(func(ctx context.Context) (string, error) {
+//		r, _ := htmx.RequestFromContext(ctx)
+//		if r == nil {
+//			return "", nil
+//		}
+//		tenant, _, _ := strings.Cut(r.Host, ".")
+//		return tenant, nil
+//	}))
+func SetTenantResolver(r TenantResolver) {
+	if r == nil {
+		tenantResolver.Store(nil)
+		return
+	}
+
+	tenantResolver.Store(&r)
+}
+
+// WithTenant returns a copy of the context that renders components for the tenant with the given key
+func WithTenant(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, key)
+}
+
+// TenantFromContext returns the tenant key that was set with WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(tenantKey{}).(string)
+	return key, ok
+}
+
+// tenantContext resolves the tenant of the render once and stores it in the context for the partials
+func tenantContext(ctx context.Context) (context.Context, error) {
+	if _, ok := ctx.Value(resolvedTenantKey{}).(resolvedTenant); ok {
+		return ctx, nil
+	}
+
+	key, err := resolveTenantKey(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	var tenant *Tenant
+	if key != "" {
+		tenantsMu.RLock()
+		tenant = tenants[key]
+		tenantsMu.RUnlock()
+
+		if tenant == nil {
+			return ctx, fmt.Errorf("%w: %q", ErrUnknownTenant, key)
+		}
+	}
+
+	return context.WithValue(ctx, resolvedTenantKey{}, resolvedTenant{tenant: tenant}), nil
+}
+
+// resolveTenantKey returns the tenant key from the resolver, or from the context when no resolver is set
+func resolveTenantKey(ctx context.Context) (string, error) {
+	if r := tenantResolver.Load(); r != nil {
+		return (*r).ResolveTenant(ctx)
+	}
+
+	key, _ := TenantFromContext(ctx)
+	return key, nil
+}
+
+// renderTenant returns the tenant that was resolved for the render
+func renderTenant(ctx context.Context) *Tenant {
+	resolved, _ := ctx.Value(resolvedTenantKey{}).(resolvedTenant)
+	return resolved.tenant
+}
+
+// global returns the global data of the component merged over the global data of the tenant
+func (t *Tenant) global(componentGlobal map[string]any) map[string]any {
+	if t == nil || len(t.Global) == 0 {
+		return componentGlobal
+	}
+
+	merged := make(map[string]any, len(t.Global)+len(componentGlobal))
+	for key, value := range t.Global {
+		merged[key] = value
+	}
+	for key, value := range componentGlobal {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// tenantNamespace returns the namespace of the template cache for the templates of the tenant
+func tenantNamespace(namespace, key string) string {
+	return namespace + "#" + key
+}
+
+// dropTenantNamespace removes the cached templates of the tenant from every template set
+func dropTenantNamespace(key string) {
+	templateCache.Range(func(k, _ any) bool {
+		if s, ok := k.(string); ok && strings.HasSuffix(cacheNamespace(s), "#"+key) {
+			templateCache.Delete(k)
+		}
+		return true
+	})
+}
+
+// Open opens the named file from the top filesystem, or the base filesystem when the top doesn't have it
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if err == nil {
+		return f, nil
+	}
+
+	return o.base.Open(name)
+}
+
+// ReadDir returns the merged entries of the directory in both filesystems, so glob patterns match the files of both
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	top, topErr := fs.ReadDir(o.top, name)
+	base, baseErr := fs.ReadDir(o.base, name)
+	if topErr != nil && baseErr != nil {
+		return nil, baseErr
+	}
+
+	entries := make(map[string]fs.DirEntry, len(top)+len(base))
+	for _, entry := range base {
+		entries[entry.Name()] = entry
+	}
+	for _, entry := range top {
+		entries[entry.Name()] = entry
+	}
+
+	merged := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		merged = append(merged, entry)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name() < merged[j].Name() })
+
+	return merged, nil
+}
@@ -0,0 +1,89 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTenants(t *testing.T) {
+	base := fstest.MapFS{
+		"page.html":  {Data: []byte(`{{ template "brand.html" . }}|{{ shout "hi" }}|{{ .Global.Plan }}`)},
+		"brand.html": {Data: []byte(`default`)},
+	}
+
+	if err := LoadTemplateSet("tenants", base); err != nil {
+		t.Fatal(err)
+	}
+	if err := ActivateTemplateSet("tenants"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = ActivateTemplateSet(DefaultTemplateSet)
+	}()
+
+	err := RegisterTenant(Tenant{
+		Key:    "acme",
+		FS:     fstest.MapFS{"brand.html": {Data: []byte(`acme`)}},
+		Funcs:  map[string]any{"shout": func(s string) string { return s + "!" }},
+		Global: map[string]any{"Plan": "pro"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RegisterTenant(Tenant{
+		Key:   "globex",
+		Funcs: map[string]any{"shout": func(s string) string { return s + "?" }},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer UnregisterTenant("acme")
+	defer UnregisterTenant("globex")
+
+	render := func(ctx context.Context) (string, error) {
+		out, err := NewComponent("page.html", "brand.html").Render(ctx)
+		return string(out), err
+	}
+
+	out, err := render(WithTenant(context.Background(), "acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "acme|hi!|pro", out)
+
+	// the functions, theme and globals of acme are not visible to globex
+	out, err = render(WithTenant(context.Background(), "globex"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "default|hi?|", out)
+
+	// renders without a tenant don't have the tenant functions
+	if _, err = render(context.Background()); err == nil {
+		t.Error("expected an error for a function that only exists for tenants")
+	}
+
+	if _, err = render(WithTenant(context.Background(), "initech")); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("expected ErrUnknownTenant, got %v", err)
+	}
+
+	// the resolver takes precedence over the context
+	SetTenantResolver(TenantResolverFunc(func(ctx context.Context) (string, error) {
+		return "acme", nil
+	}))
+	defer SetTenantResolver(nil)
+
+	out, err = render(WithTenant(context.Background(), "globex"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "acme|hi!|pro", out)
+
+	if err := RegisterTenant(Tenant{Key: "a|b"}); err == nil {
+		t.Error("expected an error for an invalid tenant key")
+	}
+}
@@ -0,0 +1,107 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//go:embed templates/toasts.html
+var toastTemplates embed.FS
+
+var (
+	// DefaultToastEvent is the HX-Trigger event that carries the toasts of a response
+	DefaultToastEvent = "toast"
+
+	// DefaultToastTarget is the id of the element the toasts are shown in
+	DefaultToastTarget = "toasts"
+
+	// DefaultToastTimeout is the time a toast is shown, zero keeps the toasts until they are removed
+	DefaultToastTimeout = 5 * time.Second
+)
+
+type (
+	// ToastMessage is a short message that is shown in the toast container, it uses the levels of the flash messages
+	ToastMessage struct {
+		Level   FlashLevel `json:"level"`
+		Message string     `json:"message"`
+	}
+
+	toastsKey struct{}
+)
+
+// Toast queues a toast on the response, it is sent in the HX-Trigger header as the DefaultToastEvent with the
+// toasts in its detail: {"toast": {"toasts": [{"level": "success", "message": "Saved"}]}}. The toast container shows
+// them after htmx requests, and renders them in place on full page loads.
+//
+//	htmx.Toast(w, htmx.FlashSuccess, "Saved")
+func Toast(w http.ResponseWriter, level FlashLevel, message string) error {
+	return addToast(w.Header(), ToastMessage{Level: level, Message: message})
+}
+
+// Toast queues a toast on the response, see the Toast function
+func (h *Handler) Toast(level FlashLevel, message string) error {
+	return addToast(h.Header(), ToastMessage{Level: level, Message: message})
+}
+
+// NewToastContainer returns the component that shows the toasts, the layout renders it once. It shows the toasts
+// queued on full page loads and adds the toasts of the DefaultToastEvent of later htmx responses, each for the
+// DefaultToastTimeout. Its script carries the nonce of the request for a Content-Security-Policy.
+func NewToastContainer() *Component {
+	c := NewComponent("templates/toasts.html").FS(toastTemplates)
+	c.AddData("Target", DefaultToastTarget)
+	c.AddData("Event", DefaultToastEvent)
+	c.AddData("Timeout", DefaultToastTimeout.Milliseconds())
+
+	return c
+}
+
+// addToast adds the toast to the toasts of the DefaultToastEvent in the HX-Trigger header
+func addToast(header http.Header, t ToastMessage) error {
+	toasts := append(queuedToasts(header), t)
+	return triggerEvents(header, HXTrigger, map[string]any{DefaultToastEvent: map[string]any{"toasts": toasts}})
+}
+
+// queuedToasts returns the toasts of the HX-Trigger header
+func queuedToasts(header http.Header) []ToastMessage {
+	existing := strings.TrimSpace(header.Get(HXTrigger.String()))
+	if !strings.HasPrefix(existing, "{") {
+		return nil
+	}
+
+	var events map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(existing), &events); err != nil {
+		return nil
+	}
+
+	// events of the same name that don't carry toasts are replaced
+	var detail struct {
+		Toasts []ToastMessage `json:"toasts"`
+	}
+	_ = json.Unmarshal(events[DefaultToastEvent], &detail)
+
+	return detail.Toasts
+}
+
+// withToasts returns a context with the response header the toasts are queued on
+func withToasts(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, toastsKey{}, header)
+}
+
+// toastsFunc is the toasts template function, it returns the queued toasts of a full page load. The toasts of htmx
+// requests are shown by the DefaultToastEvent, they would show twice otherwise.
+func toastsFunc(ctx context.Context, _ ...any) (any, error) {
+	header, ok := ctx.Value(toastsKey{}).(http.Header)
+	if !ok {
+		return []ToastMessage(nil), nil
+	}
+
+	if r, ok := RequestFromContext(ctx); ok && IsHxRequest(r) {
+		return []ToastMessage(nil), nil
+	}
+
+	return queuedToasts(header), nil
+}
@@ -0,0 +1,67 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestToast(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("HX-Trigger", "saved")
+
+	if err := Toast(w, FlashSuccess, "Saved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Toast(w, FlashError, `Mail "not" sent`); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `{"saved":"","toast":{"toasts":[{"level":"success","message":"Saved"},{"level":"error","message":"Mail \"not\" sent"}]}}`,
+		w.Header().Get("HX-Trigger"))
+}
+
+func TestToastContainer(t *testing.T) {
+	fsys := fstest.MapFS{"toast-layout.html": {Data: []byte(`<body>{{ .Partials.toasts }}</body>`)}}
+
+	render := func(hxRequest bool) string {
+		r := httptest.NewRequest(http.MethodPost, "/users", nil)
+		if hxRequest {
+			r.Header.Set("HX-Request", "true")
+		}
+		w := httptest.NewRecorder()
+
+		h := New().NewHandler(w, r)
+		if err := h.Toast(FlashInfo, "Welcome <back>"); err != nil {
+			t.Fatal(err)
+		}
+
+		layout := NewComponent("toast-layout.html").FS(fsys)
+		layout.With(NewToastContainer(), "toasts")
+
+		if _, err := h.Render(context.Background(), layout); err != nil {
+			t.Fatal(err)
+		}
+
+		return w.Body.String()
+	}
+
+	// full page loads render the queued toasts in place
+	output := render(false)
+	if !strings.Contains(output, `<div id="toasts" class="toasts" role="status" aria-live="polite">
+	<div class="toast toast-info">Welcome &lt;back&gt;</div>
+</div>`) {
+		t.Errorf("expected the toast in %s", output)
+	}
+	if !strings.Contains(output, `data-target="toasts" data-event="toast" data-timeout="5000"`) {
+		t.Errorf("expected the script settings in %s", output)
+	}
+
+	// htmx requests show them with the event
+	if output := render(true); strings.Contains(output, "toast-info") {
+		t.Errorf("expected no rendered toast in %s", output)
+	}
+}
@@ -0,0 +1,66 @@
+package htmx
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+)
+
+// RenderTracer traces the render of every component when set, e.g. with the OpenTelemetry adapter in adapters/otel.
+// The spans of partials are children of the span of their parent, as they render with its context.
+var RenderTracer Tracer
+
+type (
+	// Tracer starts a span for the render of a component
+	Tracer interface {
+		StartRender(ctx context.Context, info RenderInfo) (context.Context, RenderSpan)
+	}
+
+	// RenderSpan is the span of a render, it ends once the component and its partials were rendered
+	RenderSpan interface {
+		// End ends the span with the template cache status of the component: hit, miss or off when the cache is
+		// disabled, and the error of the render
+		End(cacheStatus string, err error)
+	}
+
+	// RenderInfo describes the component whose render is traced
+	RenderInfo struct {
+		Component string   // the name of the component, the base name of its first template
+		Templates []string // the template files of the component
+		Partials  int      // the number of partials rendered into the component
+	}
+)
+
+// observeRender renders the component in a span of the RenderTracer and reports the render to the RenderMetrics
+func observeRender[T any](ctx context.Context, c *Component, render func(context.Context) (T, error)) (T, error) {
+	info := c.renderInfo()
+	c.cacheStatus = ""
+
+	var span RenderSpan
+	if RenderTracer != nil {
+		ctx, span = RenderTracer.StartRender(ctx, info)
+	}
+
+	start := time.Now()
+	output, err := render(ctx)
+
+	if span != nil {
+		span.End(c.cacheStatus, err)
+	}
+
+	if RenderMetrics != nil {
+		RenderMetrics.ObserveRender(info, RenderStats{Duration: time.Since(start), CacheStatus: c.cacheStatus, Err: err})
+	}
+
+	return output, err
+}
+
+// renderInfo describes the component for the RenderTracer and RenderMetrics
+func (c *Component) renderInfo() RenderInfo {
+	info := RenderInfo{Templates: c.templates, Partials: len(c.with)}
+	if len(c.templates) > 0 {
+		info.Component = filepath.Base(c.templates[0])
+	}
+
+	return info
+}
@@ -0,0 +1,56 @@
+package htmx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type (
+	testTracer struct {
+		spans []string
+	}
+
+	testSpan struct {
+		tracer *testTracer
+		name   string
+		depth  int
+	}
+
+	traceDepthKey struct{}
+)
+
+func (t *testTracer) StartRender(ctx context.Context, info RenderInfo) (context.Context, RenderSpan) {
+	depth, _ := ctx.Value(traceDepthKey{}).(int)
+	name := info.Component + " " + strings.Join(info.Templates, ",") + " partials=" + strconv.Itoa(info.Partials)
+
+	return context.WithValue(ctx, traceDepthKey{}, depth+1), &testSpan{tracer: t, name: name, depth: depth}
+}
+
+func (s *testSpan) End(cacheStatus string, err error) {
+	s.tracer.spans = append(s.tracer.spans, strings.Repeat("  ", s.depth)+s.name+" cache="+cacheStatus)
+}
+
+func TestRenderTracer(t *testing.T) {
+	tracer := &testTracer{}
+	RenderTracer = tracer
+	defer func() { RenderTracer = nil }()
+
+	ClearTemplateCache()
+
+	for range 2 {
+		if _, err := benchComponent().Render(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{
+		"  list.html list.html partials=0 cache=miss",
+		"page.html page.html partials=1 cache=miss",
+		"  list.html list.html partials=0 cache=hit",
+		"page.html page.html partials=1 cache=hit",
+	}
+
+	equal(t, strings.Join(expected, "\n"), strings.Join(tracer.spans, "\n"))
+}
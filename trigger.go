@@ -2,6 +2,8 @@ package htmx
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -149,3 +151,70 @@ func (h *Handler) TriggerError(message string, vars ...map[string]any) {
 func (h *Handler) TriggerCustom(custom, message string, vars ...map[string]any) {
 	h.notifyObject(notificationType(custom), message, vars...)
 }
+
+// TriggerEvents triggers the events with their JSON details as soon as the response is received. The events are
+// merged into the events that were already triggered, a detail can be any value that marshals to JSON.
+// https://htmx.org/headers/hx-trigger/
+func (h *Handler) TriggerEvents(events map[string]any) error {
+	return triggerEvents(h.Header(), HXTrigger, events)
+}
+
+// TriggerEventsAfterSettle triggers the events with their JSON details after the settling step
+// https://htmx.org/headers/hx-trigger/
+func (h *Handler) TriggerEventsAfterSettle(events map[string]any) error {
+	return triggerEvents(h.Header(), HXTriggerAfterSettle, events)
+}
+
+// TriggerEventsAfterSwap triggers the events with their JSON details after the swap step
+// https://htmx.org/headers/hx-trigger/
+func (h *Handler) TriggerEventsAfterSwap(events map[string]any) error {
+	return triggerEvents(h.Header(), HXTriggerAfterSwap, events)
+}
+
+// TriggerEvents triggers the events with their JSON details as soon as the response is received
+// https://htmx.org/headers/hx-trigger/
+func (r *HxResponse) TriggerEvents(events map[string]any) error {
+	return triggerEvents(r.Header(), HXTrigger, events)
+}
+
+// TriggerEventsAfterSettle triggers the events with their JSON details after the settling step
+// https://htmx.org/headers/hx-trigger/
+func (r *HxResponse) TriggerEventsAfterSettle(events map[string]any) error {
+	return triggerEvents(r.Header(), HXTriggerAfterSettle, events)
+}
+
+// TriggerEventsAfterSwap triggers the events with their JSON details after the swap step
+// https://htmx.org/headers/hx-trigger/
+func (r *HxResponse) TriggerEventsAfterSwap(events map[string]any) error {
+	return triggerEvents(r.Header(), HXTriggerAfterSwap, events)
+}
+
+// triggerEvents merges the events into the trigger header, events of the header in the simple comma separated form
+// keep an empty detail
+func triggerEvents(header http.Header, key HxResponseKey, events map[string]any) error {
+	merged := make(map[string]any)
+
+	if existing := strings.TrimSpace(header.Get(key.String())); strings.HasPrefix(existing, "{") {
+		if err := json.Unmarshal([]byte(existing), &merged); err != nil {
+			return fmt.Errorf("htmx: invalid %s header: %w", key, err)
+		}
+	} else if existing != "" {
+		for _, event := range strings.Split(existing, ",") {
+			if event = strings.TrimSpace(event); event != "" {
+				merged[event] = ""
+			}
+		}
+	}
+
+	for event, detail := range events {
+		merged[event] = detail
+	}
+
+	payload, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+
+	header.Set(key.String(), asciiJSON(string(payload)))
+	return nil
+}
@@ -2,6 +2,7 @@ package htmx
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -99,3 +100,31 @@ func TestTriggerWarning(t *testing.T) {
 
 	equal(t, expected, handler.response.Get(HXTrigger))
 }
+
+func TestTriggerEvents(t *testing.T) {
+	w := httptest.NewRecorder()
+	h := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	h.Trigger("refresh, close")
+	if err := h.TriggerEvents(map[string]any{
+		"saved": map[string]any{"id": 7, "name": "Zoë"},
+		"count": 3,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `{"close":"","count":3,"refresh":"","saved":{"id":7,"name":"Zo\u00eb"}}`, w.Header().Get("HX-Trigger"))
+
+	if err := h.TriggerEventsAfterSettle(map[string]any{"settled": true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.TriggerEventsAfterSettle(map[string]any{"done": nil}); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"done":null,"settled":true}`, w.Header().Get("HX-Trigger-After-Settle"))
+
+	if err := NewHxResponse(w).TriggerEventsAfterSwap(map[string]any{"bad": make(chan int)}); err == nil {
+		t.Error("expected an error for a detail that can't be marshalled")
+	}
+	equal(t, "", w.Header().Get("HX-Trigger-After-Swap"))
+}
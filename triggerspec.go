@@ -0,0 +1,198 @@
+package htmx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AttrTrigger specifies the events that trigger the request of an element
+	// https://htmx.org/attributes/hx-trigger/
+	AttrTrigger = "hx-trigger"
+
+	// TriggerQueueFirst queues the first event that occurs while a request is in flight
+	TriggerQueueFirst TriggerQueue = "first"
+
+	// TriggerQueueLast queues the last event that occurs while a request is in flight, the default
+	TriggerQueueLast TriggerQueue = "last"
+
+	// TriggerQueueAll queues all events that occur while a request is in flight
+	TriggerQueueAll TriggerQueue = "all"
+
+	// TriggerQueueNone drops the events that occur while a request is in flight
+	TriggerQueueNone TriggerQueue = "none"
+)
+
+type (
+	// TriggerQueue determines which events are queued while a request is in flight
+	TriggerQueue string
+
+	// TriggerSpec builds the value of a hx-trigger attribute, the modifiers apply to the last added event.
+	// Use Trigger for the HX-Trigger response header.
+	//
+	//	htmx.NewTriggerSpec("keyup").Changed().Delay(500 * time.Millisecond).Or("search")
+	//	// keyup changed delay:500ms, search
+	TriggerSpec struct {
+		events []triggerSpecEvent
+	}
+
+	// triggerSpecEvent is an event of a trigger specification with its filter and modifiers
+	triggerSpecEvent struct {
+		event     string
+		filter    string
+		modifiers []string
+	}
+)
+
+// NewTriggerSpec returns a trigger specification for the event
+func NewTriggerSpec(event string) *TriggerSpec {
+	return (&TriggerSpec{}).Or(event)
+}
+
+// NewPollingSpec returns a trigger specification that polls every interval, only while the condition holds when given
+func NewPollingSpec(interval time.Duration, condition ...string) *TriggerSpec {
+	return (&TriggerSpec{}).Every(interval, condition...)
+}
+
+// Or adds another event that triggers the request
+func (t *TriggerSpec) Or(event string) *TriggerSpec {
+	t.events = append(t.events, triggerSpecEvent{event: event})
+	return t
+}
+
+// Every adds polling every interval, only while the condition holds when given
+func (t *TriggerSpec) Every(interval time.Duration, condition ...string) *TriggerSpec {
+	t.Or("every " + htmxDuration(interval))
+	if len(condition) > 0 {
+		t.Filter(condition[0])
+	}
+
+	return t
+}
+
+// Filter only triggers the request when the javascript expression is true, e.g. "ctrlKey" or "key=='Enter'"
+func (t *TriggerSpec) Filter(condition string) *TriggerSpec {
+	if len(t.events) > 0 {
+		t.events[len(t.events)-1].filter = condition
+	}
+
+	return t
+}
+
+// Once only triggers the request once
+func (t *TriggerSpec) Once() *TriggerSpec {
+	return t.modifier("once")
+}
+
+// Changed only triggers the request when the value of the element changed
+func (t *TriggerSpec) Changed() *TriggerSpec {
+	return t.modifier("changed")
+}
+
+// Delay waits before the request is issued, the delay restarts when the event occurs again
+func (t *TriggerSpec) Delay(d time.Duration) *TriggerSpec {
+	return t.modifier("delay:" + htmxDuration(d))
+}
+
+// Throttle issues the request right away and drops the events of the interval that follows
+func (t *TriggerSpec) Throttle(d time.Duration) *TriggerSpec {
+	return t.modifier("throttle:" + htmxDuration(d))
+}
+
+// From listens for the event on the element of the extended CSS selector, e.g. "document" or "closest form"
+func (t *TriggerSpec) From(selector string) *TriggerSpec {
+	return t.modifier("from:" + selector)
+}
+
+// Target only triggers the request when the target of the event matches the CSS selector
+func (t *TriggerSpec) Target(selector string) *TriggerSpec {
+	return t.modifier("target:" + selector)
+}
+
+// Consume stops the event from triggering requests of parent elements
+func (t *TriggerSpec) Consume() *TriggerSpec {
+	return t.modifier("consume")
+}
+
+// Queue determines which events are queued while a request is in flight
+func (t *TriggerSpec) Queue(queue TriggerQueue) *TriggerSpec {
+	return t.modifier("queue:" + string(queue))
+}
+
+// String returns the value of the hx-trigger attribute
+func (t *TriggerSpec) String() string {
+	events := make([]string, len(t.events))
+
+	for i, e := range t.events {
+		var sb strings.Builder
+		sb.WriteString(e.event)
+
+		if e.filter != "" {
+			// polling takes its condition after a space, like the htmx documentation shows it
+			if strings.HasPrefix(e.event, "every ") {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString("[" + e.filter + "]")
+		}
+
+		for _, m := range e.modifiers {
+			sb.WriteString(" " + m)
+		}
+
+		events[i] = sb.String()
+	}
+
+	return strings.Join(events, ", ")
+}
+
+// Trigger sets hx-trigger to the trigger specification
+func (a *Attributes) Trigger(t *TriggerSpec) *Attributes {
+	return a.Set(AttrTrigger, t.String())
+}
+
+// modifier adds the modifier to the last added event
+func (t *TriggerSpec) modifier(m string) *TriggerSpec {
+	if len(t.events) > 0 {
+		last := &t.events[len(t.events)-1]
+		last.modifiers = append(last.modifiers, m)
+	}
+
+	return t
+}
+
+// triggerSpecModifiers are the modifiers accepted by the trigger template function, with whether they take a value
+var triggerSpecModifiers = map[string]bool{
+	"once": false, "changed": false, "consume": false,
+	"delay": true, "throttle": true, "from": true, "target": true, "queue": true,
+}
+
+// triggerSpecFunc returns a trigger specification for the event and modifiers, to be used in templates
+//
+//	<input name="q" {{ hxGet "/search" | hxTrigger (trigger "keyup" "changed" "delay:500ms") }}>
+func triggerSpecFunc(event string, modifiers ...string) (*TriggerSpec, error) {
+	t := NewTriggerSpec(event)
+
+	for _, m := range modifiers {
+		name, value, hasValue := strings.Cut(m, ":")
+
+		takesValue, ok := triggerSpecModifiers[name]
+		if !ok || takesValue != hasValue || hasValue && value == "" {
+			return nil, fmt.Errorf("htmx: invalid hx-trigger modifier %q", m)
+		}
+
+		t.modifier(m)
+	}
+
+	return t, nil
+}
+
+// htmxDuration formats the duration as htmx parses it, in seconds when possible and in milliseconds otherwise
+func htmxDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
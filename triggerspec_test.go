@@ -0,0 +1,49 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTriggerSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *TriggerSpec
+		expected string
+	}{
+		{"event", NewTriggerSpec("click"), "click"},
+		{"modifiers", NewTriggerSpec("keyup").Changed().Delay(500 * time.Millisecond).Queue(TriggerQueueLast), "keyup changed delay:500ms queue:last"},
+		{"filter", NewTriggerSpec("keyup").Filter("key=='Enter'").Once(), "keyup[key=='Enter'] once"},
+		{"multiple events", NewTriggerSpec("click").From("document").Consume().Or("load").Throttle(2 * time.Second), "click from:document consume, load throttle:2s"},
+		{"target", NewTriggerSpec("click").Target(".row"), "click target:.row"},
+		{"polling", NewPollingSpec(2*time.Second, "isActive"), "every 2s [isActive]"},
+		{"polling with event", NewTriggerSpec("load").Every(1500 * time.Millisecond), "load, every 1500ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal(t, tt.expected, tt.spec.String())
+		})
+	}
+
+	equal(t, `hx-trigger="revealed once"`, NewAttributes().Trigger(NewTriggerSpec("revealed").Once()).String())
+}
+
+func TestTriggerSpecFunc(t *testing.T) {
+	fsys := fstest.MapFS{
+		"triggerspec.html":         {Data: []byte(`<input {{ hxGet "/search" | hxTrigger (trigger "keyup" "changed" "delay:500ms") }}>`)},
+		"triggerspec-invalid.html": {Data: []byte(`<input {{ hxTrigger (trigger "keyup" "delay") }}>`)},
+	}
+
+	output, err := NewComponent("triggerspec.html").FS(fsys).Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<input hx-get="/search" hx-trigger="keyup changed delay:500ms">`, string(output))
+
+	if _, err := NewComponent("triggerspec-invalid.html").FS(fsys).Render(context.Background()); err == nil {
+		t.Error("expected an error for a modifier without its value")
+	}
+}
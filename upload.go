@@ -0,0 +1,268 @@
+package htmx
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//go:embed templates/upload.html templates/upload_result.html
+var uploadTemplates embed.FS
+
+var (
+	// DefaultMaxUploadSize is the size of the request body an upload may have, when the options don't set one
+	DefaultMaxUploadSize int64 = 32 << 20
+
+	// ErrUploadTooLarge is returned when the body of an upload exceeds its maximum size
+	ErrUploadTooLarge = errors.New("htmx: upload too large")
+
+	// ErrFileTooLarge is returned when a file of an upload exceeds its maximum size
+	ErrFileTooLarge = errors.New("htmx: file too large")
+)
+
+type (
+	// Upload renders a form that uploads files with hx-post and shows the progress of the upload. The response is
+	// swapped into the result element below the input, UploadHandler renders it.
+	//
+	//	page.With(htmx.NewUpload("avatar", "/avatar").Accept("image/*").Component(), "upload")
+	Upload struct {
+		id       string
+		url      string
+		field    string
+		label    string
+		submit   string
+		accept   []string
+		multiple bool
+	}
+
+	// UploadOptions are the limits of an upload
+	UploadOptions struct {
+		MaxSize     int64 // size of the request body, defaults to DefaultMaxUploadSize
+		MaxFileSize int64 // size of a single file, defaults to MaxSize
+	}
+
+	// UploadedFile is a file of an upload, its size is known once it was read
+	UploadedFile struct {
+		Field       string
+		Name        string
+		ContentType string
+		Size        int64
+	}
+
+	// UploadResult are the files and the other form values of an upload
+	UploadResult struct {
+		Files  []UploadedFile
+		Values url.Values
+	}
+
+	// UploadFunc stores a file of an upload while it is streamed from the request, reading content fails with
+	// ErrFileTooLarge or ErrUploadTooLarge when the file exceeds the limits
+	UploadFunc func(ctx context.Context, file *UploadedFile, content io.Reader) error
+
+	// UploadError is an error of an UploadFunc whose message is shown to the user, e.g. for a rejected file type
+	UploadError struct {
+		Message string
+	}
+
+	// fileLimitReader fails with ErrFileTooLarge after more than limit bytes were read
+	fileLimitReader struct {
+		r     io.Reader
+		n     int64
+		limit int64
+	}
+)
+
+// NewUpload returns an upload form with the id that posts the files to the url
+func NewUpload(id, url string) *Upload {
+	return &Upload{id: id, url: url, field: "file", submit: "Upload"}
+}
+
+// Field sets the name of the file input, it defaults to file
+func (u *Upload) Field(name string) *Upload {
+	u.field = name
+	return u
+}
+
+// Label sets the label of the file input
+func (u *Upload) Label(label string) *Upload {
+	u.label = label
+	return u
+}
+
+// Submit sets the label of the submit button
+func (u *Upload) Submit(label string) *Upload {
+	u.submit = label
+	return u
+}
+
+// Accept sets the content types and extensions the file input accepts, e.g. image/* or .pdf
+func (u *Upload) Accept(types ...string) *Upload {
+	u.accept = append(u.accept, types...)
+	return u
+}
+
+// Multiple allows to upload multiple files at once
+func (u *Upload) Multiple() *Upload {
+	u.multiple = true
+	return u
+}
+
+// Component returns the component of the upload form
+func (u *Upload) Component() *Component {
+	attrs := NewAttributes().
+		Set("hx-post", u.url).
+		Set("hx-encoding", "multipart/form-data").
+		Set("hx-target", "#"+u.id+"-result")
+
+	c := NewComponent("templates/upload.html").FS(uploadTemplates)
+	c.AddData("ID", u.id)
+	c.AddData("Field", u.field)
+	c.AddData("Label", u.label)
+	c.AddData("Submit", u.submit)
+	c.AddData("Accept", strings.Join(u.accept, ","))
+	c.AddData("Multiple", u.multiple)
+	c.AddData("Attributes", attrs.HTMLAttr())
+
+	return c
+}
+
+// Error returns the message of the error
+func (e *UploadError) Error() string {
+	return e.Message
+}
+
+// StreamUpload streams the files of the multipart request to fn one by one, without buffering them in memory or on
+// disk. The other form values are collected into the result. The body is limited to the MaxSize of the options and
+// every file to the MaxFileSize.
+func StreamUpload(w http.ResponseWriter, r *http.Request, opts UploadOptions, fn UploadFunc) (*UploadResult, error) {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxUploadSize
+	}
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = opts.MaxSize
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, opts.MaxSize)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UploadResult{Values: url.Values{}}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, uploadError(err)
+		}
+
+		if part.FileName() == "" {
+			value, err := io.ReadAll(&fileLimitReader{r: part, limit: opts.MaxFileSize})
+			if err != nil {
+				return nil, uploadError(err)
+			}
+
+			result.Values.Add(part.FormName(), string(value))
+			continue
+		}
+
+		file := UploadedFile{Field: part.FormName(), Name: part.FileName(), ContentType: part.Header.Get("Content-Type")}
+		content := &fileLimitReader{r: part, limit: opts.MaxFileSize}
+
+		if err := fn(r.Context(), &file, content); err != nil {
+			return nil, uploadError(err)
+		}
+
+		// the rest of a file that wasn't read counts towards its size
+		if _, err := io.Copy(io.Discard, content); err != nil {
+			return nil, uploadError(err)
+		}
+
+		file.Size = content.n
+		result.Files = append(result.Files, file)
+	}
+}
+
+// UploadHandler returns a handler that streams the files of the upload to fn and renders the uploaded files, or
+// the error of the upload. htmx doesn't swap error responses, so htmx requests get the error with status 200.
+func (h *HTMX) UploadHandler(opts UploadOptions, fn UploadFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler := h.NewHandler(w, r)
+
+		result, err := StreamUpload(w, r, opts, fn)
+		if err != nil {
+			status, message := uploadErrorMessage(err)
+			if status == http.StatusInternalServerError {
+				h.log.Warn("unable to store the upload", "error", err)
+			}
+
+			if !IsHxRequest(r) {
+				handler.WriteHeader(status)
+			}
+
+			if _, err := handler.Render(r.Context(), NewUploadResult(nil, message)); err != nil {
+				h.log.Warn("unable to render the upload error", "error", err)
+			}
+			return
+		}
+
+		if _, err := handler.Render(r.Context(), NewUploadResult(result.Files, "")); err != nil {
+			h.log.Warn("unable to render the upload", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	})
+}
+
+// NewUploadResult returns a component that renders the uploaded files, or the error message when it is set
+func NewUploadResult(files []UploadedFile, message string) *Component {
+	c := NewComponent("templates/upload_result.html").FS(uploadTemplates)
+	c.AddData("Files", files)
+	c.AddData("Error", message)
+
+	return c
+}
+
+// Read reads from the reader and fails once more than the limit was read
+func (l *fileLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		return n, ErrFileTooLarge
+	}
+
+	return n, err
+}
+
+// uploadError returns ErrUploadTooLarge for a body that exceeded its limit
+func uploadError(err error) error {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		return ErrUploadTooLarge
+	}
+
+	return err
+}
+
+// uploadErrorMessage returns the status and the message shown to the user for the error of an upload
+func uploadErrorMessage(err error) (int, string) {
+	var uploadErr *UploadError
+
+	switch {
+	case errors.Is(err, ErrUploadTooLarge), errors.Is(err, ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge, "The file is too large"
+	case errors.Is(err, http.ErrNotMultipart), errors.Is(err, http.ErrMissingBoundary):
+		return http.StatusBadRequest, "No file was uploaded"
+	case errors.As(err, &uploadErr):
+		return http.StatusUnprocessableEntity, uploadErr.Message
+	default:
+		return http.StatusInternalServerError, "The upload failed"
+	}
+}
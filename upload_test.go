@@ -0,0 +1,106 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func multipartUpload(t *testing.T, files map[string]string, values map[string]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range values {
+		_ = mw.WriteField(name, value)
+	}
+	for name, content := range files {
+		fw, err := mw.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write([]byte(content))
+	}
+	_ = mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	r.Header.Set("HX-Request", "true")
+	return r
+}
+
+func TestUpload(t *testing.T) {
+	output, err := NewUpload("avatar", "/avatar").Accept("image/*", ".png").Label("Avatar").Component().Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`<form id="avatar" class="upload" hx-post="/avatar" hx-encoding="multipart/form-data" hx-target="#avatar-result"`,
+		`<input id="avatar-input" type="file" name="file" accept="image/*,.png">`,
+		`<progress value="0" max="100"></progress>`,
+	} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("expected %s in %s", expected, output)
+		}
+	}
+}
+
+func TestStreamUpload(t *testing.T) {
+	r := multipartUpload(t, map[string]string{"notes.txt": "hello world"}, map[string]string{"title": "Notes"})
+
+	var stored string
+	result, err := StreamUpload(httptest.NewRecorder(), r, UploadOptions{}, func(_ context.Context, file *UploadedFile, content io.Reader) error {
+		b, err := io.ReadAll(content)
+		stored = file.Name + ":" + string(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, "notes.txt:hello world", stored)
+	equal(t, "Notes", result.Values.Get("title"))
+	equalInt(t, 11, int(result.Files[0].Size))
+}
+
+func TestUploadHandler(t *testing.T) {
+	handler := New().UploadHandler(UploadOptions{MaxFileSize: 5}, func(_ context.Context, file *UploadedFile, content io.Reader) error {
+		if strings.HasSuffix(file.Name, ".exe") {
+			return &UploadError{Message: "Executables are not allowed"}
+		}
+
+		_, err := io.Copy(io.Discard, content)
+		return err
+	})
+
+	tests := []struct {
+		files    map[string]string
+		expected string
+	}{
+		{map[string]string{"a.txt": "abc"}, "<ul class=\"upload-files\">\n\t<li>a.txt</li>\n</ul>"},
+		{map[string]string{"big.txt": "too large"}, `<p class="upload-error" role="alert">The file is too large</p>`},
+		{map[string]string{"run.exe": "x"}, `<p class="upload-error" role="alert">Executables are not allowed</p>`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, multipartUpload(t, tt.files, nil))
+
+		equalInt(t, http.StatusOK, w.Code)
+		equal(t, tt.expected, w.Body.String())
+	}
+
+	// requests without htmx get the status of the error
+	r := multipartUpload(t, map[string]string{"big.txt": "too large"}, nil)
+	r.Header.Del("HX-Request")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	equalInt(t, http.StatusRequestEntityTooLarge, w.Code)
+}
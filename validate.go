@@ -0,0 +1,79 @@
+package htmx
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// Validator validates a struct, the errors of its fields are returned as FieldErrors.
+// The adapters/validator package provides one for go-playground/validator.
+type Validator interface {
+	Validate(v any) error
+}
+
+// DefaultValidator is the validator of Validate and BindAndValidate, nil skips the validation
+var DefaultValidator Validator
+
+// Validate validates the struct with the DefaultValidator
+func Validate(v any) error {
+	if DefaultValidator == nil {
+		return nil
+	}
+
+	return DefaultValidator.Validate(v)
+}
+
+// BindAndValidate binds the values of the request to the struct and validates it with the DefaultValidator. The
+// struct is validated when values couldn't be converted as well, the field errors of both are returned together.
+//
+//	var signup Signup
+//	if err := htmx.BindAndValidate(r, &signup); err != nil {
+//		if !htmx.ValidationErrors(component, r, err) {
+//			http.Error(w, err.Error(), http.StatusBadRequest)
+//			return
+//		}
+//		h.Render(r.Context(), component)
+//		return
+//	}
+func BindAndValidate(r *http.Request, v any) error {
+	var bindErrs FieldErrors
+	if err := Bind(r, v); err != nil && !errors.As(err, &bindErrs) {
+		return err
+	}
+
+	err := Validate(v)
+
+	var validationErrs FieldErrors
+	if err != nil && !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	if errs := slices.Concat(bindErrs, validationErrs); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ValidationErrors sets the field errors of err on the form of the component, along with the submitted values of the
+// request, so the old, fieldError and hasError template functions render the form again. It returns false when err
+// has no field errors, e.g. for a malformed request, which the handler has to answer otherwise.
+func ValidationErrors(c *Component, r *http.Request, err error) bool {
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		return false
+	}
+
+	if c.form == nil {
+		c.form = &Form{Values: r.Form}
+	}
+
+	for field, message := range errs.Map() {
+		if c.form.Error(field) == "" {
+			c.form.AddError(field, message)
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,51 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type validatorFunc func(v any) error
+
+func (f validatorFunc) Validate(v any) error {
+	return f(v)
+}
+
+func TestValidationErrors(t *testing.T) {
+	type signup struct {
+		Email string `form:"email"`
+		Age   int    `form:"age"`
+	}
+
+	DefaultValidator = validatorFunc(func(v any) error {
+		if v.(*signup).Email == "" {
+			return FieldErrors{{Field: "email", Message: "is required"}}
+		}
+		return nil
+	})
+	defer func() { DefaultValidator = nil }()
+
+	fsys := fstest.MapFS{
+		"validation-errors.html": {Data: []byte(`<input name="age" value="{{ old "age" }}"><span>{{ fieldError "age" }}</span><span>{{ fieldError "email" }}</span>`)},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("email=&age=x"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var s signup
+	err := BindAndValidate(r, &s)
+
+	c := NewComponent("validation-errors.html").FS(fsys)
+	equalBool(t, true, ValidationErrors(c, r, err))
+
+	out, err := c.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, `<input name="age" value="x"><span>must be a whole number</span><span>is required</span>`, string(out))
+}
@@ -0,0 +1,84 @@
+package htmx
+
+import (
+	"net/http"
+	"strings"
+)
+
+// fragmentVary are the request headers that decide between the fragment and the full page
+var fragmentVary = []string{
+	HxRequestHeaderRequest.String(),
+	HxRequestHeaderBoosted.String(),
+	HxRequestHeaderHistoryRestoreRequest.String(),
+}
+
+// Vary adds the request headers to the Vary header of the response, so caches keep a response per value of the
+// headers. The accessors of the htmx request headers add their own header, and Render adds the headers that decide
+// between the fragment and the full page for wrapped components. Handlers that branch on Request call Vary themselves.
+func (h *Handler) Vary(headers ...string) {
+	addVary(h.w.Header(), headers...)
+}
+
+// HxTarget returns the id of the target element, the response varies by HX-Target
+func (h *Handler) HxTarget() string {
+	h.Vary(HxRequestHeaderTarget.String())
+	return h.request.HxTarget
+}
+
+// HxTrigger returns the id of the triggered element, the response varies by HX-Trigger
+func (h *Handler) HxTrigger() string {
+	h.Vary(HxRequestHeaderTrigger.String())
+	return h.request.HxTrigger
+}
+
+// HxTriggerName returns the name of the triggered element, the response varies by HX-Trigger-Name
+func (h *Handler) HxTriggerName() string {
+	h.Vary(HxRequestHeaderTriggerName.String())
+	return h.request.HxTriggerName
+}
+
+// varyFragment adds the headers that decide between the fragment and the full page, if the output depends on them
+func (h *Handler) varyFragment(r RenderableComponent) {
+	wrapped := r.isWrapped()
+	if b, ok := r.(boostedWrapper); ok {
+		layout, _ := b.boostedLayout()
+		wrapped = wrapped || layout != nil
+	}
+
+	if wrapped {
+		h.Vary(fragmentVary...)
+	}
+}
+
+// addVary adds the values to the Vary header that it doesn't contain yet, the values are merged into a single line
+func addVary(header http.Header, values ...string) {
+	var vary []string
+	seen := make(map[string]bool)
+
+	for _, line := range header.Values("Vary") {
+		for _, v := range strings.Split(line, ",") {
+			v = strings.TrimSpace(v)
+			if v == "*" {
+				// the response varies by everything already
+				return
+			}
+
+			if v != "" && !seen[strings.ToLower(v)] {
+				seen[strings.ToLower(v)] = true
+				vary = append(vary, v)
+			}
+		}
+	}
+
+	n := len(vary)
+	for _, v := range values {
+		if !seen[strings.ToLower(v)] {
+			seen[strings.ToLower(v)] = true
+			vary = append(vary, v)
+		}
+	}
+
+	if len(vary) > n {
+		header.Set("Vary", strings.Join(vary, ", "))
+	}
+}
@@ -0,0 +1,45 @@
+package htmx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVary(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vary-layout.html": {Data: []byte(`<main>{{ .Partials.content }}</main>`)},
+		"vary-list.html":   {Data: []byte(`<ul></ul>`)},
+	}
+
+	render := func(c RenderableComponent) http.Header {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if _, err := New().NewHandler(w, r).Render(context.Background(), c); err != nil {
+			t.Fatal(err)
+		}
+
+		return w.Header()
+	}
+
+	equal(t, "", render(NewComponent("vary-list.html").FS(fsys)).Get("Vary"))
+	equal(t, "HX-Request, HX-Boosted, HX-History-Restore-Request",
+		render(NewComponent("vary-list.html").FS(fsys).Wrap(NewComponent("vary-layout.html").FS(fsys), "content")).Get("Vary"))
+
+	w := httptest.NewRecorder()
+	h := New().NewHandler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	w.Header().Set("Vary", "accept-encoding")
+	h.HxTarget()
+	h.HxTrigger()
+	h.IsHxRequest()
+	h.Vary("Accept-Encoding", "HX-Target")
+	equal(t, "accept-encoding, HX-Target, HX-Trigger, HX-Request", w.Header().Get("Vary"))
+
+	w.Header().Set("Vary", "*")
+	h.Vary("HX-Request")
+	equal(t, "*", w.Header().Get("Vary"))
+}
@@ -0,0 +1,118 @@
+package vet
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// Reference is a reference to the data of a component in a template, {{ .Data.Key }} or {{ $.Data.Key }}
+type Reference struct {
+	Key  string // the data key
+	Line int    // the line of the reference in the template
+}
+
+// References returns the references to the data of the component in the template source, sorted by line. References
+// within range and with, where the dot is an element of the data, are only found through $.
+func References(name string, src []byte) ([]Reference, error) {
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+
+	trees := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(string(src), "", "", trees); err != nil {
+		return nil, err
+	}
+
+	// defined templates are walked as if they were called with the dot of the component
+	names := make([]string, 0, len(trees))
+	for n := range trees {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var refs []Reference
+	for _, n := range names {
+		t := trees[n]
+		if t.Root == nil {
+			continue
+		}
+
+		w := &referenceWalker{tree: t}
+		w.walk(t.Root, true)
+		refs = append(refs, w.refs...)
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].Line < refs[j].Line })
+	return refs, nil
+}
+
+// referenceWalker collects the data references of a parse tree
+type referenceWalker struct {
+	tree *parse.Tree
+	refs []Reference
+}
+
+// walk walks the node, root is true while the dot is the data of the component
+func (w *referenceWalker) walk(node parse.Node, root bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, root)
+		}
+	case *parse.ActionNode:
+		w.walk(n.Pipe, root)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			w.walk(cmd, root)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			w.walk(arg, root)
+		}
+	case *parse.FieldNode:
+		if root && len(n.Ident) > 1 && n.Ident[0] == "Data" {
+			w.add(n, n.Ident[1])
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 2 && n.Ident[0] == "$" && n.Ident[1] == "Data" {
+			w.add(n, n.Ident[2])
+		}
+	case *parse.ChainNode:
+		w.walk(n.Node, root)
+	case *parse.IfNode:
+		w.walk(n.Pipe, root)
+		w.walk(n.List, root)
+		w.walk(n.ElseList, root)
+	case *parse.RangeNode:
+		w.walk(n.Pipe, root)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, root)
+	case *parse.WithNode:
+		w.walk(n.Pipe, root)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, root)
+	case *parse.TemplateNode:
+		w.walk(n.Pipe, root)
+	}
+}
+
+// add adds a reference to the key at the position of the node
+func (w *referenceWalker) add(node parse.Node, key string) {
+	location, _ := w.tree.ErrorContext(node)
+
+	// the location is name:line:column
+	parts := strings.Split(location, ":")
+	line := 0
+	if len(parts) >= 3 {
+		line, _ = strconv.Atoi(parts[len(parts)-2])
+	}
+
+	w.refs = append(w.refs, Reference{Key: key, Line: line})
+}
@@ -0,0 +1,368 @@
+// Package vet checks the data references of templates against the data their components are given. It finds the
+// components that Go sources construct with NewComponent, the keys they set with AddData, SetData and Requires, and
+// reports the {{ .Data.Key }} references of their templates that no component provides, like a typo in a key.
+package vet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Component is a component constructed with NewComponent in Go source
+	Component struct {
+		Pos       token.Position // the position of the NewComponent call
+		Scope     string         // the function that constructs the component, the data of its components is shared
+		Templates []string       // the templates of NewComponent and Attach
+		Keys      []string       // the keys set with AddData and SetData
+		Requires  []string       // the keys declared with Requires
+		Dynamic   bool           // the templates or keys are not all constant, the component isn't checked
+	}
+
+	// Diagnostic is a data reference that no component provides
+	Diagnostic struct {
+		Template  string    // the template of the reference
+		Reference Reference // the reference
+		Component Component // the component of the template
+		Suggest   string    // the provided key that is closest to the referenced key, if any
+	}
+)
+
+// String returns the diagnostic as file:line: message
+func (d Diagnostic) String() string {
+	msg := fmt.Sprintf("%s:%d: .Data.%s is not provided by %s:%d", d.Template, d.Reference.Line, d.Reference.Key,
+		d.Component.Pos.Filename, d.Component.Pos.Line)
+	if d.Suggest != "" {
+		msg += fmt.Sprintf(" (did you mean .Data.%s?)", d.Suggest)
+	}
+
+	return msg
+}
+
+// Components returns the components constructed with NewComponent in the Go source, in source order. The methods
+// called on the result of NewComponent, directly or through a variable of the same function, add to the component.
+func Components(filename string, src []byte) ([]Component, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []*Component
+	byCall := make(map[*ast.CallExpr]*Component)
+	byObject := make(map[*ast.Object]*Component)
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		scope := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			scope = receiverName(fn.Recv.List[0].Type) + "." + scope
+		}
+
+		// the constructors first, so the variables they are assigned to are known when their methods are called
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isNewComponent(call) {
+				return true
+			}
+
+			c := &Component{Pos: fset.Position(call.Pos()), Scope: scope}
+			for _, arg := range call.Args {
+				if s, ok := stringLit(arg); ok {
+					c.Templates = append(c.Templates, s)
+				} else {
+					c.Dynamic = true
+				}
+			}
+
+			components = append(components, c)
+			byCall[call] = c
+			return true
+		})
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) == len(n.Rhs) {
+					for i, lhs := range n.Lhs {
+						bind(byObject, byCall, lhs, n.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				if len(n.Names) == len(n.Values) {
+					for i, name := range n.Names {
+						bind(byObject, byCall, name, n.Values[i])
+					}
+				}
+			}
+			return true
+		})
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			c := root(byObject, byCall, sel.X)
+			if c == nil {
+				return true
+			}
+
+			addMethod(c, sel.Sel.Name, call.Args)
+			return true
+		})
+	}
+
+	out := make([]Component, len(components))
+	for i, c := range components {
+		out[i] = *c
+	}
+
+	return out, nil
+}
+
+// Check checks the data references of the templates of the components, load returns the source of a template and
+// false when it can't be found. Components that are dynamic or whose scope provides no keys at all are skipped, as
+// their data can't be known.
+func Check(components []Component, load func(name string) ([]byte, bool)) ([]Diagnostic, error) {
+	type scope struct {
+		keys    map[string]bool
+		dynamic bool
+	}
+
+	scopes := make(map[string]*scope)
+	for _, c := range components {
+		key := c.Pos.Filename + "|" + c.Scope
+		s, ok := scopes[key]
+		if !ok {
+			s = &scope{keys: make(map[string]bool)}
+			scopes[key] = s
+		}
+
+		s.dynamic = s.dynamic || c.Dynamic
+		for _, k := range c.Keys {
+			s.keys[k] = true
+		}
+		for _, k := range c.Requires {
+			s.keys[k] = true
+		}
+	}
+
+	var diagnostics []Diagnostic
+	for _, c := range components {
+		s := scopes[c.Pos.Filename+"|"+c.Scope]
+		if s.dynamic || len(s.keys) == 0 {
+			continue
+		}
+
+		provided := make([]string, 0, len(s.keys))
+		for k := range s.keys {
+			provided = append(provided, k)
+		}
+		sort.Strings(provided)
+
+		for _, name := range c.Templates {
+			src, ok := load(name)
+			if !ok {
+				continue
+			}
+
+			refs, err := References(name, src)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			for _, ref := range refs {
+				if s.keys[ref.Key] {
+					continue
+				}
+
+				diagnostics = append(diagnostics, Diagnostic{
+					Template:  name,
+					Reference: ref,
+					Component: c,
+					Suggest:   closest(ref.Key, provided),
+				})
+			}
+		}
+	}
+
+	return diagnostics, nil
+}
+
+// addMethod adds the arguments of a method called on the component
+func addMethod(c *Component, method string, args []ast.Expr) {
+	switch method {
+	case "AddData":
+		if len(args) != 2 {
+			return
+		}
+		if key, ok := stringLit(args[0]); ok {
+			c.Keys = append(c.Keys, key)
+		} else {
+			c.Dynamic = true
+		}
+	case "SetData":
+		if len(args) != 1 {
+			return
+		}
+		lit, ok := args[0].(*ast.CompositeLit)
+		if !ok {
+			c.Dynamic = true
+			return
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := stringLit(kv.Key); ok {
+				c.Keys = append(c.Keys, key)
+			} else {
+				c.Dynamic = true
+			}
+		}
+	case "Requires":
+		for _, arg := range args {
+			if key, ok := stringLit(arg); ok {
+				c.Requires = append(c.Requires, key)
+			} else {
+				c.Dynamic = true
+			}
+		}
+	case "Attach":
+		if len(args) != 1 {
+			return
+		}
+		if name, ok := stringLit(args[0]); ok {
+			c.Templates = append(c.Templates, name)
+		} else {
+			c.Dynamic = true
+		}
+	}
+}
+
+// bind binds the variable to the component of the value, when the value is a component
+func bind(byObject map[*ast.Object]*Component, byCall map[*ast.CallExpr]*Component, lhs, value ast.Expr) {
+	ident, ok := lhs.(*ast.Ident)
+	if !ok || ident.Obj == nil {
+		return
+	}
+
+	if c := root(byObject, byCall, value); c != nil {
+		byObject[ident.Obj] = c
+	}
+}
+
+// root returns the component that the expression, a chain of method calls, starts with
+func root(byObject map[*ast.Object]*Component, byCall map[*ast.CallExpr]*Component, expr ast.Expr) *Component {
+	for {
+		switch e := expr.(type) {
+		case *ast.CallExpr:
+			if c, ok := byCall[e]; ok {
+				return c
+			}
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return nil
+			}
+			expr = sel.X
+		case *ast.Ident:
+			if e.Obj == nil {
+				return nil
+			}
+			return byObject[e.Obj]
+		case *ast.ParenExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// isNewComponent returns true for calls of NewComponent, qualified or not
+func isNewComponent(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name == "NewComponent"
+	case *ast.SelectorExpr:
+		return fun.Sel.Name == "NewComponent"
+	}
+
+	return false
+}
+
+// stringLit returns the value of a string literal
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// receiverName returns the name of the receiver type of a method
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	}
+
+	return ""
+}
+
+// closest returns the key that is at most two edits away from the referenced key, ignoring case, if any
+func closest(key string, keys []string) string {
+	best, bestDistance := "", 3
+	for _, k := range keys {
+		if d := distance(strings.ToLower(key), strings.ToLower(k)); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+
+	return best
+}
+
+// distance returns the Levenshtein distance of the strings
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
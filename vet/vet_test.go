@@ -0,0 +1,100 @@
+package vet
+
+import (
+	"strings"
+	"testing"
+)
+
+const views = `package views
+
+import "github.com/jkc-2/go-htmx"
+
+func NewUserPage(name string, items []string) htmx.RenderableComponent {
+	list := htmx.NewComponent("list.html").AddData("Items", items)
+	list.AddData("Title", "Items")
+
+	return htmx.NewComponent("page.html").
+		SetData(map[string]any{"Name": name}).
+		With(list, "list")
+}
+
+func NewCard(card htmx.RenderableComponent) *htmx.Component {
+	return htmx.NewComponent("card.html").Requires("Title")
+}
+
+func NewDynamic(key string) htmx.RenderableComponent {
+	return htmx.NewComponent("dynamic.html").AddData(key, 1)
+}
+`
+
+func TestComponents(t *testing.T) {
+	components, err := Components("views.go", []byte(views))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, 4, len(components))
+
+	list := components[0]
+	equal(t, "NewUserPage", list.Scope)
+	equal(t, "list.html", strings.Join(list.Templates, ","))
+	equal(t, "Items,Title", strings.Join(list.Keys, ","))
+	equal(t, 6, list.Pos.Line)
+
+	equal(t, "Name", strings.Join(components[1].Keys, ","))
+	equal(t, "Title", strings.Join(components[2].Requires, ","))
+	equal(t, true, components[3].Dynamic)
+}
+
+func TestReferences(t *testing.T) {
+	src := `<h1>{{ .Data.Title }}</h1>
+{{ range .Data.Items }}<li>{{ .Name }} {{ $.Data.Owner }}</li>{{ end }}
+{{ with .Data.User }}{{ .Data.Ignored }}{{ else }}{{ .Data.Guest }}{{ end }}
+{{ define "footer" }}{{ .Data.Year | printf "%d" }}{{ end }}`
+
+	refs, err := References("page.html", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, r := range refs {
+		keys = append(keys, r.Key)
+	}
+	equal(t, "Title Items Owner User Guest Year", strings.Join(keys, " "))
+	equal(t, 2, refs[2].Line)
+}
+
+func TestCheck(t *testing.T) {
+	components, err := Components("views.go", []byte(views))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates := map[string]string{
+		"page.html":    `<main>{{ .Data.Nmae }} {{ .Partials.list }}</main>`,
+		"list.html":    `<h2>{{ .Data.Title }}</h2>{{ range .Data.Items }}{{ . }}{{ end }}`,
+		"card.html":    `{{ .Data.Title }} {{ .Data.Body }}`,
+		"dynamic.html": `{{ .Data.Anything }}`,
+	}
+
+	diagnostics, err := Check(components, func(name string) ([]byte, bool) {
+		src, ok := templates[name]
+		return []byte(src), ok
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, 2, len(diagnostics))
+	equal(t, "page.html:1: .Data.Nmae is not provided by views.go:9 (did you mean .Data.Name?)", diagnostics[0].String())
+	equal(t, "card.html:1: .Data.Body is not provided by views.go:15", diagnostics[1].String())
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
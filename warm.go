@@ -0,0 +1,130 @@
+package htmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TemplateParse is the parse of the templates of a component by WarmCache
+type TemplateParse struct {
+	Templates []string      // the templates of the component
+	Duration  time.Duration // the time it took to parse the templates
+	Cached    bool          // the templates were already cached, they weren't parsed again
+	Err       error         // the parse error, if any
+}
+
+// String returns the parse as templates: duration
+func (p TemplateParse) String() string {
+	status := p.Duration.String()
+	switch {
+	case p.Err != nil:
+		status = p.Err.Error()
+	case p.Cached:
+		status += " (cached)"
+	}
+
+	return strings.Join(p.Templates, ",") + ": " + status
+}
+
+// WarmCache parses the templates of the components, their partials and their layouts ahead of traffic, so the first
+// request after a deploy doesn't pay for parsing them. It returns the parse of every distinct component in the order
+// they were walked and the parse errors. Templates of tenants are parsed on their first render.
+//
+//	parses, err := htmx.WarmCache(ctx, pages.Home(), pages.Users())
+//	for _, p := range parses {
+//		log.Println(p)
+//	}
+func WarmCache(ctx context.Context, components ...RenderableComponent) ([]TemplateParse, error) {
+	var (
+		parses []TemplateParse
+		errs   []error
+	)
+	seen := make(map[RenderableComponent]bool)
+
+	var walk func(c RenderableComponent) error
+	walk = func(c RenderableComponent) error {
+		if c == nil || seen[c] {
+			return nil
+		}
+		seen[c] = true
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if templates := c.templateFiles(); len(templates) > 0 {
+			start := time.Now()
+			cached, err := c.warmTemplates()
+			parses = append(parses, TemplateParse{
+				Templates: templates,
+				Duration:  time.Since(start),
+				Cached:    cached,
+				Err:       err,
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", strings.Join(templates, ","), err))
+			}
+		}
+
+		if c.isWrapped() {
+			if err := walk(c.wrapper()); err != nil {
+				return err
+			}
+		}
+
+		if b, ok := c.(boostedWrapper); ok {
+			if layout, _ := b.boostedLayout(); layout != nil {
+				if err := walk(layout); err != nil {
+					return err
+				}
+			}
+		}
+
+		partials := c.partials()
+		targets := make([]string, 0, len(partials))
+		for target := range partials {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		for _, target := range targets {
+			if err := walk(partials[target]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, c := range components {
+		if err := walk(c); err != nil {
+			return parses, err
+		}
+	}
+
+	return parses, errors.Join(errs...)
+}
+
+// WarmCacheFS parses every template of the filesystem that matches the pattern, see fs.Glob, as the template of a
+// component of its own. It warms the cache of components that render a single template without template functions
+// of their own, the templates of other components are cached under other keys and should be warmed with WarmCache.
+//
+//	parses, err := htmx.WarmCacheFS(ctx, templates, "partials/*.html")
+func WarmCacheFS(ctx context.Context, fsys fs.FS, pattern string) ([]TemplateParse, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]RenderableComponent, len(names))
+	for i, name := range names {
+		components[i] = NewComponent(name).FS(fsys)
+	}
+
+	return WarmCache(ctx, components...)
+}
@@ -0,0 +1,54 @@
+package htmx
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWarmCache(t *testing.T) {
+	ClearTemplateCache()
+	defer ClearTemplateCache()
+
+	fsys := fstest.MapFS{
+		"warm-layout.html": {Data: []byte(`<main>{{ .Partials.content }}</main>`)},
+		"warm-page.html":   {Data: []byte(`<h1>{{ .Data.Title }}</h1>{{ .Partials.list }}`)},
+		"warm-list.html":   {Data: []byte(`<ul></ul>`)},
+		"warm-broken.html": {Data: []byte(`{{ if }}`)},
+	}
+
+	page := NewComponent("warm-page.html").FS(fsys).With(NewComponent("warm-list.html").FS(fsys), "list")
+	page.Wrap(NewComponent("warm-layout.html").FS(fsys), "content")
+
+	parses, err := WarmCache(context.Background(), page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalInt(t, 3, len(parses))
+	equal(t, "warm-page.html", parses[0].Templates[0])
+	equal(t, "warm-layout.html", parses[1].Templates[0])
+	equal(t, "warm-list.html", parses[2].Templates[0])
+	equalBool(t, false, parses[0].Cached)
+	equalInt(t, 3, cachedTemplates())
+
+	parses, err = WarmCache(context.Background(), page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalBool(t, true, parses[0].Cached)
+	equal(t, "warm-list.html: "+parses[2].Duration.String()+" (cached)", parses[2].String())
+
+	parses, err = WarmCacheFS(context.Background(), fsys, "warm-*.html")
+	equalBool(t, true, err != nil)
+	equalInt(t, 4, len(parses))
+	equal(t, "warm-broken.html", parses[0].Templates[0])
+	equalBool(t, true, parses[0].Err != nil)
+	equalBool(t, true, parses[2].Cached)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := WarmCache(ctx, page); err == nil {
+		t.Error("expected the canceled context to stop warming")
+	}
+}
@@ -0,0 +1,39 @@
+package htmx
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// WriterFunc writes the HTML of a component to the writer, like the Render method of templ components
+type WriterFunc func(ctx context.Context, w io.Writer) error
+
+// NewWriterComponent returns a component that is rendered by the function instead of templates, to use HTML from
+// other template engines as partials, pages and layouts of components. The component is wrapped, post-processed and
+// traced like any other, its partials and data are not passed to the function.
+//
+//	page.With(htmx.NewWriterComponent(func(ctx context.Context, w io.Writer) error {
+//		_, err := io.WriteString(w, "<p>rendered elsewhere</p>")
+//		return err
+//	}), "footer")
+func NewWriterComponent(render WriterFunc) *Component {
+	c := NewComponent()
+	c.writer = render
+	return c
+}
+
+// renderWriter renders the component with its writer function into a pooled buffer, the caller owns the buffer
+func (c *Component) renderWriter(ctx context.Context) (*bytes.Buffer, error) {
+	buf := getBuffer()
+
+	endExecute := c.startPhase(PhaseExecute)
+	err := c.writer(ctx, buf)
+	endExecute()
+	if err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+
+	return buf, nil
+}
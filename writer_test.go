@@ -0,0 +1,53 @@
+package htmx
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewWriterComponent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"writer-layout.html": {Data: []byte(`<main>{{ .Partials.content }}</main>`)},
+	}
+
+	page := NewWriterComponent(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<p>written</p>")
+		return err
+	})
+	page.Wrap(NewComponent("writer-layout.html").FS(fsys), "content")
+
+	w := httptest.NewRecorder()
+	if _, err := New().NewHandler(w, httptest.NewRequest("GET", "/", nil)).Render(context.Background(), page); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "<main><p>written</p></main>", w.Body.String())
+}
+
+func TestWriterComponentDevMode(t *testing.T) {
+	SetMode(Dev)
+	defer SetMode(Prod)
+
+	page := NewWriterComponent(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<p>written</p>")
+		return err
+	})
+
+	w := httptest.NewRecorder()
+	if _, err := New().NewHandler(w, httptest.NewRequest("GET", "/?htmx-debug", nil)).Render(context.Background(), page); err != nil {
+		t.Fatal(err)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`<!-- htmx:begin component="(writer)" templates="" --><p data-htmx-component="(writer)" data-htmx-templates=""`,
+		`</p><!-- htmx:end component="(writer)" -->`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in %s", want, body)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Package ws serves the htmx websocket extension: it upgrades the connection, parses the messages that htmx sends and
+// sends rendered components back as out-of-band swaps.
+//
+//	<div hx-ext="ws" ws-connect="/live">
+//		<div id="stats"></div>
+//		<form ws-send><input name="q"></form>
+//	</div>
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/jkc-2/go-htmx"
+	"golang.org/x/net/websocket"
+)
+
+type (
+	// Options configures a Server
+	Options struct {
+		// OnConnect is called after the connection was upgraded, it can push updates to the connection until its
+		// context is done. OnConnect must not block.
+		OnConnect func(c *Conn)
+
+		// OnMessage is called for every message of the client, an error closes the connection
+		OnMessage func(c *Conn, m *Message) error
+
+		// CheckOrigin returns true if the request may be upgraded, by default browsers may only connect from the
+		// same host
+		CheckOrigin func(r *http.Request) bool
+	}
+
+	// Server upgrades requests to websocket connections for the htmx websocket extension
+	Server struct {
+		opts Options
+	}
+
+	// Headers are the request headers htmx sends along with every message
+	Headers struct {
+		Request     bool   // HX-Request
+		Trigger     string // HX-Trigger, the id of the element that sent the message
+		TriggerName string // HX-Trigger-Name, the name of the element that sent the message
+		Target      string // HX-Target, the id of the target element
+		CurrentURL  string // HX-Current-URL, the url of the browser
+	}
+
+	// Message is a message sent by htmx, the values of the form that was sent and the htmx headers
+	Message struct {
+		Headers Headers
+		Values  url.Values
+	}
+
+	// Conn is an upgraded websocket connection, it is safe for concurrent use
+	Conn struct {
+		ws  *websocket.Conn
+		r   *http.Request
+		ctx context.Context
+
+		mu sync.Mutex
+	}
+)
+
+// NewServer returns a new server for the options
+func NewServer(opts Options) *Server {
+	if opts.CheckOrigin == nil {
+		opts.CheckOrigin = sameOrigin
+	}
+
+	return &Server{opts: opts}
+}
+
+// ServeHTTP upgrades the request and reads its messages until the client disconnects
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.opts.CheckOrigin(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			c := &Conn{ws: ws, r: r, ctx: ctx}
+			if s.opts.OnConnect != nil {
+				s.opts.OnConnect(c)
+			}
+
+			for {
+				var raw string
+				if err := websocket.Message.Receive(ws, &raw); err != nil {
+					return
+				}
+
+				if s.opts.OnMessage == nil {
+					continue
+				}
+
+				m, err := ParseMessage([]byte(raw))
+				if err != nil {
+					return
+				}
+
+				if err := s.opts.OnMessage(c, m); err != nil {
+					return
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(w, r)
+}
+
+// ParseMessage parses a message of the htmx websocket extension, the values of the form with the htmx headers in HEADERS
+func ParseMessage(data []byte) (*Message, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("ws: invalid message: %w", err)
+	}
+
+	m := &Message{Values: url.Values{}}
+
+	if raw, ok := fields["HEADERS"]; ok {
+		var headers map[string]any
+		if err := json.Unmarshal(raw, &headers); err != nil {
+			return nil, fmt.Errorf("ws: invalid message headers: %w", err)
+		}
+
+		m.Headers = Headers{
+			Request:     htmx.HxStrToBool(headerValue(headers, htmx.HxRequestHeaderRequest)),
+			Trigger:     headerValue(headers, htmx.HxRequestHeaderTrigger),
+			TriggerName: headerValue(headers, htmx.HxRequestHeaderTriggerName),
+			Target:      headerValue(headers, htmx.HxRequestHeaderTarget),
+			CurrentURL:  headerValue(headers, htmx.HxRequestHeaderCurrentURL),
+		}
+		delete(fields, "HEADERS")
+	}
+
+	for key, raw := range fields {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("ws: invalid message value %s: %w", key, err)
+		}
+
+		// fields with multiple values, like a multi select, are sent as an array
+		if values, ok := value.([]any); ok {
+			for _, v := range values {
+				m.Values.Add(key, formValue(v))
+			}
+			continue
+		}
+
+		m.Values.Set(key, formValue(value))
+	}
+
+	return m, nil
+}
+
+// Get returns the first value of the form field
+func (m *Message) Get(key string) string {
+	return m.Values.Get(key)
+}
+
+// Request returns the request that was upgraded
+func (c *Conn) Request() *http.Request {
+	return c.r
+}
+
+// Context returns the context of the connection, it is done when the client disconnected
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// Send sends the html, htmx swaps its elements into the elements with the same id
+func (c *Conn) Send(html template.HTML) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ctx.Err(); err != nil {
+		return errors.Join(errors.New("ws: connection closed"), err)
+	}
+
+	return websocket.Message.Send(c.ws, string(html))
+}
+
+// Render renders the component with the request of the connection and sends it as an out-of-band swap into the
+// element with the id, a nil swap replaces the element. See htmx.Component.RenderOOB.
+func (c *Conn) Render(id string, component htmx.RenderableComponent, swap *htmx.Swap) error {
+	fragment := htmx.NewMultiFragment(nil).OOB(id, component, swap)
+	fragment.SetRequest(c.r)
+
+	output, err := fragment.Render(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.Send(output)
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	return c.ws.Close()
+}
+
+// headerValue returns the header as a string
+func headerValue(headers map[string]any, key htmx.HxRequestHeaderKey) string {
+	if v, ok := headers[key.String()]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+
+	return ""
+}
+
+// formValue returns the JSON value as a form value
+func formValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// sameOrigin returns true for requests without an Origin header and for origins with the host of the request
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return u.Host == r.Host
+}
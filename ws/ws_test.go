@@ -0,0 +1,96 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jkc-2/go-htmx"
+	"golang.org/x/net/websocket"
+)
+
+func TestParseMessage(t *testing.T) {
+	m, err := ParseMessage([]byte(`{"q":"ada","tags":["a","b"],"page":2,"HEADERS":{"HX-Request":"true","HX-Trigger":"search","HX-Trigger-Name":null,"HX-Target":"results","HX-Current-URL":"http://localhost/users"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, true, m.Headers.Request)
+	equal(t, "search", m.Headers.Trigger)
+	equal(t, "", m.Headers.TriggerName)
+	equal(t, "results", m.Headers.Target)
+	equal(t, "http://localhost/users", m.Headers.CurrentURL)
+	equal(t, "ada", m.Get("q"))
+	equal(t, "2", m.Get("page"))
+	equal(t, "a,b", strings.Join(m.Values["tags"], ","))
+	equal(t, "", m.Get("HEADERS"))
+
+	if _, err := ParseMessage([]byte(`not json`)); err == nil {
+		t.Error("expected an error for an invalid message")
+	}
+}
+
+func TestServer(t *testing.T) {
+	fsys := fstest.MapFS{"results.html": {Data: []byte(`<ul><li>{{ .Data.Query }}</li></ul>`)}}
+
+	server := NewServer(Options{
+		OnConnect: func(c *Conn) {
+			_ = c.Send(`<div id="status">connected</div>`)
+		},
+		OnMessage: func(c *Conn, m *Message) error {
+			return c.Render(m.Headers.Target, htmx.NewComponent("results.html").FS(fsys).AddData("Query", m.Get("q")), nil)
+		},
+	})
+
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	conn, err := websocket.Dial(url, "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var msg string
+	if err := websocket.Message.Receive(conn, &msg); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<div id="status">connected</div>`, msg)
+
+	if err := websocket.Message.Send(conn, `{"q":"ada","HEADERS":{"HX-Request":"true","HX-Target":"results"}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := websocket.Message.Receive(conn, &msg); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `<ul id="results" hx-swap-oob="outerHTML"><li>ada</li></ul>`, msg)
+}
+
+func TestServerOrigin(t *testing.T) {
+	srv := httptest.NewServer(NewServer(Options{}))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	req.Header.Set("Origin", "https://evil.example")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = res.Body.Close()
+
+	equal(t, http.StatusForbidden, res.StatusCode)
+}
+
+func equal[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}